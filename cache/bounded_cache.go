@@ -0,0 +1,354 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// BoundedOption configures the limits of a bounded cache.
+type BoundedOption func(*bounds)
+
+// bounds holds the limits of a bounded cache. A zero limit is no limit.
+type bounds struct {
+	maxEntries int
+	maxBytes   int64
+	ttl        time.Duration
+}
+
+// WithMaxEntries limits the number of entries a bounded cache holds. The least recently used entries are
+// evicted first.
+func WithMaxEntries(maxEntries int) BoundedOption {
+	return func(b *bounds) {
+		b.maxEntries = max(maxEntries, 0)
+	}
+}
+
+// WithMaxBytes limits the size of a bounded cache, measured in bytes of rendered schema (RenderedJSON and
+// RenderedInline). The least recently used entries are evicted first. Regex caches are not limited by size.
+func WithMaxBytes(maxBytes int64) BoundedOption {
+	return func(b *bounds) {
+		b.maxBytes = max(maxBytes, 0)
+	}
+}
+
+// WithTTL expires entries that were stored longer than ttl ago.
+func WithTTL(ttl time.Duration) BoundedOption {
+	return func(b *bounds) {
+		b.ttl = max(ttl, 0)
+	}
+}
+
+// Stats holds the counters of a bounded cache.
+type Stats struct {
+	Hits        uint64 `json:"hits"`
+	Misses      uint64 `json:"misses"`
+	Evictions   uint64 `json:"evictions"`   // entries removed to stay within the limits
+	Expirations uint64 `json:"expirations"` // entries removed because their TTL passed
+	Entries     int    `json:"entries"`
+	Bytes       int64  `json:"bytes"`
+}
+
+// BoundedCache is a SchemaCache that evicts the least recently used entries when it holds too many entries or
+// bytes, and expires entries after an optional TTL.
+type BoundedCache struct {
+	lru *lru[uint64, *SchemaCacheEntry]
+}
+
+// BoundedSchemaResourceCache is a SchemaResourceCache that evicts the least recently used resources when it
+// holds too many entries or bytes, and expires resources after an optional TTL.
+type BoundedSchemaResourceCache struct {
+	lru *lru[string, *SchemaResourceCacheEntry]
+}
+
+// BoundedRegexCache is a bounded cache of compiled regular expressions, it can be used as a config.RegexCache
+// in place of a sync.Map.
+type BoundedRegexCache struct {
+	lru *lru[any, any]
+}
+
+var (
+	_ SchemaCache         = &BoundedCache{}
+	_ EvictingSchemaCache = &BoundedCache{}
+	_ SchemaResourceCache = &BoundedSchemaResourceCache{}
+)
+
+// NewBoundedCache creates a new BoundedCache with the given limits.
+func NewBoundedCache(opts ...BoundedOption) *BoundedCache {
+	return &BoundedCache{lru: newLRU[uint64](newBounds(opts), func(e *SchemaCacheEntry) int64 {
+		if e == nil {
+			return 0
+		}
+		return int64(len(e.RenderedJSON) + len(e.RenderedInline))
+	})}
+}
+
+// NewBoundedSchemaResourceCache creates a new BoundedSchemaResourceCache with the given limits.
+func NewBoundedSchemaResourceCache(opts ...BoundedOption) *BoundedSchemaResourceCache {
+	return &BoundedSchemaResourceCache{lru: newLRU[string](newBounds(opts), func(e *SchemaResourceCacheEntry) int64 {
+		if e == nil {
+			return 0
+		}
+		return int64(len(e.RenderedJSON) + len(e.RenderedInline))
+	})}
+}
+
+// NewBoundedRegexCache creates a new BoundedRegexCache with the given limits.
+func NewBoundedRegexCache(opts ...BoundedOption) *BoundedRegexCache {
+	b := newBounds(opts)
+	b.maxBytes = 0
+	return &BoundedRegexCache{lru: newLRU[any](b, func(any) int64 { return 0 })}
+}
+
+func newBounds(opts []BoundedOption) bounds {
+	var b bounds
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&b)
+		}
+	}
+	return b
+}
+
+// Load retrieves a schema from the cache.
+func (c *BoundedCache) Load(key uint64) (*SchemaCacheEntry, bool) {
+	if c == nil {
+		return nil, false
+	}
+	return c.lru.load(key)
+}
+
+// Store saves a schema to the cache, evicting older entries when the cache is full.
+func (c *BoundedCache) Store(key uint64, value *SchemaCacheEntry) {
+	if c == nil {
+		return
+	}
+	c.lru.store(key, value)
+}
+
+// Range calls f for each entry in the cache (for testing/inspection), without marking entries as used.
+func (c *BoundedCache) Range(f func(key uint64, value *SchemaCacheEntry) bool) {
+	if c == nil {
+		return
+	}
+	c.lru.rangeEntries(f)
+}
+
+// Evicts returns true when the cache has a limit, and may evict entries.
+func (c *BoundedCache) Evicts() bool {
+	return c != nil && c.lru.bounds != bounds{}
+}
+
+// Release clears all cached schema entries.
+func (c *BoundedCache) Release() {
+	if c == nil {
+		return
+	}
+	c.lru.clear()
+}
+
+// Stats returns the counters of the cache.
+func (c *BoundedCache) Stats() Stats {
+	if c == nil {
+		return Stats{}
+	}
+	return c.lru.stats()
+}
+
+// Load retrieves a rendered document resource from the cache.
+func (c *BoundedSchemaResourceCache) Load(key string) (*SchemaResourceCacheEntry, bool) {
+	if c == nil {
+		return nil, false
+	}
+	return c.lru.load(key)
+}
+
+// Store saves a rendered document resource to the cache, evicting older entries when the cache is full.
+func (c *BoundedSchemaResourceCache) Store(key string, value *SchemaResourceCacheEntry) {
+	if c == nil {
+		return
+	}
+	c.lru.store(key, value)
+}
+
+// Range calls f for each rendered document resource cache entry, without marking entries as used.
+func (c *BoundedSchemaResourceCache) Range(f func(key string, value *SchemaResourceCacheEntry) bool) {
+	if c == nil {
+		return
+	}
+	c.lru.rangeEntries(f)
+}
+
+// Release clears all cached rendered document resources.
+func (c *BoundedSchemaResourceCache) Release() {
+	if c == nil {
+		return
+	}
+	c.lru.clear()
+}
+
+// Stats returns the counters of the cache.
+func (c *BoundedSchemaResourceCache) Stats() Stats {
+	if c == nil {
+		return Stats{}
+	}
+	return c.lru.stats()
+}
+
+// Load retrieves a compiled regex from the cache.
+func (c *BoundedRegexCache) Load(key any) (any, bool) {
+	if c == nil {
+		return nil, false
+	}
+	return c.lru.load(key)
+}
+
+// Store saves a compiled regex to the cache, evicting older entries when the cache is full.
+func (c *BoundedRegexCache) Store(key, value any) {
+	if c == nil {
+		return
+	}
+	c.lru.store(key, value)
+}
+
+// Release clears all cached regular expressions. Releasing a validator leaves the regex cache it was given
+// alone, so callers release the cache once nothing uses it.
+func (c *BoundedRegexCache) Release() {
+	if c == nil {
+		return
+	}
+	c.lru.clear()
+}
+
+// Stats returns the counters of the cache.
+func (c *BoundedRegexCache) Stats() Stats {
+	if c == nil {
+		return Stats{}
+	}
+	return c.lru.stats()
+}
+
+// lru is a thread safe, least recently used cache with optional count, size and age limits.
+type lru[K comparable, V any] struct {
+	mu      sync.Mutex
+	bounds  bounds
+	size    func(V) int64
+	now     func() time.Time
+	entries map[K]*list.Element
+	order   *list.List // front is the most recently used entry
+	bytes   int64
+	counts  Stats
+}
+
+type lruEntry[K comparable, V any] struct {
+	key    K
+	value  V
+	size   int64
+	stored time.Time
+}
+
+func newLRU[K comparable, V any](b bounds, size func(V) int64) *lru[K, V] {
+	return &lru[K, V]{
+		bounds:  b,
+		size:    size,
+		now:     time.Now,
+		entries: make(map[K]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (l *lru[K, V]) load(key K) (V, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var zero V
+	element, ok := l.entries[key]
+	if !ok {
+		l.counts.Misses++
+		return zero, false
+	}
+	entry := element.Value.(*lruEntry[K, V])
+	if l.expired(entry) {
+		l.remove(element)
+		l.counts.Expirations++
+		l.counts.Misses++
+		return zero, false
+	}
+	l.order.MoveToFront(element)
+	l.counts.Hits++
+	return entry.value, true
+}
+
+func (l *lru[K, V]) store(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if element, ok := l.entries[key]; ok {
+		l.remove(element)
+	}
+	entry := &lruEntry[K, V]{key: key, value: value, size: l.size(value), stored: l.now()}
+
+	// an entry larger than the whole cache is never kept, and does not push out the entries that fit.
+	if l.bounds.maxBytes > 0 && entry.size > l.bounds.maxBytes {
+		l.counts.Evictions++
+		return
+	}
+	l.entries[key] = l.order.PushFront(entry)
+	l.bytes += entry.size
+
+	for l.full() {
+		l.remove(l.order.Back())
+		l.counts.Evictions++
+	}
+}
+
+func (l *lru[K, V]) rangeEntries(f func(key K, value V) bool) {
+	l.mu.Lock()
+	live := make([]*lruEntry[K, V], 0, l.order.Len())
+	for element := l.order.Front(); element != nil; element = element.Next() {
+		if entry := element.Value.(*lruEntry[K, V]); !l.expired(entry) {
+			live = append(live, entry)
+		}
+	}
+	l.mu.Unlock()
+
+	// callbacks run outside the lock, so they can use the cache.
+	for _, entry := range live {
+		if !f(entry.key, entry.value) {
+			return
+		}
+	}
+}
+
+func (l *lru[K, V]) clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = make(map[K]*list.Element)
+	l.order.Init()
+	l.bytes = 0
+}
+
+func (l *lru[K, V]) stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s := l.counts
+	s.Entries = l.order.Len()
+	s.Bytes = l.bytes
+	return s
+}
+
+func (l *lru[K, V]) full() bool {
+	return (l.bounds.maxEntries > 0 && l.order.Len() > l.bounds.maxEntries) ||
+		(l.bounds.maxBytes > 0 && l.bytes > l.bounds.maxBytes)
+}
+
+func (l *lru[K, V]) expired(entry *lruEntry[K, V]) bool {
+	return l.bounds.ttl > 0 && l.now().Sub(entry.stored) > l.bounds.ttl
+}
+
+func (l *lru[K, V]) remove(element *list.Element) {
+	entry := l.order.Remove(element).(*lruEntry[K, V])
+	delete(l.entries, entry.key)
+	l.bytes -= entry.size
+}
@@ -0,0 +1,208 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package cache
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+)
+
+func TestBoundedCache_MaxEntries(t *testing.T) {
+	cache := NewBoundedCache(WithMaxEntries(2))
+
+	cache.Store(1, &SchemaCacheEntry{RenderedInline: []byte("one")})
+	cache.Store(2, &SchemaCacheEntry{RenderedInline: []byte("two")})
+
+	// using 1 makes 2 the least recently used entry.
+	_, ok := cache.Load(1)
+	assert.True(t, ok)
+	cache.Store(3, &SchemaCacheEntry{RenderedInline: []byte("three")})
+
+	_, ok = cache.Load(2)
+	assert.False(t, ok)
+	loaded, ok := cache.Load(1)
+	require.True(t, ok)
+	assert.Equal(t, []byte("one"), loaded.RenderedInline)
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, uint64(1), stats.Evictions)
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, int64(8), stats.Bytes)
+}
+
+func TestBoundedCache_Evicts(t *testing.T) {
+	assert.False(t, Evicts(NewBoundedCache()))
+	assert.True(t, Evicts(NewBoundedCache(WithMaxEntries(2))))
+	assert.True(t, Evicts(NewBoundedCache(WithMaxBytes(10))))
+	assert.True(t, Evicts(NewBoundedCache(WithTTL(time.Minute))))
+	assert.False(t, Evicts(NewDefaultCache()))
+	assert.False(t, Evicts(nil))
+
+	disk, err := NewDiskCache(t.TempDir(), NewBoundedCache(WithMaxEntries(2)))
+	require.NoError(t, err)
+	assert.True(t, Evicts(disk))
+	disk, err = NewDiskCache(t.TempDir(), nil)
+	require.NoError(t, err)
+	assert.False(t, Evicts(disk))
+}
+
+func TestBoundedCache_MaxBytes(t *testing.T) {
+	cache := NewBoundedCache(WithMaxBytes(10))
+
+	cache.Store(1, &SchemaCacheEntry{RenderedInline: []byte("12345"), RenderedJSON: []byte("123")})
+	cache.Store(2, &SchemaCacheEntry{RenderedJSON: []byte("1234")})
+	assert.Equal(t, int64(4), cache.Stats().Bytes)
+	_, ok := cache.Load(1)
+	assert.False(t, ok)
+
+	// replacing an entry replaces its size.
+	cache.Store(2, &SchemaCacheEntry{RenderedJSON: []byte("12")})
+	assert.Equal(t, int64(2), cache.Stats().Bytes)
+
+	// entries larger than the cache are not kept.
+	cache.Store(3, &SchemaCacheEntry{RenderedJSON: []byte("12345678901")})
+	_, ok = cache.Load(3)
+	assert.False(t, ok)
+	_, ok = cache.Load(2)
+	assert.True(t, ok)
+}
+
+func TestBoundedCache_TTL(t *testing.T) {
+	cache := NewBoundedCache(WithTTL(time.Minute))
+	now := time.Now()
+	cache.lru.now = func() time.Time { return now }
+
+	cache.Store(1, &SchemaCacheEntry{})
+	_, ok := cache.Load(1)
+	assert.True(t, ok)
+
+	now = now.Add(2 * time.Minute)
+	count := 0
+	cache.Range(func(uint64, *SchemaCacheEntry) bool {
+		count++
+		return true
+	})
+	assert.Equal(t, 0, count)
+
+	_, ok = cache.Load(1)
+	assert.False(t, ok)
+	assert.Equal(t, uint64(1), cache.Stats().Expirations)
+	assert.Equal(t, 0, cache.Stats().Entries)
+}
+
+func TestBoundedCache_RangeAndRelease(t *testing.T) {
+	cache := NewBoundedCache()
+	for i := 0; i < 5; i++ {
+		cache.Store(uint64(i), &SchemaCacheEntry{})
+	}
+
+	count := 0
+	cache.Range(func(key uint64, _ *SchemaCacheEntry) bool {
+		count++
+		// the cache can be used while ranging over it.
+		cache.Store(key+100, &SchemaCacheEntry{})
+		return count < 3
+	})
+	assert.Equal(t, 3, count)
+	assert.Equal(t, 8, cache.Stats().Entries)
+
+	cache.Release()
+	assert.Equal(t, 0, cache.Stats().Entries)
+	assert.Equal(t, int64(0), cache.Stats().Bytes)
+
+	var nilCache *BoundedCache
+	nilCache.Store(1, &SchemaCacheEntry{})
+	_, ok := nilCache.Load(1)
+	assert.False(t, ok)
+	nilCache.Range(func(uint64, *SchemaCacheEntry) bool { return true })
+	nilCache.Release()
+	assert.Equal(t, Stats{}, nilCache.Stats())
+}
+
+func TestBoundedCache_Concurrent(t *testing.T) {
+	cache := NewBoundedCache(WithMaxEntries(16))
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				key := uint64(g*1000 + i%32)
+				cache.Store(key, &SchemaCacheEntry{})
+				cache.Load(key)
+			}
+		}(g)
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, cache.Stats().Entries, 16)
+}
+
+func TestBoundedSchemaResourceCache(t *testing.T) {
+	cache := NewBoundedSchemaResourceCache(WithMaxEntries(2), WithMaxBytes(100))
+
+	for i := 0; i < 3; i++ {
+		cache.Store(fmt.Sprintf("resource-%d", i), &SchemaResourceCacheEntry{RenderedJSON: []byte("{}")})
+	}
+	_, ok := cache.Load("resource-0")
+	assert.False(t, ok)
+	loaded, ok := cache.Load("resource-2")
+	require.True(t, ok)
+	assert.Equal(t, []byte("{}"), loaded.RenderedJSON)
+
+	var keys []string
+	cache.Range(func(key string, _ *SchemaResourceCacheEntry) bool {
+		keys = append(keys, key)
+		return true
+	})
+	assert.Equal(t, []string{"resource-2", "resource-1"}, keys)
+	assert.Equal(t, int64(4), cache.Stats().Bytes)
+
+	cache.Release()
+	assert.Equal(t, 0, cache.Stats().Entries)
+
+	var nilCache *BoundedSchemaResourceCache
+	nilCache.Store("a", nil)
+	_, ok = nilCache.Load("a")
+	assert.False(t, ok)
+	nilCache.Range(func(string, *SchemaResourceCacheEntry) bool { return true })
+	nilCache.Release()
+	assert.Equal(t, Stats{}, nilCache.Stats())
+}
+
+func TestBoundedRegexCache(t *testing.T) {
+	cache := NewBoundedRegexCache(WithMaxEntries(1), WithMaxBytes(1))
+
+	cache.Store("{id}", regexp.MustCompile(`^[^/]+$`))
+	cache.Store("{name}", regexp.MustCompile(`^[a-z]+$`))
+
+	_, ok := cache.Load("{id}")
+	assert.False(t, ok)
+	value, ok := cache.Load("{name}")
+	require.True(t, ok)
+	assert.True(t, value.(*regexp.Regexp).MatchString("fido"))
+	assert.Equal(t, uint64(1), cache.Stats().Evictions)
+
+	cache.Release()
+	assert.Equal(t, 0, cache.Stats().Entries)
+
+	var nilCache *BoundedRegexCache
+	nilCache.Store("a", nil)
+	_, ok = nilCache.Load("a")
+	assert.False(t, ok)
+	nilCache.Release()
+	assert.Equal(t, Stats{}, nilCache.Stats())
+}
+
+func TestBoundedOptions(t *testing.T) {
+	b := newBounds([]BoundedOption{WithMaxEntries(-1), WithMaxBytes(-1), WithTTL(-time.Second), nil})
+	assert.Equal(t, bounds{}, b)
+}
@@ -19,6 +19,15 @@ type SchemaCacheEntry struct {
 	CompiledSchema  *jsonschema.Schema
 	RenderedNode    *yaml.Node
 	ResourceNodes   map[string]*yaml.Node
+	Source          *SchemaSource // What is needed to compile the schema again, without the document
+}
+
+// SchemaSource holds the rendered JSON a schema was compiled from, so it can be compiled again without
+// rendering the document, for example after it was loaded from disk by a DiskCache.
+type SchemaSource struct {
+	Version   float32           // OpenAPI version the schema was compiled for
+	Name      string            // name of the compiled schema, a resource name and fragment for document resources
+	Resources map[string][]byte // rendered JSON document resources, empty when RenderedJSON compiles on its own
 }
 
 // SchemaResourceCacheEntry holds one rendered document-level JSON Schema resource.
@@ -40,6 +49,19 @@ type SchemaCache interface {
 	Range(f func(key uint64, value *SchemaCacheEntry) bool)
 }
 
+// EvictingSchemaCache is implemented by schema caches that may drop entries on their own, such as a BoundedCache
+// with limits. Validation plans don't hold on to the entries of a cache that evicts, so evicted entries are freed.
+// Caches that wrap another cache implement it by asking the cache they wrap.
+type EvictingSchemaCache interface {
+	Evicts() bool
+}
+
+// Evicts returns true when a schema cache may drop entries on its own.
+func Evicts(schemaCache SchemaCache) bool {
+	evicting, ok := schemaCache.(EvictingSchemaCache)
+	return ok && evicting.Evicts()
+}
+
 // SchemaResourceCache caches rendered document resources by parsed document identity.
 // Entries are immutable once stored; implementations must be safe for concurrent use.
 type SchemaResourceCache interface {
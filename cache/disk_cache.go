@@ -0,0 +1,334 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// diskCacheFormat is bumped whenever the layout of the files written by DiskCache changes.
+const diskCacheFormat = 1
+
+// diskCacheSchemaVersion is bumped whenever the way schemas are rendered or compiled changes. Module versions are
+// "(devel)" or empty in development builds, so they can't tell those changes apart on their own.
+const diskCacheSchemaVersion = 1
+
+// modules whose versions change the rendered or compiled form of schemas.
+var diskCacheModules = []string{
+	"github.com/pb33f/libopenapi-validator",
+	"github.com/pb33f/libopenapi",
+	"github.com/santhosh-tekuri/jsonschema/v6",
+}
+
+// SchemaCompiler compiles a schema cache entry that was loaded without a compiled schema.
+type SchemaCompiler func(entry *SchemaCacheEntry) (*jsonschema.Schema, error)
+
+// PersistentSchemaCache is a SchemaCache that keeps entries beyond the life of the process. The validator binds
+// it to the document being validated before warming, entries persisted for another document (or another
+// version of the library) are discarded.
+type PersistentSchemaCache interface {
+	SchemaCache
+	Bind(documentHash string, compile SchemaCompiler) error
+}
+
+// DiskCache is a SchemaCache that persists rendered schemas to a local directory, so later starts only need to
+// compile them. Entries are kept in memory once loaded or stored.
+//
+// A directory holds the schemas of one document, validators of different documents need their own directories.
+// Document resources are stored once, by content, and shared by every schema that references them. Schemas
+// loaded from disk have no rendered YAML nodes, error locations are found by parsing the rendered schema.
+type DiskCache struct {
+	dir     string
+	memory  SchemaCache
+	mu      sync.RWMutex
+	bound   bool
+	compile SchemaCompiler
+	// resources maps content hashes to resources known to be on disk, so schemas share one copy of each.
+	resources sync.Map
+}
+
+// diskManifest identifies the document and library that wrote a cache directory.
+type diskManifest struct {
+	Format   int    `json:"format"`
+	Schema   int    `json:"schema"`
+	Library  string `json:"library"`
+	Document string `json:"document"`
+}
+
+// diskEntry is a schema cache entry as it is stored on disk.
+type diskEntry struct {
+	Version        float32           `json:"version"`
+	Name           string            `json:"name"`
+	RenderedInline []byte            `json:"renderedInline"`
+	RenderedJSON   []byte            `json:"renderedJSON"`
+	Resources      map[string]string `json:"resources,omitempty"` // resource name to content hash
+}
+
+var _ PersistentSchemaCache = &DiskCache{}
+
+// NewDiskCache creates a DiskCache that persists schemas in dir, creating it when needed. Loaded and stored
+// entries are kept in memory, a nil memory cache uses a DefaultCache.
+func NewDiskCache(dir string, memory SchemaCache) (*DiskCache, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("disk cache directory is empty")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create disk cache directory: %w", err)
+	}
+	if memory == nil {
+		memory = NewDefaultCache()
+	}
+	return &DiskCache{dir: dir, memory: memory}, nil
+}
+
+// Bind points the cache at a document. When the directory was written for another document, by another
+// version of the library, or with another way of rendering schemas, its entries are removed. Until the cache is bound, nothing is read from or written
+// to disk.
+func (c *DiskCache) Bind(documentHash string, compile SchemaCompiler) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	manifest := diskManifest{Format: diskCacheFormat, Schema: diskCacheSchemaVersion, Library: libraryVersion(), Document: documentHash}
+	manifestPath := filepath.Join(c.dir, "manifest.json")
+
+	var existing diskManifest
+	if data, err := os.ReadFile(manifestPath); err != nil || json.Unmarshal(data, &existing) != nil || existing != manifest {
+		for _, sub := range []string{"entries", "resources"} {
+			if err := os.RemoveAll(filepath.Join(c.dir, sub)); err != nil {
+				return fmt.Errorf("unable to invalidate disk cache: %w", err)
+			}
+		}
+		c.resources.Clear()
+		encoded, _ := json.Marshal(manifest)
+		if err := writeFileAtomic(manifestPath, encoded); err != nil {
+			return fmt.Errorf("unable to write disk cache manifest: %w", err)
+		}
+	}
+	for _, sub := range []string{"entries", "resources"} {
+		if err := os.MkdirAll(filepath.Join(c.dir, sub), 0o755); err != nil {
+			return fmt.Errorf("unable to create disk cache directory: %w", err)
+		}
+	}
+	c.compile = compile
+	c.bound = true
+	return nil
+}
+
+// Load retrieves a schema from memory, or from disk when it was persisted by an earlier start, in which case it
+// is compiled before it is returned.
+func (c *DiskCache) Load(key uint64) (*SchemaCacheEntry, bool) {
+	if c == nil || c.memory == nil {
+		return nil, false
+	}
+	if entry, ok := c.memory.Load(key); ok {
+		return entry, true
+	}
+
+	c.mu.RLock()
+	bound, compile := c.bound, c.compile
+	c.mu.RUnlock()
+	if !bound || compile == nil {
+		return nil, false
+	}
+
+	entry := c.read(key)
+	if entry == nil {
+		return nil, false
+	}
+	compiled, err := compile(entry)
+	if err != nil || compiled == nil {
+		// an entry that no longer compiles is rendered and stored again by the caller.
+		_ = os.Remove(c.entryPath(key))
+		return nil, false
+	}
+	entry.CompiledSchema = compiled
+	c.memory.Store(key, entry)
+	return entry, true
+}
+
+// Store saves a schema in memory, and persists it when the cache is bound and the entry can be compiled again.
+func (c *DiskCache) Store(key uint64, value *SchemaCacheEntry) {
+	if c == nil || c.memory == nil {
+		return
+	}
+	c.memory.Store(key, value)
+
+	c.mu.RLock()
+	bound := c.bound
+	c.mu.RUnlock()
+	if !bound || value == nil || value.Source == nil {
+		return
+	}
+	// persisting is best effort, a schema that could not be written is compiled again on the next start.
+	_ = c.write(key, value)
+}
+
+// Range calls f for each schema held in memory.
+func (c *DiskCache) Range(f func(key uint64, value *SchemaCacheEntry) bool) {
+	if c == nil || c.memory == nil {
+		return
+	}
+	c.memory.Range(f)
+}
+
+// Evicts returns true when the cache holding schemas in memory may evict them.
+func (c *DiskCache) Evicts() bool {
+	return c != nil && Evicts(c.memory)
+}
+
+// Release clears the schemas held in memory, persisted schemas are kept for the next start.
+func (c *DiskCache) Release() {
+	if c == nil {
+		return
+	}
+	if r, ok := c.memory.(interface{ Release() }); ok {
+		r.Release()
+	}
+	c.resources.Clear()
+	c.mu.Lock()
+	c.compile = nil
+	c.mu.Unlock()
+}
+
+func (c *DiskCache) read(key uint64) *SchemaCacheEntry {
+	data, err := os.ReadFile(c.entryPath(key))
+	if err != nil {
+		return nil
+	}
+	var stored diskEntry
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil
+	}
+	source := &SchemaSource{Version: stored.Version, Name: stored.Name}
+	if len(stored.Resources) > 0 {
+		source.Resources = make(map[string][]byte, len(stored.Resources))
+		for name, hash := range stored.Resources {
+			resource, err := c.readResource(hash)
+			if err != nil {
+				return nil
+			}
+			source.Resources[name] = resource
+		}
+	}
+	return &SchemaCacheEntry{
+		RenderedInline:  stored.RenderedInline,
+		ReferenceSchema: string(stored.RenderedInline),
+		RenderedJSON:    stored.RenderedJSON,
+		Source:          source,
+	}
+}
+
+func (c *DiskCache) write(key uint64, value *SchemaCacheEntry) error {
+	stored := diskEntry{
+		Version:        value.Source.Version,
+		Name:           value.Source.Name,
+		RenderedInline: value.RenderedInline,
+		RenderedJSON:   value.RenderedJSON,
+	}
+	if len(value.Source.Resources) > 0 {
+		stored.Resources = make(map[string]string, len(value.Source.Resources))
+		for name, resource := range value.Source.Resources {
+			hash, err := c.writeResource(resource)
+			if err != nil {
+				return err
+			}
+			stored.Resources[name] = hash
+		}
+	}
+	encoded, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(c.entryPath(key), encoded)
+}
+
+func (c *DiskCache) readResource(hash string) ([]byte, error) {
+	if resource, ok := c.resources.Load(hash); ok {
+		return resource.([]byte), nil
+	}
+	resource, err := os.ReadFile(c.resourcePath(hash))
+	if err != nil {
+		return nil, err
+	}
+	c.resources.Store(hash, resource)
+	return resource, nil
+}
+
+func (c *DiskCache) writeResource(resource []byte) (string, error) {
+	sum := sha256.Sum256(resource)
+	hash := hex.EncodeToString(sum[:])
+	if _, ok := c.resources.Load(hash); ok {
+		return hash, nil
+	}
+	if _, err := os.Stat(c.resourcePath(hash)); err != nil {
+		if err := writeFileAtomic(c.resourcePath(hash), resource); err != nil {
+			return "", err
+		}
+	}
+	c.resources.Store(hash, resource)
+	return hash, nil
+}
+
+func (c *DiskCache) entryPath(key uint64) string {
+	return filepath.Join(c.dir, "entries", strconv.FormatUint(key, 16)+".json")
+}
+
+func (c *DiskCache) resourcePath(hash string) string {
+	return filepath.Join(c.dir, "resources", hash+".json")
+}
+
+// writeFileAtomic writes data to a temporary file and renames it, so readers never see a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Close()
+	} else {
+		_ = tmp.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// libraryVersion returns the versions of the modules that render and compile schemas, as built into the binary.
+func libraryVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	modules := map[string]string{info.Main.Path: info.Main.Version}
+	for _, dep := range info.Deps {
+		version := dep.Version
+		if dep.Replace != nil {
+			version = dep.Replace.Path + "@" + dep.Replace.Version
+		}
+		modules[dep.Path] = version
+	}
+	versions := make([]string, 0, len(diskCacheModules))
+	for _, module := range diskCacheModules {
+		versions = append(versions, module+"@"+modules[module])
+	}
+	return strings.Join(versions, ",")
+}
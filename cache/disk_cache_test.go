@@ -0,0 +1,170 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func countingCompiler(calls *int) SchemaCompiler {
+	return func(entry *SchemaCacheEntry) (*jsonschema.Schema, error) {
+		*calls++
+		if string(entry.RenderedJSON) == "broken" {
+			return nil, fmt.Errorf("broken")
+		}
+		return &jsonschema.Schema{}, nil
+	}
+}
+
+func TestDiskCache_PersistsAcrossStarts(t *testing.T) {
+	dir := t.TempDir()
+	calls := 0
+
+	first, err := NewDiskCache(dir, nil)
+	require.NoError(t, err)
+	require.NoError(t, first.Bind("doc-1", countingCompiler(&calls)))
+
+	resource := []byte(`{"components":{}}`)
+	first.Store(1, &SchemaCacheEntry{
+		RenderedInline: []byte("type: object"),
+		RenderedJSON:   []byte(`{"type":"object"}`),
+		CompiledSchema: &jsonschema.Schema{},
+		Source:         &SchemaSource{Version: 3.1, Name: "one"},
+	})
+	first.Store(2, &SchemaCacheEntry{
+		RenderedJSON:   []byte(`{"$ref":"#/components"}`),
+		CompiledSchema: &jsonschema.Schema{},
+		Source:         &SchemaSource{Version: 3.0, Name: "doc.json#/x", Resources: map[string][]byte{"doc.json": resource}},
+	})
+	first.Store(3, &SchemaCacheEntry{RenderedJSON: []byte(`{}`)}) // no source, memory only
+	first.Store(4, &SchemaCacheEntry{
+		RenderedJSON: []byte(`{}`),
+		Source:       &SchemaSource{Name: "shared", Resources: map[string][]byte{"doc.json": resource}},
+	})
+
+	// resources are stored once.
+	resources, err := os.ReadDir(filepath.Join(dir, "resources"))
+	require.NoError(t, err)
+	assert.Len(t, resources, 1)
+	assert.Equal(t, 0, calls)
+
+	second, err := NewDiskCache(dir, NewBoundedCache())
+	require.NoError(t, err)
+	_, ok := second.Load(1)
+	assert.False(t, ok, "nothing is read before the cache is bound")
+	require.NoError(t, second.Bind("doc-1", countingCompiler(&calls)))
+
+	loaded, ok := second.Load(1)
+	require.True(t, ok)
+	assert.Equal(t, 1, calls)
+	assert.NotNil(t, loaded.CompiledSchema)
+	assert.Equal(t, "type: object", loaded.ReferenceSchema)
+	assert.Equal(t, float32(3.1), loaded.Source.Version)
+	assert.Nil(t, loaded.RenderedNode)
+
+	loaded, ok = second.Load(2)
+	require.True(t, ok)
+	assert.Equal(t, "doc.json#/x", loaded.Source.Name)
+	assert.Equal(t, resource, loaded.Source.Resources["doc.json"])
+
+	// entries loaded from disk are kept in memory.
+	_, ok = second.Load(1)
+	assert.True(t, ok)
+	assert.Equal(t, 2, calls)
+
+	_, ok = second.Load(3)
+	assert.False(t, ok)
+
+	count := 0
+	second.Range(func(uint64, *SchemaCacheEntry) bool {
+		count++
+		return true
+	})
+	assert.Equal(t, 2, count)
+
+	// releasing clears memory, not disk.
+	second.Release()
+	_, err = os.Stat(filepath.Join(dir, "entries", "1.json"))
+	assert.NoError(t, err)
+}
+
+func TestDiskCache_Invalidation(t *testing.T) {
+	dir := t.TempDir()
+	calls := 0
+
+	c, err := NewDiskCache(dir, nil)
+	require.NoError(t, err)
+	require.NoError(t, c.Bind("doc-1", countingCompiler(&calls)))
+	c.Store(1, &SchemaCacheEntry{RenderedJSON: []byte(`{}`), Source: &SchemaSource{Name: "one"}})
+	c.Store(2, &SchemaCacheEntry{RenderedJSON: []byte("broken"), Source: &SchemaSource{Name: "two"}})
+
+	// entries that no longer compile are removed.
+	reopened, err := NewDiskCache(dir, nil)
+	require.NoError(t, err)
+	require.NoError(t, reopened.Bind("doc-1", countingCompiler(&calls)))
+	_, ok := reopened.Load(2)
+	assert.False(t, ok)
+	_, err = os.Stat(filepath.Join(dir, "entries", "2.json"))
+	assert.True(t, os.IsNotExist(err))
+
+	// a changed document discards everything persisted for the old one.
+	reopened, err = NewDiskCache(dir, nil)
+	require.NoError(t, err)
+	require.NoError(t, reopened.Bind("doc-2", countingCompiler(&calls)))
+	_, ok = reopened.Load(1)
+	assert.False(t, ok)
+
+	// as does a cache written by another version of the library.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "manifest.json"),
+		[]byte(`{"format":1,"schema":1,"library":"older","document":"doc-2"}`), 0o644))
+	reopened.Store(1, &SchemaCacheEntry{RenderedJSON: []byte(`{}`), Source: &SchemaSource{Name: "one"}})
+	reopened, err = NewDiskCache(dir, nil)
+	require.NoError(t, err)
+	require.NoError(t, reopened.Bind("doc-2", countingCompiler(&calls)))
+	_, ok = reopened.Load(1)
+	assert.False(t, ok)
+
+	// or one that rendered schemas differently, even when the module versions match, as in development builds.
+	older, _ := json.Marshal(diskManifest{
+		Format:   diskCacheFormat,
+		Schema:   diskCacheSchemaVersion - 1,
+		Library:  libraryVersion(),
+		Document: "doc-2",
+	})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "manifest.json"), older, 0o644))
+	reopened.Store(1, &SchemaCacheEntry{RenderedJSON: []byte(`{}`), Source: &SchemaSource{Name: "one"}})
+	reopened, err = NewDiskCache(dir, nil)
+	require.NoError(t, err)
+	require.NoError(t, reopened.Bind("doc-2", countingCompiler(&calls)))
+	_, ok = reopened.Load(1)
+	assert.False(t, ok)
+}
+
+func TestDiskCache_Errors(t *testing.T) {
+	_, err := NewDiskCache("", nil)
+	assert.Error(t, err)
+
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(file, nil, 0o644))
+	_, err = NewDiskCache(filepath.Join(file, "cache"), nil)
+	assert.Error(t, err)
+
+	var nilCache *DiskCache
+	assert.NoError(t, nilCache.Bind("doc", nil))
+	nilCache.Store(1, &SchemaCacheEntry{})
+	_, ok := nilCache.Load(1)
+	assert.False(t, ok)
+	nilCache.Range(func(uint64, *SchemaCacheEntry) bool { return true })
+	nilCache.Release()
+
+	assert.NotEmpty(t, libraryVersion())
+}
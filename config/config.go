@@ -12,11 +12,16 @@ import (
 	"github.com/santhosh-tekuri/jsonschema/v6"
 
 	"github.com/pb33f/libopenapi-validator/cache"
+	"github.com/pb33f/libopenapi-validator/formats"
+	"github.com/pb33f/libopenapi-validator/message"
+	"github.com/pb33f/libopenapi-validator/openapi_vocabulary"
+	"github.com/pb33f/libopenapi-validator/plan"
 	"github.com/pb33f/libopenapi-validator/radix"
 )
 
 // RegexCache can be set to enable compiled regex caching.
-// It can be just a sync.Map, or a custom implementation with possible cleanup.
+// It can be just a sync.Map, or a custom implementation with possible cleanup, such as
+// cache.NewBoundedRegexCache, which limits how many expressions are kept.
 //
 // Be aware that the cache should be thread safe
 type RegexCache interface {
@@ -36,6 +41,52 @@ type AuthenticationInput struct {
 	Scopes             []string
 }
 
+// InjectedDefault is a schema default that was added to a request by default injection.
+type InjectedDefault struct {
+	In    string // where the default was injected: "query", "header" or "body"
+	Name  string // the parameter name, or the property name for body defaults
+	Path  string // the JSONPath of body defaults, such as '$.body.address.country'
+	Value any    // the injected value
+}
+
+// DefaultsInjectedFunc receives the defaults injected into a request, after the request is validated.
+type DefaultsInjectedFunc func(request message.Request, injected []InjectedDefault)
+
+// WarmProgress reports the progress of schema cache warming. It is sent after each schema is compiled, and once
+// more with Done set when warming has finished.
+type WarmProgress struct {
+	Total     int    // schemas to warm
+	Completed int    // schemas warmed so far, including failures
+	Failed    int    // schemas that failed to compile
+	Method    string // the operation of the schema that was warmed
+	Path      string
+	Location  string // where the schema is, such as 'requestBody application/json' or 'parameter id'
+	Err       error  // why the schema failed to compile
+	Done      bool   // warming has finished
+}
+
+// WarmProgressFunc receives the progress of schema cache warming. It is never called concurrently.
+type WarmProgressFunc func(progress WarmProgress)
+
+// Concurrency picks how ValidateHttpRequest spreads the phases of validating a request over goroutines.
+type Concurrency int
+
+const (
+	// ConcurrencyAuto validates the request body on a shared worker pool, alongside the parameters, when the
+	// body is at least ParallelBodyThreshold bytes, and validates everything on the calling goroutine otherwise.
+	ConcurrencyAuto Concurrency = iota
+
+	// ConcurrencyInline validates every phase on the calling goroutine.
+	ConcurrencyInline
+
+	// ConcurrencyParallel validates the request body on a shared worker pool whenever the request has one.
+	ConcurrencyParallel
+)
+
+// DefaultParallelBodyThreshold is the smallest request body ConcurrencyAuto validates in parallel with the
+// parameters, smaller bodies are validated faster than a goroutine can be handed the work.
+const DefaultParallelBodyThreshold int64 = 64 << 10
+
 // ValidationOptions A container for validation configuration.
 //
 // Generally fluent With... style functions are used to establish the desired behavior.
@@ -49,13 +100,33 @@ type ValidationOptions struct {
 	OpenAPIMode                   bool // Enable OpenAPI-specific vocabulary validation
 	AllowScalarCoercion           bool // Enable string->boolean/number coercion
 	Formats                       map[string]func(v any) error
-	SchemaCache                   cache.SchemaCache         // Optional cache for compiled schemas
-	SchemaResourceCache           cache.SchemaResourceCache // Optional cache for rendered document-level schema resources
-	PathTree                      radix.PathLookup          // O(k) path lookup via radix tree (built automatically)
-	pathTreeDisabled              bool                      // Internal: true if radix tree auto-build was disabled via DisablePathTree
-	Logger                        *slog.Logger              // Logger for debug/error output (nil = silent)
-	AllowXMLBodyValidation        bool                      // Allows to convert XML to JSON for validating a request/response body.
-	AllowURLEncodedBodyValidation bool                      // Allows to convert URL Encoded to JSON for validating a request/response body.
+	Keywords                      map[string]openapi_vocabulary.KeywordCompileFunc // Custom schema keywords
+	SchemaCache                   cache.SchemaCache                                // Optional cache for compiled schemas
+	SchemaResourceCache           cache.SchemaResourceCache                        // Optional cache for rendered document-level schema resources
+	PathTree                      radix.PathLookup                                 // O(k) path lookup via radix tree (built automatically)
+	pathTreeDisabled              bool                                             // Internal: true if radix tree auto-build was disabled via DisablePathTree
+	Plans                         *plan.Plans                                      // Per-operation validation plans (built automatically)
+	plansDisabled                 bool                                             // Internal: true if plan auto-build was disabled via DisableValidationPlans
+	PathTemplateAnalysis          bool                                             // Report ambiguous/conflicting path templates from ValidateDocument
+	Logger                        *slog.Logger                                     // Logger for debug/error output (nil = silent)
+	Observer                      Observer                                         // Optional receiver of validation events, for metrics and tracing
+	Coverage                      CoverageRecorder                                 // Optional record of the parts of the specification traffic exercises
+	AllowXMLBodyValidation        bool                                             // Allows to convert XML to JSON for validating a request/response body.
+	AllowURLEncodedBodyValidation bool                                             // Allows to convert URL Encoded to JSON for validating a request/response body.
+	InjectDefaults                bool                                             // Add schema defaults for missing values to valid requests
+	DefaultsInjected              DefaultsInjectedFunc                             // Optional record of the defaults that were injected
+	DeprecationWarnings           bool                                             // Report the use of deprecated operations, parameters, headers and properties
+	DeprecationsAsErrors          bool                                             // Deprecation warnings fail validation
+	MaxErrors                     int                                              // Stop validating once this many errors are found (0 = no limit)
+	MaxPhaseErrors                int                                              // Stop each phase once it finds this many errors (0 = no limit)
+	LazyErrorDetails              bool                                             // Compute rendered schemas and spec locations of errors when they are inspected
+	WarmWorkers                   int                                              // Compile schemas on this many goroutines while warming (0 or 1 = one)
+	WarmInBackground              bool                                             // Warm schema caches in the background, requests compile schemas on demand
+	WarmOperations                []string                                         // Only warm these operations, by operationId or 'METHOD /path' (nil = all)
+	WarmProgress                  WarmProgressFunc                                 // Optional report of warming progress and failures
+	Concurrency                   Concurrency                                      // How ValidateHttpRequest uses goroutines (default ConcurrencyAuto)
+	ParallelBodyThreshold         int64                                            // Smallest body ConcurrencyAuto validates in parallel (0 = DefaultParallelBodyThreshold)
+	Sampling                      *Sampling                                        // Only validate a share of exchanges (nil = all)
 
 	// strict mode options - detect undeclared properties even when additionalProperties: true
 	StrictMode                bool     // Enable strict property validation
@@ -64,6 +135,7 @@ type ValidationOptions struct {
 	strictIgnoredHeadersMerge bool     // Internal: true if merging with defaults
 	StrictRejectReadOnly      bool     // Reject readOnly properties in requests
 	StrictRejectWriteOnly     bool     // Reject writeOnly properties in responses
+	StrictSanitize            bool     // Strip undeclared values from requests/responses instead of reporting them
 }
 
 // Option Enables an 'Options pattern' approach
@@ -91,6 +163,7 @@ func NewValidationOptions(opts ...Option) *ValidationOptions {
 
 // Release clears cached validation state and drops references that can keep
 // parsed documents, rendered schemas, path trees, or user-provided callbacks alive.
+// A RegexCache is always supplied by the caller and may be shared, so it is only dropped, not cleared.
 func (o *ValidationOptions) Release() {
 	if o == nil {
 		return
@@ -102,11 +175,19 @@ func (o *ValidationOptions) Release() {
 	o.RegexEngine = nil
 	o.RegexCache = nil
 	o.AuthenticationFunc = nil
+	o.DefaultsInjected = nil
 	o.Formats = nil
+	o.Keywords = nil
 	o.SchemaCache = nil
 	o.SchemaResourceCache = nil
 	o.PathTree = nil
+	o.Plans = nil
 	o.Logger = nil
+	o.Observer = nil
+	o.Coverage = nil
+	o.WarmOperations = nil
+	o.WarmProgress = nil
+	o.Sampling = nil
 	o.StrictIgnorePaths = nil
 	o.StrictIgnoredHeaders = nil
 }
@@ -134,11 +215,24 @@ func WithExistingOpts(options *ValidationOptions) Option {
 			o.OpenAPIMode = options.OpenAPIMode
 			o.AllowScalarCoercion = options.AllowScalarCoercion
 			o.Formats = options.Formats
+			o.Keywords = options.Keywords
 			o.SchemaCache = options.SchemaCache
 			o.SchemaResourceCache = options.SchemaResourceCache
 			o.PathTree = options.PathTree
 			o.pathTreeDisabled = options.pathTreeDisabled
+			o.Plans = options.Plans
+			o.plansDisabled = options.plansDisabled
+			o.PathTemplateAnalysis = options.PathTemplateAnalysis
 			o.Logger = options.Logger
+			o.Observer = options.Observer
+			o.Coverage = options.Coverage
+			o.WarmWorkers = options.WarmWorkers
+			o.WarmInBackground = options.WarmInBackground
+			o.WarmOperations = options.WarmOperations
+			o.WarmProgress = options.WarmProgress
+			o.Concurrency = options.Concurrency
+			o.ParallelBodyThreshold = options.ParallelBodyThreshold
+			o.Sampling = options.Sampling
 			o.AllowXMLBodyValidation = options.AllowXMLBodyValidation
 			o.AllowURLEncodedBodyValidation = options.AllowURLEncodedBodyValidation
 			o.StrictMode = options.StrictMode
@@ -147,6 +241,14 @@ func WithExistingOpts(options *ValidationOptions) Option {
 			o.strictIgnoredHeadersMerge = options.strictIgnoredHeadersMerge
 			o.StrictRejectReadOnly = options.StrictRejectReadOnly
 			o.StrictRejectWriteOnly = options.StrictRejectWriteOnly
+			o.StrictSanitize = options.StrictSanitize
+			o.InjectDefaults = options.InjectDefaults
+			o.DefaultsInjected = options.DefaultsInjected
+			o.DeprecationWarnings = options.DeprecationWarnings
+			o.DeprecationsAsErrors = options.DeprecationsAsErrors
+			o.MaxErrors = options.MaxErrors
+			o.MaxPhaseErrors = options.MaxPhaseErrors
+			o.LazyErrorDetails = options.LazyErrorDetails
 		}
 	}
 }
@@ -216,6 +318,38 @@ func WithCustomFormat(name string, validator func(v any) error) Option {
 	}
 }
 
+// WithFormatRegistry enables format assertions, and adds the formats of the OpenAPI Format Registry
+// (int32, int64, float, double, byte, base64url, char, decimal, date-time-local, http-date, media-range, sf-*, etc.).
+// Formats registered with WithCustomFormat take precedence over the registry, regardless of option order.
+func WithFormatRegistry() Option {
+	return func(o *ValidationOptions) {
+		o.FormatAssertions = true
+		if o.Formats == nil {
+			o.Formats = make(map[string]func(v any) error)
+		}
+
+		for name, validator := range formats.Registry() {
+			if _, exists := o.Formats[name]; !exists {
+				o.Formats[name] = validator
+			}
+		}
+	}
+}
+
+// WithKeyword registers a custom schema keyword, such as 'x-iban' or 'x-must-match-field'. compile is called
+// for every schema that uses the keyword, and the extension it returns validates instances of that schema.
+// Keyword failures are reported as schema validation failures, like any other keyword.
+// When you register the same keyword more than once, only the last registration will take effect.
+func WithKeyword(name string, compile openapi_vocabulary.KeywordCompileFunc) Option {
+	return func(o *ValidationOptions) {
+		if o.Keywords == nil {
+			o.Keywords = make(map[string]openapi_vocabulary.KeywordCompileFunc)
+		}
+
+		o.Keywords[name] = compile
+	}
+}
+
 // WithOpenAPIMode enables OpenAPI-specific keyword validation (default: true)
 func WithOpenAPIMode() Option {
 	return func(o *ValidationOptions) {
@@ -255,7 +389,7 @@ func WithURLEncodedBodyValidation() Option {
 
 // WithSchemaCache sets a custom cache implementation or disables caching if nil.
 // Pass nil to disable schema caching and skip cache warming during validator initialization.
-// The default cache is a thread-safe sync.Map wrapper.
+// The default cache is a thread-safe sync.Map wrapper, it is unbounded; cache.NewBoundedCache limits its size.
 func WithSchemaCache(schemaCache cache.SchemaCache) Option {
 	return func(o *ValidationOptions) {
 		o.SchemaCache = schemaCache
@@ -264,7 +398,8 @@ func WithSchemaCache(schemaCache cache.SchemaCache) Option {
 
 // WithSchemaResourceCache sets a cache for rendered document-level schema resources.
 // Pass nil to disable resource reuse when compiling referenced schemas.
-// Cached entries retain source YAML nodes, so long-lived shared caches should be bounded or scoped deliberately.
+// Cached entries retain source YAML nodes, so long-lived shared caches should be bounded or scoped deliberately,
+// for example with cache.NewBoundedSchemaResourceCache.
 func WithSchemaResourceCache(schemaResourceCache cache.SchemaResourceCache) Option {
 	return func(o *ValidationOptions) {
 		o.SchemaResourceCache = schemaResourceCache
@@ -287,6 +422,23 @@ func DisablePathTree() Option {
 	}
 }
 
+// DisableValidationPlans prevents building per-operation validation plans when the validator is built.
+// Each request then derives the parameters, security, media types and schemas of its operation.
+func DisableValidationPlans() Option {
+	return func(o *ValidationOptions) {
+		o.plansDisabled = true
+	}
+}
+
+// WithPathTemplateAnalysis makes ValidateDocument also analyze the path templates of the document, reporting
+// equivalent or ambiguous templates, undeclared or unused path parameters, and templates that can only be
+// matched by the regex fallback. See paths.AnalyzePathTemplates for details.
+func WithPathTemplateAnalysis() Option {
+	return func(o *ValidationOptions) {
+		o.PathTemplateAnalysis = true
+	}
+}
+
 // WithStrictMode enables strict property validation.
 // In strict mode, undeclared properties are reported as errors even when
 // additionalProperties: true would normally allow them.
@@ -335,6 +487,161 @@ func WithStrictRejectWriteOnly() Option {
 	}
 }
 
+// WithStrictSanitize enables strict mode in sanitizing mode. Rather than reporting undeclared
+// properties, query parameters, headers and cookies, they are removed from the *http.Request or
+// *http.Response being validated, so only what the contract declares is passed on. readOnly properties
+// are also removed from request bodies, and writeOnly properties from response bodies.
+//
+// Removed values are not reported as validation errors. Only JSON bodies are sanitized, undeclared values
+// in XML and urlencoded bodies are reported as they are in strict mode. Sanitizing modifies the request, so
+// ValidateHttpRequest runs synchronously when it's enabled.
+func WithStrictSanitize() Option {
+	return func(o *ValidationOptions) {
+		o.StrictMode = true
+		o.StrictSanitize = true
+	}
+}
+
+// WithDefaultInjection adds the schema 'default' of missing query parameters, headers and body properties
+// to requests that pass validation, so handlers don't need to re-implement them. Body objects are filled
+// recursively, including allOf schemas and the matching oneOf or anyOf variant, and JSON bodies are re-serialized.
+//
+// onInjected is optional, when set it receives every default that was injected into a request.
+func WithDefaultInjection(onInjected DefaultsInjectedFunc) Option {
+	return func(o *ValidationOptions) {
+		o.InjectDefaults = true
+		o.DefaultsInjected = onInjected
+	}
+}
+
+// WithDeprecationWarnings reports requests and responses that use a deprecated operation, parameter, header or
+// schema property. Deprecations are warnings, they are returned by ValidateHttpRequestResult and
+// ValidateHttpResponseResult, and logged, but don't fail validation.
+func WithDeprecationWarnings() Option {
+	return func(o *ValidationOptions) {
+		o.DeprecationWarnings = true
+	}
+}
+
+// WithDeprecationsAsErrors reports the use of deprecated operations, parameters, headers and schema properties
+// as errors that fail validation, for APIs that are ready to remove them.
+func WithDeprecationsAsErrors() Option {
+	return func(o *ValidationOptions) {
+		o.DeprecationWarnings = true
+		o.DeprecationsAsErrors = true
+	}
+}
+
+// WithFailFast stops validation at the first error, for callers that only need to know whether a message is
+// valid. It is the same as WithErrorBudget(1, 1), and pairs well with WithLazyErrorDetails.
+func WithFailFast() Option {
+	return WithErrorBudget(1, 1)
+}
+
+// WithErrorBudget stops validation once enough errors are found. Each phase (path, query, header and cookie
+// parameters, security, and the request or response body) stops once it finds perPhase errors, and no further
+// phases run once overall errors are found. The schema failures of a body error are limited to perPhase too.
+// A limit of zero or less means no limit. ValidateHttpRequest runs synchronously when a budget is set, so
+// phases can be skipped.
+func WithErrorBudget(perPhase, overall int) Option {
+	return func(o *ValidationOptions) {
+		o.MaxPhaseErrors = max(perPhase, 0)
+		o.MaxErrors = max(overall, 0)
+	}
+}
+
+// WithLazyErrorDetails skips the expensive parts of building schema validation errors, the rendered
+// 'ReferenceSchema', the 'ReferenceObject' and the line and column of the failure in the schema. They are
+// computed when errors.SchemaValidationFailure.Enrich or errors.ValidationError.Enrich is called, or when
+// the failure is marshalled to JSON or YAML. Until then those fields are empty, so code that reads them directly
+// must call Enrich first. Without this option they are always computed up front.
+func WithLazyErrorDetails() Option {
+	return func(o *ValidationOptions) {
+		o.LazyErrorDetails = true
+	}
+}
+
+// WithWarmWorkers compiles schemas on the given number of goroutines when the schema caches are warmed.
+func WithWarmWorkers(workers int) Option {
+	return func(o *ValidationOptions) {
+		o.WarmWorkers = max(workers, 0)
+	}
+}
+
+// WithBackgroundWarming returns the validator without waiting for the schema caches to be warmed. Warming
+// continues in the background, requests that need a schema before it is warmed compile it on demand, and
+// each schema is only compiled once. Use WithWarmProgress to learn when warming is done.
+func WithBackgroundWarming() Option {
+	return func(o *ValidationOptions) {
+		o.WarmInBackground = true
+	}
+}
+
+// WithWarmOperations only warms the schemas of the given operations, by operationId or as 'METHOD /path'
+// using the path template, for example 'GET /pets/{id}'. Other schemas are compiled on demand.
+func WithWarmOperations(operations ...string) Option {
+	return func(o *ValidationOptions) {
+		o.WarmOperations = append(o.WarmOperations, operations...)
+	}
+}
+
+// WithWarmProgress sets a function that receives the progress and failures of schema cache warming.
+func WithWarmProgress(progress WarmProgressFunc) Option {
+	return func(o *ValidationOptions) {
+		o.WarmProgress = progress
+	}
+}
+
+// WithConcurrency picks how ValidateHttpRequest spreads the phases of validating a request over goroutines.
+// ValidateHttpRequestSync always validates on the calling goroutine.
+func WithConcurrency(concurrency Concurrency) Option {
+	return func(o *ValidationOptions) {
+		o.Concurrency = concurrency
+	}
+}
+
+// WithParallelBodyThreshold sets the smallest request body, in bytes, that ConcurrencyAuto validates in parallel
+// with the parameters. Bodies of unknown length are validated inline.
+func WithParallelBodyThreshold(threshold int64) Option {
+	return func(o *ValidationOptions) {
+		o.ParallelBodyThreshold = max(threshold, 0)
+	}
+}
+
+// ParallelBody returns true when a request body of contentLength bytes is validated in parallel with the
+// parameters of the request. A negative contentLength is a body of unknown length.
+func (o *ValidationOptions) ParallelBody(contentLength int64) bool {
+	if o == nil {
+		return false
+	}
+	switch o.Concurrency {
+	case ConcurrencyParallel:
+		return true
+	case ConcurrencyInline:
+		return false
+	}
+	threshold := o.ParallelBodyThreshold
+	if threshold == 0 {
+		threshold = DefaultParallelBodyThreshold
+	}
+	return contentLength >= threshold
+}
+
+// PhaseBudgetSpent returns true when a phase has found as many errors as its budget allows, so it can stop.
+func (o *ValidationOptions) PhaseBudgetSpent(found int) bool {
+	return o != nil && o.MaxPhaseErrors > 0 && found >= o.MaxPhaseErrors
+}
+
+// BudgetSpent returns true when validation has found as many errors as its overall budget allows.
+func (o *ValidationOptions) BudgetSpent(found int) bool {
+	return o != nil && o.MaxErrors > 0 && found >= o.MaxErrors
+}
+
+// HasErrorBudget returns true when an overall or phase error budget is set.
+func (o *ValidationOptions) HasErrorBudget() bool {
+	return o != nil && (o.MaxErrors > 0 || o.MaxPhaseErrors > 0)
+}
+
 // WithStrictIgnoredHeaders replaces the default ignored headers list entirely.
 // Use this to fully control which headers are ignored in strict mode.
 // For the default list, see the strict package's DefaultIgnoredHeaders.
@@ -373,6 +680,11 @@ func (o *ValidationOptions) IsPathTreeDisabled() bool {
 	return o.pathTreeDisabled
 }
 
+// IsValidationPlansDisabled returns true if plan auto-build was disabled via DisableValidationPlans.
+func (o *ValidationOptions) IsValidationPlansDisabled() bool {
+	return o.plansDisabled
+}
+
 // GetEffectiveStrictIgnoredHeaders returns the list of headers to ignore
 // based on configuration. Returns defaults if not configured, merged list
 // if extra headers were added, or replaced list if headers were fully replaced.
@@ -5,14 +5,20 @@ package config
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"net/http"
+	"regexp"
 	"sync"
 	"testing"
 
 	validatorcache "github.com/pb33f/libopenapi-validator/cache"
+	"github.com/pb33f/libopenapi-validator/message"
+	"github.com/pb33f/libopenapi-validator/plan"
 	"github.com/pb33f/libopenapi-validator/radix"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
 	"github.com/santhosh-tekuri/jsonschema/v6"
 )
 
@@ -167,6 +173,9 @@ func TestValidationOptions_Release(t *testing.T) {
 		WithPathTree(pathTree),
 		WithRegexCache(&sync.Map{}),
 		WithCustomFormat("custom", func(v any) error { return nil }),
+		WithKeyword("x-custom", func(*jsonschema.CompilerContext, any, map[string]any) (jsonschema.SchemaExt, error) {
+			return nil, nil
+		}),
 		WithStrictIgnorePaths("$.body.internal"),
 		WithStrictIgnoredHeaders("X-Internal"),
 		WithLogger(slog.Default()),
@@ -178,6 +187,7 @@ func TestValidationOptions_Release(t *testing.T) {
 	assert.Nil(t, opts.RegexCache)
 	assert.Nil(t, opts.AuthenticationFunc)
 	assert.Nil(t, opts.Formats)
+	assert.Nil(t, opts.Keywords)
 	assert.Nil(t, opts.SchemaCache)
 	assert.Nil(t, opts.SchemaResourceCache)
 	assert.Nil(t, opts.PathTree)
@@ -198,6 +208,33 @@ func TestValidationOptions_Release(t *testing.T) {
 	nilOptions.Release()
 }
 
+func TestValidationOptions_ReleaseKeepsSuppliedRegexCache(t *testing.T) {
+	regexCache := validatorcache.NewBoundedRegexCache(validatorcache.WithMaxEntries(10))
+	regexCache.Store("{id}", regexp.MustCompile(`^[^/]+$`))
+
+	opts := NewValidationOptions(WithRegexCache(regexCache))
+	shared := NewValidationOptions(WithRegexCache(regexCache))
+	assert.Same(t, regexCache, opts.RegexCache)
+	opts.Release()
+
+	// the cache belongs to the caller, and can still be used by other validators.
+	assert.Nil(t, opts.RegexCache)
+	assert.Equal(t, 1, regexCache.Stats().Entries)
+	_, ok := shared.RegexCache.Load("{id}")
+	assert.True(t, ok)
+}
+
+func TestWithPathTemplateAnalysis(t *testing.T) {
+	opts := NewValidationOptions()
+	assert.False(t, opts.PathTemplateAnalysis)
+
+	opts = NewValidationOptions(WithPathTemplateAnalysis())
+	assert.True(t, opts.PathTemplateAnalysis)
+
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	assert.True(t, copied.PathTemplateAnalysis)
+}
+
 func TestWithExistingOpts_NilSource(t *testing.T) {
 	// Test with nil source options
 	opts := NewValidationOptions(WithExistingOpts(nil))
@@ -475,6 +512,128 @@ func TestWithCustomFormat(t *testing.T) {
 	assert.NotNil(t, opts.Formats["test-format"])
 }
 
+func TestWithDefaultInjection(t *testing.T) {
+	opts := NewValidationOptions(WithDefaultInjection(nil))
+	assert.True(t, opts.InjectDefaults)
+	assert.Nil(t, opts.DefaultsInjected)
+
+	var recorded []InjectedDefault
+	opts = NewValidationOptions(WithDefaultInjection(func(_ message.Request, injected []InjectedDefault) {
+		recorded = injected
+	}))
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	assert.True(t, copied.InjectDefaults)
+	require.NotNil(t, copied.DefaultsInjected)
+
+	copied.DefaultsInjected(nil, []InjectedDefault{{In: "query", Name: "limit", Value: 10}})
+	assert.Len(t, recorded, 1)
+
+	copied.Release()
+	assert.Nil(t, copied.DefaultsInjected)
+}
+
+func TestWithDeprecationWarnings(t *testing.T) {
+	opts := NewValidationOptions()
+	assert.False(t, opts.DeprecationWarnings)
+	assert.False(t, opts.DeprecationsAsErrors)
+
+	opts = NewValidationOptions(WithDeprecationWarnings())
+	assert.True(t, opts.DeprecationWarnings)
+	assert.False(t, opts.DeprecationsAsErrors)
+
+	opts = NewValidationOptions(WithDeprecationsAsErrors())
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	assert.True(t, copied.DeprecationWarnings)
+	assert.True(t, copied.DeprecationsAsErrors)
+}
+
+func TestWithErrorBudget(t *testing.T) {
+	var nilOpts *ValidationOptions
+	assert.False(t, nilOpts.HasErrorBudget())
+	assert.False(t, nilOpts.BudgetSpent(10))
+	assert.False(t, nilOpts.PhaseBudgetSpent(10))
+
+	opts := NewValidationOptions()
+	assert.False(t, opts.HasErrorBudget())
+	assert.False(t, opts.BudgetSpent(100))
+
+	opts = NewValidationOptions(WithFailFast())
+	assert.Equal(t, 1, opts.MaxErrors)
+	assert.Equal(t, 1, opts.MaxPhaseErrors)
+	assert.True(t, opts.HasErrorBudget())
+	assert.False(t, opts.BudgetSpent(0))
+	assert.True(t, opts.BudgetSpent(1))
+	assert.True(t, opts.PhaseBudgetSpent(1))
+
+	opts = NewValidationOptions(WithErrorBudget(3, -1), WithLazyErrorDetails())
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	assert.Equal(t, 3, copied.MaxPhaseErrors)
+	assert.Equal(t, 0, copied.MaxErrors)
+	assert.True(t, copied.LazyErrorDetails)
+	assert.False(t, copied.PhaseBudgetSpent(2))
+	assert.True(t, copied.PhaseBudgetSpent(3))
+	assert.False(t, copied.BudgetSpent(1000))
+}
+
+func TestWithWarming(t *testing.T) {
+	opts := NewValidationOptions()
+	assert.Zero(t, opts.WarmWorkers)
+	assert.False(t, opts.WarmInBackground)
+	assert.Nil(t, opts.WarmOperations)
+
+	var progress []WarmProgress
+	opts = NewValidationOptions(
+		WithWarmWorkers(4),
+		WithBackgroundWarming(),
+		WithWarmOperations("getPet"),
+		WithWarmOperations("GET /pets"),
+		WithWarmProgress(func(p WarmProgress) { progress = append(progress, p) }),
+	)
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	assert.Equal(t, 4, copied.WarmWorkers)
+	assert.True(t, copied.WarmInBackground)
+	assert.Equal(t, []string{"getPet", "GET /pets"}, copied.WarmOperations)
+	require.NotNil(t, copied.WarmProgress)
+	copied.WarmProgress(WarmProgress{Done: true})
+	assert.Len(t, progress, 1)
+
+	assert.Zero(t, NewValidationOptions(WithWarmWorkers(-2)).WarmWorkers)
+
+	copied.Release()
+	assert.Nil(t, copied.WarmOperations)
+	assert.Nil(t, copied.WarmProgress)
+}
+
+func TestWithFormatRegistry(t *testing.T) {
+	opts := NewValidationOptions(WithFormatRegistry())
+
+	assert.True(t, opts.FormatAssertions)
+	assert.NotNil(t, opts.Formats["int32"])
+	assert.NotNil(t, opts.Formats["sf-token"])
+	assert.Error(t, opts.Formats["int32"](float64(1<<31)))
+
+	// custom formats are never replaced by the registry
+	custom := func(v any) error { return nil }
+	opts = NewValidationOptions(WithCustomFormat("int32", custom), WithFormatRegistry())
+	assert.NoError(t, opts.Formats["int32"](float64(1<<31)))
+	assert.NotNil(t, opts.Formats["int64"])
+}
+
+func TestWithKeyword(t *testing.T) {
+	compile := func(*jsonschema.CompilerContext, any, map[string]any) (jsonschema.SchemaExt, error) {
+		return nil, nil
+	}
+
+	opts := NewValidationOptions(WithKeyword("x-iban", compile), WithKeyword("x-currency-precision", compile))
+
+	assert.Len(t, opts.Keywords, 2)
+	assert.NotNil(t, opts.Keywords["x-iban"])
+	assert.NotNil(t, opts.Keywords["x-currency-precision"])
+
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	assert.Len(t, copied.Keywords, 2)
+}
+
 func TestWithSchemaCache(t *testing.T) {
 	// Test with nil cache (disables caching)
 	opts := NewValidationOptions(WithSchemaCache(nil))
@@ -576,6 +735,7 @@ func TestWithExistingOpts_StrictFields(t *testing.T) {
 		StrictMode:                true,
 		StrictRejectReadOnly:      true,
 		StrictRejectWriteOnly:     true,
+		StrictSanitize:            true,
 		StrictIgnorePaths:         []string{"$.body.*"},
 		StrictIgnoredHeaders:      []string{"x-custom"},
 		strictIgnoredHeadersMerge: true,
@@ -587,6 +747,7 @@ func TestWithExistingOpts_StrictFields(t *testing.T) {
 	assert.True(t, opts.StrictMode)
 	assert.True(t, opts.StrictRejectReadOnly)
 	assert.True(t, opts.StrictRejectWriteOnly)
+	assert.True(t, opts.StrictSanitize)
 	assert.Equal(t, original.StrictIgnorePaths, opts.StrictIgnorePaths)
 	assert.Equal(t, original.StrictIgnoredHeaders, opts.StrictIgnoredHeaders)
 	assert.True(t, opts.strictIgnoredHeadersMerge)
@@ -603,6 +764,12 @@ func TestWithStrictRejectWriteOnly(t *testing.T) {
 	assert.True(t, opts.StrictRejectWriteOnly)
 }
 
+func TestWithStrictSanitize(t *testing.T) {
+	opts := NewValidationOptions(WithStrictSanitize())
+	assert.True(t, opts.StrictMode)
+	assert.True(t, opts.StrictSanitize)
+}
+
 func TestStrictModeWithIgnorePaths(t *testing.T) {
 	paths := []string{"$.body.metadata.*"}
 	opts := NewValidationOptions(
@@ -644,3 +811,168 @@ func TestWithExistingOpts_PathTreeFields(t *testing.T) {
 	assert.True(t, opts.IsPathTreeDisabled())
 	assert.Nil(t, opts.PathTree)
 }
+
+func TestDisableValidationPlans(t *testing.T) {
+	assert.False(t, NewValidationOptions().IsValidationPlansDisabled())
+
+	original := NewValidationOptions(DisableValidationPlans())
+	original.Plans = plan.NewPlans()
+	assert.True(t, original.IsValidationPlansDisabled())
+
+	opts := NewValidationOptions(WithExistingOpts(original))
+	assert.True(t, opts.IsValidationPlansDisabled())
+	assert.Same(t, original.Plans, opts.Plans)
+
+	opts.Release()
+	assert.Nil(t, opts.Plans)
+}
+
+func TestWithConcurrency(t *testing.T) {
+	opts := NewValidationOptions()
+	assert.Equal(t, ConcurrencyAuto, opts.Concurrency)
+	assert.False(t, opts.ParallelBody(DefaultParallelBodyThreshold-1))
+	assert.True(t, opts.ParallelBody(DefaultParallelBodyThreshold))
+	assert.False(t, opts.ParallelBody(-1))
+
+	opts = NewValidationOptions(WithParallelBodyThreshold(10))
+	assert.True(t, opts.ParallelBody(10))
+	assert.False(t, opts.ParallelBody(9))
+	assert.Zero(t, NewValidationOptions(WithParallelBodyThreshold(-1)).ParallelBodyThreshold)
+
+	opts = NewValidationOptions(WithConcurrency(ConcurrencyInline), WithParallelBodyThreshold(10))
+	assert.False(t, opts.ParallelBody(1<<20))
+
+	opts = NewValidationOptions(WithConcurrency(ConcurrencyParallel))
+	assert.True(t, opts.ParallelBody(-1))
+
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	assert.Equal(t, ConcurrencyParallel, copied.Concurrency)
+
+	var none *ValidationOptions
+	assert.False(t, none.ParallelBody(1<<20))
+}
+
+type recordingObserver struct {
+	NopObserver
+	hits, misses []SchemaCacheEvent
+}
+
+func (r *recordingObserver) SchemaCacheHit(event SchemaCacheEvent) { r.hits = append(r.hits, event) }
+func (r *recordingObserver) SchemaCacheMiss(event SchemaCacheEvent) {
+	r.misses = append(r.misses, event)
+}
+
+func TestWithObserver(t *testing.T) {
+	observer := &recordingObserver{}
+	opts := NewValidationOptions(WithObserver(observer))
+	assert.Same(t, observer, opts.Observer)
+	assert.Same(t, observer, NewValidationOptions(WithExistingOpts(opts)).Observer)
+
+	opts.ObserveSchemaCache(1, "requestBody", true)
+	opts.ObserveSchemaCache(2, "parameter", false)
+	assert.Equal(t, []SchemaCacheEvent{{Key: 1, Location: "requestBody"}}, observer.hits)
+	assert.Equal(t, []SchemaCacheEvent{{Key: 2, Location: "parameter"}}, observer.misses)
+
+	opts.Release()
+	assert.Nil(t, opts.Observer)
+
+	// without an observer there is nothing to report to.
+	NewValidationOptions().ObserveSchemaCache(1, "schema", true)
+	var none *ValidationOptions
+	none.ObserveSchemaCache(1, "schema", true)
+}
+
+func TestWithSampling(t *testing.T) {
+	assert.Nil(t, NewValidationOptions().Sampling)
+
+	opts := NewValidationOptions(
+		WithSampling(0.25),
+		WithOperationSampling("listPets", 0.5),
+		WithOperationSampling("GET /pets/{id}", 2),
+		WithSamplingDebugHeader("X-Debug"),
+	)
+	require.NotNil(t, opts.Sampling)
+	assert.Equal(t, 0.25, opts.Sampling.Rate)
+	assert.Equal(t, map[string]float64{"listPets": 0.5, "GET /pets/{id}": 1}, opts.Sampling.Operations)
+	assert.Equal(t, "X-Debug", opts.Sampling.DebugHeader)
+	assert.Equal(t, DefaultSamplingRequestIDHeader, opts.Sampling.RequestIDHeader)
+
+	rate, ok := opts.Sampling.OperationRate(http.MethodGet, "/pets", "listPets")
+	assert.True(t, ok)
+	assert.Equal(t, 0.5, rate)
+	rate, ok = opts.Sampling.OperationRate(http.MethodGet, "/pets/{id}", "")
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, rate)
+	_, ok = opts.Sampling.OperationRate(http.MethodPost, "/pets", "createPet")
+	assert.False(t, ok)
+
+	// options built on top of others don't change their sampling.
+	copied := NewValidationOptions(WithExistingOpts(opts), WithSamplingRequestID("X-Trace"), WithOperationSampling("listPets", 0))
+	assert.Equal(t, "X-Trace", copied.Sampling.RequestIDHeader)
+	assert.Equal(t, DefaultSamplingRequestIDHeader, opts.Sampling.RequestIDHeader)
+	assert.Equal(t, 0.5, opts.Sampling.Operations["listPets"])
+
+	opts.Release()
+	assert.Nil(t, opts.Sampling)
+}
+
+func TestSampling_Sample(t *testing.T) {
+	s := NewValidationOptions(WithSampling(0.5), WithSamplingDebugHeader("X-Debug")).Sampling
+	request := func(id string) *http.Request {
+		r, _ := http.NewRequest(http.MethodGet, "https://api.com/pets", nil)
+		if id != "" {
+			r.Header.Set(DefaultSamplingRequestIDHeader, id)
+		}
+		return r
+	}
+
+	// the same request id is always sampled the same way, and about half of them are sampled.
+	sampled := 0
+	for i := range 1000 {
+		id := fmt.Sprintf("request-%d", i)
+		first := s.Sample(request(id), 0.5)
+		assert.Equal(t, first, s.Sample(request(id), 0.5))
+		if first {
+			sampled++
+		}
+	}
+	assert.InDelta(t, 500, sampled, 100)
+
+	assert.True(t, s.Sample(request("any"), 1))
+	assert.False(t, s.Sample(request("any"), 0))
+	assert.False(t, s.Sample(request(""), 0))
+
+	debug := request("any")
+	debug.Header.Set("X-Debug", "1")
+	assert.True(t, s.Sample(debug, 0))
+	assert.True(t, s.SampleHeader(debug.Header, 0))
+	assert.Equal(t, s.Sample(request("request-1"), 0.5), s.SampleHeader(request("request-1").Header, 0.5))
+
+	// without sampling settings the request id header is the default one.
+	var none *Sampling
+	assert.True(t, none.Sample(request(""), 1))
+	assert.False(t, none.Sample(request("any"), 0))
+}
+
+type countingCoverage struct {
+	requests, responses, unmatched int
+}
+
+func (c *countingCoverage) RecordRequest(message.Request, *v3.PathItem, string) { c.requests++ }
+
+func (c *countingCoverage) RecordResponse(message.Request, message.Response, *v3.PathItem, string) {
+	c.responses++
+}
+
+func (c *countingCoverage) RecordUnmatched(string, string, string) { c.unmatched++ }
+
+func TestWithCoverage(t *testing.T) {
+	recorder := &countingCoverage{}
+	opts := NewValidationOptions(WithCoverage(recorder))
+	assert.Same(t, recorder, opts.Coverage)
+	assert.Same(t, recorder, NewValidationOptions(WithExistingOpts(opts)).Coverage)
+
+	opts.Release()
+	assert.Nil(t, opts.Coverage)
+	assert.Nil(t, NewValidationOptions().Coverage)
+}
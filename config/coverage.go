@@ -0,0 +1,33 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+// CoverageRecorder records which parts of the specification validated traffic exercises. The coverage package
+// has a Collector that counts them and reports on them. Its methods are called on the goroutines that validate,
+// possibly concurrently, so they must be safe for concurrent use.
+type CoverageRecorder interface {
+	// RecordRequest is called for every request validated against an operation, once validation is done.
+	RecordRequest(request message.Request, pathItem *v3.PathItem, pathValue string)
+
+	// RecordResponse is called for every response validated against an operation, once validation is done.
+	RecordResponse(request message.Request, response message.Response, pathItem *v3.PathItem, pathValue string)
+
+	// RecordUnmatched is called for requests that match no path, when pathTemplate is empty, or that match a
+	// path without an operation for their method.
+	RecordUnmatched(method, requestPath, pathTemplate string)
+}
+
+// WithCoverage sets a CoverageRecorder that records the operations, parameters, responses, media types and
+// schema branches exercised by validated requests and responses.
+func WithCoverage(recorder CoverageRecorder) Option {
+	return func(o *ValidationOptions) {
+		o.Coverage = recorder
+	}
+}
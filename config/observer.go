@@ -0,0 +1,113 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package config
+
+import "time"
+
+// Observer receives events from validation, for metrics and tracing. Its methods are called on the goroutines
+// that validate, possibly concurrently, so they must be safe for concurrent use and should return quickly.
+//
+// Embed NopObserver to implement only the events of interest.
+type Observer interface {
+	// PathMatched is called when a request matches a path and operation of the specification.
+	PathMatched(event PathEvent)
+
+	// PathNotFound is called when a request matches no path, or a path without an operation for its method.
+	PathNotFound(event PathEvent)
+
+	// PhaseStarted is called before a phase of validation runs, such as 'queryParams' or 'requestBody'.
+	PhaseStarted(event PhaseEvent)
+
+	// PhaseFinished is called after a phase of validation has run, with its duration and outcome.
+	PhaseFinished(event PhaseEvent)
+
+	// SchemaCacheHit is called when validation finds a compiled schema in the schema cache.
+	SchemaCacheHit(event SchemaCacheEvent)
+
+	// SchemaCacheMiss is called when validation does not find a compiled schema in the schema cache, and has
+	// to compile it.
+	SchemaCacheMiss(event SchemaCacheEvent)
+
+	// SchemaCompiled is called after a schema has been compiled, while warming or on demand.
+	SchemaCompiled(event SchemaCompiledEvent)
+
+	// ErrorEmitted is called for every validation error a phase finds.
+	ErrorEmitted(event ErrorEvent)
+}
+
+// PathEvent describes the path lookup of a request.
+type PathEvent struct {
+	Method       string
+	RequestPath  string
+	PathTemplate string // the path of the specification that matched, empty when none did
+}
+
+// PhaseEvent describes a phase of validating a request or a response.
+type PhaseEvent struct {
+	Phase        string
+	Method       string
+	PathTemplate string
+	OperationID  string
+	Duration     time.Duration // set when the phase has finished
+	Valid        bool          // set when the phase has finished
+	Errors       int           // the number of errors the phase found, set when it has finished
+}
+
+// SchemaCacheEvent describes a lookup of the schema cache.
+type SchemaCacheEvent struct {
+	Key      uint64
+	Location string // what the schema validates: 'requestBody', 'responseBody', 'parameter' or 'schema'
+}
+
+// SchemaCompiledEvent describes the compilation of a schema.
+type SchemaCompiledEvent struct {
+	Location string // what the schema validates: 'requestBody', 'responseBody' or 'schema'
+	Duration time.Duration
+	Err      error // why the schema failed to compile
+}
+
+// ErrorEvent describes a validation error found by a phase. ValidationType and ValidationSubType are the code of
+// the error.
+type ErrorEvent struct {
+	Phase             string
+	Method            string
+	PathTemplate      string
+	OperationID       string
+	ValidationType    string
+	ValidationSubType string
+	Err               error // the *errors.ValidationError
+}
+
+// NopObserver is an Observer that ignores every event, to embed in observers that only handle some of them.
+type NopObserver struct{}
+
+func (NopObserver) PathMatched(PathEvent)              {}
+func (NopObserver) PathNotFound(PathEvent)             {}
+func (NopObserver) PhaseStarted(PhaseEvent)            {}
+func (NopObserver) PhaseFinished(PhaseEvent)           {}
+func (NopObserver) SchemaCacheHit(SchemaCacheEvent)    {}
+func (NopObserver) SchemaCacheMiss(SchemaCacheEvent)   {}
+func (NopObserver) SchemaCompiled(SchemaCompiledEvent) {}
+func (NopObserver) ErrorEmitted(ErrorEvent)            {}
+
+// WithObserver sets an Observer that receives events from validation, for metrics and tracing. The observe
+// package has observers that publish counters through expvar, and that log events with slog.
+func WithObserver(observer Observer) Option {
+	return func(o *ValidationOptions) {
+		o.Observer = observer
+	}
+}
+
+// ObserveSchemaCache reports a lookup of the schema cache to the observer, when there is one.
+func (o *ValidationOptions) ObserveSchemaCache(key uint64, location string, hit bool) {
+	if o == nil || o.Observer == nil {
+		return
+	}
+	event := SchemaCacheEvent{Key: key, Location: location}
+	if hit {
+		o.Observer.SchemaCacheHit(event)
+	} else {
+		o.Observer.SchemaCacheMiss(event)
+	}
+}
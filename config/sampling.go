@@ -0,0 +1,139 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"hash/fnv"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"net/http"
+)
+
+// DefaultSamplingRequestIDHeader is the header exchanges are sampled by, unless WithSamplingRequestID sets another.
+const DefaultSamplingRequestIDHeader = "X-Request-ID"
+
+// Sampling controls which exchanges are validated, so a share of production traffic can be validated to detect
+// drift without paying for validating all of it. Sampled out requests and responses are reported as valid.
+//
+// An exchange is sampled on the value of its request id header, so the request and the response of an exchange
+// are always sampled together. Without a request id, requests and responses are sampled independently, at random.
+type Sampling struct {
+	Rate            float64            // Share of exchanges validated, from 0 to 1
+	Operations      map[string]float64 // Rates of operations, by operationId or 'METHOD /path', overriding every other rate
+	DebugHeader     string             // Requests carrying this header are always validated (empty = none)
+	RequestIDHeader string             // Header holding the id exchanges are sampled by
+}
+
+// WithSampling only validates the given share of exchanges, from 0 (none) to 1 (all). Operations can set their
+// own rate with WithOperationSampling, or with 'sampleRate' in their 'x-validation' policy, which also overrides
+// this rate at the document level.
+func WithSampling(rate float64) Option {
+	return func(o *ValidationOptions) {
+		o.sampling().Rate = clampRate(rate)
+	}
+}
+
+// WithOperationSampling validates the given share of the exchanges of an operation, by operationId or as
+// 'METHOD /path' using the path template, for example 'GET /pets/{id}'. It overrides the rate of WithSampling,
+// and the 'sampleRate' of 'x-validation' policies.
+func WithOperationSampling(operation string, rate float64) Option {
+	return func(o *ValidationOptions) {
+		s := o.sampling()
+		if s.Operations == nil {
+			s.Operations = make(map[string]float64)
+		}
+		s.Operations[operation] = clampRate(rate)
+	}
+}
+
+// WithSamplingDebugHeader always validates requests carrying the given header, and their responses, whatever
+// their sampling rate.
+func WithSamplingDebugHeader(header string) Option {
+	return func(o *ValidationOptions) {
+		o.sampling().DebugHeader = header
+	}
+}
+
+// WithSamplingRequestID samples exchanges on the value of the given header, rather than on
+// DefaultSamplingRequestIDHeader.
+func WithSamplingRequestID(header string) Option {
+	return func(o *ValidationOptions) {
+		o.sampling().RequestIDHeader = header
+	}
+}
+
+// sampling returns the sampling settings to change, copying settings shared with other options first.
+func (o *ValidationOptions) sampling() *Sampling {
+	if o.Sampling == nil {
+		o.Sampling = &Sampling{Rate: 1, RequestIDHeader: DefaultSamplingRequestIDHeader}
+		return o.Sampling
+	}
+	copied := *o.Sampling
+	copied.Operations = maps.Clone(copied.Operations)
+	o.Sampling = &copied
+	return o.Sampling
+}
+
+// OperationRate returns the rate set for an operation with WithOperationSampling, by its operationId or its
+// method and path template.
+func (s *Sampling) OperationRate(method, pathTemplate, operationID string) (float64, bool) {
+	if s == nil || len(s.Operations) == 0 {
+		return 0, false
+	}
+	if operationID != "" {
+		if rate, ok := s.Operations[operationID]; ok {
+			return rate, true
+		}
+	}
+	rate, ok := s.Operations[method+" "+pathTemplate]
+	return rate, ok
+}
+
+// Sample returns true if an exchange of the request is validated at the given rate. Requests carrying the debug
+// header are always validated, and requests with a request id are sampled on a hash of it.
+func (s *Sampling) Sample(request *http.Request, rate float64) bool {
+	return s.SampleHeader(request.Header, rate)
+}
+
+// SampleHeader returns true if an exchange of a request with the given headers is validated at the given rate,
+// in the same way as Sample.
+func (s *Sampling) SampleHeader(header http.Header, rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	if s != nil && s.DebugHeader != "" && header.Get(s.DebugHeader) != "" {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+	idHeader := DefaultSamplingRequestIDHeader
+	if s != nil && s.RequestIDHeader != "" {
+		idHeader = s.RequestIDHeader
+	}
+	if id := header.Get(idHeader); id != "" {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(id))
+		return float64(mix(h.Sum64()))/math.MaxUint64 < rate
+	}
+	return rand.Float64() < rate
+}
+
+// mix spreads the bits of an FNV hash, whose high bits barely change between similar ids, such as counters.
+func mix(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+func clampRate(rate float64) float64 {
+	if math.IsNaN(rate) {
+		return 1
+	}
+	return min(max(rate, 0), 1)
+}
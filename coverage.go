@@ -0,0 +1,25 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+// recordRequest reports a validated request to the coverage recorder of the options, when there is one.
+func recordRequest(options *config.ValidationOptions, request message.Request, pathItem *v3.PathItem, pathValue string) {
+	if options != nil && options.Coverage != nil {
+		options.Coverage.RecordRequest(request, pathItem, pathValue)
+	}
+}
+
+// recordResponse reports a validated response to the coverage recorder of the options, when there is one.
+func recordResponse(options *config.ValidationOptions, request message.Request, response message.Response, pathItem *v3.PathItem, pathValue string) {
+	if options != nil && options.Coverage != nil {
+		options.Coverage.RecordResponse(request, response, pathItem, pathValue)
+	}
+}
@@ -0,0 +1,303 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
+	"github.com/pb33f/libopenapi-validator/schema_validation"
+)
+
+var _ config.CoverageRecorder = (*Collector)(nil)
+
+// MaxUndocumented is the number of distinct undocumented elements a Collector keeps. Once it is reached, further
+// ones are only counted, as Report.UndocumentedDropped, so that traffic to arbitrary paths can't grow it forever.
+const MaxUndocumented = 1000
+
+// Collector counts the elements of a specification that validated requests and responses exercise, and the
+// ones they use that the specification does not document. It's safe for concurrent use.
+type Collector struct {
+	document     *v3.Document
+	version      float32
+	schemas      schema_validation.SchemaValidator
+	documented   []Element
+	counts       sync.Map // pointer -> *atomic.Int64
+	undocumented sync.Map // Undocumented without its count -> *atomic.Int64
+	distinct     atomic.Int64
+	dropped      atomic.Int64
+}
+
+// NewCollector creates a Collector for a document. The options are used to check which oneOf and anyOf
+// alternatives a body matches, and can share the schema cache of the validator.
+func NewCollector(document *v3.Document, opts ...config.Option) *Collector {
+	c := &Collector{
+		document: document,
+		version:  helpers.VersionToFloat(document.Version),
+		schemas:  schema_validation.NewSchemaValidator(opts...),
+	}
+	c.documented = documentedElements(document)
+	return c
+}
+
+// Count returns how many times the element at pointer was seen.
+func (c *Collector) Count(pointer string) int64 {
+	if counter, ok := c.counts.Load(pointer); ok {
+		return counter.(*atomic.Int64).Load()
+	}
+	return 0
+}
+
+// Reset forgets everything that was seen.
+func (c *Collector) Reset() {
+	c.counts.Clear()
+	c.undocumented.Clear()
+	c.distinct.Store(0)
+	c.dropped.Store(0)
+}
+
+// RecordRequest counts the operation, parameters, request body media type and schema branches a request uses.
+func (c *Collector) RecordRequest(request message.Request, pathItem *v3.PathItem, pathValue string) {
+	if request == nil || pathItem == nil {
+		return
+	}
+	operation := helpers.OperationForMethod(request.Method(), pathItem)
+	if operation == nil {
+		c.RecordUnmatched(request.Method(), request.URL().Path, pathValue)
+		return
+	}
+	pointer := operationPointer(pathValue, request.Method())
+	c.hit(pointer)
+	c.recordParameters(request, pathItem, operation, pathValue, pointer)
+
+	contentType := request.Header().Get(helpers.ContentTypeHeader)
+	if contentType == "" {
+		return
+	}
+	if operation.RequestBody == nil {
+		c.seeUndocumented(Undocumented{Kind: KindMediaType, Method: request.Method(), Path: pathValue,
+			Description: fmt.Sprintf("request body %s, the operation has no request body", contentType)})
+		return
+	}
+	mediaType, name := findMediaType(operation.RequestBody.Content, contentType)
+	if mediaType == nil {
+		c.seeUndocumented(Undocumented{Kind: KindMediaType, Method: request.Method(), Path: pathValue,
+			Description: fmt.Sprintf("request body %s", contentType)})
+		return
+	}
+	mediaPointer := pointer + "/requestBody/content/" + escape(name)
+	c.hit(mediaPointer)
+	if mediaType.Schema != nil && isJSON(name, contentType) {
+		if value, ok := decode(message.ReadRequestBody(request)); ok {
+			c.walkSchema(mediaType.Schema, mediaPointer+"/schema", value, 0)
+		}
+	}
+}
+
+// RecordResponse counts the operation, response, response media type and schema branches a response uses.
+func (c *Collector) RecordResponse(request message.Request, response message.Response, pathItem *v3.PathItem, pathValue string) {
+	if request == nil || response == nil || pathItem == nil {
+		return
+	}
+	operation := helpers.OperationForMethod(request.Method(), pathItem)
+	if operation == nil {
+		c.RecordUnmatched(request.Method(), request.URL().Path, pathValue)
+		return
+	}
+	pointer := operationPointer(pathValue, request.Method())
+	c.hit(pointer)
+
+	var found *v3.Response
+	var code string
+	if operation.Responses != nil {
+		code = fmt.Sprint(response.StatusCode())
+		found = operation.Responses.Codes.GetOrZero(code)
+		if found == nil {
+			code = fmt.Sprintf("%dXX", response.StatusCode()/100)
+			found = operation.Responses.Codes.GetOrZero(code)
+		}
+		if found == nil && operation.Responses.Default != nil {
+			code, found = "default", operation.Responses.Default
+		}
+	}
+	if found == nil {
+		c.seeUndocumented(Undocumented{Kind: KindResponse, Method: request.Method(), Path: pathValue,
+			Description: fmt.Sprintf("response %d", response.StatusCode())})
+		return
+	}
+	responsePointer := pointer + "/responses/" + escape(code)
+	c.hit(responsePointer)
+
+	contentType := response.Header().Get(helpers.ContentTypeHeader)
+	if contentType == "" {
+		return
+	}
+	mediaType, name := findMediaType(found.Content, contentType)
+	if mediaType == nil {
+		c.seeUndocumented(Undocumented{Kind: KindMediaType, Method: request.Method(), Path: pathValue,
+			Description: fmt.Sprintf("response %d %s", response.StatusCode(), contentType)})
+		return
+	}
+	mediaPointer := responsePointer + "/content/" + escape(name)
+	c.hit(mediaPointer)
+	if mediaType.Schema != nil && isJSON(name, contentType) {
+		if value, ok := decode(message.ReadResponseBody(response)); ok {
+			c.walkSchema(mediaType.Schema, mediaPointer+"/schema", value, 0)
+		}
+	}
+}
+
+// RecordUnmatched counts a request to a path, or to an operation of a path, the specification does not document.
+func (c *Collector) RecordUnmatched(method, requestPath, pathTemplate string) {
+	if pathTemplate == "" {
+		c.seeUndocumented(Undocumented{Kind: KindPath, Method: method, Path: requestPath, Description: "path"})
+		return
+	}
+	c.seeUndocumented(Undocumented{Kind: KindOperation, Method: method, Path: pathTemplate, Description: "operation"})
+}
+
+// recordParameters counts the parameters present in the request, and the undocumented query parameters.
+// Operation parameters replace the path item parameters with the same name and location.
+func (c *Collector) recordParameters(request message.Request, pathItem *v3.PathItem, operation *v3.Operation, pathValue, pointer string) {
+	overridden := make(map[string]bool, len(operation.Parameters))
+	for _, param := range operation.Parameters {
+		if param != nil {
+			overridden[param.In+" "+param.Name] = true
+		}
+	}
+	query := request.URL().Query()
+	declared := make(map[string]bool)
+	record := func(params []*v3.Parameter, base string, shadowed bool) {
+		for i, param := range params {
+			if param == nil || (shadowed && overridden[param.In+" "+param.Name]) {
+				continue
+			}
+			if param.In == helpers.Query {
+				declared[param.Name] = true
+			}
+			value, present := parameterValue(request, query, param)
+			if !present {
+				continue
+			}
+			paramPointer := fmt.Sprintf("%s/parameters/%d", base, i)
+			c.hit(paramPointer)
+			if param.Schema != nil && value != "" {
+				schemaPointer := schemaPointer(param.Schema, paramPointer+"/schema")
+				if schema := param.Schema.Schema(); schema != nil {
+					if i := enumIndex(schema.Enum, value, true); i >= 0 {
+						c.hit(fmt.Sprintf("%s/enum/%d", schemaPointer, i))
+					}
+				}
+			}
+		}
+	}
+	record(pathItem.Parameters, pathPointer(pathValue), true)
+	record(operation.Parameters, pointer, false)
+
+	for name := range query {
+		base, _, _ := strings.Cut(name, "[")
+		if !declared[name] && !declared[base] {
+			c.seeUndocumented(Undocumented{Kind: KindParameter, Method: request.Method(), Path: pathValue,
+				Description: fmt.Sprintf("query parameter '%s'", name)})
+		}
+	}
+}
+
+// parameterValue returns the raw value of a parameter in the request, and whether the request has it. Path
+// parameters are always present, their value isn't extracted.
+func parameterValue(request message.Request, query map[string][]string, param *v3.Parameter) (string, bool) {
+	switch param.In {
+	case helpers.Path:
+		return "", true
+	case helpers.Query:
+		values, ok := query[param.Name]
+		if !ok || len(values) == 0 {
+			return "", ok
+		}
+		return values[0], true
+	case helpers.Header:
+		values := request.Header().Values(param.Name)
+		if len(values) == 0 {
+			return "", false
+		}
+		return values[0], true
+	case helpers.Cookie:
+		cookie, err := request.Cookie(param.Name)
+		if err != nil {
+			return "", false
+		}
+		return cookie.Value, true
+	}
+	return "", false
+}
+
+func (c *Collector) hit(pointer string) {
+	counter, ok := c.counts.Load(pointer)
+	if !ok {
+		counter, _ = c.counts.LoadOrStore(pointer, new(atomic.Int64))
+	}
+	counter.(*atomic.Int64).Add(1)
+}
+
+func (c *Collector) seeUndocumented(undocumented Undocumented) {
+	counter, ok := c.undocumented.Load(undocumented)
+	if !ok {
+		if c.distinct.Load() >= MaxUndocumented {
+			c.dropped.Add(1)
+			return
+		}
+		var loaded bool
+		counter, loaded = c.undocumented.LoadOrStore(undocumented, new(atomic.Int64))
+		if !loaded {
+			c.distinct.Add(1)
+		}
+	}
+	counter.(*atomic.Int64).Add(1)
+}
+
+// findMediaType finds the media type of content matching a content type, exactly or through a 'type/*' or
+// '*/*' range, and returns it with its name.
+func findMediaType(content *orderedmap.Map[string, *v3.MediaType], contentType string) (*v3.MediaType, string) {
+	if content == nil {
+		return nil, ""
+	}
+	name, _, _ := helpers.ExtractContentType(contentType)
+	major, _, _ := strings.Cut(name, "/")
+	for _, candidate := range []string{name, major + "/*", "*/*"} {
+		if mediaType := content.GetOrZero(candidate); mediaType != nil {
+			return mediaType, candidate
+		}
+	}
+	return nil, ""
+}
+
+func isJSON(names ...string) bool {
+	for _, name := range names {
+		if strings.Contains(strings.ToLower(name), "json") {
+			return true
+		}
+	}
+	return false
+}
+
+func decode(body []byte) (any, bool) {
+	if len(body) == 0 {
+		return nil, false
+	}
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
@@ -0,0 +1,228 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	validator "github.com/pb33f/libopenapi-validator"
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+const coverageSpec = `openapi: 3.1.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - name: status
+          in: query
+          schema:
+            type: string
+            enum: [available, sold]
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      operationId: createPet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        '201':
+          description: created
+        default:
+          description: error
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+    delete:
+      operationId: deletePet
+      responses:
+        '204':
+          description: deleted
+components:
+  schemas:
+    Pet:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+        - $ref: '#/components/schemas/Dog'
+    Cat:
+      type: object
+      required: [meows]
+      properties:
+        meows:
+          type: boolean
+    Dog:
+      type: object
+      required: [barks]
+      properties:
+        barks:
+          type: boolean
+        size:
+          type: string
+          enum: [small, large]`
+
+func newCollector(t *testing.T) (*Collector, validator.Validator) {
+	t.Helper()
+	doc, err := libopenapi.NewDocument([]byte(coverageSpec))
+	require.NoError(t, err)
+	model, err := doc.BuildV3Model()
+	require.NoError(t, err)
+	collector := NewCollector(&model.Model)
+	v := validator.NewValidatorFromV3Model(&model.Model, config.WithCoverage(collector))
+	return collector, v
+}
+
+func jsonRequest(method, url, body string) *http.Request {
+	request, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", "application/json")
+	return request
+}
+
+func jsonResponse(code int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+	}
+}
+
+func TestCollector_Request(t *testing.T) {
+	collector, v := newCollector(t)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://api.com/pets?status=sold&debug=true", nil)
+	v.ValidateHttpRequest(request)
+	assert.Equal(t, int64(1), collector.Count("#/paths/~1pets/get"))
+	assert.Equal(t, int64(1), collector.Count("#/paths/~1pets/get/parameters/0"))
+	assert.Equal(t, int64(1), collector.Count("#/paths/~1pets/get/parameters/0/schema/enum/1"))
+	assert.Zero(t, collector.Count("#/paths/~1pets/get/parameters/1"))
+
+	request = jsonRequest(http.MethodPost, "https://api.com/pets", `{"barks": true, "size": "large"}`)
+	valid, errs := v.ValidateHttpRequestSync(request)
+	require.True(t, valid, errs)
+	assert.Equal(t, int64(1), collector.Count("#/paths/~1pets/post/requestBody/content/application~1json"))
+	assert.Equal(t, int64(1), collector.Count("#/components/schemas/Pet/oneOf/1"))
+	assert.Zero(t, collector.Count("#/components/schemas/Pet/oneOf/0"))
+	assert.Equal(t, int64(1), collector.Count("#/components/schemas/Dog/properties/size/enum/1"))
+
+	// the body can still be read once it's recorded.
+	body, _ := io.ReadAll(request.Body)
+	assert.JSONEq(t, `{"barks": true, "size": "large"}`, string(body))
+
+	request, _ = http.NewRequest(http.MethodDelete, "https://api.com/pets/1", nil)
+	v.ValidateHttpRequest(request)
+	assert.Equal(t, int64(1), collector.Count("#/paths/~1pets~1{id}/parameters/0"))
+
+	request = jsonRequest(http.MethodPut, "https://api.com/pets", `{}`)
+	v.ValidateHttpRequest(request)
+	request = jsonRequest(http.MethodGet, "https://api.com/cats", `{}`)
+	v.ValidateHttpRequest(request)
+
+	report := collector.Report()
+	assert.ElementsMatch(t, []Undocumented{
+		{Kind: KindParameter, Method: http.MethodGet, Path: "/pets", Description: "query parameter 'debug'", Count: 1},
+		{Kind: KindOperation, Method: http.MethodPut, Path: "/pets", Description: "operation", Count: 1},
+		{Kind: KindPath, Method: http.MethodGet, Path: "/cats", Description: "path", Count: 1},
+	}, report.Undocumented)
+}
+
+func TestCollector_Response(t *testing.T) {
+	collector, v := newCollector(t)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://api.com/pets", nil)
+	valid, errs := v.ValidateHttpResponse(request, jsonResponse(http.StatusOK, `[{"meows": true}, {"barks": false}]`))
+	require.True(t, valid, errs)
+	assert.Equal(t, int64(1), collector.Count("#/paths/~1pets/get/responses/200"))
+	assert.Equal(t, int64(1), collector.Count("#/paths/~1pets/get/responses/200/content/application~1json"))
+	assert.Equal(t, int64(1), collector.Count("#/components/schemas/Pet/oneOf/0"))
+	assert.Equal(t, int64(1), collector.Count("#/components/schemas/Pet/oneOf/1"))
+
+	request, _ = http.NewRequest(http.MethodPost, "https://api.com/pets", nil)
+	v.ValidateHttpResponse(request, &http.Response{StatusCode: http.StatusBadRequest, Header: http.Header{}, Body: http.NoBody})
+	assert.Equal(t, int64(1), collector.Count("#/paths/~1pets/post/responses/default"))
+
+	request, _ = http.NewRequest(http.MethodGet, "https://api.com/pets", nil)
+	v.ValidateHttpResponse(request, &http.Response{StatusCode: http.StatusTeapot, Header: http.Header{}, Body: http.NoBody})
+	request, _ = http.NewRequest(http.MethodGet, "https://api.com/pets", nil)
+	v.ValidateHttpResponse(request, jsonResponse(http.StatusOK, `[]`))
+	v.ValidateHttpResponse(request, &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/csv"}},
+		Body:       io.NopCloser(bytes.NewBufferString("a,b")),
+	})
+
+	report := collector.Report()
+	assert.ElementsMatch(t, []Undocumented{
+		{Kind: KindResponse, Method: http.MethodGet, Path: "/pets", Description: "response 418", Count: 1},
+		{Kind: KindMediaType, Method: http.MethodGet, Path: "/pets", Description: "response 200 text/csv", Count: 1},
+	}, report.Undocumented)
+	assert.Equal(t, int64(3), collector.Count("#/paths/~1pets/get/responses/200"))
+
+	collector.Reset()
+	assert.Zero(t, collector.Count("#/paths/~1pets/get/responses/200"))
+	assert.Empty(t, collector.Report().Undocumented)
+}
+
+func TestCollector_Concurrent(t *testing.T) {
+	collector, v := newCollector(t)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			for range 25 {
+				request := jsonRequest(http.MethodPost, "https://api.com/pets", `{"meows": true}`)
+				v.ValidateHttpRequest(request)
+				request, _ = http.NewRequest(http.MethodGet, "https://api.com/dogs", nil)
+				v.ValidateHttpRequest(request)
+			}
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, int64(200), collector.Count("#/paths/~1pets/post"))
+	assert.Equal(t, int64(200), collector.Count("#/components/schemas/Pet/oneOf/0"))
+	report := collector.Report()
+	require.Len(t, report.Undocumented, 1)
+	assert.Equal(t, int64(200), report.Undocumented[0].Count)
+}
+
+func TestCollector_MaxUndocumented(t *testing.T) {
+	collector := NewCollector(&v3.Document{Version: "3.1.0"})
+	for i := range MaxUndocumented + 5 {
+		collector.RecordUnmatched(http.MethodGet, fmt.Sprintf("/unknown/%d", i), "")
+	}
+	report := collector.Report()
+	assert.Len(t, report.Undocumented, MaxUndocumented)
+	assert.Equal(t, int64(5), report.UndocumentedDropped)
+	assert.Empty(t, report.NeverSeen)
+}
@@ -0,0 +1,289 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"go.yaml.in/yaml/v4"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+// Kind is the kind of element of a specification that coverage is tracked for.
+type Kind string
+
+const (
+	KindPath      Kind = "path" // only reported as undocumented
+	KindOperation Kind = "operation"
+	KindParameter Kind = "parameter"
+	KindResponse  Kind = "response"
+	KindMediaType Kind = "mediaType" // of a request body or a response
+	KindBranch    Kind = "branch"    // a oneOf or anyOf alternative
+	KindEnum      Kind = "enum"      // an enum value
+)
+
+// maxSchemaDepth stops following the values of a body into its schema, for deeply nested or recursive bodies.
+const maxSchemaDepth = 64
+
+// documentedElements lists the elements of a document coverage is tracked for, in document order. Schemas
+// referenced from several places are listed once, under the pointer of the component they reference.
+func documentedElements(document *v3.Document) []Element {
+	if document == nil || document.Paths == nil {
+		return nil
+	}
+	var elements []Element
+	visited := make(map[string]bool)
+	add := func(element Element) {
+		elements = append(elements, element)
+	}
+	var schemaElements func(proxy *base.SchemaProxy, pointer, method, path string)
+	schemaElements = func(proxy *base.SchemaProxy, pointer, method, path string) {
+		if proxy == nil {
+			return
+		}
+		pointer = schemaPointer(proxy, pointer)
+		if strings.HasPrefix(pointer, "#/components/") {
+			// components are shared by operations, and listed apart from them.
+			method, path = "", ""
+		}
+		if visited[pointer] {
+			return
+		}
+		visited[pointer] = true
+		schema := proxy.Schema()
+		if schema == nil {
+			return
+		}
+		for i, node := range schema.Enum {
+			add(Element{Pointer: fmt.Sprintf("%s/enum/%d", pointer, i), Kind: KindEnum, Method: method, Path: path,
+				Description: fmt.Sprintf("enum value %s", enumString(node))})
+		}
+		for _, alternatives := range alternativesOf(schema) {
+			for i, branch := range alternatives.branches {
+				branchPointer := fmt.Sprintf("%s/%s/%d", pointer, alternatives.keyword, i)
+				add(Element{Pointer: branchPointer, Kind: KindBranch, Method: method, Path: path,
+					Description: fmt.Sprintf("%s alternative %d", alternatives.keyword, i)})
+				schemaElements(branch, branchPointer, method, path)
+			}
+		}
+		for i, branch := range schema.AllOf {
+			schemaElements(branch, fmt.Sprintf("%s/allOf/%d", pointer, i), method, path)
+		}
+		for pair := orderedmap.First(schema.Properties); pair != nil; pair = pair.Next() {
+			schemaElements(pair.Value(), pointer+"/properties/"+escape(pair.Key()), method, path)
+		}
+		if schema.Items != nil && schema.Items.IsA() {
+			schemaElements(schema.Items.A, pointer+"/items", method, path)
+		}
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.IsA() {
+			schemaElements(schema.AdditionalProperties.A, pointer+"/additionalProperties", method, path)
+		}
+	}
+	params := func(params []*v3.Parameter, base, method, path string) {
+		for i, param := range params {
+			if param == nil {
+				continue
+			}
+			pointer := fmt.Sprintf("%s/parameters/%d", base, i)
+			add(Element{Pointer: pointer, Kind: KindParameter, Method: method, Path: path,
+				Description: fmt.Sprintf("%s parameter '%s'", param.In, param.Name)})
+			schemaElements(param.Schema, pointer+"/schema", method, path)
+		}
+	}
+	content := func(content *orderedmap.Map[string, *v3.MediaType], base, description, method, path string) {
+		for pair := orderedmap.First(content); pair != nil; pair = pair.Next() {
+			pointer := base + "/content/" + escape(pair.Key())
+			add(Element{Pointer: pointer, Kind: KindMediaType, Method: method, Path: path,
+				Description: fmt.Sprintf("%s %s", description, pair.Key())})
+			if mediaType := pair.Value(); mediaType != nil {
+				schemaElements(mediaType.Schema, pointer+"/schema", method, path)
+			}
+		}
+	}
+
+	for pathPair := orderedmap.First(document.Paths.PathItems); pathPair != nil; pathPair = pathPair.Next() {
+		path, pathItem := pathPair.Key(), pathPair.Value()
+		if pathItem == nil {
+			continue
+		}
+		params(pathItem.Parameters, pathPointer(path), "", path)
+		for opPair := orderedmap.First(pathItem.GetOperations()); opPair != nil; opPair = opPair.Next() {
+			operation := opPair.Value()
+			if operation == nil {
+				continue
+			}
+			method := strings.ToUpper(opPair.Key())
+			pointer := operationPointer(path, method)
+			description := "operation"
+			if operation.OperationId != "" {
+				description = fmt.Sprintf("operation '%s'", operation.OperationId)
+			}
+			add(Element{Pointer: pointer, Kind: KindOperation, Method: method, Path: path, Description: description})
+			params(operation.Parameters, pointer, method, path)
+			if operation.RequestBody != nil {
+				content(operation.RequestBody.Content, pointer+"/requestBody", "request body", method, path)
+			}
+			if operation.Responses == nil {
+				continue
+			}
+			responses := func(code string, response *v3.Response) {
+				responsePointer := pointer + "/responses/" + escape(code)
+				add(Element{Pointer: responsePointer, Kind: KindResponse, Method: method, Path: path,
+					Description: fmt.Sprintf("response %s", code)})
+				if response != nil {
+					content(response.Content, responsePointer, fmt.Sprintf("response %s", code), method, path)
+				}
+			}
+			for codePair := orderedmap.First(operation.Responses.Codes); codePair != nil; codePair = codePair.Next() {
+				responses(codePair.Key(), codePair.Value())
+			}
+			if operation.Responses.Default != nil {
+				responses("default", operation.Responses.Default)
+			}
+		}
+	}
+	return elements
+}
+
+// walkSchema counts the enum values and oneOf and anyOf alternatives a value uses, following it into the
+// properties and items of its schema.
+func (c *Collector) walkSchema(proxy *base.SchemaProxy, pointer string, value any, depth int) {
+	if proxy == nil || depth > maxSchemaDepth {
+		return
+	}
+	pointer = schemaPointer(proxy, pointer)
+	schema := proxy.Schema()
+	if schema == nil {
+		return
+	}
+	if i := enumIndex(schema.Enum, value, false); i >= 0 {
+		c.hit(fmt.Sprintf("%s/enum/%d", pointer, i))
+	}
+	for _, alternatives := range alternativesOf(schema) {
+		for i, branch := range alternatives.branches {
+			if branchSchema := branch.Schema(); branchSchema != nil {
+				if valid, _ := c.schemas.ValidateSchemaObjectWithVersion(branchSchema, value, c.version); valid {
+					branchPointer := fmt.Sprintf("%s/%s/%d", pointer, alternatives.keyword, i)
+					c.hit(branchPointer)
+					c.walkSchema(branch, branchPointer, value, depth+1)
+				}
+			}
+		}
+	}
+	for i, branch := range schema.AllOf {
+		c.walkSchema(branch, fmt.Sprintf("%s/allOf/%d", pointer, i), value, depth+1)
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		for pair := orderedmap.First(schema.Properties); pair != nil; pair = pair.Next() {
+			if property, ok := v[pair.Key()]; ok {
+				c.walkSchema(pair.Value(), pointer+"/properties/"+escape(pair.Key()), property, depth+1)
+			}
+		}
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.IsA() {
+			for name, property := range v {
+				if schema.Properties == nil || schema.Properties.GetOrZero(name) == nil {
+					c.walkSchema(schema.AdditionalProperties.A, pointer+"/additionalProperties", property, depth+1)
+				}
+			}
+		}
+	case []any:
+		if schema.Items != nil && schema.Items.IsA() {
+			for _, item := range v {
+				c.walkSchema(schema.Items.A, pointer+"/items", item, depth+1)
+			}
+		}
+	}
+}
+
+// alternatives are the oneOf or anyOf branches of a schema.
+type alternatives struct {
+	keyword  string
+	branches []*base.SchemaProxy
+}
+
+func alternativesOf(schema *base.Schema) []alternatives {
+	return []alternatives{{"oneOf", schema.OneOf}, {"anyOf", schema.AnyOf}}
+}
+
+// enumIndex returns the index of the enum value equal to value, -1 when there is none. A raw value is the
+// string of a parameter, which is compared with the string form of the enum values.
+func enumIndex(enum []*yaml.Node, value any, raw bool) int {
+	for i, node := range enum {
+		decoded, ok := enumValue(node)
+		if !ok {
+			continue
+		}
+		if raw {
+			if s, ok := value.(string); ok && fmt.Sprint(decoded) == s {
+				return i
+			}
+			continue
+		}
+		if reflect.DeepEqual(decoded, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+// enumValue decodes an enum value in the same form as a value decoded from JSON.
+func enumValue(node *yaml.Node) (any, bool) {
+	if node == nil {
+		return nil, false
+	}
+	var decoded any
+	if err := node.Decode(&decoded); err != nil {
+		return nil, false
+	}
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, false
+	}
+	var value any
+	if err = json.Unmarshal(encoded, &value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+func enumString(node *yaml.Node) string {
+	value, ok := enumValue(node)
+	if !ok {
+		return "?"
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+// schemaPointer returns the pointer of a schema, which is the component it references when it's a local
+// reference, so a component is counted once wherever it's used.
+func schemaPointer(proxy *base.SchemaProxy, pointer string) string {
+	if proxy.IsReference() {
+		if reference := proxy.GetReference(); strings.HasPrefix(reference, "#/") {
+			return reference
+		}
+	}
+	return pointer
+}
+
+func pathPointer(path string) string {
+	return "#/paths/" + escape(path)
+}
+
+func operationPointer(path, method string) string {
+	return pathPointer(path) + "/" + strings.ToLower(method)
+}
+
+func escape(segment string) string {
+	return helpers.EscapeJSONPointerSegment(segment)
+}
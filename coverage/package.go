@@ -0,0 +1,8 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+// Package coverage tracks which parts of a specification validated traffic exercises. A Collector is passed to
+// config.WithCoverage, counts the operations, parameters, responses, media types, oneOf and anyOf alternatives
+// and enum values that requests and responses use, keyed by JSON pointer into the specification, and reports
+// the elements that were never seen, and the traffic the specification does not document, as JSON or HTML.
+package coverage
@@ -0,0 +1,182 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Element is an element of the specification, and how many times it was seen.
+type Element struct {
+	Pointer     string `json:"pointer"`
+	Kind        Kind   `json:"kind"`
+	Method      string `json:"method,omitempty"` // empty for path item parameters and components
+	Path        string `json:"path,omitempty"`   // the path template, empty for components
+	Description string `json:"description"`
+	Count       int64  `json:"count"`
+}
+
+// Undocumented is something seen in traffic that the specification does not document, and how many times.
+type Undocumented struct {
+	Kind        Kind   `json:"kind"`
+	Method      string `json:"method"`
+	Path        string `json:"path"` // the request path for undocumented paths, the path template otherwise
+	Description string `json:"description"`
+	Count       int64  `json:"count"`
+}
+
+// KindSummary is the coverage of the elements of a Kind.
+type KindSummary struct {
+	Documented int     `json:"documented"`
+	Seen       int     `json:"seen"`
+	Percent    float64 `json:"percent"`
+}
+
+// Report is the coverage of a specification by the traffic a Collector has seen.
+type Report struct {
+	Title     string                `json:"title,omitempty"`
+	Version   string                `json:"version,omitempty"`
+	Generated time.Time             `json:"generated"`
+	Summary   KindSummary           `json:"summary"`
+	Kinds     map[Kind]*KindSummary `json:"kinds"`
+
+	// Seen and NeverSeen are the documented elements, in document order.
+	Seen      []Element `json:"seen"`
+	NeverSeen []Element `json:"neverSeen"`
+
+	// Undocumented is what was seen but isn't documented, most seen first.
+	Undocumented []Undocumented `json:"undocumented"`
+
+	// UndocumentedDropped counts undocumented elements that weren't kept, once MaxUndocumented were.
+	UndocumentedDropped int64 `json:"undocumentedDropped,omitempty"`
+}
+
+// Report returns the coverage of the specification by the traffic seen so far.
+func (c *Collector) Report() *Report {
+	r := &Report{
+		Generated:           time.Now(),
+		Kinds:               make(map[Kind]*KindSummary),
+		Seen:                []Element{},
+		NeverSeen:           []Element{},
+		Undocumented:        []Undocumented{},
+		UndocumentedDropped: c.dropped.Load(),
+	}
+	if c.document != nil && c.document.Info != nil {
+		r.Title, r.Version = c.document.Info.Title, c.document.Info.Version
+	}
+	for _, element := range c.documented {
+		element.Count = c.Count(element.Pointer)
+		kind := r.Kinds[element.Kind]
+		if kind == nil {
+			kind = &KindSummary{}
+			r.Kinds[element.Kind] = kind
+		}
+		kind.Documented++
+		r.Summary.Documented++
+		if element.Count > 0 {
+			kind.Seen++
+			r.Summary.Seen++
+			r.Seen = append(r.Seen, element)
+		} else {
+			r.NeverSeen = append(r.NeverSeen, element)
+		}
+	}
+	r.Summary.Percent = percent(r.Summary.Seen, r.Summary.Documented)
+	for _, kind := range r.Kinds {
+		kind.Percent = percent(kind.Seen, kind.Documented)
+	}
+
+	c.undocumented.Range(func(key, value any) bool {
+		undocumented := key.(Undocumented)
+		undocumented.Count = value.(*atomic.Int64).Load()
+		r.Undocumented = append(r.Undocumented, undocumented)
+		return true
+	})
+	sort.Slice(r.Undocumented, func(i, j int) bool {
+		a, b := r.Undocumented[i], r.Undocumented[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		return a.Description < b.Description
+	})
+	return r
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+var reportTemplate = template.Must(template.New("coverage").Parse(reportHTML))
+
+// WriteHTML writes the report as a standalone HTML page.
+func (r *Report) WriteHTML(w io.Writer) error {
+	return reportTemplate.Execute(w, r)
+}
+
+func percent(seen, documented int) float64 {
+	if documented == 0 {
+		return 0
+	}
+	return float64(seen) * 100 / float64(documented)
+}
+
+const reportHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{if .Title}}{{.Title}} {{end}}coverage</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; }
+th { background: #f0f0f0; }
+td.count { text-align: right; }
+code { font-size: 0.9em; }
+.never { color: #a00; }
+.undocumented { color: #a60; }
+</style>
+</head>
+<body>
+<h1>{{if .Title}}{{.Title}} {{.Version}} {{end}}coverage</h1>
+<p>{{.Summary.Seen}} of {{.Summary.Documented}} documented elements seen ({{printf "%.1f" .Summary.Percent}}%), generated {{.Generated.Format "2006-01-02 15:04:05 MST"}}.</p>
+<table>
+<tr><th>Kind</th><th>Seen</th><th>Documented</th><th>Coverage</th></tr>
+{{range $kind, $summary := .Kinds}}<tr><td>{{$kind}}</td><td class="count">{{$summary.Seen}}</td><td class="count">{{$summary.Documented}}</td><td class="count">{{printf "%.1f" $summary.Percent}}%</td></tr>
+{{end}}</table>
+
+<h2 class="undocumented">Undocumented but seen ({{len .Undocumented}})</h2>
+{{if .UndocumentedDropped}}<p>{{.UndocumentedDropped}} more were seen, but not kept.</p>
+{{end}}<table>
+<tr><th>Kind</th><th>Method</th><th>Path</th><th>Description</th><th>Count</th></tr>
+{{range .Undocumented}}<tr><td>{{.Kind}}</td><td>{{.Method}}</td><td>{{.Path}}</td><td>{{.Description}}</td><td class="count">{{.Count}}</td></tr>
+{{end}}</table>
+
+<h2 class="never">Documented but never seen ({{len .NeverSeen}})</h2>
+<table>
+<tr><th>Kind</th><th>Method</th><th>Path</th><th>Description</th><th>Pointer</th></tr>
+{{range .NeverSeen}}<tr><td>{{.Kind}}</td><td>{{.Method}}</td><td>{{.Path}}</td><td>{{.Description}}</td><td><code>{{.Pointer}}</code></td></tr>
+{{end}}</table>
+
+<h2>Seen ({{len .Seen}})</h2>
+<table>
+<tr><th>Kind</th><th>Method</th><th>Path</th><th>Description</th><th>Pointer</th><th>Count</th></tr>
+{{range .Seen}}<tr><td>{{.Kind}}</td><td>{{.Method}}</td><td>{{.Path}}</td><td>{{.Description}}</td><td><code>{{.Pointer}}</code></td><td class="count">{{.Count}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`
@@ -0,0 +1,87 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+)
+
+func TestCollector_Report(t *testing.T) {
+	collector, v := newCollector(t)
+
+	report := collector.Report()
+	assert.Equal(t, "Pets", report.Title)
+	assert.Zero(t, report.Summary.Seen)
+	assert.Empty(t, report.Seen)
+
+	pointers := make(map[string]Kind)
+	for _, element := range report.NeverSeen {
+		pointers[element.Pointer] = element.Kind
+	}
+	assert.Equal(t, map[string]Kind{
+		"#/paths/~1pets/get":                                         KindOperation,
+		"#/paths/~1pets/get/parameters/0":                            KindParameter,
+		"#/paths/~1pets/get/parameters/0/schema/enum/0":              KindEnum,
+		"#/paths/~1pets/get/parameters/0/schema/enum/1":              KindEnum,
+		"#/paths/~1pets/get/parameters/1":                            KindParameter,
+		"#/paths/~1pets/get/responses/200":                           KindResponse,
+		"#/paths/~1pets/get/responses/200/content/application~1json": KindMediaType,
+		"#/components/schemas/Pet/oneOf/0":                           KindBranch,
+		"#/components/schemas/Pet/oneOf/1":                           KindBranch,
+		"#/components/schemas/Dog/properties/size/enum/0":            KindEnum,
+		"#/components/schemas/Dog/properties/size/enum/1":            KindEnum,
+		"#/paths/~1pets/post":                                        KindOperation,
+		"#/paths/~1pets/post/requestBody/content/application~1json":  KindMediaType,
+		"#/paths/~1pets/post/responses/201":                          KindResponse,
+		"#/paths/~1pets/post/responses/default":                      KindResponse,
+		"#/paths/~1pets~1{id}/parameters/0":                          KindParameter,
+		"#/paths/~1pets~1{id}/delete":                                KindOperation,
+		"#/paths/~1pets~1{id}/delete/responses/204":                  KindResponse,
+	}, pointers)
+	assert.Equal(t, len(pointers), report.Summary.Documented)
+
+	request := jsonRequest(http.MethodPost, "https://api.com/pets", `{"meows": true}`)
+	v.ValidateHttpRequest(request)
+	request, _ = http.NewRequest(http.MethodGet, "https://api.com/cats", nil)
+	v.ValidateHttpRequest(request)
+
+	report = collector.Report()
+	assert.Equal(t, 3, report.Summary.Seen)
+	assert.InDelta(t, 300.0/18, report.Summary.Percent, 0.01)
+	assert.Equal(t, &KindSummary{Documented: 3, Seen: 1, Percent: 100.0 / 3}, report.Kinds[KindOperation])
+	require.Len(t, report.Seen, 3)
+	assert.Contains(t, report.Seen, Element{
+		Pointer:     "#/paths/~1pets/post",
+		Kind:        KindOperation,
+		Method:      http.MethodPost,
+		Path:        "/pets",
+		Description: "operation 'createPet'",
+		Count:       1,
+	})
+	assert.Len(t, report.NeverSeen, 15)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJSON(&buf))
+	var decoded Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, report.Summary, decoded.Summary)
+	assert.Equal(t, report.Seen, decoded.Seen)
+	assert.Equal(t, report.Undocumented, decoded.Undocumented)
+
+	buf.Reset()
+	require.NoError(t, report.WriteHTML(&buf))
+	html := buf.String()
+	assert.Contains(t, html, "<h1>Pets 1.0.0 coverage</h1>")
+	assert.Contains(t, html, "3 of 18 documented elements seen (16.7%)")
+	assert.Contains(t, html, "Undocumented but seen (1)")
+	assert.Contains(t, html, "<td>/cats</td>")
+	assert.Contains(t, html, "Documented but never seen (15)")
+	assert.Contains(t, html, "operation &#39;createPet&#39;")
+}
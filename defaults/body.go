@@ -0,0 +1,174 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package defaults
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
+	"github.com/pb33f/libopenapi-validator/strict"
+)
+
+// maxDepth stops filling schemas that reference themselves without consuming any data, such as allOf cycles.
+const maxDepth = 100
+
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Body fills the missing properties of a JSON request body with their defaults, and re-serializes the body
+// when anything was injected. Bodies that aren't JSON, or can't be decoded, are left alone, as are the bodies of
+// messages that can't be replaced.
+func Body(request message.Request, operation *v3.Operation, options *config.ValidationOptions, version float32) []config.InjectedDefault {
+	if request == nil || operation == nil || operation.RequestBody == nil {
+		return nil
+	}
+	setter, ok := request.(message.BodySetter)
+	if !ok {
+		return nil
+	}
+	contentType := request.Header().Get(helpers.ContentTypeHeader)
+	if !strings.Contains(strings.ToLower(contentType), helpers.JSONType) {
+		return nil
+	}
+	mediaType := mediaTypeFor(contentType, operation.RequestBody)
+	if mediaType == nil || mediaType.Schema == nil {
+		return nil
+	}
+
+	body := message.ReadRequestBody(request)
+	if len(body) == 0 {
+		return nil
+	}
+
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil
+	}
+	filled, injected := Fill(mediaType.Schema.Schema(), data, "$.body", options, version)
+	if len(injected) == 0 {
+		return nil
+	}
+	encoded, err := json.Marshal(filled)
+	if err != nil {
+		return nil
+	}
+	setter.SetBody(encoded)
+	if header := request.Header(); header != nil && header.Get(helpers.ContentLengthHeader) != "" {
+		header.Set(helpers.ContentLengthHeader, strconv.Itoa(len(encoded)))
+	}
+	return injected
+}
+
+// Fill adds defaults for the missing properties of data, which must be decoded JSON. Paths of injected
+// values start at basePath. Objects in data are modified in place.
+func Fill(schema *base.Schema, data any, basePath string, options *config.ValidationOptions, version float32) (any, []config.InjectedDefault) {
+	f := &filler{variants: strict.NewValidator(options, version)}
+	return f.fill(schema, data, basePath, 0), f.injected
+}
+
+type filler struct {
+	variants *strict.Validator
+	injected []config.InjectedDefault
+}
+
+func (f *filler) fill(schema *base.Schema, data any, path string, depth int) any {
+	if schema == nil || data == nil || depth > maxDepth {
+		return data
+	}
+
+	switch val := data.(type) {
+	case map[string]any:
+		if schema.Properties != nil {
+			for pair := schema.Properties.First(); pair != nil; pair = pair.Next() {
+				name, proxy := pair.Key(), pair.Value()
+				if proxy == nil {
+					continue
+				}
+				propSchema := proxy.Schema()
+				if propSchema == nil {
+					continue
+				}
+				propPath := propertyPath(path, name)
+				if existing, ok := val[name]; ok {
+					val[name] = f.fill(propSchema, existing, propPath, depth+1)
+					continue
+				}
+				// readOnly properties are never sent by clients.
+				if propSchema.ReadOnly != nil && *propSchema.ReadOnly {
+					continue
+				}
+				if value, ok := decodeDefault(propSchema.Default); ok {
+					val[name] = value
+					f.injected = append(f.injected, config.InjectedDefault{In: InBody, Name: name, Path: propPath, Value: value})
+				}
+			}
+		}
+		for _, proxy := range schema.AllOf {
+			if proxy != nil {
+				f.fill(proxy.Schema(), val, path, depth+1)
+			}
+		}
+		for _, variants := range [][]*base.SchemaProxy{schema.OneOf, schema.AnyOf} {
+			if len(variants) == 0 {
+				continue
+			}
+			// select the variant before anything is injected, so defaults can't change the selection.
+			if variant := f.variants.SelectVariant(schema, variants, val); variant != nil {
+				f.fill(variant, val, path, depth+1)
+			}
+		}
+		return val
+
+	case []any:
+		if schema.Items == nil || !schema.Items.IsA() || schema.Items.A == nil {
+			return val
+		}
+		items := schema.Items.A.Schema()
+		for i, item := range val {
+			val[i] = f.fill(items, item, path+"["+strconv.Itoa(i)+"]", depth+1)
+		}
+		return val
+	}
+	return data
+}
+
+// propertyPath appends a property to a JSONPath, using bracket notation for names that need it.
+func propertyPath(path, name string) string {
+	if identifierRegex.MatchString(name) {
+		return path + "." + name
+	}
+	return fmt.Sprintf("%s['%s']", path, strings.ReplaceAll(name, "'", "\\'"))
+}
+
+// mediaTypeFor finds the media type of a request body for a content type, including media ranges.
+func mediaTypeFor(contentType string, requestBody *v3.RequestBody) *v3.MediaType {
+	if requestBody.Content == nil {
+		return nil
+	}
+	ct, _, _ := helpers.ExtractContentType(contentType)
+	if mediaType, ok := requestBody.Content.Get(ct); ok {
+		return mediaType
+	}
+	ctMediaRange := strings.SplitN(ct, "/", 2)
+	if len(ctMediaRange) != 2 {
+		return nil
+	}
+	for pair := requestBody.Content.First(); pair != nil; pair = pair.Next() {
+		opMediaRange := strings.SplitN(pair.Key(), "/", 2)
+		if len(opMediaRange) == 2 &&
+			(opMediaRange[0] == "*" || opMediaRange[0] == ctMediaRange[0]) &&
+			(opMediaRange[1] == "*" || opMediaRange[1] == ctMediaRange[1]) {
+			return pair.Value()
+		}
+	}
+	return nil
+}
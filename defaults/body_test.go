@@ -0,0 +1,188 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package defaults
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+const bodySpec = `openapi: 3.1.0
+info:
+  title: Defaults
+  version: 1.0.0
+paths:
+  /orders:
+    post:
+      requestBody:
+        content:
+          application/*:
+            schema:
+              $ref: '#/components/schemas/Order'
+      responses:
+        '200':
+          description: OK
+components:
+  schemas:
+    Order:
+      allOf:
+        - $ref: '#/components/schemas/Audit'
+        - type: object
+          required: [payment]
+          properties:
+            id:
+              type: string
+              readOnly: true
+              default: generated
+            currency:
+              type: string
+              default: EUR
+            weird key:
+              type: boolean
+              default: false
+            lines:
+              type: array
+              items:
+                type: object
+                properties:
+                  quantity:
+                    type: integer
+                    default: 1
+            payment:
+              oneOf:
+                - $ref: '#/components/schemas/Card'
+                - $ref: '#/components/schemas/Transfer'
+    Audit:
+      type: object
+      properties:
+        source:
+          type: string
+          default: api
+    Card:
+      type: object
+      required: [card]
+      properties:
+        card:
+          type: string
+        capture:
+          type: boolean
+          default: true
+    Transfer:
+      type: object
+      required: [iban]
+      properties:
+        iban:
+          type: string
+        instant:
+          type: boolean
+          default: false
+`
+
+func TestFill(t *testing.T) {
+	model := buildModel(t, bodySpec)
+	schema := model.Components.Schemas.GetOrZero("Order").Schema()
+
+	data := map[string]any{
+		"lines":   []any{map[string]any{}, map[string]any{"quantity": 5.0}},
+		"payment": map[string]any{"iban": "GB82"},
+	}
+	filled, injected := Fill(schema, data, "$.body", nil, 3.1)
+
+	assert.Equal(t, map[string]any{
+		"source":    "api",
+		"currency":  "EUR",
+		"weird key": false,
+		"lines":     []any{map[string]any{"quantity": 1}, map[string]any{"quantity": 5.0}},
+		"payment":   map[string]any{"iban": "GB82", "instant": false},
+	}, filled)
+
+	var paths []string
+	for _, value := range injected {
+		assert.Equal(t, InBody, value.In)
+		paths = append(paths, value.Path)
+	}
+	assert.ElementsMatch(t, []string{
+		"$.body.source",
+		"$.body.currency",
+		"$.body['weird key']",
+		"$.body.lines[0].quantity",
+		"$.body.payment.instant",
+	}, paths)
+}
+
+func TestFill_NoData(t *testing.T) {
+	model := buildModel(t, bodySpec)
+	schema := model.Components.Schemas.GetOrZero("Order").Schema()
+
+	filled, injected := Fill(schema, nil, "$.body", nil, 3.1)
+	assert.Nil(t, filled)
+	assert.Empty(t, injected)
+
+	filled, injected = Fill(schema, "scalar", "$.body", nil, 3.1)
+	assert.Equal(t, "scalar", filled)
+	assert.Empty(t, injected)
+
+	filled, _ = Fill(nil, map[string]any{}, "$.body", nil, 3.1)
+	assert.Equal(t, map[string]any{}, filled)
+}
+
+func TestBody(t *testing.T) {
+	model := buildModel(t, bodySpec)
+	operation := model.Paths.PathItems.GetOrZero("/orders").Post
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/orders",
+		bytes.NewBufferString(`{"payment":{"card":"4111"},"currency":"GBP"}`))
+	request.Header.Set("Content-Type", "application/json; charset=utf-8")
+	request.Header.Set("Content-Length", "44")
+
+	injected := Body(message.FromHTTPRequest(request), operation, config.NewValidationOptions(), 3.1)
+	require.Len(t, injected, 3)
+
+	body, _ := io.ReadAll(request.Body)
+	assert.JSONEq(t, `{"payment":{"card":"4111","capture":true},"currency":"GBP","source":"api","weird key":false}`, string(body))
+	assert.Equal(t, int64(len(body)), request.ContentLength)
+	assert.Equal(t, strconv.Itoa(len(body)), request.Header.Get("Content-Length"))
+
+	again, _ := request.GetBody()
+	againBody, _ := io.ReadAll(again)
+	assert.Equal(t, body, againBody)
+}
+
+func TestBody_LeftAlone(t *testing.T) {
+	model := buildModel(t, bodySpec)
+	operation := model.Paths.PathItems.GetOrZero("/orders").Post
+
+	for name, tc := range map[string]struct {
+		contentType string
+		body        string
+	}{
+		"not json":    {contentType: "application/xml", body: `<order/>`},
+		"invalid":     {contentType: "application/json", body: `{"payment":`},
+		"empty":       {contentType: "application/json", body: ``},
+		"no defaults": {contentType: "application/json", body: `[1, 2]`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodPost, "https://things.com/orders", bytes.NewBufferString(tc.body))
+			request.Header.Set("Content-Type", tc.contentType)
+
+			assert.Empty(t, Body(message.FromHTTPRequest(request), operation, nil, 3.1))
+			body, _ := io.ReadAll(request.Body)
+			assert.Equal(t, tc.body, string(body))
+		})
+	}
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/orders", nil)
+	request.Header.Set("Content-Type", "application/json")
+	assert.Nil(t, Body(message.FromHTTPRequest(request), operation, nil, 3.1))
+	assert.Nil(t, Body(nil, operation, nil, 3.1))
+}
@@ -0,0 +1,83 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+// Package defaults materializes schema 'default' values into requests, so handlers see the same
+// values the specification documents, rather than re-implementing them.
+//
+// Defaults are injected for:
+//   - Missing query parameters and headers, serialized using the style of the parameter.
+//   - Missing properties of JSON request bodies, recursively, including allOf schemas and the matching
+//     oneOf or anyOf variant. The body is re-serialized when anything was injected.
+//
+// Defaults are only injected once a request passes validation, they are not validated themselves.
+package defaults
+
+import (
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"go.yaml.in/yaml/v4"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+const (
+	// InQuery marks defaults injected into the query string.
+	InQuery = "query"
+
+	// InHeader marks defaults injected into the request headers.
+	InHeader = "header"
+
+	// InBody marks defaults injected into the request body.
+	InBody = "body"
+)
+
+// Inject adds the defaults of the operation a request is for to the request, and returns what was injected.
+func Inject(request message.Request, pathItem *v3.PathItem, options *config.ValidationOptions, version float32) []config.InjectedDefault {
+	if request == nil || pathItem == nil {
+		return nil
+	}
+	operation := helpers.OperationForMethod(request.Method(), pathItem)
+	if operation == nil {
+		return nil
+	}
+
+	injected := Parameters(request, helpers.ParamsForOperation(request.Method(), pathItem, options))
+	return append(injected, Body(request, operation, options, version)...)
+}
+
+// decodeDefault decodes a schema default into the values produced by decoding JSON.
+func decodeDefault(node *yaml.Node) (any, bool) {
+	if node == nil {
+		return nil, false
+	}
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil, false
+	}
+	return normalize(value), true
+}
+
+// normalize converts maps with non-string keys decoded from YAML, so values can be marshalled to JSON.
+func normalize(value any) any {
+	switch val := value.(type) {
+	case map[string]any:
+		for k, item := range val {
+			val[k] = normalize(item)
+		}
+		return val
+	case map[any]any:
+		converted := make(map[string]any, len(val))
+		for k, item := range val {
+			converted[toString(k)] = normalize(item)
+		}
+		return converted
+	case []any:
+		for i, item := range val {
+			val[i] = normalize(item)
+		}
+		return val
+	default:
+		return val
+	}
+}
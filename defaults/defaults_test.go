@@ -0,0 +1,124 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package defaults
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+	"go.yaml.in/yaml/v4"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+func buildModel(t *testing.T, spec string) *v3.Document {
+	t.Helper()
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	model, err := doc.BuildV3Model()
+	require.NoError(t, err)
+	return &model.Model
+}
+
+func TestInject(t *testing.T) {
+	model := buildModel(t, `openapi: 3.1.0
+info:
+  title: Defaults
+  version: 1.0.0
+paths:
+  /pets:
+    parameters:
+      - name: limit
+        in: query
+        schema:
+          type: integer
+          default: 20
+    post:
+      parameters:
+        - name: X-Region
+          in: header
+          schema:
+            type: string
+            default: eu
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                status:
+                  type: string
+                  default: available
+      responses:
+        '200':
+          description: OK
+`)
+	pathItem := model.Paths.PathItems.GetOrZero("/pets")
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/pets", bytes.NewBufferString(`{"name":"fido"}`))
+	request.Header.Set("Content-Type", "application/json")
+
+	injected := Inject(message.FromHTTPRequest(request), pathItem, config.NewValidationOptions(), 3.1)
+	assert.Equal(t, []config.InjectedDefault{
+		{In: InQuery, Name: "limit", Value: 20},
+		{In: InHeader, Name: "X-Region", Value: "eu"},
+		{In: InBody, Name: "status", Path: "$.body.status", Value: "available"},
+	}, injected)
+
+	assert.Equal(t, "limit=20", request.URL.RawQuery)
+	assert.Equal(t, "eu", request.Header.Get("X-Region"))
+	body, _ := io.ReadAll(request.Body)
+	assert.JSONEq(t, `{"name":"fido","status":"available"}`, string(body))
+	assert.Equal(t, int64(len(body)), request.ContentLength)
+
+	// nothing is missing the second time around
+	request.Body = io.NopCloser(bytes.NewReader(body))
+	assert.Empty(t, Inject(message.FromHTTPRequest(request), pathItem, config.NewValidationOptions(), 3.1))
+
+	// messages that don't come from net/http are filled in directly
+	event, err := message.NewAPIGatewayRequest(&message.APIGatewayProxyRequest{
+		Path:       "/pets",
+		HTTPMethod: http.MethodPost,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       `{"name":"fido"}`,
+	})
+	require.NoError(t, err)
+	assert.Len(t, Inject(event, pathItem, config.NewValidationOptions(), 3.1), 3)
+	assert.Equal(t, "limit=20", event.URL().RawQuery)
+	assert.Equal(t, "eu", event.Header().Get("X-Region"))
+	body, _ = io.ReadAll(event.Body())
+	assert.JSONEq(t, `{"name":"fido","status":"available"}`, string(body))
+
+	// unknown operations and missing inputs are ignored
+	request, _ = http.NewRequest(http.MethodDelete, "https://things.com/pets", nil)
+	assert.Nil(t, Inject(message.FromHTTPRequest(request), pathItem, nil, 3.1))
+	assert.Nil(t, Inject(nil, pathItem, nil, 3.1))
+	assert.Nil(t, Inject(message.FromHTTPRequest(request), nil, nil, 3.1))
+}
+
+func TestDecodeDefault(t *testing.T) {
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`{a: 1, b: [x, {c: true}], 1: one}`), &node))
+
+	value, ok := decodeDefault(node.Content[0])
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{
+		"a": 1,
+		"b": []any{"x", map[string]any{"c": true}},
+		"1": "one",
+	}, value)
+
+	_, ok = decodeDefault(nil)
+	assert.False(t, ok)
+}
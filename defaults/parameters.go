@@ -0,0 +1,195 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package defaults
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+// Parameters adds the defaults of missing query and header parameters to the request.
+// Path parameters are always present, and cookies are left alone.
+func Parameters(request message.Request, params []*v3.Parameter) []config.InjectedDefault {
+	if request == nil || request.URL() == nil {
+		return nil
+	}
+
+	var injected []config.InjectedDefault
+	query := request.URL().Query()
+	header := request.Header()
+	queryChanged := false
+	for _, param := range params {
+		if param == nil || param.Schema == nil {
+			continue
+		}
+		schema := param.Schema.Schema()
+		if schema == nil {
+			continue
+		}
+		value, ok := decodeDefault(schema.Default)
+		if !ok {
+			continue
+		}
+
+		switch strings.ToLower(param.In) {
+		case helpers.Query:
+			if queryParamPresent(query, param.Name) {
+				continue
+			}
+			for key, values := range serializeQuery(param, value) {
+				for _, v := range values {
+					query.Add(key, v)
+				}
+			}
+			queryChanged = true
+			injected = append(injected, config.InjectedDefault{In: InQuery, Name: param.Name, Value: value})
+
+		case helpers.Header:
+			if header == nil {
+				header = newHeader(request)
+			}
+			if header == nil || len(header.Values(param.Name)) > 0 {
+				continue
+			}
+			header.Set(param.Name, serializeHeader(param, value))
+			injected = append(injected, config.InjectedDefault{In: InHeader, Name: param.Name, Value: value})
+		}
+	}
+
+	if queryChanged {
+		request.URL().RawQuery = query.Encode()
+	}
+	return injected
+}
+
+// newHeader gives a *http.Request without headers an empty set, so defaults can be added to it. Other messages
+// without headers are left alone, and nil is returned.
+func newHeader(request message.Request) http.Header {
+	provider, ok := request.(message.HTTPRequestProvider)
+	if !ok {
+		return nil
+	}
+	provider.HTTPRequest().Header = make(http.Header)
+	return provider.HTTPRequest().Header
+}
+
+// queryParamPresent checks for a query parameter, including deepObject encoded keys such as 'name[key]'.
+func queryParamPresent(query map[string][]string, name string) bool {
+	if _, ok := query[name]; ok {
+		return true
+	}
+	for key := range query {
+		if strings.HasPrefix(key, name+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// serializeQuery serializes a default as query parameters, using the style and explode of the parameter.
+func serializeQuery(param *v3.Parameter, value any) map[string][]string {
+	style := param.Style
+	if style == "" {
+		style = helpers.Form
+	}
+	explode := style == helpers.Form
+	if param.Explode != nil {
+		explode = *param.Explode
+	}
+
+	delimiter := ","
+	switch style {
+	case helpers.SpaceDelimited:
+		delimiter = " "
+	case helpers.PipeDelimited:
+		delimiter = "|"
+	}
+
+	switch val := value.(type) {
+	case []any:
+		items := make([]string, len(val))
+		for i, item := range val {
+			items[i] = toString(item)
+		}
+		if explode {
+			return map[string][]string{param.Name: items}
+		}
+		return map[string][]string{param.Name: {strings.Join(items, delimiter)}}
+
+	case map[string]any:
+		keys := sortedKeys(val)
+		if style == helpers.DeepObject {
+			values := make(map[string][]string, len(keys))
+			for _, key := range keys {
+				values[fmt.Sprintf("%s[%s]", param.Name, key)] = []string{toString(val[key])}
+			}
+			return values
+		}
+		if explode {
+			values := make(map[string][]string, len(keys))
+			for _, key := range keys {
+				values[key] = []string{toString(val[key])}
+			}
+			return values
+		}
+		pairs := make([]string, 0, len(keys)*2)
+		for _, key := range keys {
+			pairs = append(pairs, key, toString(val[key]))
+		}
+		return map[string][]string{param.Name: {strings.Join(pairs, delimiter)}}
+
+	default:
+		return map[string][]string{param.Name: {toString(val)}}
+	}
+}
+
+// serializeHeader serializes a default as a header value, using the simple style.
+func serializeHeader(param *v3.Parameter, value any) string {
+	switch val := value.(type) {
+	case []any:
+		items := make([]string, len(val))
+		for i, item := range val {
+			items[i] = toString(item)
+		}
+		return strings.Join(items, ",")
+
+	case map[string]any:
+		var pairs []string
+		for _, key := range sortedKeys(val) {
+			if param.IsExploded() {
+				pairs = append(pairs, key+"="+toString(val[key]))
+			} else {
+				pairs = append(pairs, key, toString(val[key]))
+			}
+		}
+		return strings.Join(pairs, ",")
+
+	default:
+		return toString(val)
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func toString(value any) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,142 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package defaults
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+const parametersSpec = `openapi: 3.1.0
+info:
+  title: Defaults
+  version: 1.0.0
+paths:
+  /search:
+    get:
+      parameters:
+        - name: tags
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+            default: [a, b]
+        - name: ids
+          in: query
+          explode: false
+          schema:
+            type: array
+            items:
+              type: integer
+            default: [1, 2]
+        - name: pipes
+          in: query
+          style: pipeDelimited
+          explode: false
+          schema:
+            type: array
+            default: [x, y]
+        - name: filter
+          in: query
+          style: deepObject
+          schema:
+            type: object
+            default: {color: red, size: 2}
+        - name: point
+          in: query
+          schema:
+            type: object
+            default: {x: 1, y: 2}
+        - name: flat
+          in: query
+          explode: false
+          schema:
+            type: object
+            default: {k: v}
+        - name: X-Tags
+          in: header
+          schema:
+            type: array
+            default: [one, two]
+        - name: X-Object
+          in: header
+          explode: true
+          schema:
+            type: object
+            default: {a: 1, b: 2}
+        - name: X-Plain
+          in: header
+          schema:
+            type: object
+            default: {a: 1}
+        - name: X-None
+          in: header
+          schema:
+            type: string
+        - name: session
+          in: cookie
+          schema:
+            type: string
+            default: abc
+        - name: Content
+          in: query
+          content:
+            application/json:
+              schema:
+                type: string
+                default: ignored
+      responses:
+        '200':
+          description: OK
+`
+
+func TestParameters(t *testing.T) {
+	model := buildModel(t, parametersSpec)
+	params := model.Paths.PathItems.GetOrZero("/search").Get.Parameters
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/search", nil)
+	injected := Parameters(message.FromHTTPRequest(request), params)
+	require.Len(t, injected, 9)
+
+	query := request.URL.Query()
+	assert.Equal(t, []string{"a", "b"}, query["tags"])
+	assert.Equal(t, []string{"1,2"}, query["ids"])
+	assert.Equal(t, []string{"x|y"}, query["pipes"])
+	assert.Equal(t, []string{"red"}, query["filter[color]"])
+	assert.Equal(t, []string{"2"}, query["filter[size]"])
+	assert.Equal(t, []string{"1"}, query["x"])
+	assert.Equal(t, []string{"2"}, query["y"])
+	assert.Equal(t, []string{"k,v"}, query["flat"])
+	assert.NotContains(t, query, "Content")
+
+	assert.Equal(t, "one,two", request.Header.Get("X-Tags"))
+	assert.Equal(t, "a=1,b=2", request.Header.Get("X-Object"))
+	assert.Equal(t, "a,1", request.Header.Get("X-Plain"))
+	assert.Empty(t, request.Header.Get("X-None"))
+	assert.Empty(t, request.Cookies())
+}
+
+func TestParameters_Present(t *testing.T) {
+	model := buildModel(t, parametersSpec)
+	params := model.Paths.PathItems.GetOrZero("/search").Get.Parameters
+
+	request, _ := http.NewRequest(http.MethodGet,
+		"https://things.com/search?tags=z&ids=3&pipes=q&filter[color]=blue&point=1&flat=a,b", nil)
+	request.Header.Set("X-Tags", "three")
+	request.Header.Set("X-Object", "a=9")
+	request.Header.Set("X-Plain", "a,9")
+	raw := request.URL.RawQuery
+
+	assert.Empty(t, Parameters(message.FromHTTPRequest(request), params))
+	assert.Equal(t, raw, request.URL.RawQuery)
+	assert.Equal(t, "three", request.Header.Get("X-Tags"))
+
+	assert.Nil(t, Parameters(nil, params))
+}
@@ -0,0 +1,143 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package deprecation
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
+	"github.com/pb33f/libopenapi-validator/strict"
+)
+
+// maxDepth stops walking schemas that reference themselves without consuming any data, such as allOf cycles.
+const maxDepth = 100
+
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+func requestBody(request message.Request, contentType string, schema *base.Schema, options *config.ValidationOptions, version float32) []*errors.ValidationError {
+	if !isJSON(contentType) {
+		return nil
+	}
+	return Body(request, schema, message.ReadRequestBody(request), "$.body", options, version)
+}
+
+func responseBody(request message.Request, response message.Response, contentType string, schema *base.Schema, options *config.ValidationOptions, version float32) []*errors.ValidationError {
+	if !isJSON(contentType) {
+		return nil
+	}
+	return Body(request, schema, message.ReadResponseBody(response), "$.body", options, version)
+}
+
+// Body returns the properties of a JSON body that have a deprecated schema. Paths of properties start at
+// basePath. Bodies that can't be decoded have no deprecations.
+func Body(request message.Request, schema *base.Schema, body []byte, basePath string, options *config.ValidationOptions, version float32) []*errors.ValidationError {
+	if schema == nil || len(body) == 0 {
+		return nil
+	}
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil
+	}
+	w := &walker{request: request, variants: strict.NewValidator(options, version), seen: make(map[string]bool)}
+	w.walk(schema, data, basePath, 0)
+	return w.found
+}
+
+type walker struct {
+	request  message.Request
+	variants *strict.Validator
+	seen     map[string]bool
+	found    []*errors.ValidationError
+}
+
+func (w *walker) walk(schema *base.Schema, data any, path string, depth int) {
+	if schema == nil || data == nil || depth > maxDepth {
+		return
+	}
+	// the same value can be reached through allOf and variants, it is only reported once.
+	if schema.Deprecated != nil && *schema.Deprecated && !w.seen[path] {
+		w.seen[path] = true
+		w.found = append(w.found, errors.DeprecatedPropertyUsed(path, schema, w.request))
+	}
+
+	switch val := data.(type) {
+	case map[string]any:
+		if schema.Properties != nil {
+			for name, proxy := range schema.Properties.FromOldest() {
+				if existing, ok := val[name]; ok && proxy != nil {
+					w.walk(proxy.Schema(), existing, propertyPath(path, name), depth+1)
+				}
+			}
+		}
+		for _, proxy := range schema.AllOf {
+			if proxy != nil {
+				w.walk(proxy.Schema(), val, path, depth+1)
+			}
+		}
+		for _, variants := range [][]*base.SchemaProxy{schema.OneOf, schema.AnyOf} {
+			if len(variants) == 0 {
+				continue
+			}
+			if variant := w.variants.SelectVariant(schema, variants, val); variant != nil {
+				w.walk(variant, val, path, depth+1)
+			}
+		}
+
+	case []any:
+		if schema.Items == nil || !schema.Items.IsA() || schema.Items.A == nil {
+			return
+		}
+		items := schema.Items.A.Schema()
+		for i, item := range val {
+			w.walk(items, item, path+"["+strconv.Itoa(i)+"]", depth+1)
+		}
+	}
+}
+
+// propertyPath appends a property to a JSONPath, using bracket notation for names that need it.
+func propertyPath(path, name string) string {
+	if identifierRegex.MatchString(name) {
+		return path + "." + name
+	}
+	return fmt.Sprintf("%s['%s']", path, strings.ReplaceAll(name, "'", "\\'"))
+}
+
+func isJSON(contentType string) bool {
+	return strings.Contains(strings.ToLower(contentType), helpers.JSONType)
+}
+
+// mediaTypeFor finds the media type for a content type, including media ranges.
+func mediaTypeFor(contentType string, content *orderedmap.Map[string, *v3.MediaType]) *v3.MediaType {
+	if content == nil {
+		return nil
+	}
+	ct, _, _ := helpers.ExtractContentType(contentType)
+	if mediaType, ok := content.Get(ct); ok {
+		return mediaType
+	}
+	ctMediaRange := strings.SplitN(ct, "/", 2)
+	if len(ctMediaRange) != 2 {
+		return nil
+	}
+	for pair := content.First(); pair != nil; pair = pair.Next() {
+		opMediaRange := strings.SplitN(pair.Key(), "/", 2)
+		if len(opMediaRange) == 2 &&
+			(opMediaRange[0] == "*" || opMediaRange[0] == ctMediaRange[0]) &&
+			(opMediaRange[1] == "*" || opMediaRange[1] == ctMediaRange[1]) {
+			return pair.Value()
+		}
+	}
+	return nil
+}
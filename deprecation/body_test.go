@@ -0,0 +1,137 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package deprecation
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+func bodySchema(t *testing.T, spec string) *base.Schema {
+	t.Helper()
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, errs := doc.BuildV3Model()
+	require.NoError(t, errs)
+	schema, _ := m.Model.Components.Schemas.Get("Body")
+	return schema.Schema()
+}
+
+func TestBody(t *testing.T) {
+	schema := bodySchema(t, `openapi: 3.1.0
+components:
+  schemas:
+    Base:
+      type: object
+      properties:
+        old:
+          type: string
+          deprecated: true
+    Body:
+      type: object
+      allOf:
+        - $ref: '#/components/schemas/Base'
+      properties:
+        old:
+          type: string
+          deprecated: true
+        tags:
+          type: array
+          items:
+            type: object
+            properties:
+              label:
+                type: string
+                deprecated: true
+        weird name:
+          type: string
+          deprecated: true
+        pet:
+          oneOf:
+            - type: object
+              required: [bark]
+              properties:
+                bark:
+                  type: boolean
+                  deprecated: true
+            - type: object
+              required: [meow]
+              properties:
+                meow:
+                  type: boolean
+`)
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/pets", nil)
+	options := config.NewValidationOptions()
+
+	found := Body(message.FromHTTPRequest(request), schema,
+		[]byte(`{"old":"x","tags":[{"label":"a"},{}],"weird name":"y","pet":{"bark":true}}`), "$.body", options, 3.1)
+
+	var paths []string
+	for _, f := range found {
+		paths = append(paths, f.SchemaValidationErrors[0].FieldPath)
+	}
+	// 'old' is deprecated by the schema and its allOf, it is only reported once.
+	assert.Equal(t, []string{"$.body.old", "$.body.tags[0].label", "$.body['weird name']", "$.body.pet.bark"}, paths)
+
+	found = Body(message.FromHTTPRequest(request), schema, []byte(`{"pet":{"meow":true}}`), "$.body", options, 3.1)
+	assert.Empty(t, found)
+
+	assert.Nil(t, Body(message.FromHTTPRequest(request), schema, []byte(`not json`), "$.body", options, 3.1))
+	assert.Nil(t, Body(message.FromHTTPRequest(request), nil, []byte(`{}`), "$.body", options, 3.1))
+}
+
+func TestRequest_Body(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(`openapi: 3.1.0
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/*:
+            schema:
+              type: object
+              properties:
+                legacy:
+                  type: string
+                  deprecated: true
+`))
+	require.NoError(t, err)
+	m, errs := doc.BuildV3Model()
+	require.NoError(t, errs)
+	pathItem, _ := m.Model.Paths.PathItems.Get("/pets")
+	options := config.NewValidationOptions(config.WithDeprecationWarnings())
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/pets", strings.NewReader(`{"legacy":"x"}`))
+	request.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	found := Request(message.FromHTTPRequest(request), pathItem, "/pets", options, 3.1)
+	require.Len(t, found, 1)
+	assert.Equal(t, "$.body.legacy", found[0].SchemaValidationErrors[0].FieldPath)
+
+	body, _ := io.ReadAll(request.Body)
+	assert.Equal(t, `{"legacy":"x"}`, string(body))
+
+	// messages that don't come from net/http are read directly.
+	raw, err := message.ParseRawRequest([]byte("POST /pets HTTP/1.1\r\nHost: things.com\r\n" +
+		"Content-Type: application/json\r\nContent-Length: 14\r\n\r\n{\"legacy\":\"x\"}"))
+	require.NoError(t, err)
+	require.Len(t, Request(raw, pathItem, "/pets", options, 3.1), 1)
+	body, _ = io.ReadAll(raw.Body())
+	assert.Equal(t, `{"legacy":"x"}`, string(body))
+
+	// bodies that aren't JSON are not checked.
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/pets", strings.NewReader(`legacy=x`))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	assert.Empty(t, Request(message.FromHTTPRequest(request), pathItem, "/pets", options, 3.1))
+}
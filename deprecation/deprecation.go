@@ -0,0 +1,171 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+// Package deprecation reports requests and responses that use parts of a specification marked as
+// 'deprecated', so the clients still relying on them can be found before they are removed.
+//
+// Deprecations are reported for:
+//   - Requests made to a deprecated operation.
+//   - Path, query, header and cookie parameters that are deprecated, or have a deprecated schema, and are sent.
+//   - Response headers that are deprecated and are sent.
+//   - Properties of JSON request and response bodies with a deprecated schema, recursively, including allOf
+//     schemas and the matching oneOf or anyOf variant.
+//
+// Findings have a severity of errors.SeverityWarning, or errors.SeverityError when the options ask for
+// deprecations to be reported as errors.
+package deprecation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+// Request returns the deprecated parts of the specification a request uses. The request body is read and
+// restored, so it can still be read by the caller.
+func Request(request message.Request, pathItem *v3.PathItem, pathValue string, options *config.ValidationOptions, version float32) []*errors.ValidationError {
+	if request == nil || pathItem == nil {
+		return nil
+	}
+	operation := helpers.OperationForMethod(request.Method(), pathItem)
+	if operation == nil {
+		return nil
+	}
+
+	var found []*errors.ValidationError
+	if operation.Deprecated != nil && *operation.Deprecated {
+		found = append(found, errors.DeprecatedOperationUsed(operation, request, pathValue))
+	}
+	for _, param := range helpers.ParamsForOperation(request.Method(), pathItem, options) {
+		if param != nil && parameterDeprecated(param) && parameterPresent(request, param) {
+			found = append(found, errors.DeprecatedParameterUsed(param, request))
+		}
+	}
+	if operation.RequestBody != nil {
+		contentType := request.Header().Get(helpers.ContentTypeHeader)
+		if mediaType := mediaTypeFor(contentType, operation.RequestBody.Content); mediaType != nil && mediaType.Schema != nil {
+			found = append(found, requestBody(request, contentType, mediaType.Schema.Schema(), options, version)...)
+		}
+	}
+	return withSeverity(found, options)
+}
+
+// Response returns the deprecated parts of the specification a response uses. The response body is read and
+// restored, so it can still be read by the caller.
+func Response(request message.Request, response message.Response, pathItem *v3.PathItem, options *config.ValidationOptions, version float32) []*errors.ValidationError {
+	if request == nil || response == nil || pathItem == nil {
+		return nil
+	}
+	operation := helpers.OperationForMethod(request.Method(), pathItem)
+	if operation == nil || operation.Responses == nil {
+		return nil
+	}
+	found := responseFor(operation.Responses, response.StatusCode())
+	if found == nil {
+		return nil
+	}
+
+	var deprecated []*errors.ValidationError
+	if found.Headers != nil {
+		for name, header := range found.Headers.FromOldest() {
+			if header != nil && headerDeprecated(header) && len(response.Header().Values(name)) > 0 {
+				deprecated = append(deprecated, errors.DeprecatedHeaderUsed(name, header, request))
+			}
+		}
+	}
+	contentType := response.Header().Get(helpers.ContentTypeHeader)
+	if mediaType := mediaTypeFor(contentType, found.Content); mediaType != nil && mediaType.Schema != nil {
+		deprecated = append(deprecated, responseBody(request, response, contentType, mediaType.Schema.Schema(), options, version)...)
+	}
+	return withSeverity(deprecated, options)
+}
+
+// withSeverity raises deprecations to errors, when the options ask for it.
+func withSeverity(found []*errors.ValidationError, options *config.ValidationOptions) []*errors.ValidationError {
+	if options != nil && options.DeprecationsAsErrors {
+		for _, validationError := range found {
+			validationError.Severity = errors.SeverityError
+		}
+	}
+	return found
+}
+
+func parameterDeprecated(param *v3.Parameter) bool {
+	if param.Deprecated {
+		return true
+	}
+	if param.Schema != nil {
+		if schema := param.Schema.Schema(); schema != nil && schema.Deprecated != nil {
+			return *schema.Deprecated
+		}
+	}
+	return false
+}
+
+func headerDeprecated(header *v3.Header) bool {
+	if header.Deprecated {
+		return true
+	}
+	if header.Schema != nil {
+		if schema := header.Schema.Schema(); schema != nil && schema.Deprecated != nil {
+			return *schema.Deprecated
+		}
+	}
+	return false
+}
+
+// parameterPresent reports whether a request sends a parameter. Path parameters are always sent.
+func parameterPresent(request message.Request, param *v3.Parameter) bool {
+	switch strings.ToLower(param.In) {
+	case helpers.Path:
+		return true
+	case helpers.Query:
+		query := request.URL().Query()
+		if _, ok := query[param.Name]; ok {
+			return true
+		}
+		for key := range query {
+			if strings.HasPrefix(key, param.Name+"[") {
+				return true
+			}
+		}
+		// exploded form objects are sent as their properties.
+		if param.Schema != nil && (param.Explode == nil || *param.Explode) &&
+			(param.Style == "" || param.Style == helpers.Form) {
+			if schema := param.Schema.Schema(); schema != nil && schema.Properties != nil {
+				for name := range schema.Properties.KeysFromOldest() {
+					if _, ok := query[name]; ok {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	case helpers.Header:
+		return len(request.Header().Values(param.Name)) > 0
+	case helpers.Cookie:
+		_, err := request.Cookie(param.Name)
+		return err == nil
+	}
+	return false
+}
+
+// responseFor finds the response for a status code, falling back to its range, then the default response.
+func responseFor(responses *v3.Responses, statusCode int) *v3.Response {
+	if responses.Codes != nil {
+		if found := responses.Codes.GetOrZero(strconv.Itoa(statusCode)); found != nil {
+			return found
+		}
+		if found := responses.Codes.GetOrZero(fmt.Sprintf("%dXX", statusCode/100)); found != nil {
+			return found
+		}
+	}
+	return responses.Default
+}
@@ -0,0 +1,168 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package deprecation
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+const deprecatedSpec = `openapi: 3.1.0
+paths:
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+    get:
+      operationId: getPet
+      deprecated: true
+      parameters:
+        - name: legacy
+          in: query
+          deprecated: true
+          schema:
+            type: string
+        - name: filter
+          in: query
+          schema:
+            type: object
+            deprecated: true
+            properties:
+              color:
+                type: string
+        - name: X-Old
+          in: header
+          deprecated: true
+          schema:
+            type: string
+        - name: session
+          in: cookie
+          deprecated: true
+          schema:
+            type: string
+        - name: current
+          in: query
+          schema:
+            type: string
+      responses:
+        '200':
+          description: OK
+          headers:
+            X-Legacy-Rate:
+              deprecated: true
+              schema:
+                type: integer
+            X-Rate:
+              schema:
+                type: integer
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: integer
+                  nickname:
+                    type: string
+                    deprecated: true
+`
+
+func newPathItem(t *testing.T) *v3.PathItem {
+	t.Helper()
+	doc, err := libopenapi.NewDocument([]byte(deprecatedSpec))
+	require.NoError(t, err)
+	m, errs := doc.BuildV3Model()
+	require.NoError(t, errs)
+	pathItem, _ := m.Model.Paths.PathItems.Get("/pets/{id}")
+	return pathItem
+}
+
+func TestRequest(t *testing.T) {
+	pathItem := newPathItem(t)
+	options := config.NewValidationOptions(config.WithDeprecationWarnings())
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/pets/1?legacy=yes&color=red&current=ok", nil)
+	request.Header.Set("X-Old", "1")
+	request.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+
+	found := Request(message.FromHTTPRequest(request), pathItem, "/pets/{id}", options, 3.1)
+	require.Len(t, found, 5)
+
+	assert.Equal(t, helpers.DeprecationValidation, found[0].ValidationType)
+	assert.Equal(t, helpers.DeprecatedOperation, found[0].ValidationSubType)
+	assert.Equal(t, errors.SeverityWarning, found[0].Severity)
+	assert.True(t, found[0].IsWarning())
+	assert.Contains(t, found[0].Reason, "getPet")
+
+	var names []string
+	for _, f := range found[1:] {
+		assert.Equal(t, helpers.DeprecatedParameter, f.ValidationSubType)
+		names = append(names, f.ParameterName)
+	}
+	assert.Equal(t, []string{"legacy", "filter", "X-Old", "session"}, names)
+
+	// parameters that are not sent are not reported.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/pets/1?current=ok", nil)
+	found = Request(message.FromHTTPRequest(request), pathItem, "/pets/{id}", options, 3.1)
+	require.Len(t, found, 1)
+	assert.Equal(t, helpers.DeprecatedOperation, found[0].ValidationSubType)
+
+	// deprecations can be reported as errors.
+	found = Request(message.FromHTTPRequest(request), pathItem, "/pets/{id}", config.NewValidationOptions(config.WithDeprecationsAsErrors()), 3.1)
+	require.Len(t, found, 1)
+	assert.Equal(t, errors.SeverityError, found[0].Severity)
+	assert.False(t, found[0].IsWarning())
+
+	assert.Nil(t, Request(nil, pathItem, "", options, 3.1))
+	assert.Nil(t, Request(message.FromHTTPRequest(request), nil, "", options, 3.1))
+	request, _ = http.NewRequest(http.MethodDelete, "https://things.com/pets/1", nil)
+	assert.Nil(t, Request(message.FromHTTPRequest(request), pathItem, "/pets/{id}", options, 3.1))
+}
+
+func TestResponse(t *testing.T) {
+	pathItem := newPathItem(t)
+	options := config.NewValidationOptions(config.WithDeprecationWarnings())
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/pets/1", nil)
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type":  {"application/json"},
+			"X-Legacy-Rate": {"1"},
+			"X-Rate":        {"1"},
+		},
+		Body: io.NopCloser(strings.NewReader(`{"id":1,"nickname":"fido"}`)),
+	}
+
+	found := Response(message.FromHTTPRequest(request), message.FromHTTPResponse(response), pathItem, options, 3.1)
+	require.Len(t, found, 2)
+	assert.Equal(t, helpers.DeprecatedHeader, found[0].ValidationSubType)
+	assert.Equal(t, "X-Legacy-Rate", found[0].ParameterName)
+	assert.Equal(t, helpers.DeprecatedProperty, found[1].ValidationSubType)
+	assert.Equal(t, "$.body.nickname", found[1].SchemaValidationErrors[0].FieldPath)
+
+	// the body can still be read.
+	body, _ := io.ReadAll(response.Body)
+	assert.Equal(t, `{"id":1,"nickname":"fido"}`, string(body))
+
+	// responses that are not in the specification have no deprecations.
+	response.StatusCode = http.StatusNotFound
+	assert.Empty(t, Response(message.FromHTTPRequest(request), message.FromHTTPResponse(response), pathItem, options, 3.1))
+	assert.Nil(t, Response(message.FromHTTPRequest(request), nil, pathItem, options, 3.1))
+}
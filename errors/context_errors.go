@@ -0,0 +1,64 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package errors
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"net/http"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+// ValidationCancelled creates a ValidationError for validation that was stopped because its context was
+// cancelled, or its deadline passed. The sub type is helpers.ValidationTimeout when the deadline passed,
+// and helpers.ValidationCancelled otherwise.
+func ValidationCancelled(err error, request *http.Request) *ValidationError {
+	return validationCancelled(err, request.Method, request.URL.Path)
+}
+
+func validationCancelled(err error, method, path string) *ValidationError {
+	subType, outcome := helpers.ValidationCancelled, "was cancelled"
+	if goerrors.Is(err, context.DeadlineExceeded) {
+		subType, outcome = helpers.ValidationTimeout, "timed out"
+	}
+	return &ValidationError{
+		ValidationType:    helpers.ContextValidation,
+		ValidationSubType: subType,
+		Message:           fmt.Sprintf("Validation of %s %s %s", method, path, outcome),
+		Reason:            fmt.Sprintf("The validation context is done: %s", err.Error()),
+		SpecLine:          -1,
+		SpecCol:           -1,
+		HowToFix:          HowToFixValidationCancelled,
+		RequestMethod:     method,
+		RequestPath:       path,
+	}
+}
+
+// MessageContextDone returns a ValidationCancelled error when the context the request was given with
+// message.WithContext is done, or nil when validation can carry on. Validators call it between steps so
+// cancelled validations stop early.
+func MessageContextDone(request message.Request) []*ValidationError {
+	if request == nil {
+		return nil
+	}
+	if ctx := message.Context(request); ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return []*ValidationError{validationCancelled(err, request.Method(), request.URL().Path)}
+		}
+	}
+	return nil
+}
+
+// IsCancelled returns true when validation was stopped by its context, rather than completing.
+func IsCancelled(validationErrors []*ValidationError) bool {
+	for _, validationError := range validationErrors {
+		if validationError != nil && validationError.ValidationType == helpers.ContextValidation {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package errors
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/pb33f/testify/assert"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+func TestValidationCancelled(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/pets", nil)
+
+	err := ValidationCancelled(context.Canceled, request)
+	assert.Equal(t, helpers.ContextValidation, err.ValidationType)
+	assert.Equal(t, helpers.ValidationCancelled, err.ValidationSubType)
+	assert.Equal(t, "Validation of GET /pets was cancelled", err.Message)
+	assert.Contains(t, err.Reason, context.Canceled.Error())
+	assert.Equal(t, HowToFixValidationCancelled, err.HowToFix)
+	assert.Equal(t, http.MethodGet, err.RequestMethod)
+	assert.Equal(t, "/pets", err.RequestPath)
+	assert.Equal(t, -1, err.SpecLine)
+
+	err = ValidationCancelled(context.DeadlineExceeded, request)
+	assert.Equal(t, helpers.ValidationTimeout, err.ValidationSubType)
+	assert.Equal(t, "Validation of GET /pets timed out", err.Message)
+}
+
+func TestMessageContextDone(t *testing.T) {
+	assert.Nil(t, MessageContextDone(nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// only the context given to the message counts, not the context of the *http.Request.
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://things.com/pets", nil)
+	assert.Nil(t, MessageContextDone(message.FromHTTPRequest(request)))
+	assert.False(t, IsCancelled(nil))
+
+	errs := MessageContextDone(message.WithContext(ctx, message.FromHTTPRequest(request)))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, helpers.ValidationCancelled, errs[0].ValidationSubType)
+	assert.Equal(t, "/pets", errs[0].RequestPath)
+	assert.True(t, IsCancelled(errs))
+	assert.False(t, IsCancelled([]*ValidationError{{ValidationType: helpers.ParameterValidation}, nil}))
+}
@@ -0,0 +1,97 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package errors
+
+import (
+	"fmt"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"go.yaml.in/yaml/v4"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+// DeprecatedOperationUsed creates a warning for a request made to a deprecated operation.
+func DeprecatedOperationUsed(operation *v3.Operation, request message.Request, pathTemplate string) *ValidationError {
+	var keyNode *yaml.Node
+	if low := operation.GoLow(); low != nil {
+		keyNode = low.Deprecated.KeyNode
+	}
+	name := fmt.Sprintf("%s %s", request.Method(), pathTemplate)
+	if operation.OperationId != "" {
+		name = fmt.Sprintf("operation '%s'", operation.OperationId)
+	}
+	return deprecationWarning(helpers.DeprecatedOperation, request, keyNode,
+		fmt.Sprintf("%s %s is deprecated", request.Method(), pathTemplate),
+		fmt.Sprintf("The request was made to %s, which is marked as deprecated", name),
+		fmt.Sprintf("%s %s", request.Method(), pathTemplate), operation)
+}
+
+// DeprecatedParameterUsed creates a warning for a request that sends a deprecated parameter.
+func DeprecatedParameterUsed(param *v3.Parameter, request message.Request) *ValidationError {
+	var keyNode *yaml.Node
+	if low := param.GoLow(); low != nil {
+		keyNode = low.Deprecated.KeyNode
+	}
+	validationError := deprecationWarning(helpers.DeprecatedParameter, request, keyNode,
+		fmt.Sprintf("%s parameter '%s' is deprecated", param.In, param.Name),
+		fmt.Sprintf("The request sends the %s parameter '%s', which is marked as deprecated", param.In, param.Name),
+		fmt.Sprintf("the %s parameter '%s'", param.In, param.Name), param)
+	validationError.ParameterName = param.Name
+	return validationError
+}
+
+// DeprecatedHeaderUsed creates a warning for a response that sends a deprecated header.
+func DeprecatedHeaderUsed(name string, header *v3.Header, request message.Request) *ValidationError {
+	var keyNode *yaml.Node
+	if low := header.GoLow(); low != nil {
+		keyNode = low.Deprecated.KeyNode
+	}
+	validationError := deprecationWarning(helpers.DeprecatedHeader, request, keyNode,
+		fmt.Sprintf("response header '%s' is deprecated", name),
+		fmt.Sprintf("The response sends the header '%s', which is marked as deprecated", name),
+		fmt.Sprintf("the response header '%s'", name), header)
+	validationError.ParameterName = name
+	return validationError
+}
+
+// DeprecatedPropertyUsed creates a warning for a request or response body that contains a property whose
+// schema is deprecated. path is the JSONPath of the property, such as '$.body.nickname'.
+func DeprecatedPropertyUsed(path string, schema *base.Schema, request message.Request) *ValidationError {
+	var keyNode *yaml.Node
+	if low := schema.GoLow(); low != nil {
+		keyNode = low.Deprecated.KeyNode
+	}
+	validationError := deprecationWarning(helpers.DeprecatedProperty, request, keyNode,
+		fmt.Sprintf("property '%s' is deprecated", path),
+		fmt.Sprintf("The body contains '%s', which is marked as deprecated", path),
+		fmt.Sprintf("the property '%s'", path), schema)
+	validationError.SchemaValidationErrors = []*SchemaValidationFailure{{
+		Reason:    validationError.Reason,
+		FieldPath: path,
+	}}
+	return validationError
+}
+
+func deprecationWarning(subType string, request message.Request, keyNode *yaml.Node, message, reason, what string, context any) *ValidationError {
+	specLine, specCol := 1, 0
+	if keyNode != nil {
+		specLine, specCol = keyNode.Line, keyNode.Column
+	}
+	return &ValidationError{
+		ValidationType:    helpers.DeprecationValidation,
+		ValidationSubType: subType,
+		Severity:          SeverityWarning,
+		Message:           message,
+		Reason:            reason,
+		SpecLine:          specLine,
+		SpecCol:           specCol,
+		HowToFix:          fmt.Sprintf(HowToFixDeprecated, what),
+		RequestPath:       request.URL().Path,
+		RequestMethod:     request.Method(),
+		Context:           context,
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package errors
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+func TestDeprecationErrors(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(`openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      deprecated: true
+      parameters:
+        - name: legacy
+          in: query
+          deprecated: true
+      responses:
+        '200':
+          description: OK
+          headers:
+            X-Old:
+              deprecated: true
+          content:
+            application/json:
+              schema:
+                type: object
+                deprecated: true
+`))
+	require.NoError(t, err)
+	m, errs := doc.BuildV3Model()
+	require.NoError(t, errs)
+	pathItem, _ := m.Model.Paths.PathItems.Get("/pets")
+	operation := pathItem.Get
+	httpRequest, _ := http.NewRequest(http.MethodGet, "https://things.com/pets", nil)
+	request := message.FromHTTPRequest(httpRequest)
+
+	err1 := DeprecatedOperationUsed(operation, request, "/pets")
+	assert.Equal(t, helpers.DeprecationValidation, err1.ValidationType)
+	assert.Equal(t, helpers.DeprecatedOperation, err1.ValidationSubType)
+	assert.Equal(t, SeverityWarning, err1.Severity)
+	assert.Equal(t, "GET /pets is deprecated", err1.Message)
+	assert.Equal(t, 5, err1.SpecLine)
+	assert.Equal(t, "Stop using GET /pets, it is deprecated and may be removed from the API", err1.HowToFix)
+
+	operation.OperationId = "listPets"
+	assert.Contains(t, DeprecatedOperationUsed(operation, request, "/pets").Reason, "operation 'listPets'")
+
+	err2 := DeprecatedParameterUsed(operation.Parameters[0], request)
+	assert.Equal(t, helpers.DeprecatedParameter, err2.ValidationSubType)
+	assert.Equal(t, "legacy", err2.ParameterName)
+	assert.Equal(t, "query parameter 'legacy' is deprecated", err2.Message)
+	assert.Equal(t, 9, err2.SpecLine)
+
+	response := operation.Responses.Codes.GetOrZero("200")
+	header, _ := response.Headers.Get("X-Old")
+	err3 := DeprecatedHeaderUsed("X-Old", header, request)
+	assert.Equal(t, helpers.DeprecatedHeader, err3.ValidationSubType)
+	assert.Equal(t, "X-Old", err3.ParameterName)
+	assert.Equal(t, 15, err3.SpecLine)
+
+	mediaType, _ := response.Content.Get("application/json")
+	err4 := DeprecatedPropertyUsed("$.body", mediaType.Schema.Schema(), request)
+	assert.Equal(t, helpers.DeprecatedProperty, err4.ValidationSubType)
+	require.Len(t, err4.SchemaValidationErrors, 1)
+	assert.Equal(t, "$.body", err4.SchemaValidationErrors[0].FieldPath)
+	assert.Equal(t, 20, err4.SpecLine)
+}
@@ -2,15 +2,23 @@ package errors
 
 import (
 	"net/http"
+
+	"github.com/pb33f/libopenapi-validator/message"
 )
 
 // PopulateValidationErrors mutates the provided validation errors with additional useful error information, that is
 // not necessarily available when the ValidationError was created and are standard for all errors.
 // Specifically, the RequestPath, SpecPath and RequestMethod are populated.
 func PopulateValidationErrors(validationErrors []*ValidationError, request *http.Request, path string) {
+	PopulateMessageValidationErrors(validationErrors, message.FromHTTPRequest(request), path)
+}
+
+// PopulateMessageValidationErrors works the same way as PopulateValidationErrors, for a transport-neutral
+// message.Request.
+func PopulateMessageValidationErrors(validationErrors []*ValidationError, request message.Request, path string) {
 	for _, validationError := range validationErrors {
 		validationError.SpecPath = path
-		validationError.RequestMethod = request.Method
-		validationError.RequestPath = request.URL.Path
+		validationError.RequestMethod = request.Method()
+		validationError.RequestPath = request.URL().Path
 	}
 }
@@ -34,6 +34,14 @@ const (
 	HowToFixMissingValue                       string = "Ensure the value has been set"
 	HowToFixPath                               string = "Check the path is correct, and check that the correct HTTP method has been used (e.g. GET, POST, PUT, DELETE)"
 	HowToFixPathMethod                         string = "Add the missing operation to the contract for the path"
+	HowToFixPathEquivalent                     string = "Merge the operations of '%s' and '%s' into a single path, or change the literal segments so they differ"
+	HowToFixPathAmbiguous                      string = "Make one of the templates more specific (use a literal segment), or merge the paths"
+	HowToFixPathSlowPath                       string = "Use whole-segment parameters ({id}) or literal text around named parameters ({name}.{ext}) to enable fast path matching"
+	HowToFixPathParameterUndeclared            string = "Declare a parameter named '%s' with 'in: path' on the path item or operation"
+	HowToFixPathParameterUnused                string = "Add '{%s}' to the path template, or remove the parameter"
+	HowToFixInvalidValidationPolicy            string = "Fix the 'x-validation' extension, it must be an object of known options, and 'mode' must be 'enforce', 'warn' or 'off'"
+	HowToFixValidationCancelled                string = "Validation was stopped before it completed, the message has not been validated. Retry with a longer deadline if the client is still waiting"
+	HowToFixDeprecated                         string = "Stop using %s, it is deprecated and may be removed from the API"
 	HowToFixInvalidMaxItems                    string = "Reduce the number of items in the array to %d or less"
 	HowToFixInvalidMinItems                    string = "Increase the number of items in the array to %d or more"
 	HowToFixMissingHeader                      string = "Make sure the service responding sets the required headers with this response code"
@@ -0,0 +1,126 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+// PathTemplateEquivalent creates a ValidationError for two path templates that only differ by parameter names,
+// for example '/pets/{id}' and '/pets/{name}'. Only one of them can ever be matched.
+func PathTemplateEquivalent(path, otherPath string, pathItem *v3.PathItem) *ValidationError {
+	specLine, specCol := pathItemLineCol(pathItem)
+	return &ValidationError{
+		ValidationType:    helpers.PathValidation,
+		ValidationSubType: helpers.PathTemplateEquivalent,
+		Message:           fmt.Sprintf("Path '%s' is equivalent to path '%s'", path, otherPath),
+		Reason: fmt.Sprintf("The templates '%s' and '%s' have the same hierarchy and only differ by "+
+			"parameter names, so every request matching one also matches the other. Only one of them can be matched",
+			path, otherPath),
+		SpecLine: specLine,
+		SpecCol:  specCol,
+		SpecPath: path,
+		Context:  pathItem,
+		HowToFix: fmt.Sprintf(HowToFixPathEquivalent, otherPath, path),
+	}
+}
+
+// PathTemplateAmbiguous creates a ValidationError for two path templates that can match the same request path
+// where neither is more specific than the other, for example '/{entity}/me' and '/books/{id}'.
+func PathTemplateAmbiguous(path, otherPath string, pathItem *v3.PathItem) *ValidationError {
+	specLine, specCol := pathItemLineCol(pathItem)
+	return &ValidationError{
+		ValidationType:    helpers.PathValidation,
+		ValidationSubType: helpers.PathTemplateAmbiguous,
+		Message:           fmt.Sprintf("Path '%s' is ambiguous with path '%s'", path, otherPath),
+		Reason: fmt.Sprintf("Some request paths match both '%s' and '%s', and neither template is more "+
+			"specific than the other. The template that wins depends on matching order", path, otherPath),
+		SpecLine: specLine,
+		SpecCol:  specCol,
+		SpecPath: path,
+		Context:  pathItem,
+		HowToFix: HowToFixPathAmbiguous,
+	}
+}
+
+// PathTemplateSlowPath creates a ValidationError for a path template that the radix tree can't match,
+// so requests for it are only resolved by the (much slower) regex fallback.
+func PathTemplateSlowPath(path string, segments []string, pathItem *v3.PathItem) *ValidationError {
+	specLine, specCol := pathItemLineCol(pathItem)
+	return &ValidationError{
+		ValidationType:    helpers.PathValidation,
+		ValidationSubType: helpers.PathTemplateSlowPath,
+		Message:           fmt.Sprintf("Path '%s' can only be matched by regular expression", path),
+		Reason: fmt.Sprintf("The segments [%s] of '%s' can't be compiled into the path tree, "+
+			"so every request for this path falls back to regular expression matching", strings.Join(segments, ", "), path),
+		SpecLine: specLine,
+		SpecCol:  specCol,
+		SpecPath: path,
+		Context:  pathItem,
+		HowToFix: HowToFixPathSlowPath,
+	}
+}
+
+// PathParameterUndeclared creates a ValidationError for a parameter used in a path template that isn't
+// declared as an 'in: path' parameter for the operation.
+func PathParameterUndeclared(path, method, name string, operation *v3.Operation) *ValidationError {
+	specLine, specCol := 1, 0
+	if low := operation.GoLow(); low != nil {
+		specLine, specCol = SafeNodeLineCol(low.KeyNode)
+	}
+	return &ValidationError{
+		ValidationType:    helpers.PathValidation,
+		ValidationSubType: helpers.PathParameterUndeclared,
+		Message:           fmt.Sprintf("Path parameter '%s' is not declared for %s '%s'", name, method, path),
+		Reason: fmt.Sprintf("The path template '%s' contains the parameter '{%s}', however the %s operation "+
+			"does not declare a path parameter named '%s'", path, name, method, name),
+		SpecLine:      specLine,
+		SpecCol:       specCol,
+		SpecPath:      path,
+		RequestMethod: method,
+		ParameterName: name,
+		Context:       operation,
+		HowToFix:      fmt.Sprintf(HowToFixPathParameterUndeclared, name),
+	}
+}
+
+// PathParameterUnused creates a ValidationError for an 'in: path' parameter that doesn't appear in the path template.
+func PathParameterUnused(path, method string, param *v3.Parameter) *ValidationError {
+	specLine, specCol := 1, 0
+	if low := param.GoLow(); low != nil {
+		if low.Name.ValueNode != nil {
+			specLine, specCol = SafeNodeLineCol(low.Name.ValueNode)
+		} else {
+			specLine, specCol = SafeNodeLineCol(low.RootNode)
+		}
+	}
+	return &ValidationError{
+		ValidationType:    helpers.PathValidation,
+		ValidationSubType: helpers.PathParameterUnused,
+		Message:           fmt.Sprintf("Path parameter '%s' is not used in path '%s'", param.Name, path),
+		Reason: fmt.Sprintf("The %s operation declares the path parameter '%s', however the path template '%s' "+
+			"does not contain '{%s}'", method, param.Name, path, param.Name),
+		SpecLine:      specLine,
+		SpecCol:       specCol,
+		SpecPath:      path,
+		RequestMethod: method,
+		ParameterName: param.Name,
+		Context:       param,
+		HowToFix:      fmt.Sprintf(HowToFixPathParameterUnused, param.Name),
+	}
+}
+
+func pathItemLineCol(pathItem *v3.PathItem) (int, int) {
+	if pathItem != nil {
+		if low := pathItem.GoLow(); low != nil {
+			return SafeNodeLineCol(low.KeyNode)
+		}
+	}
+	return 1, 0
+}
@@ -0,0 +1,58 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package errors
+
+import (
+	"testing"
+
+	"github.com/pb33f/testify/assert"
+	"go.yaml.in/yaml/v4"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+func TestPathTemplateErrors_NoLowModel(t *testing.T) {
+	pathItem := &v3.PathItem{}
+	operation := &v3.Operation{}
+	param := &v3.Parameter{Name: "slug", In: "path"}
+
+	errs := []*ValidationError{
+		PathTemplateEquivalent("/pets/{name}", "/pets/{id}", pathItem),
+		PathTemplateAmbiguous("/books/{id}", "/{entity}/me", pathItem),
+		PathTemplateSlowPath("/items/{id:[0-9]+}.json", []string{"{id:[0-9]+}.json"}, pathItem),
+		PathParameterUndeclared("/pets/{id}", "GET", "id", operation),
+		PathParameterUnused("/pets", "GET", param),
+	}
+	subTypes := []string{
+		helpers.PathTemplateEquivalent,
+		helpers.PathTemplateAmbiguous,
+		helpers.PathTemplateSlowPath,
+		helpers.PathParameterUndeclared,
+		helpers.PathParameterUnused,
+	}
+
+	for i, err := range errs {
+		assert.Equal(t, helpers.PathValidation, err.ValidationType)
+		assert.Equal(t, subTypes[i], err.ValidationSubType)
+		assert.Equal(t, 1, err.SpecLine)
+		assert.Equal(t, 0, err.SpecCol)
+		assert.NotEmpty(t, err.HowToFix)
+		assert.False(t, err.IsPathMissingError())
+	}
+	assert.Equal(t, "slug", errs[4].ParameterName)
+	assert.Equal(t, "Add '{slug}' to the path template, or remove the parameter", errs[4].HowToFix)
+}
+
+func TestPathItemLineCol(t *testing.T) {
+	line, col := pathItemLineCol(nil)
+	assert.Equal(t, 1, line)
+	assert.Equal(t, 0, col)
+
+	node := &yaml.Node{Line: 12, Column: 3}
+	line, col = SafeNodeLineCol(node)
+	assert.Equal(t, 12, line)
+	assert.Equal(t, 3, col)
+}
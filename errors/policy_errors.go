@@ -0,0 +1,28 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package errors
+
+import (
+	"fmt"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+// InvalidValidationPolicy creates a ValidationError for an 'x-validation' extension that can't be read.
+// location describes where the extension is, for example "GET /pets" or "parameter 'limit'", and node
+// is the extension value.
+func InvalidValidationPolicy(location string, err error, node *yaml.Node) *ValidationError {
+	specLine, specCol := SafeNodeLineCol(node)
+	return &ValidationError{
+		ValidationType:    helpers.DocumentValidation,
+		ValidationSubType: helpers.ValidationPolicyInvalid,
+		Message:           fmt.Sprintf("Validation policy for %s is invalid", location),
+		Reason:            fmt.Sprintf("The 'x-validation' extension for %s can't be read: %s", location, err.Error()),
+		SpecLine:          specLine,
+		SpecCol:           specCol,
+		HowToFix:          HowToFixInvalidValidationPolicy,
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pb33f/testify/assert"
+	"go.yaml.in/yaml/v4"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+func TestInvalidValidationPolicy(t *testing.T) {
+	node := &yaml.Node{Line: 12, Column: 7}
+	err := InvalidValidationPolicy("GET /pets", fmt.Errorf("unknown mode 'loud'"), node)
+
+	assert.Equal(t, helpers.DocumentValidation, err.ValidationType)
+	assert.Equal(t, helpers.ValidationPolicyInvalid, err.ValidationSubType)
+	assert.Equal(t, "Validation policy for GET /pets is invalid", err.Message)
+	assert.Contains(t, err.Reason, "unknown mode 'loud'")
+	assert.Equal(t, HowToFixInvalidValidationPolicy, err.HowToFix)
+	assert.Equal(t, 12, err.SpecLine)
+	assert.Equal(t, 7, err.SpecCol)
+}
+
+func TestInvalidValidationPolicy_NilNode(t *testing.T) {
+	err := InvalidValidationPolicy("the document", fmt.Errorf("bad"), nil)
+	assert.Equal(t, "Validation policy for the document is invalid", err.Message)
+	assert.Equal(t, 1, err.SpecLine)
+}
@@ -13,10 +13,17 @@ import (
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
 )
 
 func RequestContentTypeNotFound(op *v3.Operation, request *http.Request, specPath string) *ValidationError {
-	ct := request.Header.Get(helpers.ContentTypeHeader)
+	return MessageRequestContentTypeNotFound(op, message.FromHTTPRequest(request), specPath)
+}
+
+// MessageRequestContentTypeNotFound works the same way as RequestContentTypeNotFound, for a transport-neutral
+// message.Request.
+func MessageRequestContentTypeNotFound(op *v3.Operation, request message.Request, specPath string) *ValidationError {
+	ct := request.Header().Get(helpers.ContentTypeHeader)
 	var ctypes []string
 	var contentMap *orderedmap.Map[string, *v3.MediaType]
 	specLine, specCol := 1, 0
@@ -34,20 +41,25 @@ func RequestContentTypeNotFound(op *v3.Operation, request *http.Request, specPat
 		ValidationType:    helpers.RequestBodyValidation,
 		ValidationSubType: helpers.RequestBodyContentType,
 		Message: fmt.Sprintf("%s operation request content type '%s' does not exist",
-			request.Method, ct),
+			request.Method(), ct),
 		Reason: fmt.Sprintf("The content type '%s' of the %s request submitted has not "+
-			"been defined, it's an unknown type", ct, request.Method),
+			"been defined, it's an unknown type", ct, request.Method()),
 		SpecLine:      specLine,
 		SpecCol:       specCol,
 		Context:       op,
 		HowToFix:      fmt.Sprintf(HowToFixInvalidContentType, orderedmap.Len(contentMap), strings.Join(ctypes, ", ")),
-		RequestPath:   request.URL.Path,
-		RequestMethod: request.Method,
+		RequestPath:   request.URL().Path,
+		RequestMethod: request.Method(),
 		SpecPath:      specPath,
 	}
 }
 
 func OperationNotFound(pathItem *v3.PathItem, request *http.Request, method string, specPath string) *ValidationError {
+	return MessageOperationNotFound(pathItem, message.FromHTTPRequest(request), method, specPath)
+}
+
+// MessageOperationNotFound works the same way as OperationNotFound, for a transport-neutral message.Request.
+func MessageOperationNotFound(pathItem *v3.PathItem, request message.Request, method string, specPath string) *ValidationError {
 	specLine, specCol := 1, 0
 	if low := pathItem.GoLow(); low != nil && low.KeyNode != nil {
 		specLine = low.KeyNode.Line
@@ -57,14 +69,14 @@ func OperationNotFound(pathItem *v3.PathItem, request *http.Request, method stri
 		ValidationType:    helpers.RequestValidation,
 		ValidationSubType: helpers.ValidationMissingOperation,
 		Message: fmt.Sprintf("%s operation request content type '%s' does not exist",
-			request.Method, method),
-		Reason:        fmt.Sprintf("The path was found, but there was no '%s' method found in the spec", request.Method),
+			request.Method(), method),
+		Reason:        fmt.Sprintf("The path was found, but there was no '%s' method found in the spec", request.Method()),
 		SpecLine:      specLine,
 		SpecCol:       specCol,
 		Context:       pathItem,
 		HowToFix:      HowToFixPathMethod,
-		RequestPath:   request.URL.Path,
-		RequestMethod: request.Method,
+		RequestPath:   request.URL().Path,
+		RequestMethod: request.Method(),
 		SpecPath:      specPath,
 	}
 }
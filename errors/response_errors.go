@@ -13,6 +13,7 @@ import (
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
 )
 
 func ResponseContentTypeNotFound(op *v3.Operation,
@@ -21,7 +22,18 @@ func ResponseContentTypeNotFound(op *v3.Operation,
 	code string,
 	isDefault bool,
 ) *ValidationError {
-	ct := response.Header.Get(helpers.ContentTypeHeader)
+	return MessageResponseContentTypeNotFound(op, message.FromHTTPRequest(request), message.FromHTTPResponse(response), code, isDefault)
+}
+
+// MessageResponseContentTypeNotFound works the same way as ResponseContentTypeNotFound, for a transport-neutral
+// message.Request and message.Response.
+func MessageResponseContentTypeNotFound(op *v3.Operation,
+	request message.Request,
+	response message.Response,
+	code string,
+	isDefault bool,
+) *ValidationError {
+	ct := response.Header().Get(helpers.ContentTypeHeader)
 	mediaTypeString, _, _ := helpers.ExtractContentType(ct)
 	var ctypes []string
 	specLine, specCol := 1, 0
@@ -56,9 +68,9 @@ func ResponseContentTypeNotFound(op *v3.Operation,
 		ValidationType:    helpers.ResponseBodyValidation,
 		ValidationSubType: helpers.RequestBodyContentType,
 		Message: fmt.Sprintf("%s / %s operation response content type '%s' does not exist",
-			request.Method, code, mediaTypeString),
+			request.Method(), code, mediaTypeString),
 		Reason: fmt.Sprintf("The content type '%s' of the %s response received has not "+
-			"been defined, it's an unknown type", mediaTypeString, request.Method),
+			"been defined, it's an unknown type", mediaTypeString, request.Method()),
 		SpecLine: specLine,
 		SpecCol:  specCol,
 		Context:  op,
@@ -68,6 +80,11 @@ func ResponseContentTypeNotFound(op *v3.Operation,
 }
 
 func ResponseCodeNotFound(op *v3.Operation, request *http.Request, code int) *ValidationError {
+	return MessageResponseCodeNotFound(op, message.FromHTTPRequest(request), code)
+}
+
+// MessageResponseCodeNotFound works the same way as ResponseCodeNotFound, for a transport-neutral message.Request.
+func MessageResponseCodeNotFound(op *v3.Operation, request message.Request, code int) *ValidationError {
 	specLine, specCol := 1, 0
 	if low := op.GoLow(); low != nil && low.Responses.KeyNode != nil {
 		specLine = low.Responses.KeyNode.Line
@@ -77,9 +94,9 @@ func ResponseCodeNotFound(op *v3.Operation, request *http.Request, code int) *Va
 		ValidationType:    helpers.ResponseBodyValidation,
 		ValidationSubType: helpers.ResponseBodyResponseCode,
 		Message: fmt.Sprintf("%s operation request response code '%d' does not exist",
-			request.Method, code),
+			request.Method(), code),
 		Reason: fmt.Sprintf("The response code '%d' of the %s request submitted has not "+
-			"been defined, it's an unknown type", code, request.Method),
+			"been defined, it's an unknown type", code, request.Method()),
 		SpecLine: specLine,
 		SpecCol:  specCol,
 		Context:  op,
@@ -190,6 +190,17 @@ func WriteOnlyPropertyError(
 	}
 }
 
+// RelocateStrictError points the message of a strict mode body error at location, the position of the value
+// in the original XML or urlencoded payload, rather than path, the JSONPath of the value after conversion.
+// HowToFix keeps the JSONPath, as that's what StrictIgnorePaths is matched against.
+func RelocateStrictError(err *ValidationError, location, path string) *ValidationError {
+	if err == nil || location == "" || location == path {
+		return err
+	}
+	err.Message = strings.Replace(err.Message, "at '"+path+"'", "at '"+location+"'", 1)
+	return err
+}
+
 // truncateForContext creates a truncated string representation for error context.
 func truncateForContext(v any) string {
 	switch val := v.(type) {
@@ -267,3 +267,19 @@ func TestTruncateForContext_Other(t *testing.T) {
 	assert.True(t, len(result) <= 50)
 	assert.Contains(t, result, "...")
 }
+
+func TestRelocateStrictError(t *testing.T) {
+	err := UndeclaredPropertyError("$.body.-lang", "@lang", "en", []string{"name"}, "request", "/pets", "POST", 1, 1)
+	err = RelocateStrictError(err, "/pet/@lang", "$.body.-lang")
+
+	assert.Equal(t, "request property '@lang' at '/pet/@lang' is not declared in schema", err.Message)
+	assert.Contains(t, err.HowToFix, "'$.body.-lang' to StrictIgnorePaths")
+}
+
+func TestRelocateStrictError_NoLocation(t *testing.T) {
+	err := ReadOnlyPropertyError("$.body.id", "id", 1, "/pets", "POST", 1, 1)
+	message := err.Message
+
+	assert.Equal(t, message, RelocateStrictError(err, "", "$.body.id").Message)
+	assert.Nil(t, RelocateStrictError(nil, "id", "$.body.id"))
+}
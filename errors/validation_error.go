@@ -4,6 +4,7 @@
 package errors
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/pb33f/libopenapi-validator/helpers"
@@ -13,6 +14,10 @@ import (
 // SchemaValidationFailure describes any failure that occurs when validating data
 // against either an OpenAPI or JSON Schema. It aims to be a more user-friendly
 // representation of the error than what is provided by the jsonschema library.
+//
+// Every field is filled in when the failure is created, unless config.WithLazyErrorDetails is set. Then Line,
+// Column, ReferenceSchema and ReferenceObject stay empty until Enrich is called, which marshalling the failure to
+// JSON or YAML does. Callers that read those fields directly, or that use another encoder, must call Enrich first.
 type SchemaValidationFailure struct {
 	// Reason is a human-readable message describing the reason for the error.
 	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
@@ -54,6 +59,39 @@ type SchemaValidationFailure struct {
 
 	// Context is the raw schema object that failed validation (for programmatic access)
 	Context interface{} `json:"-" yaml:"-"`
+
+	// details computes the fields skipped by config.WithLazyErrorDetails, it is nil once they are computed.
+	details func(*SchemaValidationFailure)
+}
+
+// SetLazyDetails defers computing ReferenceSchema, ReferenceObject, Line and Column until Enrich is called.
+// It is used by validators when config.WithLazyErrorDetails is set.
+func (s *SchemaValidationFailure) SetLazyDetails(details func(*SchemaValidationFailure)) {
+	s.details = details
+}
+
+// Enrich computes the details of the failure that were deferred by config.WithLazyErrorDetails, and returns
+// the failure. It does nothing when details were not deferred, or were already computed. Enrich is not safe to
+// call from multiple goroutines at once.
+func (s *SchemaValidationFailure) Enrich() *SchemaValidationFailure {
+	if s != nil && s.details != nil {
+		details := s.details
+		s.details = nil
+		details(s)
+	}
+	return s
+}
+
+// MarshalJSON computes any deferred details of the failure before it is marshalled.
+func (s *SchemaValidationFailure) MarshalJSON() ([]byte, error) {
+	type failure SchemaValidationFailure
+	return json.Marshal((*failure)(s.Enrich()))
+}
+
+// MarshalYAML computes any deferred details of the failure before it is marshalled.
+func (s *SchemaValidationFailure) MarshalYAML() (interface{}, error) {
+	type failure SchemaValidationFailure
+	return (*failure)(s.Enrich()), nil
 }
 
 // Error returns a string representation of the error
@@ -64,6 +102,21 @@ func (s *SchemaValidationFailure) Error() string {
 	return fmt.Sprintf("Reason: %s", s.Reason)
 }
 
+// Severity describes how serious a ValidationError is.
+type Severity string
+
+const (
+	// SeverityError marks errors that fail validation. Errors without a severity are errors.
+	SeverityError Severity = "error"
+
+	// SeverityWarning marks findings that are reported without failing validation, such as the use of
+	// deprecated operations, or errors of operations with an 'x-validation' policy in 'warn' mode.
+	SeverityWarning Severity = "warning"
+
+	// SeverityInfo marks findings that are informational only.
+	SeverityInfo Severity = "info"
+)
+
 // ValidationError is a struct that contains all the information about a validation error.
 type ValidationError struct {
 	// Message is a human-readable message describing the error.
@@ -78,6 +131,9 @@ type ValidationError struct {
 	// ValidationSubType is a string that describes the subtype of validation that failed.
 	ValidationSubType string `json:"validationSubType" yaml:"validationSubType"`
 
+	// Severity is how serious the error is, empty means SeverityError.
+	Severity Severity `json:"severity,omitempty" yaml:"severity,omitempty"`
+
 	// SpecLine is the line number in the spec where the error occurred.
 	SpecLine int `json:"specLine" yaml:"specLine"`
 
@@ -129,6 +185,29 @@ func (v *ValidationError) Error() string {
 	}
 }
 
+// Enrich computes the details of every schema failure of the error that were deferred by
+// config.WithLazyErrorDetails, and returns the error.
+func (v *ValidationError) Enrich() *ValidationError {
+	for _, failure := range v.SchemaValidationErrors {
+		failure.Enrich()
+	}
+	return v
+}
+
+// GetSeverity returns the severity of the error, defaulting to SeverityError when none is set.
+func (v *ValidationError) GetSeverity() Severity {
+	if v.Severity == "" {
+		return SeverityError
+	}
+	return v.Severity
+}
+
+// IsWarning returns true if the error is a warning, or informational, and should not fail validation.
+func (v *ValidationError) IsWarning() bool {
+	severity := v.GetSeverity()
+	return severity == SeverityWarning || severity == SeverityInfo
+}
+
 // IsPathMissingError returns true if the error has a ValidationType of "path" and a ValidationSubType of "missing"
 func (v *ValidationError) IsPathMissingError() bool {
 	return v.ValidationType == helpers.PathValidation && v.ValidationSubType == helpers.ValidationMissing
@@ -4,11 +4,13 @@
 package errors
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/testify/require"
+	"go.yaml.in/yaml/v4"
 )
 
 func TestSchemaValidationFailure_Error(t *testing.T) {
@@ -117,3 +119,59 @@ func TestValidationError_IsOperationMissingError(t *testing.T) {
 	v.ValidationSubType = helpers.ValidationMissingOperation
 	require.False(t, v.IsOperationMissingError())
 }
+
+func TestValidationError_Severity(t *testing.T) {
+	v := &ValidationError{}
+	require.Equal(t, SeverityError, v.GetSeverity())
+	require.False(t, v.IsWarning())
+
+	v.Severity = SeverityWarning
+	require.Equal(t, SeverityWarning, v.GetSeverity())
+	require.True(t, v.IsWarning())
+
+	v.Severity = SeverityInfo
+	require.True(t, v.IsWarning())
+}
+
+func TestSchemaValidationFailure_Enrich(t *testing.T) {
+	calls := 0
+	failure := &SchemaValidationFailure{Reason: "invalid"}
+	failure.SetLazyDetails(func(f *SchemaValidationFailure) {
+		calls++
+		f.ReferenceSchema = "type: string"
+		f.Line = 4
+	})
+	require.Empty(t, failure.ReferenceSchema)
+
+	// details are computed once, when the failure is marshalled or enriched.
+	encoded, err := json.Marshal(failure)
+	require.NoError(t, err)
+	require.Contains(t, string(encoded), `"referenceSchema":"type: string"`)
+	require.Contains(t, string(encoded), `"line":4`)
+
+	validationError := &ValidationError{SchemaValidationErrors: []*SchemaValidationFailure{failure}}
+	require.Same(t, validationError, validationError.Enrich())
+	require.Equal(t, 1, calls)
+
+	var nilFailure *SchemaValidationFailure
+	require.Nil(t, nilFailure.Enrich())
+	encoded, err = json.Marshal(struct {
+		Failure *SchemaValidationFailure `json:"failure"`
+	}{})
+	require.NoError(t, err)
+	require.Equal(t, `{"failure":null}`, string(encoded))
+}
+
+func TestSchemaValidationFailure_MarshalYAML(t *testing.T) {
+	failure := &SchemaValidationFailure{Reason: "invalid"}
+	failure.SetLazyDetails(func(f *SchemaValidationFailure) {
+		f.ReferenceSchema = "type: string"
+		f.Column = 7
+	})
+
+	encoded, err := yaml.Marshal(&ValidationError{SchemaValidationErrors: []*SchemaValidationFailure{failure}})
+	require.NoError(t, err)
+	require.Contains(t, string(encoded), "referenceSchema: 'type: string'")
+	require.Contains(t, string(encoded), "column: 7")
+	require.Equal(t, 7, failure.Column)
+}
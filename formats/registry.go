@@ -0,0 +1,394 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+// Package formats implements the formats of the OpenAPI Format Registry (https://spec.openapis.org/registry/format/),
+// so they can be asserted alongside the JSON Schema formats supported by the schema compiler.
+//
+// Like all format validators, each format only checks the types it applies to, and ignores everything else.
+// Numeric formats accept any of the number types produced when decoding bodies, parameters and headers.
+package formats
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Registry returns the validators for every format in the OpenAPI Format Registry, keyed by format name.
+// A new map is returned on each call, so it can be safely modified.
+func Registry() map[string]func(v any) error {
+	return map[string]func(v any) error{
+		"int8":            integerRange("int8", math.MinInt8, math.MaxInt8),
+		"int16":           integerRange("int16", math.MinInt16, math.MaxInt16),
+		"int32":           integerRange("int32", math.MinInt32, math.MaxInt32),
+		"int64":           integerRange("int64", math.MinInt64, math.MaxInt64),
+		"uint8":           unsignedRange("uint8", math.MaxUint8),
+		"uint16":          unsignedRange("uint16", math.MaxUint16),
+		"uint32":          unsignedRange("uint32", math.MaxUint32),
+		"uint64":          unsignedRange("uint64", math.MaxUint64),
+		"float":           floatRange("float", math.MaxFloat32),
+		"double":          floatRange("double", math.MaxFloat64),
+		"byte":            Byte,
+		"base64url":       Base64URL,
+		"binary":          passThrough,
+		"char":            Char,
+		"decimal":         Decimal,
+		"decimal128":      Decimal128,
+		"date-time-local": DateTimeLocal,
+		"time-local":      TimeLocal,
+		"http-date":       HTTPDate,
+		"media-range":     MediaRange,
+		"sf-binary":       StructuredFieldBinary,
+		"sf-boolean":      passThrough,
+		"sf-decimal":      StructuredFieldDecimal,
+		"sf-integer":      StructuredFieldInteger,
+		"sf-string":       StructuredFieldString,
+		"sf-token":        StructuredFieldToken,
+		"commonmark":      passThrough,
+		"html":            passThrough,
+		"password":        passThrough,
+	}
+}
+
+var (
+	decimalRegex    = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+	decimal128Regex = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$`)
+	sfTokenRegex    = regexp.MustCompile("^[A-Za-z*][!#$%&'*+\\-.^_`|~0-9A-Za-z:/]*$")
+)
+
+const (
+	// decimal128 holds 34 significant digits, with an adjusted exponent between -6143 and 6144.
+	decimal128Digits  = 34
+	decimal128MinExp  = -6143
+	decimal128MaxExp  = 6144
+	sfIntegerMax      = 999_999_999_999_999
+	sfDecimalIntegers = 12
+	sfDecimalFraction = 3
+)
+
+func passThrough(any) error {
+	return nil
+}
+
+// integerRange creates a validator for a signed integer format.
+func integerRange(name string, minimum, maximum int64) func(v any) error {
+	lower, upper := new(big.Rat).SetInt64(minimum), new(big.Rat).SetInt64(maximum)
+	return func(v any) error {
+		n, ok := toRat(v)
+		if !ok {
+			return nil
+		}
+		if !n.IsInt() {
+			return fmt.Errorf("'%s' is not an integer", n.FloatString(3))
+		}
+		if n.Cmp(lower) < 0 || n.Cmp(upper) > 0 {
+			return fmt.Errorf("'%s' is out of range for %s, it must be between %d and %d", n.RatString(), name, minimum, maximum)
+		}
+		return nil
+	}
+}
+
+// unsignedRange creates a validator for an unsigned integer format.
+func unsignedRange(name string, maximum uint64) func(v any) error {
+	upper := new(big.Rat).SetInt(new(big.Int).SetUint64(maximum))
+	return func(v any) error {
+		n, ok := toRat(v)
+		if !ok {
+			return nil
+		}
+		if !n.IsInt() {
+			return fmt.Errorf("'%s' is not an integer", n.FloatString(3))
+		}
+		if n.Sign() < 0 || n.Cmp(upper) > 0 {
+			return fmt.Errorf("'%s' is out of range for %s, it must be between 0 and %d", n.RatString(), name, maximum)
+		}
+		return nil
+	}
+}
+
+// floatRange creates a validator for a floating point format, checking the value fits in its range.
+func floatRange(name string, maximum float64) func(v any) error {
+	limit := new(big.Rat).SetFloat64(maximum)
+	return func(v any) error {
+		if f, ok := v.(float64); ok && (math.IsNaN(f) || math.IsInf(f, 0)) {
+			return fmt.Errorf("'%v' can't be represented as a %s", f, name)
+		}
+		n, ok := toRat(v)
+		if !ok {
+			return nil
+		}
+		if new(big.Rat).Abs(n).Cmp(limit) > 0 {
+			return fmt.Errorf("'%s' can't be represented as a %s", n.FloatString(0), name)
+		}
+		return nil
+	}
+}
+
+// toRat converts any of the number types produced by decoding JSON, parameters and headers.
+func toRat(v any) (*big.Rat, bool) {
+	switch n := v.(type) {
+	case int:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int8:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int16:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int32:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int64:
+		return new(big.Rat).SetInt64(n), true
+	case uint:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint8:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint16:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint32:
+		return new(big.Rat).SetUint64(uint64(n)), true
+	case uint64:
+		return new(big.Rat).SetUint64(n), true
+	case float32:
+		return toRat(float64(n))
+	case float64:
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, false
+		}
+		return new(big.Rat).SetFloat64(n), true
+	case json.Number:
+		return new(big.Rat).SetString(n.String())
+	case *big.Int:
+		return new(big.Rat).SetInt(n), n != nil
+	case *big.Rat:
+		return n, n != nil
+	case *big.Float:
+		if n == nil || n.IsInf() {
+			return nil, false
+		}
+		r, _ := n.Rat(nil)
+		return r, true
+	}
+	return nil, false
+}
+
+// Byte validates base64 encoded data, using the standard alphabet with padding.
+func Byte(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+		return fmt.Errorf("'%s' is not valid base64: %w", s, err)
+	}
+	return nil
+}
+
+// Base64URL validates base64url encoded data, with or without padding.
+func Base64URL(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	if _, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "=")); err != nil {
+		return fmt.Errorf("'%s' is not valid base64url: %w", s, err)
+	}
+	return nil
+}
+
+// Char validates a single character string.
+func Char(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	if utf8.RuneCountInString(s) != 1 {
+		return fmt.Errorf("'%s' is not a single character", s)
+	}
+	return nil
+}
+
+// Decimal validates a fixed point decimal number. Strings must be plain decimal notation.
+func Decimal(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	if !decimalRegex.MatchString(s) {
+		return fmt.Errorf("'%s' is not a decimal number", s)
+	}
+	return nil
+}
+
+// Decimal128 validates a number that fits in an IEEE 754 decimal128, up to 34 significant digits.
+func Decimal128(v any) error {
+	var s string
+	switch n := v.(type) {
+	case string:
+		s = n
+	case json.Number:
+		s = n.String()
+	default:
+		return nil
+	}
+	if !decimal128Regex.MatchString(s) {
+		return fmt.Errorf("'%s' is not a decimal number", s)
+	}
+
+	mantissa, exponent := strings.ToLower(strings.TrimLeft(s, "+-")), 0
+	if i := strings.IndexByte(mantissa, 'e'); i >= 0 {
+		if _, err := fmt.Sscan(mantissa[i+1:], &exponent); err != nil {
+			return fmt.Errorf("'%s' is not a decimal number", s)
+		}
+		mantissa = mantissa[:i]
+	}
+	integer, fraction, _ := strings.Cut(mantissa, ".")
+	digits := strings.TrimLeft(integer+fraction, "0")
+	if digits == "" {
+		return nil // zero
+	}
+	if significant := strings.TrimRight(digits, "0"); len(significant) > decimal128Digits {
+		return fmt.Errorf("'%s' has more than %d significant digits, it can't be represented as a decimal128", s, decimal128Digits)
+	}
+	// the adjusted exponent is the exponent of the most significant digit.
+	adjusted := exponent + len(strings.TrimLeft(integer, "0")) - 1
+	if strings.TrimLeft(integer, "0") == "" {
+		adjusted = exponent - (len(fraction) - len(strings.TrimLeft(fraction, "0"))) - 1
+	}
+	if adjusted < decimal128MinExp || adjusted > decimal128MaxExp {
+		return fmt.Errorf("'%s' is out of range for a decimal128", s)
+	}
+	return nil
+}
+
+// DateTimeLocal validates a date-time without a time zone, such as '2026-01-31T10:30:00'.
+func DateTimeLocal(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	if _, err := time.Parse("2006-01-02T15:04:05.999999999", strings.Replace(s, "t", "T", 1)); err != nil {
+		return fmt.Errorf("'%s' is not a local date-time", s)
+	}
+	return nil
+}
+
+// TimeLocal validates a time without a time zone, such as '10:30:00'.
+func TimeLocal(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	if _, err := time.Parse("15:04:05.999999999", s); err != nil {
+		return fmt.Errorf("'%s' is not a local time", s)
+	}
+	return nil
+}
+
+// HTTPDate validates an HTTP-date, such as 'Sun, 06 Nov 1994 08:49:37 GMT'.
+func HTTPDate(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	if _, err := http.ParseTime(s); err != nil {
+		return fmt.Errorf("'%s' is not an HTTP-date", s)
+	}
+	return nil
+}
+
+// MediaRange validates a media range, such as 'text/*' or 'application/json; charset=utf-8'.
+func MediaRange(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(s)
+	if err != nil {
+		return fmt.Errorf("'%s' is not a media range: %w", s, err)
+	}
+	mainType, subType, found := strings.Cut(mediaType, "/")
+	if !found || (mainType == "*" && subType != "*") {
+		return fmt.Errorf("'%s' is not a media range", s)
+	}
+	return nil
+}
+
+// StructuredFieldBinary validates the base64 content of a structured field byte sequence.
+func StructuredFieldBinary(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+		return fmt.Errorf("'%s' is not a valid structured field byte sequence: %w", s, err)
+	}
+	return nil
+}
+
+// StructuredFieldDecimal validates a structured field decimal, at most 12 integer and 3 fractional digits.
+func StructuredFieldDecimal(v any) error {
+	n, ok := toRat(v)
+	if !ok {
+		return nil
+	}
+	abs := new(big.Rat).Abs(n)
+	if abs.Cmp(new(big.Rat).SetInt64(1_000_000_000_000)) >= 0 {
+		return fmt.Errorf("'%s' has more than %d integer digits, it's not a structured field decimal",
+			n.FloatString(sfDecimalFraction), sfDecimalIntegers)
+	}
+	// json numbers such as 1.1 aren't exact, so only compare to the precision a structured field decimal has.
+	scaled := new(big.Rat).Mul(abs, new(big.Rat).SetInt64(1000))
+	if f, _ := scaled.Float64(); math.Abs(f-math.Round(f)) > 1e-6 {
+		return fmt.Errorf("'%s' has more than %d fractional digits, it's not a structured field decimal",
+			n.FloatString(6), sfDecimalFraction)
+	}
+	return nil
+}
+
+// StructuredFieldInteger validates a structured field integer, at most 15 digits.
+func StructuredFieldInteger(v any) error {
+	n, ok := toRat(v)
+	if !ok {
+		return nil
+	}
+	if !n.IsInt() {
+		return fmt.Errorf("'%s' is not an integer", n.FloatString(3))
+	}
+	if new(big.Rat).Abs(n).Cmp(new(big.Rat).SetInt64(sfIntegerMax)) > 0 {
+		return fmt.Errorf("'%s' has more than 15 digits, it's not a structured field integer", n.RatString())
+	}
+	return nil
+}
+
+// StructuredFieldString validates a structured field string, which only holds printable ASCII characters.
+func StructuredFieldString(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	for _, r := range s {
+		if r < 0x20 || r > 0x7e {
+			return fmt.Errorf("'%s' contains characters that aren't allowed in a structured field string", s)
+		}
+	}
+	return nil
+}
+
+// StructuredFieldToken validates a structured field token.
+func StructuredFieldToken(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	if !sfTokenRegex.MatchString(s) {
+		return fmt.Errorf("'%s' is not a structured field token", s)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package formats
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/pb33f/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := Registry()
+	for _, name := range []string{
+		"int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64", "float", "double",
+		"byte", "base64url", "binary", "char", "decimal", "decimal128", "date-time-local", "time-local",
+		"http-date", "media-range", "sf-binary", "sf-boolean", "sf-decimal", "sf-integer", "sf-string",
+		"sf-token", "commonmark", "html", "password",
+	} {
+		assert.NotNil(t, registry[name], name)
+	}
+
+	// every call returns a new map
+	delete(registry, "int32")
+	assert.NotNil(t, Registry()["int32"])
+}
+
+func TestIntegerFormats(t *testing.T) {
+	registry := Registry()
+
+	int32Format := registry["int32"]
+	assert.NoError(t, int32Format(float64(math.MaxInt32)))
+	assert.NoError(t, int32Format(int64(math.MinInt32)))
+	assert.NoError(t, int32Format(json.Number("42")))
+	assert.ErrorContains(t, int32Format(float64(math.MaxInt32+1)), "out of range for int32")
+	assert.ErrorContains(t, int32Format(1.5), "not an integer")
+	assert.NoError(t, int32Format("not a number"))
+
+	int64Format := registry["int64"]
+	assert.NoError(t, int64Format(json.Number("9223372036854775807")))
+	assert.Error(t, int64Format(json.Number("9223372036854775808")))
+	assert.Error(t, int64Format(new(big.Int).Lsh(big.NewInt(1), 64)))
+
+	int8Format := registry["int8"]
+	assert.NoError(t, int8Format(-128))
+	assert.Error(t, int8Format(128))
+
+	uint8Format := registry["uint8"]
+	assert.NoError(t, uint8Format(uint8(255)))
+	assert.NoError(t, uint8Format(0))
+	assert.ErrorContains(t, uint8Format(-1), "between 0 and 255")
+	assert.Error(t, uint8Format(256))
+	assert.Error(t, uint8Format(2.5))
+
+	assert.NoError(t, registry["uint64"](json.Number("18446744073709551615")))
+	assert.Error(t, registry["uint64"](json.Number("18446744073709551616")))
+}
+
+func TestFloatFormats(t *testing.T) {
+	registry := Registry()
+
+	assert.NoError(t, registry["float"](3.4e38))
+	assert.NoError(t, registry["float"](float32(1.5)))
+	assert.ErrorContains(t, registry["float"](3.5e38), "can't be represented as a float")
+	assert.NoError(t, registry["double"](1.7e308))
+	assert.Error(t, registry["double"](json.Number("1e400")))
+	assert.Error(t, registry["double"](math.Inf(1)))
+	assert.NoError(t, registry["double"](true))
+}
+
+func TestEncodingFormats(t *testing.T) {
+	assert.NoError(t, Byte("aGVsbG8="))
+	assert.Error(t, Byte("aGVsbG8"))
+	assert.Error(t, Byte("a-b_"))
+	assert.NoError(t, Byte(12))
+
+	assert.NoError(t, Base64URL("aGk_-w"))
+	assert.NoError(t, Base64URL("aGVsbG8="))
+	assert.Error(t, Base64URL("aGk+/w"))
+
+	assert.NoError(t, Char("a"))
+	assert.NoError(t, Char("é"))
+	assert.Error(t, Char("ab"))
+	assert.Error(t, Char(""))
+}
+
+func TestDecimalFormats(t *testing.T) {
+	assert.NoError(t, Decimal("12.50"))
+	assert.NoError(t, Decimal("-0.5"))
+	assert.NoError(t, Decimal(12.5))
+	assert.Error(t, Decimal("1e10"))
+	assert.Error(t, Decimal("twelve"))
+
+	assert.NoError(t, Decimal128("1.234567890123456789012345678901234"))
+	assert.NoError(t, Decimal128("1e6144"))
+	assert.NoError(t, Decimal128("0.000"))
+	assert.NoError(t, Decimal128("0.00123e-6140"))
+	assert.NoError(t, Decimal128(json.Number("100")))
+	assert.ErrorContains(t, Decimal128("1.2345678901234567890123456789012345"), "34 significant digits")
+	assert.ErrorContains(t, Decimal128("1e6145"), "out of range")
+	assert.ErrorContains(t, Decimal128("0.001e-6141"), "out of range")
+	assert.Error(t, Decimal128("1e"))
+	assert.NoError(t, Decimal128(1.5))
+}
+
+func TestDateFormats(t *testing.T) {
+	assert.NoError(t, DateTimeLocal("2026-01-31T10:30:00"))
+	assert.NoError(t, DateTimeLocal("2026-01-31t10:30:00.123"))
+	assert.Error(t, DateTimeLocal("2026-01-31T10:30:00Z"))
+	assert.Error(t, DateTimeLocal("2026-01-31"))
+
+	assert.NoError(t, TimeLocal("10:30:00"))
+	assert.NoError(t, TimeLocal("23:59:59.999"))
+	assert.Error(t, TimeLocal("10:30:00+01:00"))
+	assert.Error(t, TimeLocal("25:00:00"))
+
+	assert.NoError(t, HTTPDate("Sun, 06 Nov 1994 08:49:37 GMT"))
+	assert.NoError(t, HTTPDate("Sunday, 06-Nov-94 08:49:37 GMT"))
+	assert.Error(t, HTTPDate("1994-11-06T08:49:37Z"))
+}
+
+func TestMediaRange(t *testing.T) {
+	assert.NoError(t, MediaRange("*/*"))
+	assert.NoError(t, MediaRange("text/*"))
+	assert.NoError(t, MediaRange("application/json; charset=utf-8"))
+	assert.Error(t, MediaRange("*/json"))
+	assert.Error(t, MediaRange("json"))
+	assert.Error(t, MediaRange("text/html; charset"))
+}
+
+func TestStructuredFieldFormats(t *testing.T) {
+	assert.NoError(t, StructuredFieldBinary("cHJldGVuZCB0aGlzIGlzIGJpbmFyeSBjb250ZW50Lg=="))
+	assert.Error(t, StructuredFieldBinary("not base64!"))
+
+	assert.NoError(t, StructuredFieldDecimal(4.5))
+	assert.NoError(t, StructuredFieldDecimal(1.125))
+	assert.NoError(t, StructuredFieldDecimal(json.Number("-999999999999.999")))
+	assert.ErrorContains(t, StructuredFieldDecimal(1.1255), "fractional digits")
+	assert.ErrorContains(t, StructuredFieldDecimal(1e12), "integer digits")
+
+	assert.NoError(t, StructuredFieldInteger(json.Number("999999999999999")))
+	assert.Error(t, StructuredFieldInteger(json.Number("1000000000000000")))
+	assert.Error(t, StructuredFieldInteger(1.5))
+
+	assert.NoError(t, StructuredFieldString("hello world"))
+	assert.Error(t, StructuredFieldString("tab\there"))
+	assert.Error(t, StructuredFieldString("héllo"))
+
+	assert.NoError(t, StructuredFieldToken("foo123/456"))
+	assert.NoError(t, StructuredFieldToken("*foo"))
+	assert.Error(t, StructuredFieldToken("1foo"))
+	assert.Error(t, StructuredFieldToken("foo bar"))
+}
+
+func TestPassThroughFormats(t *testing.T) {
+	registry := Registry()
+	for _, name := range []string{"binary", "commonmark", "html", "password", "sf-boolean"} {
+		assert.NoError(t, registry[name]("<b>anything</b>"), name)
+	}
+}
@@ -25,9 +25,23 @@ const (
 	PathValidation             = "path"
 	ValidationMissing          = "missing"
 	ValidationMissingOperation = "missingOperation"
+	PathTemplateEquivalent     = "equivalentTemplate"
+	PathTemplateAmbiguous      = "ambiguousTemplate"
+	PathTemplateSlowPath       = "slowPathTemplate"
+	PathParameterUndeclared    = "undeclaredPathParameter"
+	PathParameterUnused        = "unusedPathParameter"
 	ResponseBodyResponseCode   = "statusCode"
 	SecurityValidation         = "security"
 	DocumentValidation         = "document"
+	ValidationPolicyInvalid    = "invalidValidationPolicy"
+	ContextValidation          = "context"
+	ValidationCancelled        = "cancelled"
+	ValidationTimeout          = "timeout"
+	DeprecationValidation      = "deprecation"
+	DeprecatedOperation        = "operation"
+	DeprecatedParameter        = "parameter"
+	DeprecatedHeader           = "header"
+	DeprecatedProperty         = "property"
 	SpaceDelimited             = "spaceDelimited"
 	PipeDelimited              = "pipeDelimited"
 	DefaultDelimited           = "default"
@@ -57,6 +71,7 @@ const (
 	URLEncodedContentType      = "application/x-www-form-urlencoded"
 	JSONType                   = "json"
 	ContentTypeHeader          = "Content-Type"
+	ContentLengthHeader        = "Content-Length"
 	AuthorizationHeader        = "Authorization"
 	Charset                    = "charset"
 	Boundary                   = "boundary"
@@ -0,0 +1,26 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// DecodeJSON decodes a JSON document into v, in the same way as json.Unmarshal, except that numbers are decoded
+// as json.Number. Numbers keep every digit they are sent with, so large integers such as the bounds of int64 and
+// uint64 are checked against their schema exactly, rather than rounded to the nearest float64.
+func DecodeJSON(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("invalid character after top-level value")
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package helpers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	var decoded any
+	require.NoError(t, DecodeJSON([]byte(`{"max": 9223372036854775807, "list": [1.5]}`), &decoded))
+	assert.Equal(t, map[string]any{
+		"max":  json.Number("9223372036854775807"),
+		"list": []any{json.Number("1.5")},
+	}, decoded)
+
+	assert.Error(t, DecodeJSON([]byte(`{"a": 1} {"b": 2}`), &decoded))
+	assert.Error(t, DecodeJSON([]byte(`{"a": `), &decoded))
+	assert.Error(t, DecodeJSON([]byte(``), &decoded))
+}
@@ -13,7 +13,13 @@ import (
 // ExtractOperation extracts the operation from the path item based on the request method. If there is no
 // matching operation found, then nil is returned.
 func ExtractOperation(request *http.Request, item *v3.PathItem) *v3.Operation {
-	switch request.Method {
+	return OperationForMethod(request.Method, item)
+}
+
+// OperationForMethod returns the operation of the path item for an HTTP method, in the same way as
+// ExtractOperation. If there is no matching operation found, then nil is returned.
+func OperationForMethod(method string, item *v3.PathItem) *v3.Operation {
+	switch method {
 	case http.MethodGet:
 		return item.Get
 	case http.MethodPost:
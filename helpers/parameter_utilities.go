@@ -13,6 +13,8 @@ import (
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
 )
 
 // QueryParam is a struct that holds the key, values and property name for a query parameter
@@ -28,8 +30,12 @@ type QueryParam struct {
 // ExtractParamsForOperation will extract the parameters for the operation based on the request method.
 // Both the path level params and the method level params will be returned.
 func ExtractParamsForOperation(request *http.Request, item *v3.PathItem) []*v3.Parameter {
+	return paramsForMethod(request.Method, item)
+}
+
+func paramsForMethod(method string, item *v3.PathItem) []*v3.Parameter {
 	params := item.Parameters
-	switch request.Method {
+	switch method {
 	case http.MethodGet:
 		if item.Get != nil {
 			params = append(params, item.Get.Parameters...)
@@ -66,6 +72,17 @@ func ExtractParamsForOperation(request *http.Request, item *v3.PathItem) []*v3.P
 	return params
 }
 
+// ParamsForOperation returns the path and operation parameters of the operation for an HTTP method, from the
+// validation plan of the operation when the options have one, otherwise as ExtractParamsForOperation does.
+func ParamsForOperation(method string, item *v3.PathItem, options *config.ValidationOptions) []*v3.Parameter {
+	if options != nil {
+		if planned := options.Plans.For(item, method); planned != nil {
+			return planned.Parameters
+		}
+	}
+	return paramsForMethod(method, item)
+}
+
 // ExtractSecurityForOperation will extract the security requirements for the operation based on the request method.
 //
 // Deprecated: use EffectiveSecurityForOperation instead, which also handles
@@ -168,7 +185,13 @@ func ExtractSecurityHeaderNames(
 //   - Otherwise, fall back to the document-level global security.
 //   - Returns nil only when neither level defines security.
 func EffectiveSecurityForOperation(request *http.Request, item *v3.PathItem, docSecurity []*base.SecurityRequirement) []*base.SecurityRequirement {
-	op := ExtractOperation(request, item)
+	return EffectiveSecurityForMethod(request.Method, item, docSecurity)
+}
+
+// EffectiveSecurityForMethod returns the security requirements that apply to the operation of the path item for
+// an HTTP method, in the same way as EffectiveSecurityForOperation.
+func EffectiveSecurityForMethod(method string, item *v3.PathItem, docSecurity []*base.SecurityRequirement) []*base.SecurityRequirement {
+	op := OperationForMethod(method, item)
 	if op != nil && op.Security != nil {
 		return op.Security // operation-level (may be empty [] = "no security")
 	}
@@ -36,6 +36,11 @@ func ConfigureCompiler(c *jsonschema.Compiler, o *config.ValidationOptions) {
 			Validate: v,
 		})
 	}
+
+	if len(o.Keywords) > 0 {
+		c.RegisterVocabulary(openapi_vocabulary.NewCustomVocabulary(o.Keywords))
+		c.AssertVocabs()
+	}
 }
 
 // NewCompilerWithOptions mints a new JSON schema compiler with custom configuration.
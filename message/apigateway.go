@@ -0,0 +1,154 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package message
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// APIGatewayProxyRequest is an AWS API Gateway (REST API, payload format 1.0) Lambda proxy integration event.
+// The JSON tags match the event, so it can be unmarshalled directly, or converted from the type of any
+// Lambda SDK with the same fields.
+type APIGatewayProxyRequest struct {
+	Resource                        string              `json:"resource"`
+	Path                            string              `json:"path"`
+	HTTPMethod                      string              `json:"httpMethod"`
+	Headers                         map[string]string   `json:"headers"`
+	MultiValueHeaders               map[string][]string `json:"multiValueHeaders"`
+	QueryStringParameters           map[string]string   `json:"queryStringParameters"`
+	MultiValueQueryStringParameters map[string][]string `json:"multiValueQueryStringParameters"`
+	PathParameters                  map[string]string   `json:"pathParameters"`
+	Body                            string              `json:"body"`
+	IsBase64Encoded                 bool                `json:"isBase64Encoded"`
+}
+
+// APIGatewayProxyResponse is the response returned by a Lambda function behind an AWS API Gateway proxy
+// integration.
+type APIGatewayProxyResponse struct {
+	StatusCode        int                 `json:"statusCode"`
+	Headers           map[string]string   `json:"headers"`
+	MultiValueHeaders map[string][]string `json:"multiValueHeaders"`
+	Body              string              `json:"body"`
+	IsBase64Encoded   bool                `json:"isBase64Encoded"`
+}
+
+type apiGatewayRequest struct {
+	method string
+	url    *url.URL
+	header http.Header
+	body   []byte
+}
+
+// NewAPIGatewayRequest adapts an API Gateway proxy event to a Request. Multi-value headers and query
+// parameters take precedence over their single value counterparts, and base64 encoded bodies are decoded.
+// An error is returned when the body cannot be decoded.
+func NewAPIGatewayRequest(event *APIGatewayProxyRequest) (Request, error) {
+	body, err := apiGatewayBody(event.Body, event.IsBase64Encoded)
+	if err != nil {
+		return nil, err
+	}
+	header := apiGatewayHeader(event.Headers, event.MultiValueHeaders)
+
+	query := make(url.Values)
+	for k, v := range event.MultiValueQueryStringParameters {
+		query[k] = append(query[k], v...)
+	}
+	for k, v := range event.QueryStringParameters {
+		if _, ok := query[k]; !ok {
+			query.Set(k, v)
+		}
+	}
+
+	u := &url.URL{Path: event.Path, Host: header.Get("Host"), RawQuery: query.Encode()}
+	if u.Host != "" {
+		u.Scheme = "https"
+		if proto := header.Get("X-Forwarded-Proto"); proto != "" {
+			u.Scheme = proto
+		}
+	}
+
+	return &apiGatewayRequest{method: event.HTTPMethod, url: u, header: header, body: body}, nil
+}
+
+func (r *apiGatewayRequest) Method() string                           { return r.method }
+func (r *apiGatewayRequest) URL() *url.URL                            { return r.url }
+func (r *apiGatewayRequest) Header() http.Header                      { return r.header }
+func (r *apiGatewayRequest) Cookie(name string) (*http.Cookie, error) { return cookie(r.header, name) }
+
+func (r *apiGatewayRequest) Body() io.ReadCloser {
+	if r.body == nil {
+		return nil
+	}
+	return io.NopCloser(bytes.NewReader(r.body))
+}
+
+func (r *apiGatewayRequest) SetBody(body []byte) { r.body = body }
+
+type apiGatewayResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+// NewAPIGatewayResponse adapts an API Gateway proxy response to a Response. An error is returned when a
+// base64 encoded body cannot be decoded.
+func NewAPIGatewayResponse(event *APIGatewayProxyResponse) (Response, error) {
+	body, err := apiGatewayBody(event.Body, event.IsBase64Encoded)
+	if err != nil {
+		return nil, err
+	}
+	return &apiGatewayResponse{
+		statusCode: event.StatusCode,
+		header:     apiGatewayHeader(event.Headers, event.MultiValueHeaders),
+		body:       body,
+	}, nil
+}
+
+func (r *apiGatewayResponse) StatusCode() int     { return r.statusCode }
+func (r *apiGatewayResponse) Header() http.Header { return r.header }
+
+func (r *apiGatewayResponse) Body() io.ReadCloser {
+	if r.body == nil {
+		return nil
+	}
+	return io.NopCloser(bytes.NewReader(r.body))
+}
+
+func (r *apiGatewayResponse) SetBody(body []byte) { r.body = body }
+
+func apiGatewayBody(body string, encoded bool) ([]byte, error) {
+	if body == "" {
+		return nil, nil
+	}
+	if encoded {
+		return base64.StdEncoding.DecodeString(body)
+	}
+	return []byte(body), nil
+}
+
+func apiGatewayHeader(single map[string]string, multi map[string][]string) http.Header {
+	header := make(http.Header, len(multi)+len(single))
+	for k, v := range multi {
+		for _, value := range v {
+			header.Add(k, value)
+		}
+	}
+	// single value headers are applied in a stable order, so duplicates differing only in case resolve the same way.
+	keys := make([]string, 0, len(single))
+	for k := range single {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, ok := header[http.CanonicalHeaderKey(k)]; !ok {
+			header.Set(k, single[k])
+		}
+	}
+	return header
+}
@@ -0,0 +1,94 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package message
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+)
+
+func TestNewAPIGatewayRequest(t *testing.T) {
+	event := `{
+  "resource": "/pets/{id}",
+  "path": "/pets/1",
+  "httpMethod": "POST",
+  "headers": {"host": "api.things.com", "content-type": "application/json", "X-Forwarded-Proto": "http"},
+  "multiValueHeaders": {"Content-Type": ["application/json"], "Cookie": ["session=abc"]},
+  "queryStringParameters": {"limit": "2", "single": "x"},
+  "multiValueQueryStringParameters": {"limit": ["1", "2"]},
+  "pathParameters": {"id": "1"},
+  "body": "{\"name\":\"fido\"}",
+  "isBase64Encoded": false
+}`
+	var proxy APIGatewayProxyRequest
+	require.NoError(t, json.Unmarshal([]byte(event), &proxy))
+
+	msg, err := NewAPIGatewayRequest(&proxy)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPost, msg.Method())
+	assert.Equal(t, "/pets/1", msg.URL().Path)
+	assert.Equal(t, "api.things.com", msg.URL().Host)
+	assert.Equal(t, "http", msg.URL().Scheme)
+	assert.Equal(t, []string{"1", "2"}, msg.URL().Query()["limit"])
+	assert.Equal(t, "x", msg.URL().Query().Get("single"))
+	assert.Equal(t, []string{"application/json"}, msg.Header().Values("Content-Type"))
+
+	c, err := msg.Cookie("session")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", c.Value)
+
+	// the body can be read more than once.
+	for i := 0; i < 2; i++ {
+		body, _ := io.ReadAll(msg.Body())
+		assert.Equal(t, `{"name":"fido"}`, string(body))
+	}
+}
+
+func TestNewAPIGatewayRequest_Base64(t *testing.T) {
+	msg, err := NewAPIGatewayRequest(&APIGatewayProxyRequest{
+		HTTPMethod:      http.MethodPut,
+		Path:            "/upload",
+		Body:            base64.StdEncoding.EncodeToString([]byte("binary")),
+		IsBase64Encoded: true,
+	})
+	require.NoError(t, err)
+	body, _ := io.ReadAll(msg.Body())
+	assert.Equal(t, "binary", string(body))
+	assert.Empty(t, msg.URL().Host)
+
+	_, err = NewAPIGatewayRequest(&APIGatewayProxyRequest{Body: "!!", IsBase64Encoded: true})
+	assert.Error(t, err)
+
+	msg, err = NewAPIGatewayRequest(&APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/"})
+	require.NoError(t, err)
+	assert.Nil(t, msg.Body())
+}
+
+func TestNewAPIGatewayResponse(t *testing.T) {
+	msg, err := NewAPIGatewayResponse(&APIGatewayProxyResponse{
+		StatusCode:      http.StatusOK,
+		Headers:         map[string]string{"content-type": "application/json"},
+		Body:            base64.StdEncoding.EncodeToString([]byte(`{"ok":true}`)),
+		IsBase64Encoded: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, msg.StatusCode())
+	assert.Equal(t, "application/json", msg.Header().Get("Content-Type"))
+	body, _ := io.ReadAll(msg.Body())
+	assert.Equal(t, `{"ok":true}`, string(body))
+
+	msg, err = NewAPIGatewayResponse(&APIGatewayProxyResponse{StatusCode: http.StatusNoContent})
+	require.NoError(t, err)
+	assert.Nil(t, msg.Body())
+
+	_, err = NewAPIGatewayResponse(&APIGatewayProxyResponse{Body: "!!", IsBase64Encoded: true})
+	assert.Error(t, err)
+}
@@ -0,0 +1,293 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+// Package message defines a transport-neutral view of HTTP requests and responses, so traffic that does not
+// arrive through net/http (serverless events, message queue envelopes, captured wire bytes) can be validated
+// without first being converted by the caller.
+//
+// Adapters are provided for net/http, AWS API Gateway proxy events and raw HTTP/1.1 messages.
+package message
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Request is the transport-neutral view of an HTTP request that the validators read from.
+type Request interface {
+	// Method returns the HTTP method of the request, for example GET.
+	Method() string
+
+	// URL returns the URL of the request. Validators read the escaped path, raw query and fragment.
+	URL() *url.URL
+
+	// Header returns the request headers. The map may be modified by validators that sanitize requests.
+	Header() http.Header
+
+	// Cookie returns the named cookie, or http.ErrNoCookie when it is not present.
+	Cookie(name string) (*http.Cookie, error)
+
+	// Body returns a reader for the request body, or nil when the request has no body.
+	Body() io.ReadCloser
+}
+
+// Response is the transport-neutral view of an HTTP response that the validators read from.
+type Response interface {
+	// StatusCode returns the HTTP status code of the response.
+	StatusCode() int
+
+	// Header returns the response headers.
+	Header() http.Header
+
+	// Body returns a reader for the response body, or nil when the response has no body.
+	Body() io.ReadCloser
+}
+
+// HTTPRequestProvider is implemented by Request adapters that are backed by a *http.Request, allowing the
+// validators to use it directly instead of building a new one.
+type HTTPRequestProvider interface {
+	HTTPRequest() *http.Request
+}
+
+// HTTPResponseProvider is implemented by Response adapters that are backed by a *http.Response, allowing the
+// validators to use it directly instead of building a new one.
+type HTTPResponseProvider interface {
+	HTTPResponse() *http.Response
+}
+
+// BodySetter is implemented by adapters whose body can be replaced. Validators read the body of a message, and
+// put it back through SetBody so it can be read again, or replace it with a sanitized body. Adapters that return
+// a new reader over the same bytes from every call to Body don't have to implement it.
+type BodySetter interface {
+	SetBody(body []byte)
+}
+
+type httpRequest struct {
+	request *http.Request
+	ctx     context.Context // set by WithContext
+}
+
+// FromHTTPRequest adapts a *http.Request to a Request. The adapter does not copy the request.
+func FromHTTPRequest(request *http.Request) Request {
+	return httpRequest{request: request}
+}
+
+func (r httpRequest) Method() string                           { return r.request.Method }
+func (r httpRequest) URL() *url.URL                            { return r.request.URL }
+func (r httpRequest) Header() http.Header                      { return r.request.Header }
+func (r httpRequest) Cookie(name string) (*http.Cookie, error) { return r.request.Cookie(name) }
+func (r httpRequest) Body() io.ReadCloser                      { return r.request.Body }
+func (r httpRequest) HTTPRequest() *http.Request               { return r.request }
+
+func (r httpRequest) SetBody(body []byte) {
+	r.request.Body = io.NopCloser(bytes.NewReader(body))
+	r.request.ContentLength = int64(len(body))
+	r.request.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+}
+
+type httpResponse struct {
+	response *http.Response
+}
+
+// FromHTTPResponse adapts a *http.Response to a Response. The adapter does not copy the response.
+func FromHTTPResponse(response *http.Response) Response {
+	return httpResponse{response: response}
+}
+
+func (r httpResponse) StatusCode() int              { return r.response.StatusCode }
+func (r httpResponse) Header() http.Header          { return r.response.Header }
+func (r httpResponse) Body() io.ReadCloser          { return r.response.Body }
+func (r httpResponse) HTTPResponse() *http.Response { return r.response }
+
+func (r httpResponse) SetBody(body []byte) {
+	r.response.Body = io.NopCloser(bytes.NewReader(body))
+	r.response.ContentLength = int64(len(body))
+}
+
+// contextRequest is a Request carrying a context set by WithContext.
+type contextRequest struct {
+	Request
+	ctx context.Context
+}
+
+// settableContextRequest is a contextRequest whose body can be replaced.
+type settableContextRequest struct {
+	contextRequest
+}
+
+func (r *settableContextRequest) SetBody(body []byte) { r.Request.(BodySetter).SetBody(body) }
+
+// WithContext returns a Request that carries ctx, which validators check between steps so a validation stops
+// early once ctx is done. A request only carries the context it is given here: the context of a *http.Request is
+// not checked, so a request is still validated in full after its handler has returned.
+func WithContext(ctx context.Context, request Request) Request {
+	switch r := request.(type) {
+	case httpRequest:
+		r.ctx = ctx
+		return r
+	case *contextRequest:
+		request = r.Request
+	case *settableContextRequest:
+		request = r.Request
+	}
+	if _, ok := request.(BodySetter); ok {
+		return &settableContextRequest{contextRequest{Request: request, ctx: ctx}}
+	}
+	return &contextRequest{Request: request, ctx: ctx}
+}
+
+// Context returns the context a request was given with WithContext, or nil when it has none.
+func Context(request Request) context.Context {
+	switch r := request.(type) {
+	case httpRequest:
+		return r.ctx
+	case *contextRequest:
+		return r.ctx
+	case *settableContextRequest:
+		return r.ctx
+	}
+	return nil
+}
+
+// Cookies returns every cookie of a request, parsed from its Cookie headers.
+func Cookies(request Request) []*http.Cookie {
+	if provider, ok := request.(HTTPRequestProvider); ok {
+		return provider.HTTPRequest().Cookies()
+	}
+	return (&http.Request{Header: request.Header()}).Cookies()
+}
+
+// ReadRequestBody reads the body of a request, and puts it back through SetBody so it can be read again. It
+// returns nil when the request has no body.
+func ReadRequestBody(request Request) []byte {
+	return readBody(request.Body, request)
+}
+
+// ReadResponseBody reads the body of a response, and puts it back through SetBody so it can be read again. It
+// returns nil when the response has no body.
+func ReadResponseBody(response Response) []byte {
+	return readBody(response.Body, response)
+}
+
+// ReadRequestBodyContext reads the body of a request like ReadRequestBody, but stops once ctx is done and returns
+// the error of ctx, leaving the body partly read. A read that is blocked when ctx is done finishes in the
+// background, after which the body is closed.
+func ReadRequestBodyContext(ctx context.Context, request Request) ([]byte, error) {
+	reader := request.Body()
+	if reader == nil || reader == http.NoBody {
+		return nil, ctx.Err()
+	}
+	read := make(chan []byte, 1)
+	go func() {
+		body := bytes.NewBuffer(make([]byte, 0, 512))
+		chunk := make([]byte, 32*1024)
+		for ctx.Err() == nil {
+			n, err := reader.Read(chunk)
+			body.Write(chunk[:n])
+			if err != nil {
+				break
+			}
+		}
+		_ = reader.Close()
+		read <- body.Bytes()
+	}()
+	select {
+	case body := <-read:
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if setter, ok := request.(BodySetter); ok {
+			setter.SetBody(body)
+		}
+		return body, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func readBody(open func() io.ReadCloser, message any) []byte {
+	reader := open()
+	if reader == nil || reader == http.NoBody {
+		return nil
+	}
+	body, _ := io.ReadAll(reader)
+	_ = reader.Close()
+	if setter, ok := message.(BodySetter); ok {
+		setter.SetBody(body)
+	}
+	return body
+}
+
+// ToHTTPRequest returns the *http.Request backing a Request when there is one, otherwise it builds a shallow
+// *http.Request that shares the URL, headers and body reader of the message. Nothing is copied, so changes
+// made by sanitizing validators to the headers or query are visible through the message.
+func ToHTTPRequest(request Request) *http.Request {
+	if request == nil {
+		return nil
+	}
+	if provider, ok := request.(HTTPRequestProvider); ok {
+		return provider.HTTPRequest()
+	}
+	u := request.URL()
+	if u == nil {
+		u = &url.URL{}
+	}
+	header := request.Header()
+	if header == nil {
+		header = make(http.Header)
+	}
+	body := request.Body()
+	if body == nil {
+		body = http.NoBody
+	}
+	return &http.Request{
+		Method:     request.Method(),
+		URL:        u,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header,
+		Body:       body,
+		Host:       u.Host,
+		RequestURI: u.RequestURI(),
+	}
+}
+
+// ToHTTPResponse returns the *http.Response backing a Response when there is one, otherwise it builds a shallow
+// *http.Response that shares the headers and body reader of the message.
+func ToHTTPResponse(response Response) *http.Response {
+	if response == nil {
+		return nil
+	}
+	if provider, ok := response.(HTTPResponseProvider); ok {
+		return provider.HTTPResponse()
+	}
+	header := response.Header()
+	if header == nil {
+		header = make(http.Header)
+	}
+	body := response.Body()
+	if body == nil {
+		body = http.NoBody
+	}
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", response.StatusCode(), http.StatusText(response.StatusCode())),
+		StatusCode: response.StatusCode(),
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header,
+		Body:       body,
+	}
+}
+
+// cookie reads the named cookie from a set of request headers.
+func cookie(header http.Header, name string) (*http.Cookie, error) {
+	return (&http.Request{Header: header}).Cookie(name)
+}
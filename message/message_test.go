@@ -0,0 +1,197 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package message
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+)
+
+type testRequest struct {
+	method string
+	url    *url.URL
+	header http.Header
+	body   string
+}
+
+func (r *testRequest) Method() string                           { return r.method }
+func (r *testRequest) URL() *url.URL                            { return r.url }
+func (r *testRequest) Header() http.Header                      { return r.header }
+func (r *testRequest) Cookie(name string) (*http.Cookie, error) { return cookie(r.header, name) }
+func (r *testRequest) Body() io.ReadCloser {
+	if r.body == "" {
+		return nil
+	}
+	return io.NopCloser(strings.NewReader(r.body))
+}
+
+type testResponse struct {
+	status int
+	header http.Header
+}
+
+func (r *testResponse) StatusCode() int     { return r.status }
+func (r *testResponse) Header() http.Header { return r.header }
+func (r *testResponse) Body() io.ReadCloser { return nil }
+
+func TestFromHTTPRequest(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/pets?limit=1", strings.NewReader("{}"))
+	request.Header.Set("X-Thing", "yes")
+	request.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+
+	msg := FromHTTPRequest(request)
+	assert.Equal(t, http.MethodPost, msg.Method())
+	assert.Equal(t, "/pets", msg.URL().Path)
+	assert.Equal(t, "yes", msg.Header().Get("X-Thing"))
+	c, err := msg.Cookie("session")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", c.Value)
+	assert.Same(t, request, ToHTTPRequest(msg))
+}
+
+func TestFromHTTPResponse(t *testing.T) {
+	response := &http.Response{StatusCode: http.StatusCreated, Header: http.Header{"A": {"b"}}, Body: http.NoBody}
+
+	msg := FromHTTPResponse(response)
+	assert.Equal(t, http.StatusCreated, msg.StatusCode())
+	assert.Equal(t, "b", msg.Header().Get("A"))
+	assert.Equal(t, http.NoBody, msg.Body())
+	assert.Same(t, response, ToHTTPResponse(msg))
+}
+
+func TestToHTTPRequest_Shallow(t *testing.T) {
+	u, _ := url.Parse("https://things.com/pets?limit=1")
+	msg := &testRequest{method: http.MethodPut, url: u, header: http.Header{"Cookie": {"a=b"}}, body: "hello"}
+
+	request := ToHTTPRequest(msg)
+	assert.Equal(t, http.MethodPut, request.Method)
+	assert.Same(t, u, request.URL)
+	assert.Equal(t, "things.com", request.Host)
+	assert.Equal(t, "/pets?limit=1", request.RequestURI)
+	body, _ := io.ReadAll(request.Body)
+	assert.Equal(t, "hello", string(body))
+
+	// headers are shared, not copied.
+	request.Header.Set("X-Added", "1")
+	assert.Equal(t, "1", msg.Header().Get("X-Added"))
+
+	c, err := msg.Cookie("a")
+	require.NoError(t, err)
+	assert.Equal(t, "b", c.Value)
+
+	request = ToHTTPRequest(&testRequest{method: http.MethodGet})
+	assert.NotNil(t, request.URL)
+	assert.NotNil(t, request.Header)
+	assert.Equal(t, http.NoBody, request.Body)
+
+	assert.Nil(t, ToHTTPRequest(nil))
+}
+
+func TestToHTTPResponse_Shallow(t *testing.T) {
+	response := ToHTTPResponse(&testResponse{status: http.StatusNotFound})
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Equal(t, "404 Not Found", response.Status)
+	assert.NotNil(t, response.Header)
+	assert.Equal(t, http.NoBody, response.Body)
+
+	assert.Nil(t, ToHTTPResponse(nil))
+}
+
+func TestWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the context of a *http.Request is not the context of its message.
+	httpRequest, _ := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.com/pets", strings.NewReader("{}"))
+	request := FromHTTPRequest(httpRequest)
+	assert.Nil(t, Context(request))
+
+	withContext := WithContext(ctx, request)
+	assert.Equal(t, ctx, Context(withContext))
+	provider, ok := withContext.(HTTPRequestProvider)
+	require.True(t, ok)
+	assert.Same(t, httpRequest, provider.HTTPRequest())
+
+	// other messages keep the body setter of the message they wrap.
+	gateway, err := NewAPIGatewayRequest(&APIGatewayProxyRequest{HTTPMethod: http.MethodPost, Path: "/pets", Body: "{}"})
+	require.NoError(t, err)
+	withContext = WithContext(ctx, gateway)
+	assert.Equal(t, ctx, Context(withContext))
+	setter, ok := withContext.(BodySetter)
+	require.True(t, ok)
+	setter.SetBody([]byte(`{"name":"fido"}`))
+	body, _ := io.ReadAll(gateway.Body())
+	assert.Equal(t, `{"name":"fido"}`, string(body))
+
+	plain := &testRequest{method: http.MethodGet, url: &url.URL{Path: "/pets"}}
+	withContext = WithContext(ctx, plain)
+	_, ok = withContext.(BodySetter)
+	assert.False(t, ok)
+	assert.Equal(t, http.MethodGet, withContext.Method())
+
+	// wrapping again replaces the context rather than nesting.
+	other := context.Background()
+	rewrapped := WithContext(other, withContext)
+	assert.Equal(t, other, Context(rewrapped))
+	assert.Same(t, plain, rewrapped.(*contextRequest).Request)
+	assert.Nil(t, Context(plain))
+}
+
+// slowBody returns one byte of its body per read, waiting delay before each.
+type slowBody struct {
+	body  []byte
+	delay time.Duration
+}
+
+func (b *slowBody) Read(p []byte) (int, error) {
+	if len(b.body) == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(b.delay)
+	p[0], b.body = b.body[0], b.body[1:]
+	return 1, nil
+}
+
+func (b *slowBody) Close() error { return nil }
+
+func TestReadRequestBodyContext(t *testing.T) {
+	httpRequest, _ := http.NewRequest(http.MethodPost, "https://api.com/pets", strings.NewReader(`{"name":"fido"}`))
+	body, err := ReadRequestBodyContext(context.Background(), FromHTTPRequest(httpRequest))
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"fido"}`, string(body))
+
+	// the body is put back, so it can be read again.
+	again, _ := io.ReadAll(httpRequest.Body)
+	assert.Equal(t, body, again)
+
+	// an empty body is not a missing one.
+	httpRequest, _ = http.NewRequest(http.MethodPost, "https://api.com/pets", nil)
+	httpRequest.Body = io.NopCloser(strings.NewReader(""))
+	body, err = ReadRequestBodyContext(context.Background(), FromHTTPRequest(httpRequest))
+	require.NoError(t, err)
+	assert.NotNil(t, body)
+	httpRequest, _ = http.NewRequest(http.MethodGet, "https://api.com/pets", nil)
+	body, err = ReadRequestBodyContext(context.Background(), FromHTTPRequest(httpRequest))
+	require.NoError(t, err)
+	assert.Nil(t, body)
+
+	// reading stops at the deadline, rather than once the body has been sent.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	httpRequest, _ = http.NewRequest(http.MethodPost, "https://api.com/pets", nil)
+	httpRequest.Body = &slowBody{body: []byte(strings.Repeat("a", 1000)), delay: 10 * time.Millisecond}
+	start := time.Now()
+	body, err = ReadRequestBodyContext(ctx, FromHTTPRequest(httpRequest))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, body)
+	assert.Less(t, time.Since(start), time.Second)
+}
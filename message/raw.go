@@ -0,0 +1,33 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package message
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+)
+
+// ParseRawRequest parses the bytes of an HTTP/1.1 request message, such as one captured from the wire or
+// carried in a message queue envelope. The URL host is taken from the Host header.
+func ParseRawRequest(raw []byte) (Request, error) {
+	request, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(raw)))
+	if err != nil {
+		return nil, err
+	}
+	if request.URL.Host == "" {
+		request.URL.Host = request.Host
+	}
+	return FromHTTPRequest(request), nil
+}
+
+// ParseRawResponse parses the bytes of an HTTP/1.1 response message. The request the response answers is
+// optional, it is used to interpret responses to HEAD requests, which have no body.
+func ParseRawResponse(raw []byte, request Request) (Response, error) {
+	response, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(raw)), ToHTTPRequest(request))
+	if err != nil {
+		return nil, err
+	}
+	return FromHTTPResponse(response), nil
+}
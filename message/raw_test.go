@@ -0,0 +1,59 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package message
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+)
+
+func TestParseRawRequest(t *testing.T) {
+	raw := "POST /pets?limit=1 HTTP/1.1\r\n" +
+		"Host: things.com\r\n" +
+		"Content-Type: application/json\r\n" +
+		"Content-Length: 15\r\n" +
+		"\r\n" +
+		`{"name":"fido"}`
+
+	msg, err := ParseRawRequest([]byte(raw))
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, msg.Method())
+	assert.Equal(t, "/pets", msg.URL().Path)
+	assert.Equal(t, "things.com", msg.URL().Host)
+	assert.Equal(t, "1", msg.URL().Query().Get("limit"))
+	assert.Equal(t, "application/json", msg.Header().Get("Content-Type"))
+	body, _ := io.ReadAll(msg.Body())
+	assert.Equal(t, `{"name":"fido"}`, string(body))
+
+	_, err = ParseRawRequest([]byte("not http"))
+	assert.Error(t, err)
+}
+
+func TestParseRawResponse(t *testing.T) {
+	raw := "HTTP/1.1 201 Created\r\n" +
+		"Content-Type: application/json\r\n" +
+		"Content-Length: 11\r\n" +
+		"\r\n" +
+		`{"ok":true}`
+
+	msg, err := ParseRawResponse([]byte(raw), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, msg.StatusCode())
+	assert.Equal(t, "application/json", msg.Header().Get("Content-Type"))
+	body, _ := io.ReadAll(msg.Body())
+	assert.Equal(t, `{"ok":true}`, string(body))
+
+	request, _ := http.NewRequest(http.MethodHead, "https://things.com/pets", nil)
+	msg, err = ParseRawResponse([]byte(raw), FromHTTPRequest(request))
+	require.NoError(t, err)
+	body, _ = io.ReadAll(msg.Body())
+	assert.Empty(t, body)
+
+	_, err = ParseRawResponse([]byte("nope"), nil)
+	assert.Error(t, err)
+}
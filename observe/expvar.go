@@ -0,0 +1,127 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package observe
+
+import (
+	"expvar"
+	"sync"
+
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+var _ config.Observer = (*Expvar)(nil)
+
+// Expvar is a config.Observer that counts validation events in an expvar.Map, so they are served as JSON by the
+// /debug/vars handler of expvar. The map holds:
+//
+//	paths        matched, notFound
+//	phases       for each phase: count, failed, errors, nanoseconds
+//	operations   for each operation, by operationId or 'METHOD /path': the same counters as phases, by phase
+//	errors       for each error code, 'validationType/validationSubType': count
+//	schemaCache  hits, misses
+//	schemas      compiled, failed, nanoseconds
+type Expvar struct {
+	vars        *expvar.Map
+	paths       *expvar.Map
+	phases      *expvar.Map
+	operations  *expvar.Map
+	errors      *expvar.Map
+	schemaCache *expvar.Map
+	schemas     *expvar.Map
+}
+
+// children creates the published maps, and the maps of phases and operations in them, which observers
+// published under the same name share.
+var children sync.Mutex
+
+// NewExpvar creates an Expvar observer that publishes its counters under name. Observers created with the same
+// name share their counters. It panics when name is published by expvar as something other than a map.
+func NewExpvar(name string) *Expvar {
+	children.Lock()
+	vars, ok := expvar.Get(name).(*expvar.Map)
+	if !ok {
+		vars = expvar.NewMap(name)
+	}
+	children.Unlock()
+	e := &Expvar{vars: vars}
+	e.paths = e.child(vars, "paths")
+	e.phases = e.child(vars, "phases")
+	e.operations = e.child(vars, "operations")
+	e.errors = e.child(vars, "errors")
+	e.schemaCache = e.child(vars, "schemaCache")
+	e.schemas = e.child(vars, "schemas")
+	return e
+}
+
+// Map returns the map the counters are published in.
+func (e *Expvar) Map() *expvar.Map {
+	return e.vars
+}
+
+func (e *Expvar) PathMatched(config.PathEvent) {
+	e.paths.Add("matched", 1)
+}
+
+func (e *Expvar) PathNotFound(config.PathEvent) {
+	e.paths.Add("notFound", 1)
+}
+
+func (e *Expvar) PhaseStarted(config.PhaseEvent) {}
+
+func (e *Expvar) PhaseFinished(event config.PhaseEvent) {
+	count := func(counters *expvar.Map) {
+		counters.Add("count", 1)
+		counters.Add("nanoseconds", event.Duration.Nanoseconds())
+		counters.Add("errors", int64(event.Errors))
+		if !event.Valid {
+			counters.Add("failed", 1)
+		}
+	}
+	count(e.child(e.phases, event.Phase))
+	count(e.child(e.child(e.operations, operation(event.OperationID, event.Method, event.PathTemplate)), event.Phase))
+}
+
+func (e *Expvar) SchemaCacheHit(config.SchemaCacheEvent) {
+	e.schemaCache.Add("hits", 1)
+}
+
+func (e *Expvar) SchemaCacheMiss(config.SchemaCacheEvent) {
+	e.schemaCache.Add("misses", 1)
+}
+
+func (e *Expvar) SchemaCompiled(event config.SchemaCompiledEvent) {
+	if event.Err != nil {
+		e.schemas.Add("failed", 1)
+		return
+	}
+	e.schemas.Add("compiled", 1)
+	e.schemas.Add("nanoseconds", event.Duration.Nanoseconds())
+}
+
+func (e *Expvar) ErrorEmitted(event config.ErrorEvent) {
+	e.errors.Add(event.ValidationType+"/"+event.ValidationSubType, 1)
+}
+
+// child returns the map under key in parent, creating it the first time.
+func (e *Expvar) child(parent *expvar.Map, key string) *expvar.Map {
+	if child, ok := parent.Get(key).(*expvar.Map); ok {
+		return child
+	}
+	children.Lock()
+	defer children.Unlock()
+	if child, ok := parent.Get(key).(*expvar.Map); ok {
+		return child
+	}
+	child := new(expvar.Map).Init()
+	parent.Set(key, child)
+	return child
+}
+
+// operation names an operation by its operationId, or by its method and path when it has none.
+func operation(operationID, method, pathTemplate string) string {
+	if operationID != "" {
+		return operationID
+	}
+	return method + " " + pathTemplate
+}
@@ -0,0 +1,47 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package observe
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+func TestExpvar(t *testing.T) {
+	e := NewExpvar("observe_test")
+	e.PathMatched(config.PathEvent{Method: "GET", RequestPath: "/pets/1", PathTemplate: "/pets/{id}"})
+	e.PathNotFound(config.PathEvent{Method: "GET", RequestPath: "/nope"})
+	e.PhaseStarted(config.PhaseEvent{Phase: "queryParams"})
+	e.PhaseFinished(config.PhaseEvent{Phase: "queryParams", OperationID: "getPet", Duration: 3 * time.Millisecond, Valid: true})
+	e.PhaseFinished(config.PhaseEvent{Phase: "queryParams", Method: "POST", PathTemplate: "/pets", Duration: time.Millisecond, Errors: 2})
+	e.ErrorEmitted(config.ErrorEvent{ValidationType: "query", ValidationSubType: "missing"})
+	e.ErrorEmitted(config.ErrorEvent{ValidationType: "query", ValidationSubType: "missing"})
+	e.SchemaCacheHit(config.SchemaCacheEvent{})
+	e.SchemaCacheMiss(config.SchemaCacheEvent{})
+	e.SchemaCompiled(config.SchemaCompiledEvent{Duration: time.Millisecond})
+	e.SchemaCompiled(config.SchemaCompiledEvent{Err: errors.New("bad schema")})
+
+	var published map[string]map[string]any
+	require.NoError(t, json.Unmarshal([]byte(e.Map().String()), &published))
+	assert.Equal(t, map[string]any{"matched": 1.0, "notFound": 1.0}, published["paths"])
+	assert.Equal(t, map[string]any{"count": 2.0, "failed": 1.0, "errors": 2.0, "nanoseconds": 4e6}, published["phases"]["queryParams"])
+	assert.Equal(t, map[string]any{"count": 1.0, "errors": 0.0, "nanoseconds": 3e6},
+		published["operations"]["getPet"].(map[string]any)["queryParams"])
+	assert.Contains(t, published["operations"], "POST /pets")
+	assert.Equal(t, map[string]any{"query/missing": 2.0}, published["errors"])
+	assert.Equal(t, map[string]any{"hits": 1.0, "misses": 1.0}, published["schemaCache"])
+	assert.Equal(t, map[string]any{"compiled": 1.0, "failed": 1.0, "nanoseconds": 1e6}, published["schemas"])
+
+	// observers with the same name share their counters.
+	NewExpvar("observe_test").PathMatched(config.PathEvent{})
+	require.NoError(t, json.Unmarshal([]byte(e.Map().String()), &published))
+	assert.Equal(t, 2.0, published["paths"]["matched"])
+}
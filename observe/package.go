@@ -0,0 +1,6 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+// Package observe contains config.Observer implementations, to pass to config.WithObserver. Expvar counts
+// validation events and publishes them through expvar, Slog logs them with slog.
+package observe
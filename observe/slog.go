@@ -0,0 +1,107 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package observe
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+var _ config.Observer = (*Slog)(nil)
+
+// Slog is a config.Observer that logs validation events with slog. Validation errors are logged at info level,
+// schemas that fail to compile at warn level, and every other event at debug level.
+type Slog struct {
+	logger  *slog.Logger
+	options *config.ValidationOptions
+}
+
+// NewSlog creates a Slog observer that logs to logger.
+func NewSlog(logger *slog.Logger) *Slog {
+	return &Slog{logger: logger}
+}
+
+// WithSlog returns an Option that sets a Slog observer logging to the logger set with config.WithLogger, in
+// whichever order the two options are given. Without a logger, nothing is logged.
+func WithSlog() config.Option {
+	return func(o *config.ValidationOptions) {
+		o.Observer = &Slog{options: o}
+	}
+}
+
+func (s *Slog) PathMatched(event config.PathEvent) {
+	s.log(slog.LevelDebug, "path matched",
+		slog.String("method", event.Method),
+		slog.String("requestPath", event.RequestPath),
+		slog.String("pathTemplate", event.PathTemplate))
+}
+
+func (s *Slog) PathNotFound(event config.PathEvent) {
+	s.log(slog.LevelDebug, "path not found",
+		slog.String("method", event.Method),
+		slog.String("requestPath", event.RequestPath),
+		slog.String("pathTemplate", event.PathTemplate))
+}
+
+func (s *Slog) PhaseStarted(event config.PhaseEvent) {
+	s.log(slog.LevelDebug, "validation phase started",
+		slog.String("phase", event.Phase),
+		slog.String("operation", operation(event.OperationID, event.Method, event.PathTemplate)))
+}
+
+func (s *Slog) PhaseFinished(event config.PhaseEvent) {
+	s.log(slog.LevelDebug, "validation phase finished",
+		slog.String("phase", event.Phase),
+		slog.String("operation", operation(event.OperationID, event.Method, event.PathTemplate)),
+		slog.Duration("duration", event.Duration),
+		slog.Bool("valid", event.Valid),
+		slog.Int("errors", event.Errors))
+}
+
+func (s *Slog) SchemaCacheHit(event config.SchemaCacheEvent) {
+	s.log(slog.LevelDebug, "schema cache hit",
+		slog.Uint64("key", event.Key),
+		slog.String("location", event.Location))
+}
+
+func (s *Slog) SchemaCacheMiss(event config.SchemaCacheEvent) {
+	s.log(slog.LevelDebug, "schema cache miss",
+		slog.Uint64("key", event.Key),
+		slog.String("location", event.Location))
+}
+
+func (s *Slog) SchemaCompiled(event config.SchemaCompiledEvent) {
+	if event.Err != nil {
+		s.log(slog.LevelWarn, "schema failed to compile",
+			slog.String("location", event.Location),
+			slog.Duration("duration", event.Duration),
+			slog.Any("error", event.Err))
+		return
+	}
+	s.log(slog.LevelDebug, "schema compiled",
+		slog.String("location", event.Location),
+		slog.Duration("duration", event.Duration))
+}
+
+func (s *Slog) ErrorEmitted(event config.ErrorEvent) {
+	s.log(slog.LevelInfo, "validation error",
+		slog.String("phase", event.Phase),
+		slog.String("operation", operation(event.OperationID, event.Method, event.PathTemplate)),
+		slog.String("validationType", event.ValidationType),
+		slog.String("validationSubType", event.ValidationSubType),
+		slog.Any("error", event.Err))
+}
+
+func (s *Slog) log(level slog.Level, message string, attrs ...slog.Attr) {
+	logger := s.logger
+	if logger == nil && s.options != nil {
+		logger = s.options.Logger
+	}
+	if logger == nil || !logger.Enabled(context.Background(), level) {
+		return
+	}
+	logger.LogAttrs(context.Background(), level, message, attrs...)
+}
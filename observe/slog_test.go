@@ -0,0 +1,54 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package observe
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/pb33f/testify/assert"
+
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+func TestSlog(t *testing.T) {
+	var logged bytes.Buffer
+	s := NewSlog(slog.New(slog.NewTextHandler(&logged, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	s.PathMatched(config.PathEvent{Method: "GET", RequestPath: "/pets/1"})
+	s.PhaseStarted(config.PhaseEvent{Phase: "queryParams"})
+	s.PhaseFinished(config.PhaseEvent{Phase: "queryParams"})
+	s.SchemaCacheHit(config.SchemaCacheEvent{})
+	s.SchemaCompiled(config.SchemaCompiledEvent{Location: "requestBody"})
+	// debug events are below the level of the logger.
+	assert.Empty(t, logged.String())
+
+	s.ErrorEmitted(config.ErrorEvent{
+		Phase:             "queryParams",
+		OperationID:       "getPet",
+		ValidationType:    "query",
+		ValidationSubType: "missing",
+		Err:               errors.New("query parameter 'limit' is missing"),
+	})
+	s.SchemaCompiled(config.SchemaCompiledEvent{Location: "requestBody", Err: errors.New("bad schema")})
+	assert.Contains(t, logged.String(), `level=INFO msg="validation error" phase=queryParams operation=getPet`)
+	assert.Contains(t, logged.String(), `error="query parameter 'limit' is missing"`)
+	assert.Contains(t, logged.String(), `level=WARN msg="schema failed to compile" location=requestBody`)
+}
+
+func TestWithSlog(t *testing.T) {
+	var logged bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logged, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	// the logger is found in the options, whichever order they are given in.
+	opts := config.NewValidationOptions(WithSlog(), config.WithLogger(logger))
+	opts.Observer.PathNotFound(config.PathEvent{Method: "GET", RequestPath: "/nope"})
+	assert.Contains(t, logged.String(), `level=DEBUG msg="path not found" method=GET requestPath=/nope`)
+
+	// without a logger nothing is logged.
+	config.NewValidationOptions(WithSlog()).Observer.PathNotFound(config.PathEvent{})
+	NewSlog(nil).PathMatched(config.PathEvent{})
+}
@@ -0,0 +1,74 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"time"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+// phaseObservation reports a phase of validation to the observer of the options. Without an observer it is
+// empty, and reports nothing.
+type phaseObservation struct {
+	observer config.Observer
+	event    config.PhaseEvent
+	start    time.Time
+}
+
+// observePhase reports the start of a phase, and returns the observation to finish once it has run.
+func observePhase(
+	options *config.ValidationOptions,
+	phase Phase,
+	request message.Request,
+	pathItem *v3.PathItem,
+	pathValue string,
+) phaseObservation {
+	if options == nil || options.Observer == nil {
+		return phaseObservation{}
+	}
+	event := config.PhaseEvent{Phase: string(phase), Method: request.Method(), PathTemplate: pathValue}
+	if pathItem != nil {
+		if operation := helpers.OperationForMethod(request.Method(), pathItem); operation != nil {
+			event.OperationID = operation.OperationId
+		}
+	}
+	return observe(options, event)
+}
+
+// observe reports the start of a phase described by event.
+func observe(options *config.ValidationOptions, event config.PhaseEvent) phaseObservation {
+	if options == nil || options.Observer == nil {
+		return phaseObservation{}
+	}
+	options.Observer.PhaseStarted(event)
+	return phaseObservation{observer: options.Observer, event: event, start: time.Now()}
+}
+
+// finish reports the end of the phase, and every error it found.
+func (o phaseObservation) finish(found []*errors.ValidationError) {
+	if o.observer == nil {
+		return
+	}
+	o.event.Duration = time.Since(o.start)
+	o.event.Valid = len(found) == 0
+	o.event.Errors = len(found)
+	o.observer.PhaseFinished(o.event)
+	for _, validationError := range found {
+		o.observer.ErrorEmitted(config.ErrorEvent{
+			Phase:             o.event.Phase,
+			Method:            o.event.Method,
+			PathTemplate:      o.event.PathTemplate,
+			OperationID:       o.event.OperationID,
+			ValidationType:    validationError.ValidationType,
+			ValidationSubType: validationError.ValidationSubType,
+			Err:               validationError,
+		})
+	}
+}
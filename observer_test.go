@@ -0,0 +1,227 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+const observerSpec = `openapi: 3.1.0
+info:
+  title: Observer
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      operationId: createPet
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+      responses:
+        '201':
+          description: created
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id:
+                    type: integer`
+
+type recordingObserver struct {
+	mu       sync.Mutex
+	matched  []config.PathEvent
+	notFound []config.PathEvent
+	started  []config.PhaseEvent
+	finished []config.PhaseEvent
+	hits     []config.SchemaCacheEvent
+	misses   []config.SchemaCacheEvent
+	compiled []config.SchemaCompiledEvent
+	emitted  []config.ErrorEvent
+}
+
+func (r *recordingObserver) record(record func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record()
+}
+
+func (r *recordingObserver) PathMatched(e config.PathEvent) {
+	r.record(func() { r.matched = append(r.matched, e) })
+}
+
+func (r *recordingObserver) PathNotFound(e config.PathEvent) {
+	r.record(func() { r.notFound = append(r.notFound, e) })
+}
+
+func (r *recordingObserver) PhaseStarted(e config.PhaseEvent) {
+	r.record(func() { r.started = append(r.started, e) })
+}
+
+func (r *recordingObserver) PhaseFinished(e config.PhaseEvent) {
+	r.record(func() { r.finished = append(r.finished, e) })
+}
+
+func (r *recordingObserver) SchemaCacheHit(e config.SchemaCacheEvent) {
+	r.record(func() { r.hits = append(r.hits, e) })
+}
+
+func (r *recordingObserver) SchemaCacheMiss(e config.SchemaCacheEvent) {
+	r.record(func() { r.misses = append(r.misses, e) })
+}
+
+func (r *recordingObserver) SchemaCompiled(e config.SchemaCompiledEvent) {
+	r.record(func() { r.compiled = append(r.compiled, e) })
+}
+
+func (r *recordingObserver) ErrorEmitted(e config.ErrorEvent) {
+	r.record(func() { r.emitted = append(r.emitted, e) })
+}
+
+func (r *recordingObserver) reset() {
+	r.record(func() {
+		r.matched, r.notFound, r.started, r.finished = nil, nil, nil, nil
+		r.hits, r.misses, r.compiled, r.emitted = nil, nil, nil, nil
+	})
+}
+
+func (r *recordingObserver) phases() map[string]config.PhaseEvent {
+	phases := make(map[string]config.PhaseEvent)
+	for _, event := range r.finished {
+		phases[event.Phase] = event
+	}
+	return phases
+}
+
+func TestObserver_Request(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(observerSpec))
+	require.NoError(t, err)
+	observer := &recordingObserver{}
+	v, errs := NewValidator(doc, config.WithObserver(observer))
+	require.Empty(t, errs)
+
+	// warming compiles the schemas of the document.
+	assert.NotEmpty(t, observer.compiled)
+	for _, compiled := range observer.compiled {
+		assert.NoError(t, compiled.Err)
+	}
+
+	newRequest := func() *http.Request {
+		request, _ := http.NewRequest(http.MethodPost, "https://api.com/pets?limit=ten", bytes.NewBufferString(`{}`))
+		request.Header.Set("Content-Type", "application/json")
+		return request
+	}
+
+	validations := map[string]func(*http.Request) bool{
+		"sync": func(request *http.Request) bool {
+			valid, _ := v.ValidateHttpRequestSync(request)
+			return valid
+		},
+		"concurrent": func(request *http.Request) bool {
+			valid, _ := v.ValidateHttpRequest(request)
+			return valid
+		},
+		"result": func(request *http.Request) bool {
+			return v.(ResultValidator).ValidateHttpRequestResult(request).Valid
+		},
+	}
+	for name, validate := range validations {
+		t.Run(name, func(t *testing.T) {
+			observer.reset()
+			assert.False(t, validate(newRequest()))
+
+			require.Len(t, observer.matched, 1)
+			assert.Equal(t, config.PathEvent{Method: http.MethodPost, RequestPath: "/pets", PathTemplate: "/pets"}, observer.matched[0])
+			assert.Len(t, observer.started, len(observer.finished))
+
+			phases := observer.phases()
+			assert.Contains(t, phases, string(PhasePathParams))
+			query := phases[string(PhaseQueryParams)]
+			assert.Equal(t, "createPet", query.OperationID)
+			assert.False(t, query.Valid)
+			assert.Equal(t, 1, query.Errors)
+			body := phases[string(PhaseRequestBody)]
+			assert.False(t, body.Valid)
+			assert.Equal(t, "/pets", body.PathTemplate)
+
+			require.Len(t, observer.emitted, 2)
+			for _, emitted := range observer.emitted {
+				assert.Equal(t, "createPet", emitted.OperationID)
+				assert.Error(t, emitted.Err)
+			}
+			codes := []string{
+				observer.emitted[0].Phase + " " + observer.emitted[0].ValidationType,
+				observer.emitted[1].Phase + " " + observer.emitted[1].ValidationType,
+			}
+			assert.ElementsMatch(t, []string{
+				string(PhaseQueryParams) + " " + helpers.ParameterValidation,
+				string(PhaseRequestBody) + " " + helpers.RequestBodyValidation,
+			}, codes)
+
+			// the schemas were compiled while warming, and are found without compiling them again.
+			assert.NotEmpty(t, observer.hits)
+			assert.Empty(t, observer.misses)
+			assert.Empty(t, observer.compiled)
+		})
+	}
+
+	observer.reset()
+	request, _ := http.NewRequest(http.MethodGet, "https://api.com/cats", nil)
+	valid, _ := v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	assert.Equal(t, []config.PathEvent{{Method: http.MethodGet, RequestPath: "/cats"}}, observer.notFound)
+	assert.Empty(t, observer.started)
+}
+
+func TestObserver_Response(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(observerSpec))
+	require.NoError(t, err)
+	observer := &recordingObserver{}
+	v, errs := NewValidator(doc, config.WithObserver(observer), config.WithSchemaCache(nil))
+	require.Empty(t, errs)
+	assert.Empty(t, observer.compiled)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://api.com/pets", nil)
+	response := &http.Response{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewBufferString(`{"id":"one"}`)),
+	}
+	valid, _ := v.ValidateHttpResponse(request, response)
+	assert.False(t, valid)
+
+	phase := observer.phases()[string(PhaseResponseBody)]
+	assert.Equal(t, "createPet", phase.OperationID)
+	assert.False(t, phase.Valid)
+	require.Len(t, observer.emitted, 1)
+	assert.Equal(t, string(PhaseResponseBody), observer.emitted[0].Phase)
+	assert.Equal(t, helpers.ResponseBodyValidation, observer.emitted[0].ValidationType)
+
+	// without a schema cache, the schema is compiled on demand.
+	require.NotEmpty(t, observer.compiled)
+	assert.Equal(t, "responseBody", observer.compiled[0].Location)
+}
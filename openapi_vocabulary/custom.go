@@ -0,0 +1,105 @@
+// Copyright 2026 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package openapi_vocabulary
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/message"
+)
+
+// CustomVocabularyURL is the vocabulary URL for user registered keywords
+const CustomVocabularyURL = "https://pb33f.io/openapi-validator/vocabulary/custom"
+
+// KeywordCompileFunc compiles a custom keyword. value is the value of the keyword in the schema, and schema is
+// the whole schema object the keyword was found in, so sibling keywords can be read.
+//
+// Return nil to skip the keyword for this schema. The returned SchemaExt is given the instance the schema
+// validates, so keywords placed on an object schema can implement cross-field rules. Report failures with
+// ctx.AddError and a *KeywordError, or use KeywordValidator.
+type KeywordCompileFunc func(ctx *jsonschema.CompilerContext, value any, schema map[string]any) (jsonschema.SchemaExt, error)
+
+// KeywordError is the failure of a custom keyword. It's reported as a normal schema validation failure,
+// with the keyword location pointing at the keyword.
+type KeywordError struct {
+	Keyword string
+	Message string
+}
+
+func (e *KeywordError) KeywordPath() []string {
+	return []string{e.Keyword}
+}
+
+func (e *KeywordError) LocalizedString(_ *message.Printer) string {
+	return e.Message
+}
+
+func (e *KeywordError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Keyword, e.Message)
+}
+
+// KeywordValidator creates a SchemaExt that validates instances with validate. An error returned by validate
+// is reported as a KeywordError for keyword.
+func KeywordValidator(keyword string, validate func(instance any) error) jsonschema.SchemaExt {
+	return &keywordValidator{keyword: keyword, validate: validate}
+}
+
+type keywordValidator struct {
+	keyword  string
+	validate func(instance any) error
+}
+
+func (k *keywordValidator) Validate(ctx *jsonschema.ValidatorContext, v any) {
+	if err := k.validate(v); err != nil {
+		ctx.AddError(&KeywordError{Keyword: k.keyword, Message: err.Error()})
+	}
+}
+
+// NewCustomVocabulary creates a vocabulary for user registered keywords, keyed by keyword name.
+func NewCustomVocabulary(keywords map[string]KeywordCompileFunc) *jsonschema.Vocabulary {
+	names := make([]string, 0, len(keywords))
+	for name := range keywords {
+		names = append(names, name)
+	}
+	// compile keywords in a stable order, so errors are reported in a stable order.
+	sort.Strings(names)
+
+	return &jsonschema.Vocabulary{
+		URL:    CustomVocabularyURL,
+		Schema: nil, // custom keywords validate their own values when compiled
+		Compile: func(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+			return compileCustomKeywords(ctx, obj, names, keywords)
+		},
+	}
+}
+
+// compileCustomKeywords compiles all registered keywords found in the schema object
+func compileCustomKeywords(ctx *jsonschema.CompilerContext,
+	obj map[string]any,
+	names []string,
+	keywords map[string]KeywordCompileFunc,
+) (jsonschema.SchemaExt, error) {
+	var extensions []jsonschema.SchemaExt
+	for _, name := range names {
+		value, exists := obj[name]
+		compile := keywords[name]
+		if !exists || compile == nil {
+			continue
+		}
+		ext, err := compile(ctx, value, obj)
+		if err != nil {
+			return nil, &OpenAPIKeywordError{Keyword: name, Message: err.Error()}
+		}
+		if ext != nil {
+			extensions = append(extensions, ext)
+		}
+	}
+
+	if len(extensions) == 0 {
+		return nil, nil
+	}
+	return &combinedExtension{extensions: extensions}, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package openapi_vocabulary
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/message"
+)
+
+func compileCustom(t *testing.T, schemaJSON string, keywords map[string]KeywordCompileFunc) (*jsonschema.Schema, error) {
+	t.Helper()
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(schemaJSON))
+	require.NoError(t, err)
+
+	compiler := jsonschema.NewCompiler()
+	compiler.RegisterVocabulary(NewCustomVocabulary(keywords))
+	compiler.AssertVocabs()
+	require.NoError(t, compiler.AddResource("test.json", schema))
+	return compiler.Compile("test.json")
+}
+
+func mustMatchField(_ *jsonschema.CompilerContext, value any, _ map[string]any) (jsonschema.SchemaExt, error) {
+	fields, ok := value.([]any)
+	if !ok || len(fields) != 2 {
+		return nil, fmt.Errorf("must be a list of two property names")
+	}
+	return KeywordValidator("x-must-match-field", func(instance any) error {
+		obj, ok := instance.(map[string]any)
+		if !ok {
+			return nil
+		}
+		if obj[fields[0].(string)] != obj[fields[1].(string)] {
+			return fmt.Errorf("'%s' must match '%s'", fields[1], fields[0])
+		}
+		return nil
+	}), nil
+}
+
+func TestCustomKeyword_CrossField(t *testing.T) {
+	schema, err := compileCustom(t, `{
+		"type": "object",
+		"x-must-match-field": ["password", "confirm"]
+	}`, map[string]KeywordCompileFunc{"x-must-match-field": mustMatchField})
+	require.NoError(t, err)
+
+	assert.NoError(t, schema.Validate(map[string]any{"password": "a", "confirm": "a"}))
+
+	err = schema.Validate(map[string]any{"password": "a", "confirm": "b"})
+	var ve *jsonschema.ValidationError
+	require.True(t, errors.As(err, &ve))
+
+	var found bool
+	for _, cause := range ve.Causes {
+		if kw, ok := cause.ErrorKind.(*KeywordError); ok {
+			found = true
+			assert.Equal(t, "x-must-match-field", kw.Keyword)
+			assert.Equal(t, []string{"x-must-match-field"}, kw.KeywordPath())
+			assert.Equal(t, "'confirm' must match 'password'", kw.LocalizedString(message.NewPrinter(message.MatchLanguage("en"))))
+			assert.Equal(t, "x-must-match-field: 'confirm' must match 'password'", kw.Error())
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestCustomKeyword_NestedSchema(t *testing.T) {
+	schema, err := compileCustom(t, `{
+		"type": "object",
+		"properties": {
+			"amount": {"type": "number", "x-currency-precision": 2}
+		}
+	}`, map[string]KeywordCompileFunc{
+		"x-currency-precision": func(_ *jsonschema.CompilerContext, value any, schema map[string]any) (jsonschema.SchemaExt, error) {
+			assert.Equal(t, "number", schema["type"])
+			return KeywordValidator("x-currency-precision", func(instance any) error {
+				if s := fmt.Sprint(instance); strings.Contains(s, ".") && len(s)-strings.Index(s, ".")-1 > 2 {
+					return fmt.Errorf("more than %v decimal places", value)
+				}
+				return nil
+			}), nil
+		},
+	})
+	require.NoError(t, err)
+
+	assert.NoError(t, schema.Validate(map[string]any{"amount": 1.25}))
+	assert.Error(t, schema.Validate(map[string]any{"amount": 1.255}))
+}
+
+func TestCustomKeyword_CompileError(t *testing.T) {
+	_, err := compileCustom(t, `{
+		"type": "object",
+		"x-must-match-field": "password"
+	}`, map[string]KeywordCompileFunc{"x-must-match-field": mustMatchField})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "OpenAPI keyword 'x-must-match-field': must be a list of two property names")
+}
+
+func TestCustomKeyword_NotUsed(t *testing.T) {
+	schema, err := compileCustom(t, `{"type": "string"}`, map[string]KeywordCompileFunc{
+		"x-iban": func(*jsonschema.CompilerContext, any, map[string]any) (jsonschema.SchemaExt, error) {
+			t.Fatal("keyword should not be compiled")
+			return nil, nil
+		},
+		"x-skip": nil,
+	})
+	require.NoError(t, err)
+	assert.NoError(t, schema.Validate("anything"))
+
+	// a compile func can opt out of a schema by returning nil
+	schema, err = compileCustom(t, `{"type": "string", "x-iban": false}`, map[string]KeywordCompileFunc{
+		"x-iban": func(*jsonschema.CompilerContext, any, map[string]any) (jsonschema.SchemaExt, error) {
+			return nil, nil
+		},
+	})
+	require.NoError(t, err)
+	assert.NoError(t, schema.Validate("anything"))
+}
@@ -22,7 +22,8 @@ type deprecatedExtension struct {
 }
 
 func (d *deprecatedExtension) Validate(ctx *jsonschema.ValidatorContext, v any) {
-	// Deprecated keyword is metadata only - no validation needed during runtime
+	// Deprecated keyword is metadata only - a schema keyword can only fail validation, so the use of deprecated
+	// properties is reported as a warning by the deprecation package instead.
 }
 
 // compileExample compiles the example keyword
@@ -15,6 +15,7 @@ import (
 
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
 	"github.com/pb33f/libopenapi-validator/paths"
 	"github.com/pb33f/libopenapi-validator/strict"
 )
@@ -28,31 +29,41 @@ func (v *paramValidator) ValidateCookieParams(request *http.Request) (bool, []*e
 }
 
 func (v *paramValidator) ValidateCookieParamsWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	return v.ValidateMessageCookieParamsWithPathItem(message.FromHTTPRequest(request), pathItem, pathValue)
+}
+
+func (v *paramValidator) ValidateMessageCookieParamsWithPathItem(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
 	if pathItem == nil {
 		return false, []*errors.ValidationError{{
 			ValidationType:    helpers.PathValidation,
 			ValidationSubType: helpers.ValidationMissing,
-			Message:           fmt.Sprintf("%s Path '%s' not found", request.Method, request.URL.Path),
+			Message:           fmt.Sprintf("%s Path '%s' not found", request.Method(), request.URL().Path),
 			Reason: fmt.Sprintf("The %s request contains a path of '%s' "+
 				"however that path, or the %s method for that path does not exist in the specification",
-				request.Method, request.URL.Path, request.Method),
+				request.Method(), request.URL().Path, request.Method()),
 			SpecLine: -1,
 			SpecCol:  -1,
 			HowToFix: errors.HowToFixPath,
 		}}
 	}
+	if cancelled := errors.MessageContextDone(request); cancelled != nil {
+		return false, cancelled
+	}
 	// extract params for the operation
-	params := helpers.ExtractParamsForOperation(request, pathItem)
+	params := v.operationParams(request.Method(), pathItem)
 	var validationErrors []*errors.ValidationError
-	operation := strings.ToLower(request.Method)
+	operation := strings.ToLower(request.Method())
 
 	// build a map of cookies from the request for efficient lookup
 	cookieMap := make(map[string]*http.Cookie)
-	for _, cookie := range request.Cookies() {
+	for _, cookie := range message.Cookies(request) {
 		cookieMap[cookie.Name] = cookie
 	}
 
 	for _, p := range params {
+		if v.options.PhaseBudgetSpent(len(validationErrors)) {
+			break
+		}
 		if p.In == helpers.Cookie {
 			// look up the cookie by name (cookies are case-sensitive)
 			cookie, found := cookieMap[p.Name]
@@ -185,7 +196,7 @@ func (v *paramValidator) ValidateCookieParamsWithPathItem(request *http.Request,
 		}
 	}
 
-	errors.PopulateValidationErrors(validationErrors, request, pathValue)
+	errors.PopulateMessageValidationErrors(validationErrors, request, pathValue)
 
 	if len(validationErrors) > 0 {
 		return false, validationErrors
@@ -193,7 +204,16 @@ func (v *paramValidator) ValidateCookieParamsWithPathItem(request *http.Request,
 
 	// strict mode: check for undeclared cookies
 	if v.options.StrictMode {
-		undeclaredCookies := strict.ValidateCookies(request, params, v.options)
+		var undeclaredCookies []strict.UndeclaredValue
+		if planned := v.operationPlan(request.Method(), pathItem); planned != nil {
+			undeclaredCookies = strict.ValidateDeclaredCookies(message.Cookies(request), planned.Cookies.Set, planned.Cookies.List, v.options)
+		} else {
+			undeclaredCookies = strict.ValidateCookieValues(message.Cookies(request), params, v.options)
+		}
+		if v.options.StrictSanitize {
+			strict.SanitizeCookies(request.Header(), undeclaredCookies)
+			undeclaredCookies = nil
+		}
 		for _, undeclared := range undeclaredCookies {
 			validationErrors = append(validationErrors,
 				errors.UndeclaredCookieError(
@@ -201,8 +221,8 @@ func (v *paramValidator) ValidateCookieParamsWithPathItem(request *http.Request,
 					undeclared.Name,
 					undeclared.Value,
 					undeclared.DeclaredProperties,
-					request.URL.Path,
-					request.Method,
+					request.URL().Path,
+					request.Method(),
 				))
 		}
 	}
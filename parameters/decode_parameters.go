@@ -0,0 +1,451 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package parameters
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/paths"
+)
+
+// DecodedParameters holds the values of the parameters supplied with a request, deserialized according to the
+// style, explode setting and schema of each declared parameter. Each map is keyed by the parameter name as it is
+// declared in the specification. Scalars are coerced to int64, float64 or bool when the schema declares those
+// types, arrays are decoded as []any and objects as map[string]any. Parameters that are not present in the
+// request are absent from the maps.
+type DecodedParameters struct {
+	Path   map[string]any
+	Query  map[string]any
+	Header map[string]any
+	Cookie map[string]any
+}
+
+// Get returns the decoded value of a parameter by location ('path', 'query', 'header' or 'cookie') and name.
+func (d *DecodedParameters) Get(in, name string) (any, bool) {
+	if d == nil {
+		return nil, false
+	}
+	var values map[string]any
+	switch in {
+	case helpers.Path:
+		values = d.Path
+	case helpers.Query:
+		values = d.Query
+	case helpers.Header:
+		values = d.Header
+	case helpers.Cookie:
+		values = d.Cookie
+	}
+	v, ok := values[name]
+	return v, ok
+}
+
+// ParameterDecoder decodes the parameters of a request into their values, as well as validating them. The
+// validators created by NewParameterValidator implement it:
+//
+//	decoder := parameterValidator.(parameters.ParameterDecoder)
+type ParameterDecoder interface {
+	// DecodeParameters validates the path, query, header and cookie parameters contained within *http.Request and
+	// returns their values, deserialized according to the style and schema of each parameter. Values are decoded
+	// even when validation fails, so callers should check the boolean before trusting them.
+	DecodeParameters(request *http.Request) (*DecodedParameters, bool, []*errors.ValidationError)
+
+	// DecodeParametersWithPathItem validates and decodes the path, query, header and cookie parameters contained
+	// within *http.Request, using an already located path item and path template.
+	DecodeParametersWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (*DecodedParameters, bool, []*errors.ValidationError)
+}
+
+var _ ParameterDecoder = (*paramValidator)(nil)
+
+func newDecodedParameters() *DecodedParameters {
+	return &DecodedParameters{
+		Path:   make(map[string]any),
+		Query:  make(map[string]any),
+		Header: make(map[string]any),
+		Cookie: make(map[string]any),
+	}
+}
+
+func (v *paramValidator) DecodeParameters(request *http.Request) (*DecodedParameters, bool, []*errors.ValidationError) {
+	pathItem, errs, foundPath := paths.FindPath(request, v.document, v.options)
+	if len(errs) > 0 {
+		return nil, false, errs
+	}
+	return v.DecodeParametersWithPathItem(request, pathItem, foundPath)
+}
+
+func (v *paramValidator) DecodeParametersWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (*DecodedParameters, bool, []*errors.ValidationError) {
+	var validationErrors []*errors.ValidationError
+	for _, validate := range []func(*http.Request, *v3.PathItem, string) (bool, []*errors.ValidationError){
+		v.ValidatePathParamsWithPathItem,
+		v.ValidateQueryParamsWithPathItem,
+		v.ValidateHeaderParamsWithPathItem,
+		v.ValidateCookieParamsWithPathItem,
+	} {
+		if _, errs := validate(request, pathItem, pathValue); len(errs) > 0 {
+			validationErrors = append(validationErrors, errs...)
+		}
+	}
+	if pathItem == nil {
+		// every validator reports the same missing path, only keep the first.
+		if len(validationErrors) > 1 {
+			validationErrors = validationErrors[:1]
+		}
+		return nil, false, validationErrors
+	}
+
+	decoded := newDecodedParameters()
+	params := v.operationParams(request.Method, pathItem)
+	pathValues := v.pathParameterValues(request, pathValue)
+	query := request.URL.Query()
+
+	for _, p := range params {
+		sch, contentType := parameterSchema(p)
+		switch p.In {
+		case helpers.Path:
+			if raw, ok := pathValues[p.Name]; ok {
+				decoded.Path[p.Name] = decodePathParameter(p, sch, raw)
+			}
+		case helpers.Query:
+			if value, ok := decodeQueryParameter(p, sch, contentType, query); ok {
+				decoded.Query[p.Name] = value
+			}
+		case helpers.Header:
+			if raw := request.Header.Get(p.Name); raw != "" {
+				decoded.Header[p.Name] = decodeSimple(p, sch, contentType, raw)
+			}
+		case helpers.Cookie:
+			if cookie, err := request.Cookie(p.Name); err == nil {
+				decoded.Cookie[p.Name] = decodeSimple(p, sch, contentType, cookie.Value)
+			}
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return decoded, false, validationErrors
+	}
+	return decoded, true, nil
+}
+
+// parameterSchema returns the schema of a parameter, unwrapping the single 'content' entry when the parameter
+// does not define a schema directly. The content type is empty for schema parameters.
+func parameterSchema(p *v3.Parameter) (*base.Schema, string) {
+	if p.Schema != nil {
+		return p.Schema.Schema(), ""
+	}
+	for pair := orderedmap.First(p.Content); pair != nil; pair = pair.Next() {
+		if pair.Value().Schema != nil {
+			return pair.Value().Schema.Schema(), pair.Key()
+		}
+		return nil, pair.Key()
+	}
+	return nil, ""
+}
+
+// pathTemplateValue is the raw value of a path parameter, along with the operator used in the path template.
+type pathTemplateValue struct {
+	value   string
+	label   bool
+	matrix  bool
+	explode bool
+}
+
+// pathParameterValues matches the request path against the path template and returns the raw, unescaped value
+// of each templated parameter, using the same segment matching as ValidatePathParamsWithPathItem.
+func (v *paramValidator) pathParameterValues(request *http.Request, pathValue string) map[string]pathTemplateValue {
+	values := make(map[string]pathTemplateValue)
+	submittedSegments := strings.Split(paths.StripRequestPath(request, v.document), helpers.Slash)
+	pathSegments := strings.Split(pathValue, helpers.Slash)
+
+	for x := range pathSegments {
+		if pathSegments[x] == "" || x >= len(submittedSegments) {
+			continue
+		}
+		idxs, err := helpers.BraceIndices(pathSegments[x])
+		if err != nil || len(idxs) == 0 {
+			continue
+		}
+
+		var rgx *regexp.Regexp
+		if v.options.RegexCache != nil {
+			if cachedRegex, found := v.options.RegexCache.Load(pathSegments[x]); found {
+				rgx = cachedRegex.(*regexp.Regexp)
+			}
+		}
+		if rgx == nil {
+			r, err := helpers.GetRegexForPath(pathSegments[x])
+			if err != nil {
+				continue
+			}
+			rgx = r
+			if v.options.RegexCache != nil {
+				v.options.RegexCache.Store(pathSegments[x], r)
+			}
+		}
+
+		matches := rgx.FindStringSubmatch(submittedSegments[x])
+		if len(matches) == 0 {
+			continue
+		}
+		for i, match := range matches[1:] {
+			if i*2+1 >= len(idxs) {
+				break
+			}
+			paramTemplate := pathSegments[x][idxs[i*2]+1 : idxs[i*2+1]-1]
+			tv := pathTemplateValue{}
+			if strings.HasSuffix(paramTemplate, helpers.Asterisk) {
+				tv.explode = true
+				paramTemplate = paramTemplate[:len(paramTemplate)-1]
+			}
+			if strings.HasPrefix(paramTemplate, helpers.Period) {
+				tv.label = true
+				paramTemplate = paramTemplate[1:]
+			}
+			if strings.HasPrefix(paramTemplate, helpers.SemiColon) {
+				tv.matrix = true
+				paramTemplate = paramTemplate[1:]
+			}
+			decodedValue, err := url.PathUnescape(match)
+			if err != nil {
+				decodedValue = match
+			}
+			if decodedValue == "" {
+				continue
+			}
+			tv.value = decodedValue
+			values[paramTemplate] = tv
+		}
+	}
+	return values
+}
+
+// decodePathParameter decodes a path parameter encoded with the simple, label or matrix style.
+func decodePathParameter(p *v3.Parameter, sch *base.Schema, tv pathTemplateValue) any {
+	explode := tv.explode || p.IsExploded()
+	value := tv.value
+
+	switch {
+	case tv.label || p.Style == helpers.LabelStyle:
+		value = strings.TrimPrefix(value, helpers.Period)
+		switch {
+		case schemaHasType(sch, helpers.Array):
+			if explode {
+				return decodeArray(strings.Split(value, helpers.Period), sch)
+			}
+			return decodeArray(strings.Split(value, helpers.Comma), sch)
+		case schemaHasType(sch, helpers.Object):
+			if explode {
+				return helpers.ConstructKVFromLabelEncodingWithSchema(value, sch)
+			}
+			return helpers.ConstructMapFromCSVWithSchema(value, sch)
+		}
+		return decodeScalar(value, sch)
+
+	case tv.matrix || p.Style == helpers.MatrixStyle:
+		prefix := helpers.SemiColon + p.Name + helpers.Equals
+		switch {
+		case schemaHasType(sch, helpers.Array):
+			if explode {
+				var items []string
+				for _, item := range strings.Split(strings.TrimPrefix(value, helpers.SemiColon), helpers.SemiColon) {
+					items = append(items, strings.TrimPrefix(item, p.Name+helpers.Equals))
+				}
+				return decodeArray(items, sch)
+			}
+			return decodeArray(strings.Split(strings.TrimPrefix(value, prefix), helpers.Comma), sch)
+		case schemaHasType(sch, helpers.Object):
+			if explode {
+				return helpers.ConstructKVFromMatrixCSVWithSchema(strings.TrimPrefix(value, helpers.SemiColon), sch)
+			}
+			return helpers.ConstructMapFromCSVWithSchema(strings.TrimPrefix(value, prefix), sch)
+		}
+		return decodeScalar(strings.TrimPrefix(value, prefix), sch)
+	}
+
+	return decodeSimpleValue(sch, explode, value)
+}
+
+// decodeQueryParameter decodes a query parameter encoded with the form, spaceDelimited, pipeDelimited or
+// deepObject style, or carried as JSON content. The boolean is false when the parameter is not present.
+func decodeQueryParameter(p *v3.Parameter, sch *base.Schema, contentType string, query url.Values) (any, bool) {
+	values, present := query[p.Name]
+
+	if contentType != "" {
+		if !present || len(values) == 0 {
+			return nil, false
+		}
+		return decodeContent(contentType, values[0]), true
+	}
+
+	if schemaHasType(sch, helpers.Object) {
+		switch p.Style {
+		case helpers.DeepObject:
+			var qps []*helpers.QueryParam
+			for qKey, qVal := range query {
+				if stripped, propertyPath, ok := helpers.ParseDeepObjectKey(qKey); ok && stripped == p.Name {
+					qps = append(qps, &helpers.QueryParam{
+						Key:          stripped,
+						Values:       qVal,
+						Property:     propertyPath[0],
+						PropertyPath: propertyPath,
+					})
+				}
+			}
+			if len(qps) == 0 {
+				return nil, false
+			}
+			obj, _ := helpers.ConstructParamMapFromDeepObjectEncoding(qps, sch)[p.Name].(map[string]interface{})
+			return obj, obj != nil
+		case helpers.PipeDelimited, helpers.SpaceDelimited:
+			if !present {
+				return nil, false
+			}
+			qps := []*helpers.QueryParam{{Key: p.Name, Values: values}}
+			var obj map[string]interface{}
+			if p.Style == helpers.PipeDelimited {
+				obj, _ = helpers.ConstructParamMapFromPipeEncodingWithSchema(qps, sch)[p.Name].(map[string]interface{})
+			} else {
+				obj, _ = helpers.ConstructParamMapFromSpaceEncodingWithSchema(qps, sch)[p.Name].(map[string]interface{})
+			}
+			return obj, obj != nil
+		}
+		if present && !p.IsExploded() {
+			qps := []*helpers.QueryParam{{Key: p.Name, Values: values}}
+			obj, _ := helpers.ConstructParamMapFromFormEncodingArrayWithSchema(qps, sch)[p.Name].(map[string]interface{})
+			return obj, obj != nil
+		}
+		// exploded form objects are serialized as one query key per property.
+		if sch.Properties == nil {
+			return nil, false
+		}
+		obj := make(map[string]any)
+		for pair := sch.Properties.First(); pair != nil; pair = pair.Next() {
+			if propValues, ok := query[pair.Key()]; ok && len(propValues) > 0 {
+				obj[pair.Key()] = decodeScalar(propValues[0], pair.Value().Schema())
+			}
+		}
+		return obj, len(obj) > 0
+	}
+
+	if !present || len(values) == 0 {
+		return nil, false
+	}
+
+	if schemaHasType(sch, helpers.Array) {
+		if p.IsExploded() && (p.Style == "" || p.Style == helpers.Form) {
+			return decodeArray(values, sch), true
+		}
+		var items []string
+		for _, value := range values {
+			items = append(items, helpers.ExplodeQueryValue(value, p.Style)...)
+		}
+		return decodeArray(items, sch), true
+	}
+
+	return decodeScalar(values[0], sch), true
+}
+
+// decodeSimple decodes a header or cookie value. Headers only support the simple style, and cookies are
+// serialized with the form style, which shares the same comma separated encoding when not exploded.
+func decodeSimple(p *v3.Parameter, sch *base.Schema, contentType, value string) any {
+	if contentType != "" {
+		return decodeContent(contentType, value)
+	}
+	explode := p.IsExploded()
+	if p.In == helpers.Cookie {
+		// exploded form cookies cannot carry key=value pairs in a single cookie.
+		explode = false
+	}
+	return decodeSimpleValue(sch, explode, value)
+}
+
+// decodeSimpleValue decodes a value encoded with the simple style.
+func decodeSimpleValue(sch *base.Schema, explode bool, value string) any {
+	switch {
+	case schemaHasType(sch, helpers.Array):
+		return decodeArray(strings.Split(value, helpers.Comma), sch)
+	case schemaHasType(sch, helpers.Object):
+		if explode {
+			return helpers.ConstructKVFromCSVWithSchema(value, sch)
+		}
+		return helpers.ConstructMapFromCSVWithSchema(value, sch)
+	}
+	return decodeScalar(value, sch)
+}
+
+// decodeContent decodes a parameter serialized with a media type. JSON is unmarshalled, anything else is
+// returned as the raw string.
+func decodeContent(contentType, value string) any {
+	if strings.Contains(strings.ToLower(contentType), "json") {
+		var decoded any
+		if err := json.Unmarshal([]byte(value), &decoded); err == nil {
+			return decoded
+		}
+	}
+	return value
+}
+
+// decodeArray decodes each item of an array against the item schema.
+func decodeArray(items []string, sch *base.Schema) []any {
+	var itemSchema *base.Schema
+	if sch != nil && sch.Items != nil && sch.Items.IsA() {
+		itemSchema = sch.Items.A.Schema()
+	}
+	decoded := make([]any, 0, len(items))
+	for _, item := range items {
+		decoded = append(decoded, decodeScalar(item, itemSchema))
+	}
+	return decoded
+}
+
+// decodeScalar coerces a raw value into the first type declared by the schema that can represent it.
+// Values that cannot be coerced, or have no declared type, are returned as strings.
+func decodeScalar(value string, sch *base.Schema) any {
+	if sch == nil {
+		return value
+	}
+	for _, ty := range sch.Type {
+		switch ty {
+		case helpers.Integer:
+			if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return i
+			}
+		case helpers.Number:
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				return f
+			}
+		case helpers.Boolean:
+			if b, err := strconv.ParseBool(value); err == nil {
+				return b
+			}
+		case helpers.String:
+			return value
+		}
+	}
+	return value
+}
+
+func schemaHasType(sch *base.Schema, ty string) bool {
+	if sch == nil {
+		return false
+	}
+	for _, t := range sch.Type {
+		if t == ty {
+			return true
+		}
+	}
+	return false
+}
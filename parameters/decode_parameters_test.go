@@ -0,0 +1,320 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package parameters
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+)
+
+func newDecodeValidator(t *testing.T, spec string) ParameterValidator {
+	t.Helper()
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, errs := doc.BuildV3Model()
+	require.NoError(t, errs)
+	return NewParameterValidator(&m.Model)
+}
+
+func TestDecodeParameters_PathStyles(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pets/{id}/{.tags}/{;coords*}/{meta*}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: tags
+          in: path
+          required: true
+          style: label
+          schema:
+            type: array
+            items:
+              type: string
+        - name: coords
+          in: path
+          required: true
+          style: matrix
+          explode: true
+          schema:
+            type: object
+            properties:
+              lat:
+                type: number
+              lng:
+                type: number
+        - name: meta
+          in: path
+          required: true
+          explode: true
+          schema:
+            type: object
+            properties:
+              page:
+                type: integer
+              active:
+                type: boolean
+`
+	v := newDecodeValidator(t, spec)
+
+	request, _ := http.NewRequest(http.MethodGet,
+		"https://things.com/pets/42/.cat,dog/;lat=1.5;lng=-2/page=3,active=true", nil)
+
+	decoded, valid, errs := v.(ParameterDecoder).DecodeParameters(request)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+	require.NotNil(t, decoded)
+
+	assert.Equal(t, int64(42), decoded.Path["id"])
+	assert.Equal(t, []any{"cat", "dog"}, decoded.Path["tags"])
+	assert.Equal(t, map[string]any{"lat": 1.5, "lng": int64(-2)}, decoded.Path["coords"])
+	assert.Equal(t, map[string]any{"page": int64(3), "active": true}, decoded.Path["meta"])
+
+	value, ok := decoded.Get("path", "id")
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), value)
+}
+
+func TestDecodeParameters_QueryStyles(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /search:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+        - name: ids
+          in: query
+          schema:
+            type: array
+            items:
+              type: integer
+        - name: names
+          in: query
+          style: pipeDelimited
+          explode: false
+          schema:
+            type: array
+            items:
+              type: string
+        - name: sizes
+          in: query
+          style: spaceDelimited
+          explode: false
+          schema:
+            type: array
+            items:
+              type: number
+        - name: filter
+          in: query
+          style: deepObject
+          schema:
+            type: object
+            properties:
+              color:
+                type: string
+              max:
+                type: integer
+        - name: point
+          in: query
+          explode: false
+          schema:
+            type: object
+            properties:
+              x:
+                type: integer
+              y:
+                type: integer
+        - name: json
+          in: query
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  a:
+                    type: integer
+        - name: absent
+          in: query
+          schema:
+            type: string
+`
+	v := newDecodeValidator(t, spec)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/search?limit=10&ids=1&ids=2"+
+		"&names=a|b&sizes=1.5%202&filter[color]=red&filter[max]=5&point=x,1,y,2&json=%7B%22a%22%3A1%7D", nil)
+
+	decoded, valid, errs := v.(ParameterDecoder).DecodeParameters(request)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+	require.NotNil(t, decoded)
+
+	assert.Equal(t, int64(10), decoded.Query["limit"])
+	assert.Equal(t, []any{int64(1), int64(2)}, decoded.Query["ids"])
+	assert.Equal(t, []any{"a", "b"}, decoded.Query["names"])
+	assert.Equal(t, []any{1.5, float64(2)}, decoded.Query["sizes"])
+	assert.Equal(t, map[string]any{"color": "red", "max": int64(5)}, decoded.Query["filter"])
+	assert.Equal(t, map[string]any{"x": int64(1), "y": int64(2)}, decoded.Query["point"])
+	assert.Equal(t, map[string]any{"a": float64(1)}, decoded.Query["json"])
+
+	_, ok := decoded.Get("query", "absent")
+	assert.False(t, ok)
+}
+
+func TestDecodeParameters_ExplodedFormObject(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /search:
+    get:
+      parameters:
+        - name: page
+          in: query
+          schema:
+            type: object
+            properties:
+              offset:
+                type: integer
+              sort:
+                type: string
+`
+	v := newDecodeValidator(t, spec)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/search?offset=20&sort=10", nil)
+
+	decoded, valid, errs := v.(ParameterDecoder).DecodeParameters(request)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]any{"offset": int64(20), "sort": "10"}, decoded.Query["page"])
+}
+
+func TestDecodeParameters_HeadersAndCookies(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /things:
+    get:
+      parameters:
+        - name: X-Rate
+          in: header
+          schema:
+            type: number
+        - name: X-Flags
+          in: header
+          schema:
+            type: array
+            items:
+              type: boolean
+        - name: X-Obj
+          in: header
+          explode: true
+          schema:
+            type: object
+            properties:
+              a:
+                type: integer
+              b:
+                type: string
+        - name: session
+          in: cookie
+          schema:
+            type: integer
+        - name: prefs
+          in: cookie
+          explode: false
+          schema:
+            type: array
+            items:
+              type: string
+`
+	v := newDecodeValidator(t, spec)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/things", nil)
+	request.Header.Set("X-Rate", "0.25")
+	request.Header.Set("X-Flags", "true,false")
+	request.Header.Set("X-Obj", "a=1,b=2")
+	request.AddCookie(&http.Cookie{Name: "session", Value: "99"})
+	request.AddCookie(&http.Cookie{Name: "prefs", Value: "dark,compact"})
+
+	decoded, valid, errs := v.(ParameterDecoder).DecodeParameters(request)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	assert.Equal(t, 0.25, decoded.Header["X-Rate"])
+	assert.Equal(t, []any{true, false}, decoded.Header["X-Flags"])
+	assert.Equal(t, map[string]any{"a": int64(1), "b": "2"}, decoded.Header["X-Obj"])
+	assert.Equal(t, int64(99), decoded.Cookie["session"])
+	assert.Equal(t, []any{"dark", "compact"}, decoded.Cookie["prefs"])
+}
+
+func TestDecodeParameters_InvalidStillDecodes(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /things:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            maximum: 10
+        - name: name
+          in: query
+          required: true
+          schema:
+            type: string
+`
+	v := newDecodeValidator(t, spec)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/things?limit=50", nil)
+
+	decoded, valid, errs := v.(ParameterDecoder).DecodeParameters(request)
+	assert.False(t, valid)
+	assert.Len(t, errs, 2)
+	require.NotNil(t, decoded)
+	assert.Equal(t, int64(50), decoded.Query["limit"])
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/things?limit=nope&name=x", nil)
+	decoded, valid, errs = v.(ParameterDecoder).DecodeParameters(request)
+	assert.False(t, valid)
+	assert.NotEmpty(t, errs)
+	assert.Equal(t, "nope", decoded.Query["limit"])
+}
+
+func TestDecodeParameters_PathNotFound(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /things:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+`
+	v := newDecodeValidator(t, spec)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/other", nil)
+	decoded, valid, errs := v.(ParameterDecoder).DecodeParameters(request)
+	assert.False(t, valid)
+	assert.Nil(t, decoded)
+	assert.Len(t, errs, 1)
+
+	decoded, valid, errs = v.(ParameterDecoder).DecodeParametersWithPathItem(request, nil, "")
+	assert.False(t, valid)
+	assert.Nil(t, decoded)
+	assert.Len(t, errs, 1)
+
+	var nilDecoded *DecodedParameters
+	_, ok := nilDecoded.Get("query", "limit")
+	assert.False(t, ok)
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
 	"github.com/pb33f/libopenapi-validator/paths"
 	"github.com/pb33f/libopenapi-validator/strict"
 )
@@ -29,30 +30,40 @@ func (v *paramValidator) ValidateHeaderParams(request *http.Request) (bool, []*e
 }
 
 func (v *paramValidator) ValidateHeaderParamsWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	return v.ValidateMessageHeaderParamsWithPathItem(message.FromHTTPRequest(request), pathItem, pathValue)
+}
+
+func (v *paramValidator) ValidateMessageHeaderParamsWithPathItem(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
 	if pathItem == nil {
 		return false, []*errors.ValidationError{{
 			ValidationType:    helpers.PathValidation,
 			ValidationSubType: helpers.ValidationMissing,
-			Message:           fmt.Sprintf("%s Path '%s' not found", request.Method, request.URL.Path),
+			Message:           fmt.Sprintf("%s Path '%s' not found", request.Method(), request.URL().Path),
 			Reason: fmt.Sprintf("The %s request contains a path of '%s' "+
 				"however that path, or the %s method for that path does not exist in the specification",
-				request.Method, request.URL.Path, request.Method),
+				request.Method(), request.URL().Path, request.Method()),
 			SpecLine: -1,
 			SpecCol:  -1,
 			HowToFix: errors.HowToFixPath,
 		}}
 	}
+	if cancelled := errors.MessageContextDone(request); cancelled != nil {
+		return false, cancelled
+	}
 	// extract params for the operation
-	params := helpers.ExtractParamsForOperation(request, pathItem)
+	params := v.operationParams(request.Method(), pathItem)
 
 	var validationErrors []*errors.ValidationError
 	seenHeaders := make(map[string]bool)
-	operation := strings.ToLower(request.Method)
+	operation := strings.ToLower(request.Method())
 	for _, p := range params {
+		if v.options.PhaseBudgetSpent(len(validationErrors)) {
+			break
+		}
 		if p.In == helpers.Header {
 
 			seenHeaders[strings.ToLower(p.Name)] = true
-			if param := request.Header.Get(p.Name); param != "" {
+			if param := request.Header().Get(p.Name); param != "" {
 
 				var sch *base.Schema
 				if p.Schema != nil {
@@ -67,46 +78,24 @@ func (v *paramValidator) ValidateHeaderParamsWithPathItem(request *http.Request,
 				for _, ty := range pType {
 					switch ty {
 					case helpers.Integer:
-						if _, err := strconv.ParseInt(param, 10, 64); err != nil {
+						parsed, err := strconv.ParseInt(param, 10, 64)
+						if err != nil {
 							validationErrors = append(validationErrors,
 								errors.InvalidHeaderParamInteger(p, strings.ToLower(param), sch, pathValue, operation, renderedSchema))
 							break
 						}
-						// check if the param is within the enum
-						if sch.Enum != nil {
-							matchFound := false
-							for _, enumVal := range sch.Enum {
-								if strings.TrimSpace(param) == fmt.Sprint(enumVal.Value) {
-									matchFound = true
-									break
-								}
-							}
-							if !matchFound {
-								validationErrors = append(validationErrors,
-									errors.IncorrectHeaderParamEnum(p, strings.ToLower(param), sch, pathValue, operation, renderedSchema))
-							}
-						}
+						validationErrors = append(validationErrors,
+							v.validateSimpleHeader(sch, param, parsed, p, pathValue, operation, renderedSchema)...)
 
 					case helpers.Number:
-						if _, err := strconv.ParseFloat(param, 64); err != nil {
+						parsed, err := strconv.ParseFloat(param, 64)
+						if err != nil {
 							validationErrors = append(validationErrors,
 								errors.InvalidHeaderParamNumber(p, strings.ToLower(param), sch, pathValue, operation, renderedSchema))
 							break
 						}
-						// check if the param is within the enum
-						if sch.Enum != nil {
-							matchFound := false
-							for _, enumVal := range sch.Enum {
-								if strings.TrimSpace(param) == fmt.Sprint(enumVal.Value) {
-									matchFound = true
-									break
-								}
-							}
-							if !matchFound {
-								validationErrors = append(validationErrors,
-									errors.IncorrectHeaderParamEnum(p, strings.ToLower(param), sch, pathValue, operation, renderedSchema))
-							}
-						}
+						validationErrors = append(validationErrors,
+							v.validateSimpleHeader(sch, param, parsed, p, pathValue, operation, renderedSchema)...)
 
 					case helpers.Boolean:
 						if _, err := strconv.ParseBool(param); err != nil {
@@ -155,36 +144,8 @@ func (v *paramValidator) ValidateHeaderParamsWithPathItem(request *http.Request,
 						}
 
 					case helpers.String:
-
-						// check if the schema has an enum, and if so, match the value against one of
-						// the defined enum values.
-						if sch.Enum != nil {
-							matchFound := false
-							for _, enumVal := range sch.Enum {
-								if strings.TrimSpace(param) == fmt.Sprint(enumVal.Value) {
-									matchFound = true
-									break
-								}
-							}
-							if !matchFound {
-								validationErrors = append(validationErrors,
-									errors.IncorrectHeaderParamEnum(p, strings.ToLower(param), sch, pathValue, operation, renderedSchema))
-								break
-							}
-						}
 						validationErrors = append(validationErrors,
-							ValidateSingleParameterSchema(
-								sch,
-								param,
-								"Header parameter",
-								"The header parameter",
-								p.Name,
-								helpers.ParameterValidation,
-								helpers.ParameterValidationHeader,
-								v.options,
-								pathValue,
-								operation,
-							)...)
+							v.validateSimpleHeader(sch, param, param, p, pathValue, operation, renderedSchema)...)
 					}
 				}
 				if len(pType) == 0 {
@@ -209,7 +170,7 @@ func (v *paramValidator) ValidateHeaderParamsWithPathItem(request *http.Request,
 		}
 	}
 
-	errors.PopulateValidationErrors(validationErrors, request, pathValue)
+	errors.PopulateMessageValidationErrors(validationErrors, request, pathValue)
 
 	if len(validationErrors) > 0 {
 		return false, validationErrors
@@ -217,19 +178,28 @@ func (v *paramValidator) ValidateHeaderParamsWithPathItem(request *http.Request,
 
 	// strict mode: check for undeclared headers
 	if v.options.StrictMode {
-		// Extract security headers applicable to this operation
-		var securityHeaders []string
-		if v.document.Components != nil && v.document.Components.SecuritySchemes != nil {
-			security := helpers.EffectiveSecurityForOperation(request, pathItem, v.document.Security)
-			// Convert orderedmap to regular map for the helper
-			schemesMap := make(map[string]*v3.SecurityScheme)
-			for pair := v.document.Components.SecuritySchemes.First(); pair != nil; pair = pair.Next() {
-				schemesMap[pair.Key()] = pair.Value()
+		var undeclaredHeaders []strict.UndeclaredValue
+		if planned := v.operationPlan(request.Method(), pathItem); planned != nil {
+			// the plan declares the headers of the security schemes of the operation along with its parameters
+			undeclaredHeaders = strict.ValidateDeclaredRequestHeaders(request.Header(), planned.Headers.Set, planned.Headers.List, v.options)
+		} else {
+			// Extract security headers applicable to this operation
+			var securityHeaders []string
+			if v.document.Components != nil && v.document.Components.SecuritySchemes != nil {
+				security := helpers.EffectiveSecurityForMethod(request.Method(), pathItem, v.document.Security)
+				// Convert orderedmap to regular map for the helper
+				schemesMap := make(map[string]*v3.SecurityScheme)
+				for pair := v.document.Components.SecuritySchemes.First(); pair != nil; pair = pair.Next() {
+					schemesMap[pair.Key()] = pair.Value()
+				}
+				securityHeaders = helpers.ExtractSecurityHeaderNames(security, schemesMap)
 			}
-			securityHeaders = helpers.ExtractSecurityHeaderNames(security, schemesMap)
+			undeclaredHeaders = strict.ValidateRequestHeaders(request.Header(), params, securityHeaders, v.options)
+		}
+		if v.options.StrictSanitize {
+			strict.SanitizeHeaders(request.Header(), undeclaredHeaders)
+			undeclaredHeaders = nil
 		}
-
-		undeclaredHeaders := strict.ValidateRequestHeaders(request.Header, params, securityHeaders, v.options)
 		for _, undeclared := range undeclaredHeaders {
 			validationErrors = append(validationErrors,
 				errors.UndeclaredHeaderError(
@@ -237,8 +207,8 @@ func (v *paramValidator) ValidateHeaderParamsWithPathItem(request *http.Request,
 					undeclared.Value.(string),
 					undeclared.DeclaredProperties,
 					undeclared.Direction.String(),
-					request.URL.Path,
-					request.Method,
+					request.URL().Path,
+					request.Method(),
 				))
 		}
 	}
@@ -248,3 +218,37 @@ func (v *paramValidator) ValidateHeaderParamsWithPathItem(request *http.Request,
 	}
 	return true, nil
 }
+
+// validateSimpleHeader checks a header against the enum of its schema, and then validates the parsed value
+// against the schema, so keywords such as 'maximum' and 'format' apply to headers like they do to query parameters.
+func (v *paramValidator) validateSimpleHeader(sch *base.Schema, rawParam string, parsedParam any, parameter *v3.Parameter, pathTemplate string, operation string, renderedSchema string) []*errors.ValidationError {
+	// check if the schema has an enum, and if so, match the value against one of
+	// the defined enum values.
+	if sch.Enum != nil {
+		matchFound := false
+		for _, enumVal := range sch.Enum {
+			if strings.TrimSpace(rawParam) == fmt.Sprint(enumVal.Value) {
+				matchFound = true
+				break
+			}
+		}
+		if !matchFound {
+			return []*errors.ValidationError{
+				errors.IncorrectHeaderParamEnum(parameter, strings.ToLower(rawParam), sch, pathTemplate, operation, renderedSchema),
+			}
+		}
+	}
+
+	return ValidateSingleParameterSchema(
+		sch,
+		parsedParam,
+		"Header parameter",
+		"The header parameter",
+		parameter.Name,
+		helpers.ParameterValidation,
+		helpers.ParameterValidationHeader,
+		v.options,
+		pathTemplate,
+		operation,
+	)
+}
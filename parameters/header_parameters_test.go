@@ -1486,3 +1486,42 @@ components:
 	assert.True(t, valid)
 	assert.Len(t, errors, 0)
 }
+
+func TestNewValidator_HeaderParamNumericSchemaKeywords(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /bish/bosh:
+    get:
+      parameters:
+        - name: X-Priority
+          in: header
+          schema:
+            type: integer
+            maximum: 10
+        - name: X-Ratio
+          in: header
+          schema:
+            type: number
+            format: float
+      operationId: locateFishy
+`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewParameterValidator(&m.Model, config.WithFormatRegistry())
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/bish/bosh", nil)
+	request.Header.Set("X-Priority", "5")
+	request.Header.Set("X-Ratio", "0.5")
+	valid, errors := v.ValidateHeaderParams(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request.Header.Set("X-Priority", "11")
+	request.Header.Set("X-Ratio", "1e39")
+	valid, errors = v.ValidateHeaderParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 2)
+	assert.Equal(t, "X-Priority", errors[0].ParameterName)
+	assert.Equal(t, "X-Ratio", errors[1].ParameterName)
+}
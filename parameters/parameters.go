@@ -10,6 +10,8 @@ import (
 
 	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/plan"
 )
 
 // ParameterValidator is an interface that defines the methods for validating parameters
@@ -91,3 +93,17 @@ func (p *paramValidator) Release() {
 	}
 	p.document = nil
 }
+
+// operationPlan returns the validation plan of the operation for an HTTP method, or nil when there is none.
+func (p *paramValidator) operationPlan(method string, pathItem *v3.PathItem) *plan.Operation {
+	if p.options == nil {
+		return nil
+	}
+	return p.options.Plans.For(pathItem, method)
+}
+
+// operationParams returns the path and operation parameters of the operation for an HTTP method, from its
+// validation plan when there is one.
+func (p *paramValidator) operationParams(method string, pathItem *v3.PathItem) []*v3.Parameter {
+	return helpers.ParamsForOperation(method, pathItem, p.options)
+}
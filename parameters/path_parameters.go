@@ -17,6 +17,7 @@ import (
 
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
 	"github.com/pb33f/libopenapi-validator/paths"
 )
 
@@ -29,30 +30,40 @@ func (v *paramValidator) ValidatePathParams(request *http.Request) (bool, []*err
 }
 
 func (v *paramValidator) ValidatePathParamsWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	return v.ValidateMessagePathParamsWithPathItem(message.FromHTTPRequest(request), pathItem, pathValue)
+}
+
+func (v *paramValidator) ValidateMessagePathParamsWithPathItem(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
 	if pathItem == nil {
 		return false, []*errors.ValidationError{{
 			ValidationType:    helpers.PathValidation,
 			ValidationSubType: helpers.ValidationMissing,
-			Message:           fmt.Sprintf("%s Path '%s' not found", request.Method, request.URL.Path),
+			Message:           fmt.Sprintf("%s Path '%s' not found", request.Method(), request.URL().Path),
 			Reason: fmt.Sprintf("The %s request contains a path of '%s' "+
 				"however that path, or the %s method for that path does not exist in the specification",
-				request.Method, request.URL.Path, request.Method),
+				request.Method(), request.URL().Path, request.Method()),
 			SpecLine: -1,
 			SpecCol:  -1,
 			HowToFix: errors.HowToFixPath,
 		}}
 	}
+	if cancelled := errors.MessageContextDone(request); cancelled != nil {
+		return false, cancelled
+	}
 	// split the path into segments
-	submittedSegments := strings.Split(paths.StripRequestPath(request, v.document), helpers.Slash)
+	submittedSegments := strings.Split(paths.StripMessagePath(request, v.document), helpers.Slash)
 	pathSegments := strings.Split(pathValue, helpers.Slash)
 
 	// get the operation method for error reporting
-	operation := strings.ToLower(request.Method)
+	operation := strings.ToLower(request.Method())
 
 	// extract params for the operation
-	params := helpers.ExtractParamsForOperation(request, pathItem)
+	params := v.operationParams(request.Method(), pathItem)
 	var validationErrors []*errors.ValidationError
 	for _, p := range params {
+		if v.options.PhaseBudgetSpent(len(validationErrors)) {
+			break
+		}
 		if p.In == helpers.Path {
 			// var paramTemplate string
 			for x := range pathSegments {
@@ -132,7 +143,7 @@ func (v *paramValidator) ValidatePathParamsWithPathItem(request *http.Request, p
 					if decodedParamValue == "" {
 						// Mandatory path parameter cannot be empty
 						if p.Required != nil && *p.Required {
-							validationErrors = append(validationErrors, errors.PathParameterMissing(p, pathValue, request.URL.Path))
+							validationErrors = append(validationErrors, errors.PathParameterMissing(p, pathValue, request.URL().Path))
 							break
 						}
 						continue
@@ -372,7 +383,7 @@ func (v *paramValidator) ValidatePathParamsWithPathItem(request *http.Request, p
 		}
 	}
 
-	errors.PopulateValidationErrors(validationErrors, request, pathValue)
+	errors.PopulateMessageValidationErrors(validationErrors, request, pathValue)
 
 	if len(validationErrors) > 0 {
 		return false, validationErrors
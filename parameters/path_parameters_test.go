@@ -2307,7 +2307,8 @@ paths:
 
 // TestRadixTree_RegexFallback verifies that:
 // 1. Simple paths use the radix tree (no regex cache)
-// 2. Complex paths (OData style) fall back to regex and use the cache
+// 2. Mixed literal/parameter paths (OData style) use the radix tree (no regex cache)
+// 3. Paths the radix tree can't compile (adjacent params) fall back to regex and use the cache
 func TestRadixTree_RegexFallback(t *testing.T) {
 	spec := `openapi: 3.1.0
 paths:
@@ -2316,7 +2317,10 @@ paths:
       operationId: getSimple
   /entities('{Entity}'):
     get:
-      operationId: getOData`
+      operationId: getOData
+  /reports/{year}{month}:
+    get:
+      operationId: getReport`
 
 	doc, _ := libopenapi.NewDocument([]byte(spec))
 	m, _ := doc.BuildV3Model()
@@ -2333,18 +2337,29 @@ paths:
 	assert.EqualValues(t, 0, cache.storeCount, "Simple paths should not use regex cache")
 	assert.EqualValues(t, 0, cache.hitCount+cache.missCount, "Simple paths should not touch regex cache")
 
-	// OData path - SHOULD use regex cache (radix tree can't handle embedded params)
+	// OData path - handled by the radix tree's mixed segment matching
 	odataRequest, _ := http.NewRequest(http.MethodGet, "https://things.com/entities('abc')", nil)
 	pathItem, _, foundPath = paths.FindPath(odataRequest, &m.Model, opts)
 
 	assert.NotNil(t, pathItem)
 	assert.Equal(t, "/entities('{Entity}')", foundPath)
-	assert.EqualValues(t, 1, cache.storeCount, "OData paths should use regex cache")
-	assert.EqualValues(t, 1, cache.missCount, "First OData lookup should miss cache")
+	assert.EqualValues(t, 0, cache.hitCount+cache.missCount, "OData paths should not touch regex cache")
+
+	// adjacent params - SHOULD use regex cache (radix tree can't split the segment). The fallback compares
+	// every template with as many segments as the request: "simple", "{id}", "reports" and "{year}{month}".
+	reportRequest, _ := http.NewRequest(http.MethodGet, "https://things.com/reports/202401", nil)
+	pathItem, _, foundPath = paths.FindPath(reportRequest, &m.Model, opts)
+
+	assert.NotNil(t, pathItem)
+	assert.Equal(t, "/reports/{year}{month}", foundPath)
+	assert.EqualValues(t, 4, cache.storeCount, "Fallback paths should use regex cache")
+	assert.EqualValues(t, 4, cache.missCount, "First fallback lookup should miss cache")
+	assert.EqualValues(t, 0, cache.hitCount)
 
-	// Second OData call should hit cache
-	pathItem, _, _ = paths.FindPath(odataRequest, &m.Model, opts)
+	// Second fallback call should hit cache
+	pathItem, _, _ = paths.FindPath(reportRequest, &m.Model, opts)
 	assert.NotNil(t, pathItem)
-	assert.EqualValues(t, 1, cache.storeCount, "No new stores on cache hit")
-	assert.EqualValues(t, 1, cache.hitCount, "Second OData lookup should hit cache")
+	assert.EqualValues(t, 4, cache.storeCount, "No new stores on cache hit")
+	assert.EqualValues(t, 4, cache.missCount)
+	assert.EqualValues(t, 4, cache.hitCount, "Second fallback lookup should hit cache")
 }
@@ -18,6 +18,7 @@ import (
 
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
 	"github.com/pb33f/libopenapi-validator/paths"
 	"github.com/pb33f/libopenapi-validator/strict"
 )
@@ -35,21 +36,28 @@ func (v *paramValidator) ValidateQueryParams(request *http.Request) (bool, []*er
 }
 
 func (v *paramValidator) ValidateQueryParamsWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	return v.ValidateMessageQueryParamsWithPathItem(message.FromHTTPRequest(request), pathItem, pathValue)
+}
+
+func (v *paramValidator) ValidateMessageQueryParamsWithPathItem(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
 	if pathItem == nil {
 		return false, []*errors.ValidationError{{
 			ValidationType:    helpers.PathValidation,
 			ValidationSubType: helpers.ValidationMissing,
-			Message:           fmt.Sprintf("%s Path '%s' not found", request.Method, request.URL.Path),
+			Message:           fmt.Sprintf("%s Path '%s' not found", request.Method(), request.URL().Path),
 			Reason: fmt.Sprintf("The %s request contains a path of '%s' "+
 				"however that path, or the %s method for that path does not exist in the specification",
-				request.Method, request.URL.Path, request.Method),
+				request.Method(), request.URL().Path, request.Method()),
 			SpecLine: -1,
 			SpecCol:  -1,
 			HowToFix: errors.HowToFixPath,
 		}}
 	}
+	if cancelled := errors.MessageContextDone(request); cancelled != nil {
+		return false, cancelled
+	}
 	// extract params for the operation
-	params := helpers.ExtractParamsForOperation(request, pathItem)
+	params := v.operationParams(request.Method(), pathItem)
 	queryParams := make(map[string][]*helpers.QueryParam)
 	var validationErrors []*errors.ValidationError
 
@@ -61,7 +69,7 @@ func (v *paramValidator) ValidateQueryParamsWithPathItem(request *http.Request,
 		}
 	}
 
-	for qKey, qVal := range request.URL.Query() {
+	for qKey, qVal := range request.URL().Query() {
 		// check if the query key exactly matches a spec parameter name (e.g., "match[]")
 		// if so, store it literally without deepObject stripping
 		if specParamNames[qKey] {
@@ -86,11 +94,14 @@ func (v *paramValidator) ValidateQueryParamsWithPathItem(request *http.Request,
 	}
 
 	// Get operation from request method (lowercase for JSON Pointer)
-	operation := strings.ToLower(request.Method)
+	operation := strings.ToLower(request.Method())
 
 	// look through the params for the query key
 doneLooking:
 	for p := range params {
+		if v.options.PhaseBudgetSpent(len(validationErrors)) {
+			break
+		}
 		if params[p].In == helpers.Query {
 
 			contentWrapped := false
@@ -265,7 +276,7 @@ doneLooking:
 		}
 	}
 
-	errors.PopulateValidationErrors(validationErrors, request, pathValue)
+	errors.PopulateMessageValidationErrors(validationErrors, request, pathValue)
 
 	if len(validationErrors) > 0 {
 		return false, validationErrors
@@ -273,7 +284,16 @@ doneLooking:
 
 	// strict mode: check for undeclared query parameters
 	if v.options.StrictMode {
-		undeclaredParams := strict.ValidateQueryParams(request, params, v.options)
+		var undeclaredParams []strict.UndeclaredValue
+		if planned := v.operationPlan(request.Method(), pathItem); planned != nil {
+			undeclaredParams = strict.ValidateDeclaredQueryParams(request.URL().Query(), planned.Query.Set, planned.Query.List, v.options)
+		} else {
+			undeclaredParams = strict.ValidateQueryValues(request.URL().Query(), params, v.options)
+		}
+		if v.options.StrictSanitize {
+			strict.SanitizeQueryParams(request.URL(), undeclaredParams)
+			undeclaredParams = nil
+		}
 		for _, undeclared := range undeclaredParams {
 			validationErrors = append(validationErrors,
 				errors.UndeclaredQueryParamError(
@@ -281,8 +301,8 @@ doneLooking:
 					undeclared.Name,
 					undeclared.Value,
 					undeclared.DeclaredProperties,
-					request.URL.Path,
-					request.Method,
+					request.URL().Path,
+					request.Method(),
 				))
 		}
 	}
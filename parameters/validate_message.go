@@ -0,0 +1,71 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package parameters
+
+import (
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/message"
+	"github.com/pb33f/libopenapi-validator/paths"
+)
+
+// MessageParameterValidator validates the parameters of transport-neutral message.Request values, which are read
+// directly rather than converted to a *http.Request first. The validators created by NewParameterValidator
+// implement it:
+//
+//	messageValidator := parameterValidator.(parameters.MessageParameterValidator)
+type MessageParameterValidator interface {
+	// ValidateMessageParams validates the path, cookie, header and query parameters and the security requirements
+	// of a message.Request, locating the path and operation the same way ValidateQueryParams does.
+	ValidateMessageParams(request message.Request) (bool, []*errors.ValidationError)
+
+	// ValidateMessageParamsWithPathItem validates the path, cookie, header and query parameters and the security
+	// requirements of a message.Request, using an already located path item and path template.
+	ValidateMessageParamsWithPathItem(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError)
+
+	// ValidateMessagePathParamsWithPathItem validates the path parameters of a message.Request.
+	ValidateMessagePathParamsWithPathItem(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError)
+
+	// ValidateMessageCookieParamsWithPathItem validates the cookie parameters of a message.Request.
+	ValidateMessageCookieParamsWithPathItem(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError)
+
+	// ValidateMessageHeaderParamsWithPathItem validates the header parameters of a message.Request.
+	ValidateMessageHeaderParamsWithPathItem(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError)
+
+	// ValidateMessageQueryParamsWithPathItem validates the query parameters of a message.Request.
+	ValidateMessageQueryParamsWithPathItem(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError)
+
+	// ValidateMessageSecurityWithPathItem validates the security requirements of the operation of a message.Request.
+	ValidateMessageSecurityWithPathItem(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError)
+}
+
+var _ MessageParameterValidator = (*paramValidator)(nil)
+
+func (v *paramValidator) ValidateMessageParams(request message.Request) (bool, []*errors.ValidationError) {
+	pathItem, errs, foundPath := paths.FindMessagePath(request, v.document, v.options)
+	if len(errs) > 0 {
+		return false, errs
+	}
+	return v.ValidateMessageParamsWithPathItem(request, pathItem, foundPath)
+}
+
+func (v *paramValidator) ValidateMessageParamsWithPathItem(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	var validationErrors []*errors.ValidationError
+	for _, validate := range []func(message.Request, *v3.PathItem, string) (bool, []*errors.ValidationError){
+		v.ValidateMessagePathParamsWithPathItem,
+		v.ValidateMessageCookieParamsWithPathItem,
+		v.ValidateMessageHeaderParamsWithPathItem,
+		v.ValidateMessageQueryParamsWithPathItem,
+		v.ValidateMessageSecurityWithPathItem,
+	} {
+		if valid, errs := validate(request, pathItem, pathValue); !valid {
+			validationErrors = append(validationErrors, errs...)
+		}
+	}
+	if len(validationErrors) > 0 {
+		return false, validationErrors
+	}
+	return true, nil
+}
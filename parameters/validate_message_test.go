@@ -0,0 +1,121 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package parameters
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+func TestValidateMessageParams(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pets/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: limit
+          in: query
+          required: true
+          schema:
+            type: integer
+        - name: X-Trace
+          in: header
+          schema:
+            type: string
+            enum: [on, off]
+`
+	v := newDecodeValidator(t, spec).(MessageParameterValidator)
+
+	request, err := message.NewAPIGatewayRequest(&message.APIGatewayProxyRequest{
+		HTTPMethod:            http.MethodGet,
+		Path:                  "/pets/1",
+		Headers:               map[string]string{"x-trace": "on"},
+		QueryStringParameters: map[string]string{"limit": "5"},
+	})
+	require.NoError(t, err)
+
+	valid, errs := v.ValidateMessageParams(request)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	request, err = message.NewAPIGatewayRequest(&message.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodGet,
+		Path:       "/pets/abc",
+		Headers:    map[string]string{"x-trace": "maybe"},
+	})
+	require.NoError(t, err)
+
+	valid, errs = v.ValidateMessageParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errs, 3)
+
+	request, _ = message.NewAPIGatewayRequest(&message.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/cats"})
+	valid, errs = v.ValidateMessageParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+}
+
+func TestValidateMessageParams_CookiesAndStrict(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      security:
+        - session: []
+      parameters:
+        - name: theme
+          in: cookie
+          schema:
+            type: string
+            enum: [light, dark]
+components:
+  securitySchemes:
+    session:
+      type: apiKey
+      in: header
+      name: X-Session
+`
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, errs := doc.BuildV3Model()
+	require.NoError(t, errs)
+	v := NewParameterValidator(&m.Model, config.WithStrictMode(), config.WithStrictSanitize()).(MessageParameterValidator)
+
+	request, err := message.NewAPIGatewayRequest(&message.APIGatewayProxyRequest{
+		HTTPMethod:            http.MethodGet,
+		Path:                  "/pets",
+		Headers:               map[string]string{"Cookie": "theme=dark; tracker=1", "X-Session": "abc"},
+		QueryStringParameters: map[string]string{"debug": "true"},
+	})
+	require.NoError(t, err)
+
+	valid, validationErrors := v.ValidateMessageParams(request)
+	assert.True(t, valid)
+	assert.Empty(t, validationErrors)
+
+	// undeclared values are removed from the message itself.
+	assert.Equal(t, "theme=dark", request.Header().Get("Cookie"))
+	assert.Empty(t, request.URL().RawQuery)
+
+	request, _ = message.NewAPIGatewayRequest(&message.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodGet,
+		Path:       "/pets",
+		Headers:    map[string]string{"Cookie": "theme=blue"},
+	})
+	valid, validationErrors = v.ValidateMessageParams(request)
+	assert.False(t, valid)
+	assert.Len(t, validationErrors, 2)
+}
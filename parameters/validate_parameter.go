@@ -50,7 +50,7 @@ func ValidateSingleParameterSchema(
 	if stdError.As(scErrs, &werras) {
 		validationErrors = formatJsonSchemaValidationError(
 			schema, werras, entity, reasonEntity, name,
-			validationType, subValType, pathTemplate, operation, referenceSchema,
+			validationType, subValType, pathTemplate, operation, referenceSchema, o,
 		)
 	}
 	return validationErrors
@@ -177,7 +177,7 @@ func ValidateParameterSchema(
 	if stdError.As(scErrs, &werras) {
 		validationErrors = formatJsonSchemaValidationError(
 			schema, werras, entity, reasonEntity, name,
-			validationType, subValType, "", "", referenceSchema,
+			validationType, subValType, "", "", referenceSchema, validationOptions,
 		)
 	}
 
@@ -211,22 +211,36 @@ func compileParameterValidationSchema(
 	validationType string,
 	subValType string,
 ) (*jsonschema.Schema, string, []*errors.ValidationError) {
-	if validationOptions != nil && validationOptions.SchemaCache != nil && schema != nil && schema.GoLow() != nil {
-		hash := schema_validation.SchemaCacheKey(
+	// schemas of the validation plans are compiled once and found without hashing them again
+	if validationOptions != nil && validationOptions.Plans != nil {
+		if compiled := validationOptions.Plans.ParameterSchema(schema, validationOptions.SchemaCache); compiled != nil {
+			validationOptions.ObserveSchemaCache(0, "parameter", true)
+			return compiled.CompiledSchema, compiled.ReferenceSchema, nil
+		}
+	}
+
+	var hash uint64
+	if schema != nil && schema.GoLow() != nil {
+		hash = schema_validation.SchemaCacheKey(
 			schema.GoLow().Hash(),
 			parameterSchemaVersion,
 			schema_validation.SchemaValidationPurposeGeneric,
 		)
-		if cached, ok := validationOptions.SchemaCache.Load(hash); ok && cached != nil && cached.CompiledSchema != nil {
-			return cached.CompiledSchema, cached.ReferenceSchema, nil
+		if validationOptions != nil && validationOptions.SchemaCache != nil {
+			if cached, ok := validationOptions.SchemaCache.Load(hash); ok && cached != nil && cached.CompiledSchema != nil {
+				validationOptions.ObserveSchemaCache(hash, "parameter", true)
+				return cached.CompiledSchema, cached.ReferenceSchema, nil
+			}
+			validationOptions.ObserveSchemaCache(hash, "parameter", false)
 		}
 	}
 
-	compiled, err := schema_validation.CompileSchemaForValidation(
+	compiled, err := schema_validation.CompileSchemaOnce(
 		schema,
 		schema_validation.SchemaValidationPurposeGeneric,
 		validationOptions,
 		parameterSchemaVersion,
+		hash,
 	)
 	if err != nil {
 		return nil, "", []*errors.ValidationError{
@@ -237,15 +251,6 @@ func compileParameterValidationSchema(
 		return nil, "", nil
 	}
 
-	if validationOptions != nil && validationOptions.SchemaCache != nil && schema != nil && schema.GoLow() != nil {
-		hash := schema_validation.SchemaCacheKey(
-			schema.GoLow().Hash(),
-			parameterSchemaVersion,
-			schema_validation.SchemaValidationPurposeGeneric,
-		)
-		validationOptions.SchemaCache.Store(hash, compiled.ToCacheEntry(schema))
-	}
-
 	return compiled.CompiledSchema, compiled.ReferenceSchema, nil
 }
 
@@ -283,11 +288,15 @@ func formatJsonSchemaValidationError(
 	pathTemplate string,
 	operation string,
 	referenceSchema string,
+	validationOptions *config.ValidationOptions,
 ) (validationErrors []*errors.ValidationError) {
 	// flatten the validationErrors
 	schFlatErrs := helpers.FlattenSchemaOutputErrors(scErrs.DetailedOutput())
 	var schemaValidationErrors []*errors.SchemaValidationFailure
 	for q := range schFlatErrs {
+		if validationOptions.PhaseBudgetSpent(len(schemaValidationErrors)) {
+			break
+		}
 		er := schFlatErrs[q]
 
 		errMsg := er.Error.Kind.LocalizedString(message.NewPrinter(language.Tag{}))
@@ -314,11 +323,13 @@ func formatJsonSchemaValidationError(
 		if referenceSchema != "" {
 			fail.ReferenceSchema = referenceSchema
 		} else if schema != nil {
-			renderCtx := base.NewInlineRenderContextForValidation()
-			rendered, err := schema.RenderInlineWithContext(renderCtx)
-			if err == nil && rendered != nil {
-				fail.ReferenceSchema = string(rendered)
-			}
+			schema_validation.AddFailureDetails(fail, validationOptions, func(fail *errors.SchemaValidationFailure) {
+				renderCtx := base.NewInlineRenderContextForValidation()
+				rendered, err := schema.RenderInlineWithContext(renderCtx)
+				if err == nil && rendered != nil {
+					fail.ReferenceSchema = string(rendered)
+				}
+			})
 		}
 		schemaValidationErrors = append(schemaValidationErrors, fail)
 	}
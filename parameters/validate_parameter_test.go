@@ -250,6 +250,7 @@ components:
 		"",
 		"",
 		"",
+		nil,
 	)
 
 	require.Len(t, validationErrors, 1)
@@ -287,6 +288,7 @@ components:
 		"",
 		"",
 		"",
+		nil,
 	)
 
 	require.Len(t, validationErrors, 1)
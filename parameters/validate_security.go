@@ -15,6 +15,7 @@ import (
 	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
 	"github.com/pb33f/libopenapi-validator/paths"
 )
 
@@ -27,24 +28,36 @@ func (v *paramValidator) ValidateSecurity(request *http.Request) (bool, []*error
 }
 
 func (v *paramValidator) ValidateSecurityWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	return v.ValidateMessageSecurityWithPathItem(message.FromHTTPRequest(request), pathItem, pathValue)
+}
+
+func (v *paramValidator) ValidateMessageSecurityWithPathItem(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
 	if pathItem == nil {
 		return false, []*errors.ValidationError{{
 			ValidationType:    helpers.PathValidation,
 			ValidationSubType: helpers.ValidationMissing,
-			Message:           fmt.Sprintf("%s Path '%s' not found", request.Method, request.URL.Path),
+			Message:           fmt.Sprintf("%s Path '%s' not found", request.Method(), request.URL().Path),
 			Reason: fmt.Sprintf("The %s request contains a path of '%s' "+
 				"however that path, or the %s method for that path does not exist in the specification",
-				request.Method, request.URL.Path, request.Method),
+				request.Method(), request.URL().Path, request.Method()),
 			SpecLine: -1,
 			SpecCol:  -1,
 			HowToFix: errors.HowToFixPath,
 		}}
 	}
+	if cancelled := errors.MessageContextDone(request); cancelled != nil {
+		return false, cancelled
+	}
 	if !v.options.SecurityValidation {
 		return true, nil
 	}
 	// extract security for the operation, falling back to document-level global security
-	security := helpers.EffectiveSecurityForOperation(request, pathItem, v.document.Security)
+	var security []*base.SecurityRequirement
+	if planned := v.operationPlan(request.Method(), pathItem); planned != nil {
+		security = planned.Security
+	} else {
+		security = helpers.EffectiveSecurityForMethod(request.Method(), pathItem, v.document.Security)
+	}
 
 	if len(security) == 0 {
 		return true, nil
@@ -78,7 +91,7 @@ func (v *paramValidator) ValidateSecurityWithPathItem(request *http.Request, pat
 						HowToFix:       "Add the missing security scheme to the components",
 					},
 				}
-				errors.PopulateValidationErrors(validationErrors, request, pathValue)
+				errors.PopulateMessageValidationErrors(validationErrors, request, pathValue)
 				requirementSatisfied = false
 				requirementErrors = append(requirementErrors, validationErrors...)
 				continue
@@ -86,6 +99,9 @@ func (v *paramValidator) ValidateSecurityWithPathItem(request *http.Request, pat
 
 			secScheme := v.document.Components.SecuritySchemes.GetOrZero(secName)
 			schemeValid, schemeErrors := v.validateSecurityScheme(secName, secScheme, pair.Value(), sec, request, pathValue)
+			if !schemeValid && errors.IsCancelled(schemeErrors) {
+				return false, schemeErrors
+			}
 			if !schemeValid {
 				requirementSatisfied = false
 				requirementErrors = append(requirementErrors, schemeErrors...)
@@ -108,7 +124,7 @@ func (v *paramValidator) validateSecurityScheme(
 	secScheme *v3.SecurityScheme,
 	scopes []string,
 	sec *base.SecurityRequirement,
-	request *http.Request,
+	request message.Request,
 	pathValue string,
 ) (bool, []*errors.ValidationError) {
 	if v.options.AuthenticationFunc != nil {
@@ -130,11 +146,18 @@ func (v *paramValidator) validateAuthenticationFunc(
 	secScheme *v3.SecurityScheme,
 	scopes []string,
 	sec *base.SecurityRequirement,
-	request *http.Request,
+	request message.Request,
 	pathValue string,
 ) (bool, []*errors.ValidationError) {
-	authErr := v.options.AuthenticationFunc(request.Context(), &config.AuthenticationInput{
-		Request:            request,
+	// the AuthenticationFunc is handed the *http.Request of the message, or one built from it, and the context
+	// the validation was given, or the context of the *http.Request when it wasn't given one.
+	httpRequest := message.ToHTTPRequest(request)
+	ctx := message.Context(request)
+	if ctx == nil {
+		ctx = httpRequest.Context()
+	}
+	authErr := v.options.AuthenticationFunc(ctx, &config.AuthenticationInput{
+		Request:            httpRequest,
 		SecuritySchemeName: secName,
 		SecurityScheme:     secScheme,
 		Scopes:             scopes,
@@ -142,6 +165,10 @@ func (v *paramValidator) validateAuthenticationFunc(
 	if authErr == nil {
 		return true, nil
 	}
+	// an AuthenticationFunc that gave up because the request was cancelled hasn't rejected the credentials.
+	if cancelled := errors.MessageContextDone(request); cancelled != nil {
+		return false, cancelled
+	}
 
 	validationErrors := []*errors.ValidationError{
 		{
@@ -154,17 +181,17 @@ func (v *paramValidator) validateAuthenticationFunc(
 			HowToFix:          fmt.Sprintf("Provide valid credentials for security scheme '%s'", secName),
 		},
 	}
-	errors.PopulateValidationErrors(validationErrors, request, pathValue)
+	errors.PopulateMessageValidationErrors(validationErrors, request, pathValue)
 	return false, validationErrors
 }
 
 func (v *paramValidator) validateHTTPSecurityScheme(
 	secScheme *v3.SecurityScheme,
 	sec *base.SecurityRequirement,
-	request *http.Request,
+	request message.Request,
 	pathValue string,
 ) (bool, []*errors.ValidationError) {
-	authorizationHeader := request.Header.Get("Authorization")
+	authorizationHeader := request.Header().Get("Authorization")
 	if authorizationHeader == "" {
 		validationErrors := []*errors.ValidationError{
 			{
@@ -177,7 +204,7 @@ func (v *paramValidator) validateHTTPSecurityScheme(
 				HowToFix:          "Add an 'Authorization' header to this request",
 			},
 		}
-		errors.PopulateValidationErrors(validationErrors, request, pathValue)
+		errors.PopulateMessageValidationErrors(validationErrors, request, pathValue)
 		return false, validationErrors
 	}
 	if len(authorizationHeader) < len(secScheme.Scheme) || !strings.EqualFold(authorizationHeader[:len(secScheme.Scheme)], secScheme.Scheme) {
@@ -193,7 +220,7 @@ func (v *paramValidator) validateHTTPSecurityScheme(
 					"for this request", secScheme.Scheme),
 			},
 		}
-		errors.PopulateValidationErrors(validationErrors, request, pathValue)
+		errors.PopulateMessageValidationErrors(validationErrors, request, pathValue)
 		return false, validationErrors
 	}
 	return true, nil
@@ -202,12 +229,12 @@ func (v *paramValidator) validateHTTPSecurityScheme(
 func (v *paramValidator) validateAPIKeySecurityScheme(
 	secScheme *v3.SecurityScheme,
 	sec *base.SecurityRequirement,
-	request *http.Request,
+	request message.Request,
 	pathValue string,
 ) (bool, []*errors.ValidationError) {
 	switch secScheme.In {
 	case "header":
-		if request.Header.Get(secScheme.Name) == "" {
+		if request.Header().Get(secScheme.Name) == "" {
 			validationErrors := []*errors.ValidationError{
 				{
 					Message:           fmt.Sprintf("API Key %s not found in header", secScheme.Name),
@@ -219,14 +246,14 @@ func (v *paramValidator) validateAPIKeySecurityScheme(
 					HowToFix:          fmt.Sprintf("Add the API Key via '%s' as a header of the request", secScheme.Name),
 				},
 			}
-			errors.PopulateValidationErrors(validationErrors, request, pathValue)
+			errors.PopulateMessageValidationErrors(validationErrors, request, pathValue)
 			return false, validationErrors
 		}
 		return true, nil
 
 	case "query":
-		if request.URL.Query().Get(secScheme.Name) == "" {
-			copyUrl := *request.URL
+		if request.URL().Query().Get(secScheme.Name) == "" {
+			copyUrl := *request.URL()
 			fixed := &copyUrl
 			q := fixed.Query()
 			q.Add(secScheme.Name, "your-api-key")
@@ -244,13 +271,13 @@ func (v *paramValidator) validateAPIKeySecurityScheme(
 						"of the URL, for example '%s'", secScheme.Name, fixed.String()),
 				},
 			}
-			errors.PopulateValidationErrors(validationErrors, request, pathValue)
+			errors.PopulateMessageValidationErrors(validationErrors, request, pathValue)
 			return false, validationErrors
 		}
 		return true, nil
 
 	case "cookie":
-		cookies := request.Cookies()
+		cookies := message.Cookies(request)
 		for _, cookie := range cookies {
 			if cookie.Name == secScheme.Name {
 				return true, nil
@@ -267,7 +294,7 @@ func (v *paramValidator) validateAPIKeySecurityScheme(
 				HowToFix:          fmt.Sprintf("Submit an API Key '%s' as a cookie with the request", secScheme.Name),
 			},
 		}
-		errors.PopulateValidationErrors(validationErrors, request, pathValue)
+		errors.PopulateMessageValidationErrors(validationErrors, request, pathValue)
 		return false, validationErrors
 	}
 
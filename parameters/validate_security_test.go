@@ -9,11 +9,16 @@ import (
 	"net/http"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/pb33f/testify/assert"
 
 	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
 	"github.com/pb33f/libopenapi-validator/paths"
 )
 
@@ -1518,3 +1523,102 @@ components:
 	assert.Empty(t, validationErrors)
 	assert.False(t, called)
 }
+
+func TestParamValidator_ValidateSecurity_AuthenticationFunc_Timeout(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /products:
+    get:
+      security:
+        - ApiKey: []
+        - Bearer: []
+components:
+  securitySchemes:
+    ApiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+    Bearer:
+      type: http
+      scheme: bearer
+`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	called := 0
+	authFn := func(ctx context.Context, input *config.AuthenticationInput) error {
+		called++
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	v := NewParameterValidator(&m.Model, config.WithAuthenticationFunc(authFn)).(MessageParameterValidator)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/products", nil)
+
+	pathItem := m.Model.Paths.PathItems.GetOrZero("/products")
+	valid, validationErrors := v.ValidateMessageSecurityWithPathItem(message.WithContext(ctx, message.FromHTTPRequest(request)), pathItem, "/products")
+	assert.False(t, valid)
+	assert.Len(t, validationErrors, 1)
+	assert.Equal(t, helpers.ContextValidation, validationErrors[0].ValidationType)
+	assert.Equal(t, helpers.ValidationTimeout, validationErrors[0].ValidationSubType)
+	// the second requirement isn't tried once the request has timed out.
+	assert.Equal(t, 1, called)
+}
+
+func TestParamValidator_CancelledContext(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /products/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: limit
+          in: query
+          schema:
+            type: integer
+        - name: X-Trace
+          in: header
+          schema:
+            type: string
+        - name: session
+          in: cookie
+          schema:
+            type: string
+`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewParameterValidator(&m.Model)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://things.com/products/abc?limit=x", nil)
+
+	// the context of the request is not checked, only the context given to the message.
+	valid, validationErrors := v.ValidatePathParams(request)
+	assert.False(t, valid)
+	assert.False(t, errors.IsCancelled(validationErrors))
+
+	mv := v.(MessageParameterValidator)
+	pathItem := m.Model.Paths.PathItems.GetOrZero("/products/{id}")
+	withContext := message.WithContext(ctx, message.FromHTTPRequest(request))
+	for name, validate := range map[string]func(message.Request, *v3.PathItem, string) (bool, []*errors.ValidationError){
+		"path":     mv.ValidateMessagePathParamsWithPathItem,
+		"query":    mv.ValidateMessageQueryParamsWithPathItem,
+		"header":   mv.ValidateMessageHeaderParamsWithPathItem,
+		"cookie":   mv.ValidateMessageCookieParamsWithPathItem,
+		"security": mv.ValidateMessageSecurityWithPathItem,
+	} {
+		valid, validationErrors := validate(withContext, pathItem, "/products/{id}")
+		assert.False(t, valid, name)
+		assert.True(t, errors.IsCancelled(validationErrors), name)
+		assert.Len(t, validationErrors, 1, name)
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package paths
+
+import (
+	"strings"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/radix"
+)
+
+// AnalyzePathTemplates inspects the path templates of a document and reports problems that would otherwise
+// be resolved silently at request time:
+//
+//   - templates that are equivalent (same hierarchy, different parameter names, e.g. /pets/{id} and /pets/{name})
+//   - templates that are ambiguous (some request paths match both and neither is more specific)
+//   - template parameters that an operation doesn't declare as 'in: path'
+//   - declared path parameters that don't appear in the template
+//   - templates that can only be matched by the regex fallback, not the radix tree
+//
+// Every finding is a *errors.ValidationError with a ValidationType of helpers.PathValidation, a sub-type
+// identifying the kind of finding, and the spec line and column it relates to.
+func AnalyzePathTemplates(document *v3.Document) []*errors.ValidationError {
+	if document == nil || document.Paths == nil || document.Paths.PathItems == nil {
+		return nil
+	}
+
+	var findings []*errors.ValidationError
+	var templates []*analyzedTemplate
+
+	for pair := orderedmap.First(document.Paths.PathItems); pair != nil; pair = pair.Next() {
+		path, pathItem := pair.Key(), pair.Value()
+		if pathItem == nil {
+			continue
+		}
+		tpl := analyzeTemplate(path)
+
+		// compare against every template defined before this one with the same depth.
+		// the later definition is the one reported, as it's the one that quietly loses or shadows.
+		for _, other := range templates {
+			if len(other.segments) != len(tpl.segments) {
+				continue
+			}
+			if tpl.equivalentTo(other) {
+				findings = append(findings, errors.PathTemplateEquivalent(path, other.path, pathItem))
+				continue
+			}
+			if tpl.overlaps(other) && !tpl.moreSpecificThan(other) && !other.moreSpecificThan(tpl) {
+				findings = append(findings, errors.PathTemplateAmbiguous(path, other.path, pathItem))
+			}
+		}
+		templates = append(templates, tpl)
+
+		findings = append(findings, analyzeTemplateParameters(tpl, pathItem)...)
+
+		if slow := radix.SlowPathSegments(path); len(slow) > 0 {
+			findings = append(findings, errors.PathTemplateSlowPath(path, slow, pathItem))
+		}
+	}
+	return findings
+}
+
+// analyzeTemplateParameters checks template parameters against the 'in: path' parameters of each operation.
+func analyzeTemplateParameters(tpl *analyzedTemplate, pathItem *v3.PathItem) []*errors.ValidationError {
+	var findings []*errors.ValidationError
+	for opPair := orderedmap.First(pathItem.GetOperations()); opPair != nil; opPair = opPair.Next() {
+		method, operation := strings.ToUpper(opPair.Key()), opPair.Value()
+		if operation == nil {
+			continue
+		}
+
+		declared := make(map[string]*v3.Parameter)
+		var declaredOrder []string
+		for _, params := range [][]*v3.Parameter{pathItem.Parameters, operation.Parameters} {
+			for _, param := range params {
+				if param == nil || param.In != helpers.Path {
+					continue
+				}
+				if _, seen := declared[param.Name]; !seen {
+					declaredOrder = append(declaredOrder, param.Name)
+				}
+				// operation level parameters override path level parameters
+				declared[param.Name] = param
+			}
+		}
+
+		for _, name := range tpl.params {
+			if _, ok := declared[name]; !ok {
+				findings = append(findings, errors.PathParameterUndeclared(tpl.path, method, name, operation))
+			}
+		}
+		for _, name := range declaredOrder {
+			if !tpl.hasParam(name) {
+				findings = append(findings, errors.PathParameterUnused(tpl.path, method, declared[name]))
+			}
+		}
+	}
+	return findings
+}
+
+type segmentKind int
+
+const (
+	segmentParam   segmentKind = iota // whole-segment parameter, e.g. {id}
+	segmentMixed                      // literal text mixed with parameters, e.g. {name}.{ext}
+	segmentLiteral                    // no parameters at all
+)
+
+// templateSegment is one '/' separated segment of a path template.
+type templateSegment struct {
+	raw    string
+	shape  string // raw with parameter names removed, e.g. "{}.{}"
+	kind   segmentKind
+	prefix string // literal text before the first parameter
+	suffix string // literal text after the last parameter
+}
+
+// analyzedTemplate is a path template broken into segments, with its parameter names.
+type analyzedTemplate struct {
+	path     string
+	segments []templateSegment
+	params   []string
+}
+
+func analyzeTemplate(path string) *analyzedTemplate {
+	tpl := &analyzedTemplate{path: path}
+	for _, raw := range strings.Split(strings.Trim(normalizePathForMatching(path, ""), helpers.Slash), helpers.Slash) {
+		if raw == "" {
+			continue
+		}
+		seg := templateSegment{raw: raw, shape: raw, kind: segmentLiteral}
+		idxs, err := helpers.BraceIndices(raw)
+		if err == nil && len(idxs) > 0 {
+			var shape strings.Builder
+			end := 0
+			for i := 0; i < len(idxs); i += 2 {
+				param := raw[idxs[i]+1 : idxs[i+1]-1]
+				shape.WriteString(raw[end:idxs[i]])
+				// custom regex parameters keep their pattern, as it changes what the segment matches
+				if _, pattern, found := strings.Cut(param, ":"); found {
+					shape.WriteString("{:" + pattern + "}")
+				} else {
+					shape.WriteString("{}")
+				}
+				end = idxs[i+1]
+				tpl.params = append(tpl.params, templateParamName(param))
+			}
+			shape.WriteString(raw[end:])
+			seg.shape = shape.String()
+			seg.prefix = raw[:idxs[0]]
+			seg.suffix = raw[idxs[len(idxs)-1]:]
+			seg.kind = segmentMixed
+			if seg.shape == "{}" {
+				seg.kind = segmentParam
+			}
+		}
+		tpl.segments = append(tpl.segments, seg)
+	}
+	return tpl
+}
+
+// templateParamName strips label ({.id}), matrix ({;id}), explode ({id*}) and custom regex ({id:[0-9]+})
+// decorations from a template parameter.
+func templateParamName(param string) string {
+	if name, _, found := strings.Cut(param, ":"); found {
+		param = name
+	}
+	param = strings.TrimSuffix(param, helpers.Asterisk)
+	param = strings.TrimPrefix(param, helpers.Period)
+	return strings.TrimPrefix(param, helpers.SemiColon)
+}
+
+func (t *analyzedTemplate) hasParam(name string) bool {
+	for _, p := range t.params {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// equivalentTo returns true if both templates match exactly the same request paths.
+func (t *analyzedTemplate) equivalentTo(other *analyzedTemplate) bool {
+	for i := range t.segments {
+		if t.segments[i].shape != other.segments[i].shape {
+			return false
+		}
+	}
+	return true
+}
+
+// overlaps returns true if at least one request path could match both templates.
+func (t *analyzedTemplate) overlaps(other *analyzedTemplate) bool {
+	for i := range t.segments {
+		if !segmentsOverlap(t.segments[i], other.segments[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// moreSpecificThan returns true if every segment of t is at least as specific as the matching segment
+// of other, which is how both the radix tree and the regex fallback decide between overlapping templates.
+// Two mixed segments of a different shape are not comparable.
+func (t *analyzedTemplate) moreSpecificThan(other *analyzedTemplate) bool {
+	for i := range t.segments {
+		a, b := t.segments[i], other.segments[i]
+		if a.kind == segmentMixed && b.kind == segmentMixed && a.shape != b.shape {
+			return false
+		}
+		if a.kind < b.kind {
+			return false
+		}
+	}
+	return true
+}
+
+func segmentsOverlap(a, b templateSegment) bool {
+	if a.kind > b.kind {
+		a, b = b, a
+	}
+	switch {
+	case a.kind == segmentParam:
+		return true
+	case a.kind == segmentLiteral:
+		return a.raw == b.raw
+	case b.kind == segmentLiteral:
+		// a is mixed, so check if the literal satisfies its pattern
+		rgx, err := helpers.GetRegexForPath(a.raw)
+		return err == nil && rgx.MatchString(b.raw)
+	case a.shape == b.shape:
+		return true
+	default:
+		// two mixed segments; they can only share a value if their fixed prefixes and suffixes agree.
+		return (strings.HasPrefix(a.prefix, b.prefix) || strings.HasPrefix(b.prefix, a.prefix)) &&
+			(strings.HasSuffix(a.suffix, b.suffix) || strings.HasSuffix(b.suffix, a.suffix))
+	}
+}
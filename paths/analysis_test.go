@@ -0,0 +1,248 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package paths
+
+import (
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+func buildAnalysisModel(t *testing.T, spec string) *v3.Document {
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, errs := doc.BuildV3Model()
+	require.Nil(t, errs)
+	return &m.Model
+}
+
+func findingsOfType(findings []*errors.ValidationError, subType string) []*errors.ValidationError {
+	var filtered []*errors.ValidationError
+	for _, f := range findings {
+		if f.ValidationSubType == subType {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+func TestAnalyzePathTemplates_Clean(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+  /pets/mine:
+    get:
+      operationId: myPets
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+    get:
+      operationId: getPet
+  /pets/{id}:feed:
+    post:
+      operationId: feedPet
+      parameters:
+        - name: id
+          in: path
+          required: true
+  /files/{name}.{ext}:
+    get:
+      parameters:
+        - name: name
+          in: path
+        - name: ext
+          in: path
+`
+	assert.Empty(t, AnalyzePathTemplates(buildAnalysisModel(t, spec)))
+}
+
+func TestAnalyzePathTemplates_NilDocument(t *testing.T) {
+	assert.Nil(t, AnalyzePathTemplates(nil))
+	assert.Nil(t, AnalyzePathTemplates(&v3.Document{}))
+}
+
+func TestAnalyzePathTemplates_Equivalent(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pets/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+  /pets/{name}:
+    delete:
+      parameters:
+        - name: name
+          in: path
+`
+	findings := AnalyzePathTemplates(buildAnalysisModel(t, spec))
+	require.Len(t, findings, 1)
+	f := findings[0]
+	assert.Equal(t, helpers.PathValidation, f.ValidationType)
+	assert.Equal(t, helpers.PathTemplateEquivalent, f.ValidationSubType)
+	assert.Equal(t, "/pets/{name}", f.SpecPath)
+	assert.Equal(t, "Path '/pets/{name}' is equivalent to path '/pets/{id}'", f.Message)
+	assert.Equal(t, 8, f.SpecLine)
+	assert.Equal(t, 3, f.SpecCol)
+}
+
+func TestAnalyzePathTemplates_Ambiguous(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /{entity}/me:
+    get:
+      parameters:
+        - name: entity
+          in: path
+  /books/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+  /files/{name}.json:
+    get:
+      parameters:
+        - name: name
+          in: path
+  /files/report.{ext}:
+    get:
+      parameters:
+        - name: ext
+          in: path
+  /files/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+`
+	findings := AnalyzePathTemplates(buildAnalysisModel(t, spec))
+	ambiguous := findingsOfType(findings, helpers.PathTemplateAmbiguous)
+	require.Len(t, ambiguous, 3)
+	assert.Equal(t, "Path '/books/{id}' is ambiguous with path '/{entity}/me'", ambiguous[0].Message)
+	assert.Equal(t, 8, ambiguous[0].SpecLine)
+	assert.Equal(t, "Path '/files/report.{ext}' is ambiguous with path '/files/{name}.json'", ambiguous[1].Message)
+	// '/files/me' matches both; '/files/{id}' is less specific than both mixed templates so they aren't reported
+	assert.Equal(t, "Path '/files/{id}' is ambiguous with path '/{entity}/me'", ambiguous[2].Message)
+	assert.Len(t, findings, 3)
+}
+
+func TestAnalyzePathTemplates_NoOverlap(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /files/{name}.json:
+    get:
+      parameters:
+        - name: name
+          in: path
+  /files/{name}.xml:
+    get:
+      parameters:
+        - name: name
+          in: path
+  /users/{id}/a:
+    get:
+      parameters:
+        - name: id
+          in: path
+  /{entity}/b:
+    get:
+      parameters:
+        - name: entity
+          in: path
+`
+	assert.Empty(t, AnalyzePathTemplates(buildAnalysisModel(t, spec)))
+}
+
+func TestAnalyzePathTemplates_Parameters(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /users/{userId}/posts/{postId}:
+    parameters:
+      - name: userId
+        in: path
+        required: true
+    get:
+      operationId: getPost
+    put:
+      operationId: updatePost
+      parameters:
+        - name: postId
+          in: path
+          required: true
+        - name: slug
+          in: path
+          required: true
+        - name: slug
+          in: query
+`
+	findings := AnalyzePathTemplates(buildAnalysisModel(t, spec))
+	require.Len(t, findings, 2)
+
+	undeclared := findingsOfType(findings, helpers.PathParameterUndeclared)
+	require.Len(t, undeclared, 1)
+	assert.Equal(t, "Path parameter 'postId' is not declared for GET '/users/{userId}/posts/{postId}'", undeclared[0].Message)
+	assert.Equal(t, "postId", undeclared[0].ParameterName)
+	assert.Equal(t, "GET", undeclared[0].RequestMethod)
+	assert.Equal(t, 8, undeclared[0].SpecLine)
+	assert.Equal(t, 5, undeclared[0].SpecCol)
+
+	unused := findingsOfType(findings, helpers.PathParameterUnused)
+	require.Len(t, unused, 1)
+	assert.Equal(t, "Path parameter 'slug' is not used in path '/users/{userId}/posts/{postId}'", unused[0].Message)
+	assert.Equal(t, "PUT", unused[0].RequestMethod)
+	assert.Equal(t, 16, unused[0].SpecLine)
+	assert.Equal(t, 17, unused[0].SpecCol)
+}
+
+func TestAnalyzePathTemplates_DecoratedParameters(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{;id}/{.color*}/{size:[0-9]+}:
+    get:
+      parameters:
+        - name: id
+          in: path
+        - name: color
+          in: path
+        - name: size
+          in: path
+`
+	findings := AnalyzePathTemplates(buildAnalysisModel(t, spec))
+	assert.Empty(t, findingsOfType(findings, helpers.PathParameterUndeclared))
+	assert.Empty(t, findingsOfType(findings, helpers.PathParameterUnused))
+}
+
+func TestAnalyzePathTemplates_SlowPath(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /items/{id:[0-9]+}.json:
+    get:
+      parameters:
+        - name: id
+          in: path
+  /items/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+`
+	findings := AnalyzePathTemplates(buildAnalysisModel(t, spec))
+	slow := findingsOfType(findings, helpers.PathTemplateSlowPath)
+	require.Len(t, slow, 1)
+	assert.Equal(t, "/items/{id:[0-9]+}.json", slow[0].SpecPath)
+	assert.Contains(t, slow[0].Reason, "{id:[0-9]+}.json")
+	assert.Equal(t, 3, slow[0].SpecLine)
+	assert.Len(t, findings, 1)
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
 )
 
 // FindPath will find the path in the document that matches the request path. If a successful match was found, then
@@ -26,23 +27,52 @@ import (
 // The third return value will be the path that was found in the document, as it pertains to the contract, so all path
 // parameters will not have been replaced with their values from the request - allowing model lookups.
 //
-// This function first tries a fast O(k) radix tree lookup (where k is path depth). If the radix tree
-// doesn't find a match, it falls back to regex-based matching which handles complex path patterns
-// like matrix-style ({;param}), label-style ({.param}), and OData-style (entities('{Entity}')).
+// This function first tries a fast O(k) radix tree lookup (where k is path depth). The radix tree handles
+// literal segments, whole-segment parameters ({id}) and segments mixing literals with parameters, such as
+// {name}.{ext}, {year}-{month} or OData-style entities('{Entity}'). If the radix tree doesn't find a match,
+// it falls back to regex-based matching which handles the remaining patterns, like custom regex parameters
+// ({id:[0-9]+}) and templates with fragments.
 //
 // Path matching follows the OpenAPI specification: literal (concrete) paths take precedence over
 // parameterized paths, regardless of definition order in the specification.
 func FindPath(request *http.Request, document *v3.Document, options *config.ValidationOptions) (*v3.PathItem, []*errors.ValidationError, string) {
-	stripped := StripRequestPath(request, document)
+	return findPath(request.Method, request.URL, document, options)
+}
+
+// FindMessagePath works the same way as FindPath, for a transport-neutral message.Request.
+func FindMessagePath(request message.Request, document *v3.Document, options *config.ValidationOptions) (*v3.PathItem, []*errors.ValidationError, string) {
+	return findPath(request.Method(), request.URL(), document, options)
+}
+
+// findPath finds the path of a request by its method and URL, and reports the outcome to the observer and
+// coverage recorder of the options.
+func findPath(method string, u *url.URL, document *v3.Document, options *config.ValidationOptions) (*v3.PathItem, []*errors.ValidationError, string) {
+	pathItem, errs, pathValue := matchPath(method, u, document, options)
+	if options != nil && options.Observer != nil {
+		event := config.PathEvent{Method: method, RequestPath: u.Path, PathTemplate: pathValue}
+		if len(errs) > 0 {
+			options.Observer.PathNotFound(event)
+		} else {
+			options.Observer.PathMatched(event)
+		}
+	}
+	if options != nil && options.Coverage != nil && len(errs) > 0 {
+		options.Coverage.RecordUnmatched(method, u.Path, pathValue)
+	}
+	return pathItem, errs, pathValue
+}
+
+func matchPath(method string, u *url.URL, document *v3.Document, options *config.ValidationOptions) (*v3.PathItem, []*errors.ValidationError, string) {
+	stripped := stripPath(u, document)
 
 	// Fast path: try radix tree first (O(k) where k = path depth)
 	// If no path lookup is provided, we will fall back to regex-based matching.
 	if options != nil && options.PathTree != nil {
 		if pathItem, matchedPath, found := options.PathTree.Lookup(stripped); found {
-			if pathHasMethod(pathItem, request.Method) {
+			if pathHasMethod(pathItem, method) {
 				return pathItem, nil, matchedPath
 			}
-			return pathItem, missingOperationError(request, matchedPath), matchedPath
+			return pathItem, missingOperationError(method, u, matchedPath), matchedPath
 		}
 	}
 
@@ -79,7 +109,7 @@ func FindPath(request *http.Request, document *v3.Document, options *config.Vali
 
 		// Compute specificity score and check if method exists
 		score := computeSpecificityScore(path)
-		hasMethod := pathHasMethod(pathItem, request.Method)
+		hasMethod := pathHasMethod(pathItem, method)
 
 		candidates = append(candidates, pathCandidate{
 			pathItem:  pathItem,
@@ -94,16 +124,16 @@ func FindPath(request *http.Request, document *v3.Document, options *config.Vali
 			{
 				ValidationType:    helpers.PathValidation,
 				ValidationSubType: helpers.ValidationMissing,
-				Message:           fmt.Sprintf("%s Path '%s' not found", request.Method, request.URL.Path),
+				Message:           fmt.Sprintf("%s Path '%s' not found", method, u.Path),
 				Reason: fmt.Sprintf("The %s request contains a path of '%s' "+
 					"however that path, or the %s method for that path does not exist in the specification",
-					request.Method, request.URL.Path, request.Method),
+					method, u.Path, method),
 				SpecLine: -1,
 				SpecCol:  -1,
 				HowToFix: errors.HowToFixPath,
 			},
 		}
-		errors.PopulateValidationErrors(validationErrors, request, "")
+		populateValidationErrors(validationErrors, method, u, "")
 		return nil, validationErrors, ""
 	}
 
@@ -114,7 +144,7 @@ func FindPath(request *http.Request, document *v3.Document, options *config.Vali
 	}
 
 	// path matches exist but none have the required method
-	return bestOverall.pathItem, missingOperationError(request, bestOverall.path), bestOverall.path
+	return bestOverall.pathItem, missingOperationError(method, u, bestOverall.path), bestOverall.path
 }
 
 // normalizePathForMatching removes the fragment from a path template unless
@@ -156,12 +186,22 @@ func getBasePaths(document *v3.Document) []string {
 
 // StripRequestPath strips the base path from the request path, based on the server paths provided in the specification
 func StripRequestPath(request *http.Request, document *v3.Document) string {
+	return stripPath(request.URL, document)
+}
+
+// StripMessagePath strips the base path from the path of a transport-neutral message.Request, based on the
+// server paths provided in the specification
+func StripMessagePath(request message.Request, document *v3.Document) string {
+	return stripPath(request.URL(), document)
+}
+
+func stripPath(u *url.URL, document *v3.Document) string {
 	basePaths := getBasePaths(document)
 
 	// strip any base path
-	stripped := stripBaseFromPath(request.URL.EscapedPath(), basePaths)
-	if request.URL.Fragment != "" {
-		stripped = fmt.Sprintf("%s#%s", stripped, request.URL.Fragment)
+	stripped := stripBaseFromPath(u.EscapedPath(), basePaths)
+	if u.Fragment != "" {
+		stripped = fmt.Sprintf("%s#%s", stripped, u.Fragment)
 	}
 	if !strings.HasPrefix(stripped, "/") {
 		stripped = "/" + stripped
@@ -233,17 +273,25 @@ func comparePaths(mapped, requested, basePaths []string, regexCache config.Regex
 }
 
 // missingOperationError returns a validation error for when a path was found but the HTTP method doesn't exist.
-func missingOperationError(request *http.Request, matchedPath string) []*errors.ValidationError {
+func missingOperationError(method string, u *url.URL, matchedPath string) []*errors.ValidationError {
 	validationErrors := []*errors.ValidationError{{
 		ValidationType:    helpers.PathValidation,
 		ValidationSubType: helpers.ValidationMissingOperation,
-		Message:           fmt.Sprintf("%s Path '%s' not found", request.Method, request.URL.Path),
-		Reason: fmt.Sprintf("The %s method for that path does not exist in the specification",
-			request.Method),
-		SpecLine: -1,
-		SpecCol:  -1,
-		HowToFix: errors.HowToFixPath,
+		Message:           fmt.Sprintf("%s Path '%s' not found", method, u.Path),
+		Reason:            fmt.Sprintf("The %s method for that path does not exist in the specification", method),
+		SpecLine:          -1,
+		SpecCol:           -1,
+		HowToFix:          errors.HowToFixPath,
 	}}
-	errors.PopulateValidationErrors(validationErrors, request, matchedPath)
+	populateValidationErrors(validationErrors, method, u, matchedPath)
 	return validationErrors
 }
+
+// populateValidationErrors is the equivalent of errors.PopulateValidationErrors for a method and URL.
+func populateValidationErrors(validationErrors []*errors.ValidationError, method string, u *url.URL, path string) {
+	for _, validationError := range validationErrors {
+		validationError.SpecPath = path
+		validationError.RequestMethod = method
+		validationError.RequestPath = u.Path
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/pb33f/libopenapi"
 	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/message"
 	"github.com/pb33f/libopenapi-validator/radix"
 	"github.com/pb33f/testify/assert"
 )
@@ -1665,3 +1666,141 @@ paths:
 	assert.True(t, errs[0].IsOperationMissingError())
 	assert.Equal(t, "/users/{id}", foundPath)
 }
+
+func TestFindPath_WithPathTree_MixedSegments(t *testing.T) {
+	// Mixed literal/parameter segments are resolved by the radix tree without touching the regex fallback
+	spec := `openapi: 3.1.0
+info:
+  title: Radix Tree Test
+  version: 1.0.0
+paths:
+  /files/{name}.{ext}:
+    get:
+      operationId: getFile
+  /reports/{year}-{month}:
+    get:
+      operationId: getReport
+  /users/{id}:
+    get:
+      operationId: getUser
+  /users/{id}:activate:
+    post:
+      operationId: activateUser
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	regexCache := &sync.Map{}
+	pathTree := radix.BuildPathTree(&m.Model)
+	opts := config.NewValidationOptions(config.WithPathTree(pathTree), config.WithRegexCache(regexCache))
+
+	tests := []struct {
+		method      string
+		url         string
+		operationId string
+		path        string
+	}{
+		{http.MethodGet, "https://api.com/files/report.pdf", "getFile", "/files/{name}.{ext}"},
+		{http.MethodGet, "https://api.com/reports/2024-06", "getReport", "/reports/{year}-{month}"},
+		{http.MethodPost, "https://api.com/users/42:activate", "activateUser", "/users/{id}:activate"},
+		{http.MethodGet, "https://api.com/users/42", "getUser", "/users/{id}"},
+	}
+
+	for _, tc := range tests {
+		request, _ := http.NewRequest(tc.method, tc.url, nil)
+		pathItem, errs, foundPath := FindPath(request, &m.Model, opts)
+
+		assert.Nil(t, errs, tc.url)
+		if !assert.NotNil(t, pathItem, tc.url) {
+			continue
+		}
+		assert.Equal(t, tc.path, foundPath)
+		op := pathItem.Get
+		if tc.method == http.MethodPost {
+			op = pathItem.Post
+		}
+		assert.Equal(t, tc.operationId, op.OperationId)
+	}
+
+	regexCache.Range(func(key, value any) bool {
+		t.Errorf("regex fallback should not be used, found cached segment %v", key)
+		return true
+	})
+}
+
+func TestFindPath_MixedSegmentPrecedence_TreeAndRegexAgree(t *testing.T) {
+	// whichever is defined first, the radix tree and the regex fallback prefer literals over mixed segments,
+	// and mixed segments over whole-segment parameters
+	spec := `openapi: 3.1.0
+info:
+  title: Precedence Test
+  version: 1.0.0
+paths:
+  /files/{name}:
+    get:
+      operationId: getFile
+  /files/{name}.{ext}:
+    get:
+      operationId: getFileWithExtension
+  /files/index.html:
+    get:
+      operationId: getIndex
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	withTree := config.NewValidationOptions(config.WithPathTree(radix.BuildPathTree(&m.Model)))
+	withoutTree := config.NewValidationOptions(config.DisablePathTree())
+
+	tests := []struct {
+		url  string
+		path string
+	}{
+		{"https://api.com/files/index.html", "/files/index.html"},
+		{"https://api.com/files/report.pdf", "/files/{name}.{ext}"},
+		{"https://api.com/files/report", "/files/{name}"},
+	}
+	for _, tc := range tests {
+		for name, opts := range map[string]*config.ValidationOptions{"tree": withTree, "regex": withoutTree} {
+			request, _ := http.NewRequest(http.MethodGet, tc.url, nil)
+			_, errs, foundPath := FindPath(request, &m.Model, opts)
+			assert.Nil(t, errs, "%s %s", name, tc.url)
+			assert.Equal(t, tc.path, foundPath, "%s %s", name, tc.url)
+		}
+	}
+}
+
+func TestFindMessagePath_APIGateway(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/api
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, err := message.NewAPIGatewayRequest(&message.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/api/pets/1"})
+	assert.NoError(t, err)
+
+	pathItem, errs, pathValue := FindMessagePath(request, &m.Model, nil)
+	assert.NotNil(t, pathItem)
+	assert.Empty(t, errs)
+	assert.Equal(t, "/pets/{id}", pathValue)
+	assert.Equal(t, "/pets/1", StripMessagePath(request, &m.Model))
+
+	request, _ = message.NewAPIGatewayRequest(&message.APIGatewayProxyRequest{HTTPMethod: http.MethodPost, Path: "/api/pets/1"})
+	_, errs, _ = FindMessagePath(request, &m.Model, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, http.MethodPost, errs[0].RequestMethod)
+	assert.Equal(t, "/api/pets/1", errs[0].RequestPath)
+	assert.Equal(t, "/pets/{id}", errs[0].SpecPath)
+
+	request, _ = message.NewAPIGatewayRequest(&message.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/nope"})
+	pathItem, errs, _ = FindMessagePath(request, &m.Model, nil)
+	assert.Nil(t, pathItem)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "GET Path '/nope' not found", errs[0].Message)
+}
@@ -4,6 +4,7 @@
 package paths
 
 import (
+	"math"
 	"net/http"
 	"strings"
 
@@ -85,13 +86,52 @@ func selectMatches(candidates []pathCandidate) (withMethod, highest *pathCandida
 	for i := range candidates {
 		c := &candidates[i]
 
-		if c.hasMethod && (withMethod == nil || c.score > withMethod.score) {
+		if c.hasMethod && (withMethod == nil || c.outranks(withMethod)) {
 			withMethod = c
 		}
 
-		if highest == nil || c.score > highest.score {
+		if highest == nil || c.outranks(highest) {
 			highest = c
 		}
 	}
 	return withMethod, highest
 }
+
+// outranks reports whether a candidate is more specific than another. candidates with the same score are
+// ranked the way the radix tree ranks them, segment by segment from the left: a literal segment wins over a
+// segment mixing literals and parameters, which wins over a whole-segment parameter, and of two mixed
+// segments the one with more literal text wins. when they rank the same, the first one defined wins.
+func (c *pathCandidate) outranks(other *pathCandidate) bool {
+	if c.score != other.score {
+		return c.score > other.score
+	}
+	segments := strings.Split(c.path, "/")
+	otherSegments := strings.Split(other.path, "/")
+	for i := 0; i < len(segments) && i < len(otherSegments); i++ {
+		rank, otherRank := segmentRank(segments[i]), segmentRank(otherSegments[i])
+		if rank != otherRank {
+			return rank > otherRank
+		}
+	}
+	return false
+}
+
+// segmentRank ranks a segment of a path template: literal segments rank highest, then segments mixing literals
+// and parameters by the length of their literal text, then whole-segment parameters.
+func segmentRank(seg string) int {
+	if !isParameterSegment(seg) {
+		return math.MaxInt
+	}
+	literal, depth := 0, 0
+	for i := 0; i < len(seg); i++ {
+		switch {
+		case seg[i] == '{':
+			depth++
+		case seg[i] == '}':
+			depth--
+		case depth == 0:
+			literal++
+		}
+	}
+	return literal
+}
@@ -271,6 +271,33 @@ func TestSelectMatches(t *testing.T) {
 			expectedWithMethod: "/pets/{petId}",
 			expectedHighest:    "/pets/{petId}",
 		},
+		{
+			name: "equal scores - mixed segment wins over parameter",
+			candidates: []pathCandidate{
+				{path: "/files/{name}", score: 1001, hasMethod: true},
+				{path: "/files/{name}.{ext}", score: 1001, hasMethod: true},
+			},
+			expectedWithMethod: "/files/{name}.{ext}",
+			expectedHighest:    "/files/{name}.{ext}",
+		},
+		{
+			name: "equal scores - more literal text wins",
+			candidates: []pathCandidate{
+				{path: "/entities('{id}')", score: 1, hasMethod: true},
+				{path: "/entities({id})", score: 1, hasMethod: true},
+			},
+			expectedWithMethod: "/entities('{id}')",
+			expectedHighest:    "/entities('{id}')",
+		},
+		{
+			name: "equal scores - leftmost segment decides",
+			candidates: []pathCandidate{
+				{path: "/{tenant}/{id}.json", score: 2, hasMethod: true},
+				{path: "/{tenant}.v1/{id}", score: 2, hasMethod: true},
+			},
+			expectedWithMethod: "/{tenant}.v1/{id}",
+			expectedHighest:    "/{tenant}.v1/{id}",
+		},
 		{
 			name:               "empty candidates",
 			candidates:         []pathCandidate{},
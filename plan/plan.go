@@ -0,0 +1,362 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+// Package plan holds validation plans. A plan is everything validation needs to know about an operation,
+// resolved once when the validator is built: its merged parameters, effective security, media types, response
+// codes, the names strict mode treats as declared, and the schema cache keys of its bodies and parameters.
+// Validating a request walks the plan of its operation instead of deriving all of it again.
+//
+// Plans don't change once built, apart from the compiled schemas they hold on to, and are safe to share between
+// goroutines.
+package plan
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/cache"
+)
+
+// Plans holds the plans of the operations of a document, by path item and method.
+type Plans struct {
+	operations       map[*v3.PathItem][]*Operation
+	parameterSchemas map[*base.Schema]*Schema
+	schemas          map[*Schema]struct{}
+}
+
+// Operation is the plan of one operation, as it is reached by one HTTP method.
+type Operation struct {
+	Method    string
+	Path      string
+	Operation *v3.Operation
+
+	// Parameters are the path level parameters followed by the operation parameters, in the same way as
+	// helpers.ExtractParamsForOperation returns them.
+	Parameters []*v3.Parameter
+
+	// Security holds the security requirement alternatives that apply to the operation, any one of them
+	// satisfies it. SecurityHeaders are the headers those requirements declare.
+	Security        []*base.SecurityRequirement
+	SecurityHeaders []string
+
+	// Names declared by the parameters and security of the operation, for strict mode.
+	Query   Names
+	Headers Names
+	Cookies Names
+
+	RequestBody *RequestBody // nil when the operation has no request body
+	Responses   *Responses   // nil when the operation has no response codes
+}
+
+// Names is a set of declared names, and the list of them reported in strict mode errors.
+type Names struct {
+	Set  map[string]bool
+	List []string
+}
+
+// RequestBody is the plan of a request body.
+type RequestBody struct {
+	Required bool
+	Content  []*MediaType
+}
+
+// Responses is the plan of the responses of an operation.
+type Responses struct {
+	Codes   []*Response // status codes and ranges, in document order
+	Default *Response   // nil when there is no default response with content
+}
+
+// Response is the plan of one response.
+type Response struct {
+	Code     string
+	Response *v3.Response
+	Content  []*MediaType
+	status   int  // exact status code, or zero
+	class    int  // status code class of an 'NXX' range
+	ranged   bool // the response is declared for a range of status codes
+}
+
+// MediaType is the plan of a media type of a request or response body.
+type MediaType struct {
+	Name      string
+	MediaType *v3.MediaType
+	Schema    *Schema // nil when the media type has no schema
+	kind      string
+	subtype   string
+}
+
+// Schema is a schema of a plan and the key it is compiled under in the schema cache, so finding the compiled
+// schema takes no hashing. Once the schema is compiled the plan holds on to it, along with the schema cache it
+// came from, and only looks it up again in other caches: validators that compile schemas with other options use
+// their own cache and share the plan. Schemas of a cache that evicts are never held, so an evicted schema is
+// freed rather than kept alive by the plan.
+type Schema struct {
+	Schema *base.Schema
+	Key    uint64
+	held   atomic.Pointer[heldEntry]
+}
+
+// heldEntry is a compiled schema held by a plan, and the schema cache it was compiled into.
+type heldEntry struct {
+	schemaCache cache.SchemaCache
+	entry       *cache.SchemaCacheEntry
+}
+
+// NewPlans creates an empty set of plans.
+func NewPlans() *Plans {
+	return &Plans{
+		operations:       make(map[*v3.PathItem][]*Operation),
+		parameterSchemas: make(map[*base.Schema]*Schema),
+		schemas:          make(map[*Schema]struct{}),
+	}
+}
+
+// Add adds the plan of an operation of a path item. Plans are added while they are built, before they are used.
+func (p *Plans) Add(pathItem *v3.PathItem, operation *Operation) {
+	if p == nil || pathItem == nil || operation == nil {
+		return
+	}
+	p.operations[pathItem] = append(p.operations[pathItem], operation)
+	for _, schema := range operation.schemas() {
+		p.schemas[schema] = struct{}{}
+	}
+}
+
+// AddParameterSchema adds the compiled form of a parameter schema, parameters share schemas between
+// operations, so they are found by schema.
+func (p *Plans) AddParameterSchema(schema *Schema) {
+	if p == nil || schema == nil || schema.Schema == nil {
+		return
+	}
+	if _, ok := p.parameterSchemas[schema.Schema]; ok {
+		return
+	}
+	p.parameterSchemas[schema.Schema] = schema
+	p.schemas[schema] = struct{}{}
+}
+
+// For returns the plan of the operation of a path item that a method reaches, or nil when there is none.
+func (p *Plans) For(pathItem *v3.PathItem, method string) *Operation {
+	if p == nil || pathItem == nil {
+		return nil
+	}
+	for _, operation := range p.operations[pathItem] {
+		if operation.Method == method {
+			return operation
+		}
+	}
+	return nil
+}
+
+// ParameterSchema returns the compiled form of a parameter schema from the schema cache, or nil when it has not
+// been compiled.
+func (p *Plans) ParameterSchema(schema *base.Schema, schemaCache cache.SchemaCache) *cache.SchemaCacheEntry {
+	if p == nil || schema == nil {
+		return nil
+	}
+	return p.parameterSchemas[schema].Compiled(schemaCache)
+}
+
+// Hold makes the plans hold on to the schemas compiled in the schema cache, so validating with that cache
+// doesn't have to look them up. It's called once the cache is warmed, schemas compiled later on are held the
+// first time they are found. Nothing is held for a cache that evicts.
+func (p *Plans) Hold(schemaCache cache.SchemaCache) {
+	if p == nil || !holds(schemaCache) {
+		return
+	}
+	for schema := range p.schemas {
+		if entry := schema.load(schemaCache); entry != nil {
+			schema.held.Store(&heldEntry{schemaCache: schemaCache, entry: entry})
+		}
+	}
+}
+
+// Release drops the compiled schemas the plans hold, so they are freed along with the schema cache.
+func (p *Plans) Release() {
+	if p == nil {
+		return
+	}
+	for schema := range p.schemas {
+		schema.held.Store(nil)
+	}
+}
+
+// Uncompiled returns how many schemas of the plans are not compiled in the schema cache.
+func (p *Plans) Uncompiled(schemaCache cache.SchemaCache) int {
+	if p == nil {
+		return 0
+	}
+	uncompiled := 0
+	for schema := range p.schemas {
+		if schema.load(schemaCache) == nil {
+			uncompiled++
+		}
+	}
+	return uncompiled
+}
+
+// Len returns the number of operation plans.
+func (p *Plans) Len() int {
+	if p == nil {
+		return 0
+	}
+	n := 0
+	for _, operations := range p.operations {
+		n += len(operations)
+	}
+	return n
+}
+
+func (o *Operation) schemas() []*Schema {
+	var schemas []*Schema
+	add := func(content []*MediaType) {
+		for _, mediaType := range content {
+			if mediaType.Schema != nil {
+				schemas = append(schemas, mediaType.Schema)
+			}
+		}
+	}
+	if o.RequestBody != nil {
+		add(o.RequestBody.Content)
+	}
+	if o.Responses != nil {
+		for _, response := range o.Responses.Codes {
+			add(response.Content)
+		}
+		if o.Responses.Default != nil {
+			add(o.Responses.Default.Content)
+		}
+	}
+	return schemas
+}
+
+// NewNames creates a set of declared names, keyed by their lowercase form when fold is set.
+func NewNames(names []string, fold bool) Names {
+	declared := Names{Set: make(map[string]bool, len(names)), List: names}
+	for _, name := range names {
+		if fold {
+			name = strings.ToLower(name)
+		}
+		declared.Set[name] = true
+	}
+	return declared
+}
+
+// Match returns the media type of the request body that a content type (without parameters) matches, exactly or
+// by a media range such as 'application/*', or nil when none does.
+func (b *RequestBody) Match(contentType string) *MediaType {
+	if b == nil {
+		return nil
+	}
+	if mediaType := matchExact(b.Content, contentType); mediaType != nil {
+		return mediaType
+	}
+	kind, subtype, _ := strings.Cut(contentType, "/")
+	for _, mediaType := range b.Content {
+		if (mediaType.kind == "*" || mediaType.kind == kind) && (mediaType.subtype == "*" || mediaType.subtype == subtype) {
+			return mediaType
+		}
+	}
+	return nil
+}
+
+// Find returns the response declared for a status code, or for its 'NXX' range, or nil when neither is.
+func (r *Responses) Find(status int) *Response {
+	if r == nil {
+		return nil
+	}
+	for _, response := range r.Codes {
+		if response.status != 0 && response.status == status {
+			return response
+		}
+	}
+	for _, response := range r.Codes {
+		if response.ranged && response.class == status/100 {
+			return response
+		}
+	}
+	return nil
+}
+
+// MediaType returns the media type of the response that a content type (without parameters) is, or nil.
+func (r *Response) MediaType(contentType string) *MediaType {
+	if r == nil {
+		return nil
+	}
+	return matchExact(r.Content, contentType)
+}
+
+// NewResponse creates the plan of a response declared under a status code, a range of them such as '2XX', or
+// 'default'.
+func NewResponse(code string, response *v3.Response, content []*MediaType) *Response {
+	planned := &Response{Code: code, Response: response, Content: content}
+	if status, err := strconv.Atoi(code); err == nil && strconv.Itoa(status) == code && status != 0 {
+		planned.status = status
+	} else if prefix, ok := strings.CutSuffix(code, "XX"); ok {
+		if class, err := strconv.Atoi(prefix); err == nil && strconv.Itoa(class) == prefix {
+			planned.class, planned.ranged = class, true
+		}
+	}
+	return planned
+}
+
+// NewMediaType creates the plan of a media type, with its schema when it has one.
+func NewMediaType(name string, mediaType *v3.MediaType, schema *Schema) *MediaType {
+	kind, subtype, _ := strings.Cut(name, "/")
+	return &MediaType{Name: name, MediaType: mediaType, Schema: schema, kind: kind, subtype: subtype}
+}
+
+// NewSchema creates a schema of a plan, compiled under key in the schema cache.
+func NewSchema(schema *base.Schema, key uint64) *Schema {
+	return &Schema{Schema: schema, Key: key}
+}
+
+// Compiled returns the compiled schema held by the plan when it came from the schema cache, otherwise it's
+// loaded from the schema cache, or nil when it has not been compiled yet.
+func (s *Schema) Compiled(schemaCache cache.SchemaCache) *cache.SchemaCacheEntry {
+	if s == nil || schemaCache == nil {
+		return nil
+	}
+	// caches of different types never compare equal, so a cache that can't be compared is never held.
+	if held := s.held.Load(); held != nil && held.schemaCache == schemaCache {
+		return held.entry
+	}
+	entry := s.load(schemaCache)
+	if entry != nil && s.held.Load() == nil && holds(schemaCache) {
+		s.held.Store(&heldEntry{schemaCache: schemaCache, entry: entry})
+	}
+	return entry
+}
+
+// load returns the compiled schema from the schema cache, or nil when it has not been compiled yet.
+func (s *Schema) load(schemaCache cache.SchemaCache) *cache.SchemaCacheEntry {
+	if s == nil || schemaCache == nil {
+		return nil
+	}
+	entry, ok := schemaCache.Load(s.Key)
+	if !ok || entry == nil || entry.CompiledSchema == nil {
+		return nil
+	}
+	return entry
+}
+
+// holds returns true when plans can hold on to the schemas compiled in a schema cache: the cache can be compared,
+// to tell it apart from the caches of other validators, and it doesn't evict them.
+func holds(schemaCache cache.SchemaCache) bool {
+	return schemaCache != nil && reflect.TypeOf(schemaCache).Comparable() && !cache.Evicts(schemaCache)
+}
+
+func matchExact(content []*MediaType, contentType string) *MediaType {
+	for _, mediaType := range content {
+		if mediaType.Name == contentType {
+			return mediaType
+		}
+	}
+	return nil
+}
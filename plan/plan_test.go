@@ -0,0 +1,221 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/cache"
+	"github.com/pb33f/testify/assert"
+)
+
+func TestRequestBody_Match(t *testing.T) {
+	body := &RequestBody{Content: []*MediaType{
+		NewMediaType("application/json", &v3.MediaType{}, nil),
+		NewMediaType("text/*", &v3.MediaType{}, nil),
+		NewMediaType("*/*", &v3.MediaType{}, nil),
+	}}
+
+	assert.Equal(t, "application/json", body.Match("application/json").Name)
+	assert.Equal(t, "text/*", body.Match("text/plain").Name)
+	assert.Equal(t, "*/*", body.Match("image/png").Name)
+
+	exact := &RequestBody{Content: []*MediaType{
+		NewMediaType("application/*", &v3.MediaType{}, nil),
+		NewMediaType("application/xml", &v3.MediaType{}, nil),
+	}}
+	// an exact match wins over an earlier media range.
+	assert.Equal(t, "application/xml", exact.Match("application/xml").Name)
+	assert.Nil(t, exact.Match("text/plain"))
+
+	var none *RequestBody
+	assert.Nil(t, none.Match("application/json"))
+}
+
+func TestResponses_Find(t *testing.T) {
+	responses := &Responses{Codes: []*Response{
+		NewResponse("2XX", &v3.Response{Description: "range"}, nil),
+		NewResponse("201", &v3.Response{Description: "created"}, nil),
+		NewResponse("0404", &v3.Response{Description: "padded"}, nil),
+		NewResponse("5xx", &v3.Response{Description: "lowercase"}, nil),
+	}}
+
+	// exact codes are found before ranges declared ahead of them.
+	assert.Equal(t, "201", responses.Find(201).Code)
+	assert.Equal(t, "2XX", responses.Find(200).Code)
+	assert.Nil(t, responses.Find(404))
+	assert.Nil(t, responses.Find(500))
+	assert.Nil(t, responses.Find(0))
+
+	var none *Responses
+	assert.Nil(t, none.Find(200))
+}
+
+func TestResponse_MediaType(t *testing.T) {
+	response := NewResponse("200", &v3.Response{}, []*MediaType{
+		NewMediaType("application/json", &v3.MediaType{}, nil),
+		NewMediaType("application/*", &v3.MediaType{}, nil),
+	})
+	assert.Equal(t, "application/json", response.MediaType("application/json").Name)
+	// responses match media types exactly.
+	assert.Nil(t, response.MediaType("application/xml"))
+
+	var none *Response
+	assert.Nil(t, none.MediaType("application/json"))
+}
+
+func TestNewNames(t *testing.T) {
+	names := NewNames([]string{"X-Request-ID", "Accept"}, true)
+	assert.True(t, names.Set["x-request-id"])
+	assert.True(t, names.Set["accept"])
+	assert.Equal(t, []string{"X-Request-ID", "Accept"}, names.List)
+
+	names = NewNames([]string{"Limit"}, false)
+	assert.True(t, names.Set["Limit"])
+	assert.False(t, names.Set["limit"])
+
+	names = NewNames(nil, false)
+	assert.Empty(t, names.Set)
+	assert.Nil(t, names.List)
+}
+
+func TestPlans(t *testing.T) {
+	plans := NewPlans()
+	pathItem := &v3.PathItem{}
+	body := NewSchema(&base.Schema{}, 1)
+	response := NewSchema(&base.Schema{}, 2)
+	parameter := NewSchema(&base.Schema{}, 3)
+
+	get := &Operation{Method: "GET", Path: "/pets"}
+	post := &Operation{
+		Method:      "POST",
+		Path:        "/pets",
+		RequestBody: &RequestBody{Content: []*MediaType{NewMediaType("application/json", &v3.MediaType{}, body)}},
+		Responses: &Responses{
+			Codes:   []*Response{NewResponse("201", &v3.Response{}, []*MediaType{NewMediaType("application/json", &v3.MediaType{}, response)})},
+			Default: NewResponse("default", &v3.Response{}, []*MediaType{NewMediaType("application/json", &v3.MediaType{}, response)}),
+		},
+	}
+	plans.Add(pathItem, get)
+	plans.Add(pathItem, post)
+	plans.AddParameterSchema(parameter)
+	plans.AddParameterSchema(NewSchema(parameter.Schema, 4))
+
+	assert.Equal(t, 2, plans.Len())
+	assert.Same(t, get, plans.For(pathItem, "GET"))
+	assert.Same(t, post, plans.For(pathItem, "POST"))
+	assert.Nil(t, plans.For(pathItem, "PUT"))
+	assert.Nil(t, plans.For(&v3.PathItem{}, "GET"))
+
+	schemaCache := cache.NewDefaultCache()
+	assert.Equal(t, 3, plans.Uncompiled(schemaCache))
+	assert.Nil(t, plans.ParameterSchema(parameter.Schema, schemaCache))
+
+	compiled := &cache.SchemaCacheEntry{CompiledSchema: &jsonschema.Schema{}}
+	schemaCache.Store(1, compiled)
+	schemaCache.Store(3, &cache.SchemaCacheEntry{CompiledSchema: &jsonschema.Schema{}})
+	schemaCache.Store(2, &cache.SchemaCacheEntry{}) // not compiled
+	assert.Equal(t, 1, plans.Uncompiled(schemaCache))
+	assert.NotNil(t, plans.ParameterSchema(parameter.Schema, schemaCache))
+	assert.Nil(t, plans.ParameterSchema(&base.Schema{}, schemaCache))
+
+	// compiled schemas are held by the plan for the cache they came from, and looked up in other caches.
+	assert.Same(t, compiled, body.Compiled(schemaCache))
+	assert.Nil(t, body.Compiled(cache.NewDefaultCache()))
+	assert.Nil(t, body.Compiled(nil))
+	schemaCache.Release()
+	assert.Same(t, compiled, body.Compiled(schemaCache))
+	assert.Nil(t, response.Compiled(schemaCache))
+	other := cache.NewDefaultCache()
+	otherCompiled := &cache.SchemaCacheEntry{CompiledSchema: &jsonschema.Schema{}}
+	other.Store(1, otherCompiled)
+	assert.Same(t, otherCompiled, body.Compiled(other))
+	assert.Same(t, compiled, body.Compiled(schemaCache))
+	plans.Release()
+	assert.Nil(t, body.Compiled(schemaCache))
+
+	// holding a warmed cache replaces what was held for another one.
+	plans.Hold(other)
+	schemaCache.Store(1, compiled)
+	assert.Same(t, otherCompiled, body.Compiled(other))
+	assert.Same(t, compiled, body.Compiled(schemaCache))
+	other.Release()
+	assert.Same(t, otherCompiled, body.Compiled(other))
+	plans.Hold(nil)
+
+	// caches that can't be compared are always looked up.
+	plans.Release()
+	uncomparable := mapCache{1: compiled}
+	plans.Hold(uncomparable)
+	assert.Same(t, compiled, body.Compiled(uncomparable))
+	assert.Same(t, compiled, body.Compiled(uncomparable))
+	assert.Nil(t, body.held.Load())
+
+	// caches that evict are always looked up, so the plan doesn't keep evicted schemas alive.
+	bounded := cache.NewBoundedCache(cache.WithMaxEntries(1))
+	bounded.Store(1, compiled)
+	plans.Hold(bounded)
+	assert.Same(t, compiled, body.Compiled(bounded))
+	assert.Nil(t, body.held.Load())
+	bounded.Store(2, &cache.SchemaCacheEntry{CompiledSchema: &jsonschema.Schema{}})
+	assert.Nil(t, body.Compiled(bounded))
+
+	var none *Plans
+	assert.Nil(t, none.For(pathItem, "GET"))
+	assert.Nil(t, none.ParameterSchema(parameter.Schema, schemaCache))
+	assert.Zero(t, none.Uncompiled(schemaCache))
+	assert.Zero(t, none.Len())
+	none.Add(pathItem, get)
+	none.AddParameterSchema(parameter)
+	none.Hold(schemaCache)
+	none.Release()
+
+	var noSchema *Schema
+	assert.Nil(t, noSchema.Compiled(schemaCache))
+}
+
+// mapCache is a schema cache of a type that can't be compared.
+type mapCache map[uint64]*cache.SchemaCacheEntry
+
+func (c mapCache) Load(key uint64) (*cache.SchemaCacheEntry, bool) {
+	entry, ok := c[key]
+	return entry, ok
+}
+
+func (c mapCache) Store(key uint64, value *cache.SchemaCacheEntry) { c[key] = value }
+
+func (c mapCache) Range(f func(key uint64, value *cache.SchemaCacheEntry) bool) {
+	for key, value := range c {
+		if !f(key, value) {
+			return
+		}
+	}
+}
+
+func BenchmarkSchema_Compiled(b *testing.B) {
+	schemaCache := cache.NewDefaultCache()
+	for key := uint64(0); key < 1000; key++ {
+		schemaCache.Store(key, &cache.SchemaCacheEntry{CompiledSchema: &jsonschema.Schema{}})
+	}
+
+	b.Run("held", func(b *testing.B) {
+		schema := NewSchema(&base.Schema{}, 500)
+		schema.Compiled(schemaCache)
+		for b.Loop() {
+			schema.Compiled(schemaCache)
+		}
+	})
+
+	b.Run("cache lookup", func(b *testing.B) {
+		schema := NewSchema(&base.Schema{}, 500)
+		for b.Loop() {
+			schema.load(schemaCache)
+		}
+	})
+}
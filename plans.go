@@ -0,0 +1,189 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/plan"
+	"github.com/pb33f/libopenapi-validator/schema_validation"
+)
+
+// planMethods are the methods that reach the operations of a path item, as helpers.ExtractOperation finds them.
+var planMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodDelete,
+	http.MethodOptions,
+	http.MethodHead,
+	http.MethodPatch,
+	http.MethodTrace,
+}
+
+// buildPlans builds the validation plan of every operation in the document. Each plan is derived with the same
+// helpers that validation uses without plans, so both find the same parameters, security and schemas.
+func buildPlans(doc *v3.Document) *plan.Plans {
+	if doc == nil || doc.Paths == nil || doc.Paths.PathItems == nil {
+		return nil
+	}
+	b := &planBuilder{
+		plans:   plan.NewPlans(),
+		version: helpers.VersionToFloat(doc.Version),
+		schemas: make(map[uint64]*plan.Schema),
+	}
+	var securitySchemes map[string]*v3.SecurityScheme
+	if doc.Components != nil && doc.Components.SecuritySchemes != nil {
+		securitySchemes = make(map[string]*v3.SecurityScheme)
+		for pair := doc.Components.SecuritySchemes.First(); pair != nil; pair = pair.Next() {
+			securitySchemes[pair.Key()] = pair.Value()
+		}
+	}
+
+	for pathPair := doc.Paths.PathItems.First(); pathPair != nil; pathPair = pathPair.Next() {
+		pathItem := pathPair.Value()
+		if pathItem == nil {
+			continue
+		}
+		for _, method := range planMethods {
+			request := &http.Request{Method: method}
+			operation := helpers.ExtractOperation(request, pathItem)
+			if operation == nil {
+				continue
+			}
+			planned := &plan.Operation{
+				Method:     method,
+				Path:       pathPair.Key(),
+				Operation:  operation,
+				Parameters: slices.Clip(slices.Clone(helpers.ExtractParamsForOperation(request, pathItem))),
+				Security:   helpers.EffectiveSecurityForOperation(request, pathItem, doc.Security),
+			}
+			if securitySchemes != nil {
+				planned.SecurityHeaders = helpers.ExtractSecurityHeaderNames(planned.Security, securitySchemes)
+			}
+			planned.Query = plan.NewNames(parameterNames(planned.Parameters, helpers.Query), false)
+			planned.Headers = plan.NewNames(parameterNames(planned.Parameters, helpers.Header), true)
+			planned.Cookies = plan.NewNames(parameterNames(planned.Parameters, helpers.Cookie), false)
+			for _, header := range planned.SecurityHeaders {
+				planned.Headers.Set[strings.ToLower(header)] = true
+			}
+			for _, param := range planned.Parameters {
+				b.parameter(param)
+			}
+			planned.RequestBody = b.requestBody(operation.RequestBody)
+			planned.Responses = b.responses(operation.Responses)
+			b.plans.Add(pathItem, planned)
+		}
+	}
+	return b.plans
+}
+
+// planBuilder shares the schemas of plans between the operations that use them.
+type planBuilder struct {
+	plans   *plan.Plans
+	version float32
+	schemas map[uint64]*plan.Schema
+}
+
+func (b *planBuilder) requestBody(requestBody *v3.RequestBody) *plan.RequestBody {
+	if requestBody == nil {
+		return nil
+	}
+	planned := &plan.RequestBody{Content: b.content(requestBody.Content, schema_validation.SchemaValidationPurposeRequestBody)}
+	if requestBody.Required != nil {
+		planned.Required = *requestBody.Required
+	}
+	return planned
+}
+
+func (b *planBuilder) responses(responses *v3.Responses) *plan.Responses {
+	if responses == nil || responses.Codes == nil {
+		return nil
+	}
+	planned := &plan.Responses{}
+	for codePair := responses.Codes.First(); codePair != nil; codePair = codePair.Next() {
+		if response := codePair.Value(); response != nil {
+			planned.Codes = append(planned.Codes, plan.NewResponse(codePair.Key(), response,
+				b.content(response.Content, schema_validation.SchemaValidationPurposeResponseBody)))
+		}
+	}
+	if responses.Default != nil && responses.Default.Content != nil {
+		planned.Default = plan.NewResponse("default", responses.Default,
+			b.content(responses.Default.Content, schema_validation.SchemaValidationPurposeResponseBody))
+	}
+	return planned
+}
+
+func (b *planBuilder) content(content *orderedmap.Map[string, *v3.MediaType], purpose schema_validation.SchemaValidationPurpose) []*plan.MediaType {
+	if content == nil {
+		return nil
+	}
+	var planned []*plan.MediaType
+	for contentPair := content.First(); contentPair != nil; contentPair = contentPair.Next() {
+		mediaType := contentPair.Value()
+		if mediaType == nil {
+			continue
+		}
+		var schema *plan.Schema
+		if mediaType.Schema != nil {
+			schema = b.schema(mediaType.Schema.Schema(), b.version, purpose)
+		}
+		planned = append(planned, plan.NewMediaType(contentPair.Key(), mediaType, schema))
+	}
+	return planned
+}
+
+// parameter adds the schema of a parameter, the one warmParameterSchema compiles.
+func (b *planBuilder) parameter(param *v3.Parameter) {
+	if param == nil {
+		return
+	}
+	var schema *base.Schema
+	if param.Schema != nil {
+		schema = param.Schema.Schema()
+	} else if param.Content != nil {
+		for contentPair := param.Content.First(); contentPair != nil; contentPair = contentPair.Next() {
+			if mediaType := contentPair.Value(); mediaType != nil && mediaType.Schema != nil {
+				schema = mediaType.Schema.Schema()
+				break
+			}
+		}
+	}
+	b.plans.AddParameterSchema(b.schema(schema, b.version, schema_validation.SchemaValidationPurposeGeneric))
+}
+
+func (b *planBuilder) schema(
+	schema *base.Schema,
+	version float32,
+	purpose schema_validation.SchemaValidationPurpose,
+) *plan.Schema {
+	if schema == nil || schema.GoLow() == nil {
+		return nil
+	}
+	key := schema_validation.SchemaCacheKey(schema.GoLow().Hash(), version, purpose)
+	if planned, ok := b.schemas[key]; ok && planned.Schema == schema {
+		return planned
+	}
+	planned := plan.NewSchema(schema, key)
+	b.schemas[key] = planned
+	return planned
+}
+
+func parameterNames(params []*v3.Parameter, in string) []string {
+	var names []string
+	for _, param := range params {
+		if param != nil && param.In == in {
+			names = append(names, param.Name)
+		}
+	}
+	return names
+}
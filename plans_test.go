@@ -0,0 +1,447 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"weak"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/cache"
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+)
+
+const plansSpec = `openapi: 3.1.0
+info:
+  title: Plans
+  version: 1.0.0
+security:
+  - apiKey: []
+components:
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+paths:
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+    get:
+      parameters:
+        - name: fields
+          in: query
+          schema:
+            type: string
+            enum: [name, tag]
+        - name: X-Trace
+          in: header
+          schema:
+            type: string
+            minLength: 3
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [name]
+                properties:
+                  name:
+                    type: string
+        4XX:
+          description: client error
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [message]
+                properties:
+                  message:
+                    type: string
+        default:
+          description: error
+          content:
+            application/problem+json:
+              schema:
+                type: object
+                required: [title]
+    put:
+      security: []
+      requestBody:
+        required: true
+        content:
+          application/*:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+      responses:
+        '204':
+          description: updated`
+
+func TestPlans_Built(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(plansSpec))
+	require.NoError(t, err)
+	v, errs := NewValidator(doc)
+	require.Empty(t, errs)
+
+	plans := v.(*validator).options.Plans
+	require.NotNil(t, plans)
+	// GET, PUT and HEAD, which reaches the GET operation.
+	assert.Equal(t, 3, plans.Len())
+
+	pathItem := v.(*validator).v3Model.Paths.PathItems.GetOrZero("/pets/{id}")
+	get := plans.For(pathItem, http.MethodGet)
+	require.NotNil(t, get)
+	assert.Equal(t, "/pets/{id}", get.Path)
+	assert.Len(t, get.Parameters, 3)
+	assert.Equal(t, []string{"X-API-Key"}, get.SecurityHeaders)
+	assert.True(t, get.Headers.Set["x-trace"])
+	assert.True(t, get.Headers.Set["x-api-key"])
+	assert.Equal(t, []string{"X-Trace"}, get.Headers.List)
+	assert.Equal(t, []string{"fields"}, get.Query.List)
+	require.NotNil(t, get.Responses)
+	assert.Equal(t, "4XX", get.Responses.Find(404).Code)
+	assert.NotNil(t, get.Responses.Default)
+
+	// the schemas of the plans are compiled while warming.
+	schemaCache := v.(*validator).options.SchemaCache
+	assert.NotNil(t, get.Responses.Find(200).MediaType("application/json").Schema.Compiled(schemaCache))
+	assert.NotNil(t, plans.ParameterSchema(get.Parameters[1].Schema.Schema(), schemaCache))
+
+	put := plans.For(pathItem, http.MethodPut)
+	require.NotNil(t, put)
+	assert.Empty(t, put.Security)
+	assert.Nil(t, put.SecurityHeaders)
+	assert.True(t, put.RequestBody.Required)
+	assert.Equal(t, "application/*", put.RequestBody.Match("application/json").Name)
+	assert.Nil(t, put.Responses.Find(204).Content)
+	assert.Nil(t, plans.For(pathItem, http.MethodPost))
+
+	// the plans hold on to the warmed schemas until the validator is released.
+	okSchema := get.Responses.Find(200).MediaType("application/json").Schema
+	compiled := okSchema.Compiled(schemaCache)
+	v.Release()
+	assert.Nil(t, okSchema.Compiled(schemaCache))
+	schemaCache.Store(okSchema.Key, compiled)
+	assert.Same(t, compiled, okSchema.Compiled(schemaCache))
+
+	v, errs = NewValidator(doc, config.DisableValidationPlans())
+	require.Empty(t, errs)
+	assert.Nil(t, v.(*validator).options.Plans)
+}
+
+// countingSchemaCache counts how many times each schema is looked up in a schema cache.
+type countingSchemaCache struct {
+	*cache.DefaultCache
+	loads sync.Map // key -> *atomic.Int64
+}
+
+func (c *countingSchemaCache) Load(key uint64) (*cache.SchemaCacheEntry, bool) {
+	counter, _ := c.loads.LoadOrStore(key, new(atomic.Int64))
+	counter.(*atomic.Int64).Add(1)
+	return c.DefaultCache.Load(key)
+}
+
+func (c *countingSchemaCache) count(key uint64) int64 {
+	if counter, ok := c.loads.Load(key); ok {
+		return counter.(*atomic.Int64).Load()
+	}
+	return 0
+}
+
+func TestPlans_HoldWarmedSchemas(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(plansSpec))
+	require.NoError(t, err)
+	schemaCache := &countingSchemaCache{DefaultCache: cache.NewDefaultCache()}
+	v, errs := NewValidator(doc, config.WithSchemaCache(schemaCache))
+	require.Empty(t, errs)
+	plans := v.(*validator).options.Plans
+	pathItem := v.(*validator).v3Model.Paths.PathItems.GetOrZero("/pets/{id}")
+	requestSchema := plans.For(pathItem, http.MethodPut).RequestBody.Match("application/json").Schema
+	responseSchema := plans.For(pathItem, http.MethodGet).Responses.Find(200).MediaType("application/json").Schema
+	schemaCache.loads.Clear()
+
+	request, _ := http.NewRequest(http.MethodPut, "https://api.com/pets/1", strings.NewReader(`{"name":"fido"}`))
+	request.Header.Set("Content-Type", "application/json")
+	valid, validationErrors := v.ValidateHttpRequestSync(request)
+	assert.True(t, valid, "%v", validationErrors)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://api.com/pets/1?fields=name", nil)
+	request.Header.Set("X-API-Key", "k")
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"name":"fido"}`)),
+	}
+	valid, validationErrors = v.ValidateHttpRequestResponse(request, response)
+	assert.True(t, valid, "%v", validationErrors)
+
+	// the warmed body schemas come from the plans, not the schema cache.
+	assert.Zero(t, schemaCache.count(requestSchema.Key))
+	assert.Zero(t, schemaCache.count(responseSchema.Key))
+}
+
+// recordingSchemaCache keeps a weak pointer to every entry stored in a bounded cache, to find which of them are
+// still in memory.
+type recordingSchemaCache struct {
+	*cache.BoundedCache
+	mu     sync.Mutex
+	stored []weak.Pointer[cache.SchemaCacheEntry]
+}
+
+func (c *recordingSchemaCache) Store(key uint64, value *cache.SchemaCacheEntry) {
+	c.mu.Lock()
+	c.stored = append(c.stored, weak.Make(value))
+	c.mu.Unlock()
+	c.BoundedCache.Store(key, value)
+}
+
+func (c *recordingSchemaCache) live() int {
+	runtime.GC()
+	runtime.GC()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	live := 0
+	for _, stored := range c.stored {
+		if stored.Value() != nil {
+			live++
+		}
+	}
+	return live
+}
+
+func TestPlans_BoundedCacheFreesEvictedSchemas(t *testing.T) {
+	var spec strings.Builder
+	spec.WriteString("openapi: 3.1.0\ninfo:\n  title: Bounded\n  version: 1.0.0\npaths:\n")
+	const operations = 20
+	for i := range operations {
+		fmt.Fprintf(&spec, `  /things%[1]d:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [field%[1]d]
+              properties:
+                field%[1]d:
+                  type: string
+      responses:
+        '204':
+          description: ok
+`, i)
+	}
+	doc, err := libopenapi.NewDocument([]byte(spec.String()))
+	require.NoError(t, err)
+
+	const maxEntries = 3
+	schemaCache := &recordingSchemaCache{BoundedCache: cache.NewBoundedCache(cache.WithMaxEntries(maxEntries))}
+	v, errs := NewValidator(doc, config.WithSchemaCache(schemaCache))
+	require.Empty(t, errs)
+
+	for range 2 {
+		for i := range operations {
+			request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("https://api.com/things%d", i),
+				strings.NewReader(fmt.Sprintf(`{"field%d":"value"}`, i)))
+			request.Header.Set("Content-Type", "application/json")
+			valid, validationErrors := v.ValidateHttpRequestSync(request)
+			require.True(t, valid, "%v", validationErrors)
+		}
+	}
+
+	// every schema was compiled, but only the ones the cache keeps are still in memory.
+	require.Greater(t, len(schemaCache.stored), operations)
+	assert.LessOrEqual(t, schemaCache.live(), maxEntries)
+	runtime.KeepAlive(v)
+}
+
+func TestPlans_SameResults(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(plansSpec))
+	require.NoError(t, err)
+
+	newRequest := func(method, url, body string, headers map[string]string) *http.Request {
+		request, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		for name, value := range headers {
+			request.Header.Set(name, value)
+		}
+		return request
+	}
+	newResponse := func(status int, contentType, body string) *http.Response {
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": []string{contentType}},
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+		}
+	}
+
+	type exchange struct {
+		name     string
+		request  func() *http.Request
+		response func() *http.Response
+	}
+	exchanges := []exchange{
+		{"valid", func() *http.Request {
+			return newRequest(http.MethodGet, "https://api.com/pets/1?fields=name", "", map[string]string{"X-API-Key": "k", "X-Trace": "abcd"})
+		}, func() *http.Response { return newResponse(200, "application/json", `{"name":"rex"}`) }},
+		{"invalid params", func() *http.Request {
+			return newRequest(http.MethodGet, "https://api.com/pets/one?fields=age&extra=1", "", map[string]string{"X-Trace": "a", "X-Other": "b"})
+		}, func() *http.Response { return newResponse(404, "application/json", `{}`) }},
+		{"default response", func() *http.Request {
+			return newRequest(http.MethodGet, "https://api.com/pets/1", "", map[string]string{"X-API-Key": "k"})
+		}, func() *http.Response { return newResponse(500, "application/problem+json", `{}`) }},
+		{"undeclared response", func() *http.Request {
+			return newRequest(http.MethodGet, "https://api.com/pets/1", "", map[string]string{"X-API-Key": "k"})
+		}, func() *http.Response { return newResponse(500, "text/plain", `oops`) }},
+		{"media range body", func() *http.Request {
+			return newRequest(http.MethodPut, "https://api.com/pets/1", `{}`, map[string]string{"Content-Type": "application/json"})
+		}, func() *http.Response { return newResponse(204, "", "") }},
+		{"unknown media type", func() *http.Request {
+			return newRequest(http.MethodPut, "https://api.com/pets/1", `name=rex`, map[string]string{"Content-Type": "text/plain"})
+		}, func() *http.Response { return newResponse(204, "", "") }},
+		{"head", func() *http.Request {
+			return newRequest(http.MethodHead, "https://api.com/pets/1", "", map[string]string{"X-API-Key": "k"})
+		}, func() *http.Response { return newResponse(200, "application/json", `{"name":"rex"}`) }},
+	}
+
+	messages := func(validationErrors []*errors.ValidationError) []string {
+		var found []string
+		for _, validationError := range validationErrors {
+			found = append(found, validationError.Message)
+		}
+		return found
+	}
+
+	planned, errs := NewValidator(doc, config.WithStrictMode())
+	require.Empty(t, errs)
+	unplanned, errs := NewValidator(doc, config.WithStrictMode(), config.DisableValidationPlans())
+	require.Empty(t, errs)
+
+	for _, ex := range exchanges {
+		t.Run(ex.name, func(t *testing.T) {
+			plannedValid, plannedErrors := planned.ValidateHttpRequestSync(ex.request())
+			unplannedValid, unplannedErrors := unplanned.ValidateHttpRequestSync(ex.request())
+			assert.Equal(t, unplannedValid, plannedValid)
+			assert.Equal(t, messages(unplannedErrors), messages(plannedErrors))
+
+			plannedValid, plannedErrors = planned.ValidateHttpResponse(ex.request(), ex.response())
+			unplannedValid, unplannedErrors = unplanned.ValidateHttpResponse(ex.request(), ex.response())
+			assert.Equal(t, unplannedValid, plannedValid)
+			assert.Equal(t, messages(unplannedErrors), messages(plannedErrors))
+		})
+	}
+}
+
+func TestPlans_FewerAllocations(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(plansSpec))
+	require.NoError(t, err)
+
+	allocations := func(opts ...config.Option) float64 {
+		v, errs := NewValidator(doc, append(opts, config.WithStrictMode())...)
+		require.Empty(t, errs)
+		defer v.Release()
+		return testing.AllocsPerRun(50, func() {
+			request, _ := http.NewRequest(http.MethodGet, "https://api.com/pets/1?fields=name", nil)
+			request.Header.Set("X-API-Key", "k")
+			request.Header.Set("X-Trace", "abcd")
+			valid, _ := v.ValidateHttpRequestSync(request)
+			require.True(t, valid)
+		})
+	}
+	assert.Less(t, allocations(), allocations(config.DisableValidationPlans()))
+}
+
+func TestPlans_PolicyChangesSchemas(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: Plans
+  version: 1.0.0
+paths:
+  /a:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Contact'
+      responses:
+        '204':
+          description: ok
+  /b:
+    post:
+      x-validation:
+        formatAssertions: true
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Contact'
+      responses:
+        '204':
+          description: ok
+components:
+  schemas:
+    Contact:
+      type: object
+      properties:
+        email:
+          type: string
+          format: email`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+
+	post := func(path string) *http.Request {
+		request, _ := http.NewRequest(http.MethodPost, "https://api.com"+path, bytes.NewBufferString(`{"email":"nope"}`))
+		request.Header.Set("Content-Type", "application/json")
+		return request
+	}
+
+	for _, opts := range [][]config.Option{nil, {config.DisableValidationPlans()}} {
+		v, errs := NewValidator(doc, opts...)
+		require.Empty(t, errs)
+
+		// the plan of /b is shared, its schema is compiled with the format assertions of its policy.
+		valid, _ := v.ValidateHttpRequestSync(post("/a"))
+		assert.True(t, valid)
+		valid, validationErrors := v.ValidateHttpRequestSync(post("/b"))
+		assert.False(t, valid)
+		assert.Len(t, validationErrors, 1)
+		valid, _ = v.ValidateHttpRequestSync(post("/a"))
+		assert.True(t, valid)
+		v.Release()
+	}
+}
+
+func BenchmarkValidateHttpRequest_Planned(b *testing.B) {
+	doc, _ := libopenapi.NewDocument([]byte(plansSpec))
+	v, _ := NewValidator(doc, config.WithStrictMode())
+	request, _ := http.NewRequest(http.MethodGet, "https://api.com/pets/1?fields=name", nil)
+	request.Header.Set("X-API-Key", "k")
+	request.Header.Set("X-Trace", "abcd")
+	b.ReportAllocs()
+	for b.Loop() {
+		v.ValidateHttpRequestSync(request)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+// Package policy reads validation policies from the 'x-validation' vendor extension, so API owners can
+// control validation from the specification rather than from Go code.
+//
+// A policy can be set on the document, path items, operations, parameters and schemas:
+//
+//	x-validation:
+//	  strict: true           # strict mode (undeclared properties, params, headers and cookies)
+//	  ignorePaths: [...]     # extra strict mode ignore paths
+//	  rejectReadOnly: true   # strict mode: reject readOnly properties in requests
+//	  rejectWriteOnly: true  # strict mode: reject writeOnly properties in responses
+//	  security: false        # security validation
+//	  xml: true              # XML body validation
+//	  urlencoded: true       # urlencoded body validation
+//	  formatAssertions: true # format assertions
+//	  contentAssertions: true
+//	  skipRequest: true      # don't validate requests
+//	  skipResponse: true     # don't validate responses
+//	  mode: warn             # enforce (default), warn or off
+//
+// Document, path item and operation policies are merged, the most specific level winning, and resolved into
+// an effective *config.ValidationOptions for each operation. Parameter policies only support 'mode', and apply
+// to the validation errors of that parameter. Schema policies only support 'strict', and turn strict mode
+// on or off for the schema and everything below it.
+package policy
+
+import (
+	"fmt"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+	"go.yaml.in/yaml/v4"
+
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+// ExtensionName is the vendor extension validation policies are read from.
+const ExtensionName = "x-validation"
+
+// Mode controls what happens to validation errors.
+type Mode string
+
+const (
+	// ModeEnforce reports validation errors, this is the default.
+	ModeEnforce Mode = "enforce"
+
+	// ModeWarn logs validation errors as warnings, and doesn't fail validation.
+	ModeWarn Mode = "warn"
+
+	// ModeOff skips validation entirely.
+	ModeOff Mode = "off"
+)
+
+// Policy is a validation policy read from an 'x-validation' extension. Unset fields are nil,
+// so they don't override a less specific policy when merged.
+type Policy struct {
+	Strict            *bool    `yaml:"strict"`
+	IgnorePaths       []string `yaml:"ignorePaths"`
+	RejectReadOnly    *bool    `yaml:"rejectReadOnly"`
+	RejectWriteOnly   *bool    `yaml:"rejectWriteOnly"`
+	Security          *bool    `yaml:"security"`
+	XML               *bool    `yaml:"xml"`
+	URLEncoded        *bool    `yaml:"urlencoded"`
+	FormatAssertions  *bool    `yaml:"formatAssertions"`
+	ContentAssertions *bool    `yaml:"contentAssertions"`
+	SkipRequest       *bool    `yaml:"skipRequest"`
+	SkipResponse      *bool    `yaml:"skipResponse"`
+	SampleRate        *float64 `yaml:"sampleRate"`
+	Mode              Mode     `yaml:"mode"`
+}
+
+// Parse reads the policy from a set of extensions. It returns nil when there is no 'x-validation' extension.
+func Parse(extensions *orderedmap.Map[string, *yaml.Node]) (*Policy, error) {
+	if extensions == nil {
+		return nil, nil
+	}
+	node := extensions.GetOrZero(ExtensionName)
+	if node == nil {
+		return nil, nil
+	}
+	var p Policy
+	if err := node.Decode(&p); err != nil {
+		return nil, fmt.Errorf("invalid %s extension: %w", ExtensionName, err)
+	}
+	switch p.Mode {
+	case "", ModeEnforce, ModeWarn, ModeOff:
+	default:
+		return nil, fmt.Errorf("invalid %s extension: unknown mode '%s', expected one of '%s', '%s' or '%s'",
+			ExtensionName, p.Mode, ModeEnforce, ModeWarn, ModeOff)
+	}
+	if p.SampleRate != nil && !(*p.SampleRate >= 0 && *p.SampleRate <= 1) {
+		return nil, fmt.Errorf("invalid %s extension: sampleRate %v is not between 0 and 1", ExtensionName, *p.SampleRate)
+	}
+	return &p, nil
+}
+
+// Merge returns a new policy with the fields set on child overriding the fields of p.
+// Ignore paths are combined. Either policy may be nil.
+func (p *Policy) Merge(child *Policy) *Policy {
+	if p == nil && child == nil {
+		return nil
+	}
+	merged := &Policy{}
+	for _, level := range []*Policy{p, child} {
+		if level == nil {
+			continue
+		}
+		merged.Strict = pick(merged.Strict, level.Strict)
+		merged.RejectReadOnly = pick(merged.RejectReadOnly, level.RejectReadOnly)
+		merged.RejectWriteOnly = pick(merged.RejectWriteOnly, level.RejectWriteOnly)
+		merged.Security = pick(merged.Security, level.Security)
+		merged.XML = pick(merged.XML, level.XML)
+		merged.URLEncoded = pick(merged.URLEncoded, level.URLEncoded)
+		merged.FormatAssertions = pick(merged.FormatAssertions, level.FormatAssertions)
+		merged.ContentAssertions = pick(merged.ContentAssertions, level.ContentAssertions)
+		merged.SkipRequest = pick(merged.SkipRequest, level.SkipRequest)
+		merged.SkipResponse = pick(merged.SkipResponse, level.SkipResponse)
+		merged.SampleRate = pick(merged.SampleRate, level.SampleRate)
+		merged.IgnorePaths = append(merged.IgnorePaths, level.IgnorePaths...)
+		if level.Mode != "" {
+			merged.Mode = level.Mode
+		}
+	}
+	return merged
+}
+
+// Apply sets the options the policy controls on opts.
+func (p *Policy) Apply(opts *config.ValidationOptions) {
+	if p == nil || opts == nil {
+		return
+	}
+	set(&opts.StrictMode, p.Strict)
+	set(&opts.StrictRejectReadOnly, p.RejectReadOnly)
+	set(&opts.StrictRejectWriteOnly, p.RejectWriteOnly)
+	set(&opts.SecurityValidation, p.Security)
+	set(&opts.AllowXMLBodyValidation, p.XML)
+	set(&opts.AllowURLEncodedBodyValidation, p.URLEncoded)
+	set(&opts.FormatAssertions, p.FormatAssertions)
+	set(&opts.ContentAssertions, p.ContentAssertions)
+	if len(p.IgnorePaths) > 0 {
+		opts.StrictIgnorePaths = append(append([]string(nil), opts.StrictIgnorePaths...), p.IgnorePaths...)
+	}
+}
+
+// ChangesSchemas returns true if applying the policy to opts changes how schemas are compiled,
+// in which case compiled schemas can't be shared with opts.
+func (p *Policy) ChangesSchemas(opts *config.ValidationOptions) bool {
+	if p == nil || opts == nil {
+		return false
+	}
+	return (p.FormatAssertions != nil && *p.FormatAssertions != opts.FormatAssertions) ||
+		(p.ContentAssertions != nil && *p.ContentAssertions != opts.ContentAssertions)
+}
+
+// EffectiveMode returns the mode of the policy, ModeEnforce when not set.
+func (p *Policy) EffectiveMode() Mode {
+	if p == nil || p.Mode == "" {
+		return ModeEnforce
+	}
+	return p.Mode
+}
+
+// SkipsRequest returns true if requests shouldn't be validated.
+func (p *Policy) SkipsRequest() bool {
+	return p.EffectiveMode() == ModeOff || (p != nil && p.SkipRequest != nil && *p.SkipRequest)
+}
+
+// SkipsResponse returns true if responses shouldn't be validated.
+func (p *Policy) SkipsResponse() bool {
+	return p.EffectiveMode() == ModeOff || (p != nil && p.SkipResponse != nil && *p.SkipResponse)
+}
+
+func pick[T any](current, next *T) *T {
+	if next != nil {
+		return next
+	}
+	return current
+}
+
+func set(target *bool, value *bool) {
+	if value != nil {
+		*target = *value
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+	"go.yaml.in/yaml/v4"
+
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+func extensions(t *testing.T, yml string) *orderedmap.Map[string, *yaml.Node] {
+	t.Helper()
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(yml), &node))
+	ext := orderedmap.New[string, *yaml.Node]()
+	ext.Set(ExtensionName, node.Content[0])
+	return ext
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestParse(t *testing.T) {
+	p, err := Parse(extensions(t, `
+strict: true
+ignorePaths: ["$.body.meta"]
+security: false
+skipResponse: true
+mode: warn`))
+	require.NoError(t, err)
+	require.NotNil(t, p)
+	assert.True(t, *p.Strict)
+	assert.False(t, *p.Security)
+	assert.True(t, *p.SkipResponse)
+	assert.Nil(t, p.XML)
+	assert.Equal(t, []string{"$.body.meta"}, p.IgnorePaths)
+	assert.Equal(t, ModeWarn, p.Mode)
+}
+
+func TestParse_NoExtension(t *testing.T) {
+	p, err := Parse(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	p, err = Parse(orderedmap.New[string, *yaml.Node]())
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse(extensions(t, `mode: loud`))
+	assert.ErrorContains(t, err, "unknown mode 'loud'")
+
+	_, err = Parse(extensions(t, `strict: [1, 2]`))
+	assert.ErrorContains(t, err, "invalid x-validation extension")
+
+	_, err = Parse(extensions(t, `just a string`))
+	assert.Error(t, err)
+}
+
+func TestPolicy_Merge(t *testing.T) {
+	parent := &Policy{Strict: boolPtr(true), Security: boolPtr(false), IgnorePaths: []string{"$.a"}, Mode: ModeWarn}
+	child := &Policy{Strict: boolPtr(false), IgnorePaths: []string{"$.b"}}
+
+	merged := parent.Merge(child)
+	assert.False(t, *merged.Strict)
+	assert.False(t, *merged.Security)
+	assert.Equal(t, []string{"$.a", "$.b"}, merged.IgnorePaths)
+	assert.Equal(t, ModeWarn, merged.Mode)
+
+	// neither policy is modified
+	assert.True(t, *parent.Strict)
+	assert.Equal(t, []string{"$.a"}, parent.IgnorePaths)
+
+	var none *Policy
+	assert.Nil(t, none.Merge(nil))
+	assert.True(t, *none.Merge(parent).Strict)
+	assert.True(t, *parent.Merge(nil).Strict)
+}
+
+func TestPolicy_Apply(t *testing.T) {
+	opts := config.NewValidationOptions(config.WithStrictIgnorePaths("$.x"))
+	p := &Policy{
+		Strict:            boolPtr(true),
+		RejectReadOnly:    boolPtr(true),
+		RejectWriteOnly:   boolPtr(true),
+		Security:          boolPtr(false),
+		XML:               boolPtr(true),
+		URLEncoded:        boolPtr(true),
+		FormatAssertions:  boolPtr(true),
+		ContentAssertions: boolPtr(true),
+		IgnorePaths:       []string{"$.y"},
+	}
+	p.Apply(opts)
+
+	assert.True(t, opts.StrictMode)
+	assert.True(t, opts.StrictRejectReadOnly)
+	assert.True(t, opts.StrictRejectWriteOnly)
+	assert.False(t, opts.SecurityValidation)
+	assert.True(t, opts.AllowXMLBodyValidation)
+	assert.True(t, opts.AllowURLEncodedBodyValidation)
+	assert.True(t, opts.FormatAssertions)
+	assert.True(t, opts.ContentAssertions)
+	assert.Equal(t, []string{"$.x", "$.y"}, opts.StrictIgnorePaths)
+
+	// unset fields leave options alone
+	opts = config.NewValidationOptions()
+	(&Policy{}).Apply(opts)
+	assert.True(t, opts.SecurityValidation)
+	assert.False(t, opts.StrictMode)
+
+	var none *Policy
+	none.Apply(opts)
+}
+
+func TestPolicy_ChangesSchemas(t *testing.T) {
+	opts := config.NewValidationOptions()
+	assert.False(t, (&Policy{Strict: boolPtr(true)}).ChangesSchemas(opts))
+	assert.False(t, (&Policy{FormatAssertions: boolPtr(opts.FormatAssertions)}).ChangesSchemas(opts))
+	assert.True(t, (&Policy{FormatAssertions: boolPtr(!opts.FormatAssertions)}).ChangesSchemas(opts))
+	assert.True(t, (&Policy{ContentAssertions: boolPtr(!opts.ContentAssertions)}).ChangesSchemas(opts))
+
+	var none *Policy
+	assert.False(t, none.ChangesSchemas(opts))
+}
+
+func TestPolicy_Modes(t *testing.T) {
+	var none *Policy
+	assert.Equal(t, ModeEnforce, none.EffectiveMode())
+	assert.False(t, none.SkipsRequest())
+	assert.False(t, none.SkipsResponse())
+
+	off := &Policy{Mode: ModeOff}
+	assert.True(t, off.SkipsRequest())
+	assert.True(t, off.SkipsResponse())
+
+	skip := &Policy{SkipRequest: boolPtr(true), SkipResponse: boolPtr(false)}
+	assert.Equal(t, ModeEnforce, skip.EffectiveMode())
+	assert.True(t, skip.SkipsRequest())
+	assert.False(t, skip.SkipsResponse())
+}
+
+func TestParse_SampleRate(t *testing.T) {
+	p, err := Parse(extensions(t, `sampleRate: 0.1`))
+	require.NoError(t, err)
+	assert.Equal(t, 0.1, *p.SampleRate)
+
+	_, err = Parse(extensions(t, `sampleRate: 1.5`))
+	assert.ErrorContains(t, err, "sampleRate 1.5 is not between 0 and 1")
+
+	half, tenth := 0.5, 0.1
+	merged := (&Policy{SampleRate: &half}).Merge(&Policy{Strict: boolPtr(true)})
+	assert.Equal(t, 0.5, *merged.SampleRate)
+	merged = merged.Merge(&Policy{SampleRate: &tenth})
+	assert.Equal(t, 0.1, *merged.SampleRate)
+}
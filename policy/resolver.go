@@ -0,0 +1,178 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package policy
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"go.yaml.in/yaml/v4"
+
+	"github.com/pb33f/libopenapi-validator/cache"
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+// Operation is the resolved validation policy of an operation.
+type Operation struct {
+	// Policy is the merged document, path item and operation policy. nil when none of them set one.
+	Policy *Policy
+
+	// Options are the effective options for the operation. They are the base options when Policy is nil.
+	Options *config.ValidationOptions
+
+	// Parameters holds the mode of every parameter of the operation that sets a policy, keyed by its location
+	// and name.
+	Parameters map[ParameterKey]Mode
+}
+
+// ParameterKey identifies a parameter by its location and name, as OpenAPI does. Use NewParameterKey to create
+// one, header names are not case-sensitive and are kept in lower case.
+type ParameterKey struct {
+	In   string
+	Name string
+}
+
+// NewParameterKey returns the key of the parameter with a name in a location ('path', 'query', 'header' or
+// 'cookie').
+func NewParameterKey(in, name string) ParameterKey {
+	in = strings.ToLower(in)
+	if in == "header" {
+		name = strings.ToLower(name)
+	}
+	return ParameterKey{In: in, Name: name}
+}
+
+// Resolver resolves and caches the effective policy of operations in a document.
+// It's safe for concurrent use.
+type Resolver struct {
+	base       *config.ValidationOptions
+	document   *Policy
+	unmatched  *Operation // the resolved document policy, for requests without an operation
+	sampled    bool       // a path item or operation sets a sample rate
+	operations sync.Map   // *v3.Operation -> *Operation
+}
+
+// NewResolver creates a Resolver for a document, resolving operation options on top of base.
+// Invalid policies are ignored when resolving, use Validate to report them.
+func NewResolver(document *v3.Document, base *config.ValidationOptions) *Resolver {
+	r := &Resolver{base: base}
+	if document != nil {
+		r.document, _ = Parse(document.Extensions)
+		r.sampled = setsSampleRate(document)
+	}
+	r.unmatched = &Operation{Policy: r.document, Options: r.options(r.document)}
+	return r
+}
+
+// SampleRate returns the sample rate set by the policy of the document, which applies to every operation
+// that doesn't set its own.
+func (r *Resolver) SampleRate() (float64, bool) {
+	if r == nil || r.document == nil || r.document.SampleRate == nil {
+		return 0, false
+	}
+	return *r.document.SampleRate, true
+}
+
+// SamplesOperations returns true if a path item or operation policy sets its own sample rate, so the rate
+// of an exchange depends on the operation it is for.
+func (r *Resolver) SamplesOperations() bool {
+	return r != nil && r.sampled
+}
+
+func setsSampleRate(document *v3.Document) bool {
+	if document.Paths == nil {
+		return false
+	}
+	sets := func(extensions *orderedmap.Map[string, *yaml.Node]) bool {
+		p, _ := Parse(extensions)
+		return p != nil && p.SampleRate != nil
+	}
+	for pathPair := orderedmap.First(document.Paths.PathItems); pathPair != nil; pathPair = pathPair.Next() {
+		pathItem := pathPair.Value()
+		if pathItem == nil {
+			continue
+		}
+		if sets(pathItem.Extensions) {
+			return true
+		}
+		for opPair := orderedmap.First(pathItem.GetOperations()); opPair != nil; opPair = opPair.Next() {
+			if operation := opPair.Value(); operation != nil && sets(operation.Extensions) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Resolve returns the effective policy for an operation of a path item. Without an operation it's the policy
+// of the document, which is resolved once.
+func (r *Resolver) Resolve(pathItem *v3.PathItem, operation *v3.Operation) *Operation {
+	if operation == nil {
+		return r.unmatched
+	}
+	if cached, ok := r.operations.Load(operation); ok {
+		return cached.(*Operation)
+	}
+
+	resolved := &Operation{}
+	var pathPolicy *Policy
+	var params []*v3.Parameter
+	if pathItem != nil {
+		pathPolicy, _ = Parse(pathItem.Extensions)
+		params = append(params, pathItem.Parameters...)
+	}
+	operationPolicy, _ := Parse(operation.Extensions)
+	if pathPolicy == nil && operationPolicy == nil {
+		// operations without a policy of their own share the document policy, and its options.
+		resolved.Policy, resolved.Options = r.unmatched.Policy, r.unmatched.Options
+	} else {
+		resolved.Policy = r.document.Merge(pathPolicy).Merge(operationPolicy)
+		resolved.Options = r.options(resolved.Policy)
+	}
+
+	for _, param := range append(params, operation.Parameters...) {
+		if param == nil {
+			continue
+		}
+		// operation level parameters are listed last, so they override path item parameters.
+		key := NewParameterKey(param.In, param.Name)
+		if pp, _ := Parse(param.Extensions); pp != nil {
+			if resolved.Parameters == nil {
+				resolved.Parameters = make(map[ParameterKey]Mode)
+			}
+			resolved.Parameters[key] = pp.EffectiveMode()
+		} else if resolved.Parameters != nil {
+			delete(resolved.Parameters, key)
+		}
+	}
+
+	actual, _ := r.operations.LoadOrStore(operation, resolved)
+	return actual.(*Operation)
+}
+
+func (r *Resolver) options(p *Policy) *config.ValidationOptions {
+	if p == nil {
+		return r.base
+	}
+	opts := config.NewValidationOptions(config.WithExistingOpts(r.base))
+	if p.ChangesSchemas(r.base) {
+		// compiled schemas depend on format and content assertions, so they can't be shared.
+		opts.SchemaCache = cache.NewDefaultCache()
+	}
+	p.Apply(opts)
+	return opts
+}
+
+// StrictDisabled returns true if the schema sets 'strict: false' in its policy, turning off strict mode
+// for the schema and everything below it.
+func StrictDisabled(schema *base.Schema) bool {
+	if schema == nil || schema.Extensions == nil || schema.Extensions.GetOrZero(ExtensionName) == nil {
+		return false
+	}
+	p, err := Parse(schema.Extensions)
+	return err == nil && p != nil && p.Strict != nil && !*p.Strict
+}
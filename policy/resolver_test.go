@@ -0,0 +1,212 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+func buildModel(t *testing.T, spec string) *v3.Document {
+	t.Helper()
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	model, err := doc.BuildV3Model()
+	require.NoError(t, err)
+	return &model.Model
+}
+
+const resolverSpec = `openapi: 3.1.0
+info:
+  title: Policies
+  version: 1.0.0
+x-validation:
+  strict: true
+  ignorePaths: ["$.body.meta"]
+paths:
+  /pets:
+    x-validation:
+      security: false
+    parameters:
+      - name: trace
+        in: header
+        x-validation:
+          mode: warn
+        schema:
+          type: string
+      - name: limit
+        in: query
+        x-validation:
+          mode: off
+        schema:
+          type: integer
+    get:
+      x-validation:
+        strict: false
+        mode: warn
+        formatAssertions: true
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        '200':
+          description: OK
+    post:
+      responses:
+        '200':
+          description: OK
+  /plain:
+    get:
+      responses:
+        '200':
+          description: OK
+components:
+  schemas:
+    Loose:
+      type: object
+      x-validation:
+        strict: false
+    Tight:
+      type: object
+      x-validation:
+        strict: true
+    Broken:
+      type: object
+      x-validation:
+        mode: loud
+`
+
+func TestResolver_Resolve(t *testing.T) {
+	model := buildModel(t, resolverSpec)
+	base := config.NewValidationOptions()
+	r := NewResolver(model, base)
+
+	pets := model.Paths.PathItems.GetOrZero("/pets")
+	get := r.Resolve(pets, pets.Get)
+	require.NotNil(t, get.Policy)
+	assert.Equal(t, ModeWarn, get.Policy.EffectiveMode())
+	assert.False(t, get.Options.StrictMode)
+	assert.False(t, get.Options.SecurityValidation)
+	assert.True(t, get.Options.FormatAssertions)
+	assert.Equal(t, []string{"$.body.meta"}, get.Options.StrictIgnorePaths)
+	assert.NotSame(t, base.SchemaCache, get.Options.SchemaCache)
+
+	// the operation re-declares 'limit' without a policy, so only 'trace' keeps one.
+	assert.Equal(t, map[ParameterKey]Mode{{In: "header", Name: "trace"}: ModeWarn}, get.Parameters)
+
+	post := r.Resolve(pets, pets.Post)
+	assert.True(t, post.Options.StrictMode)
+	assert.False(t, post.Options.SecurityValidation)
+	assert.Same(t, base.SchemaCache, post.Options.SchemaCache)
+	assert.Equal(t, map[ParameterKey]Mode{{In: "header", Name: "trace"}: ModeWarn, {In: "query", Name: "limit"}: ModeOff}, post.Parameters)
+
+	// base options are never modified
+	assert.False(t, base.StrictMode)
+	assert.True(t, base.SecurityValidation)
+
+	// resolved policies are cached
+	assert.Same(t, get, r.Resolve(pets, pets.Get))
+}
+
+func TestResolver_Resolve_NoPolicy(t *testing.T) {
+	model := buildModel(t, `openapi: 3.1.0
+info:
+  title: Policies
+  version: 1.0.0
+paths:
+  /plain:
+    get:
+      responses:
+        '200':
+          description: OK
+`)
+	base := config.NewValidationOptions()
+	r := NewResolver(model, base)
+	plain := model.Paths.PathItems.GetOrZero("/plain")
+
+	resolved := r.Resolve(plain, plain.Get)
+	assert.Nil(t, resolved.Policy)
+	assert.Same(t, base, resolved.Options)
+	assert.Nil(t, resolved.Parameters)
+
+	resolved = r.Resolve(nil, nil)
+	assert.Nil(t, resolved.Policy)
+	assert.Same(t, base, resolved.Options)
+}
+
+func TestResolver_Resolve_DocumentOnly(t *testing.T) {
+	model := buildModel(t, resolverSpec)
+	r := NewResolver(model, config.NewValidationOptions())
+
+	plain := model.Paths.PathItems.GetOrZero("/plain")
+	resolved := r.Resolve(plain, plain.Get)
+	require.NotNil(t, resolved.Policy)
+	assert.True(t, resolved.Options.StrictMode)
+
+	// operations without a policy of their own share the options of the document policy.
+	assert.Same(t, resolved.Options, r.Resolve(nil, nil).Options)
+	assert.Same(t, resolved.Policy, r.Resolve(nil, nil).Policy)
+
+	resolved = r.Resolve(nil, nil)
+	assert.True(t, resolved.Options.StrictMode)
+	assert.Same(t, resolved, r.Resolve(nil, nil))
+
+	assert.NotNil(t, NewResolver(nil, nil))
+}
+
+func TestStrictDisabled(t *testing.T) {
+	model := buildModel(t, resolverSpec)
+	schemas := model.Components.Schemas
+
+	assert.True(t, StrictDisabled(schemas.GetOrZero("Loose").Schema()))
+	assert.False(t, StrictDisabled(schemas.GetOrZero("Tight").Schema()))
+	assert.False(t, StrictDisabled(schemas.GetOrZero("Broken").Schema()))
+	assert.False(t, StrictDisabled(nil))
+}
+
+func TestResolver_SampleRate(t *testing.T) {
+	r := NewResolver(buildModel(t, resolverSpec), config.NewValidationOptions())
+	_, ok := r.SampleRate()
+	assert.False(t, ok)
+	assert.False(t, r.SamplesOperations())
+
+	r = NewResolver(buildModel(t, `openapi: 3.1.0
+info:
+  title: Sampled
+  version: 1.0.0
+x-validation:
+  sampleRate: 0.2
+paths:
+  /pets:
+    get:
+      x-validation:
+        sampleRate: 1
+      responses:
+        '200':
+          description: ok`), config.NewValidationOptions())
+	rate, ok := r.SampleRate()
+	assert.True(t, ok)
+	assert.Equal(t, 0.2, rate)
+	assert.True(t, r.SamplesOperations())
+
+	var none *Resolver
+	_, ok = none.SampleRate()
+	assert.False(t, ok)
+	assert.False(t, none.SamplesOperations())
+}
+
+func TestNewParameterKey(t *testing.T) {
+	assert.Equal(t, ParameterKey{In: "header", Name: "x-trace"}, NewParameterKey("Header", "X-Trace"))
+	assert.Equal(t, ParameterKey{In: "query", Name: "Limit"}, NewParameterKey("query", "Limit"))
+	assert.NotEqual(t, NewParameterKey("header", "id"), NewParameterKey("query", "id"))
+}
@@ -0,0 +1,66 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+	"go.yaml.in/yaml/v4"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+)
+
+// Validate reports every 'x-validation' extension in the document that can't be read, on the document,
+// path items, operations, parameters and component schemas. Invalid policies are ignored at request time.
+func Validate(document *v3.Document) []*errors.ValidationError {
+	if document == nil {
+		return nil
+	}
+	var findings []*errors.ValidationError
+	check := func(location string, extensions *orderedmap.Map[string, *yaml.Node]) {
+		if _, err := Parse(extensions); err != nil {
+			findings = append(findings, errors.InvalidValidationPolicy(location, err, extensions.GetOrZero(ExtensionName)))
+		}
+	}
+	checkParams := func(location string, params []*v3.Parameter) {
+		for _, param := range params {
+			if param != nil {
+				check(fmt.Sprintf("parameter '%s' of %s", param.Name, location), param.Extensions)
+			}
+		}
+	}
+
+	check("the document", document.Extensions)
+
+	if document.Paths != nil {
+		for pathPair := orderedmap.First(document.Paths.PathItems); pathPair != nil; pathPair = pathPair.Next() {
+			path, pathItem := pathPair.Key(), pathPair.Value()
+			if pathItem == nil {
+				continue
+			}
+			check(fmt.Sprintf("path '%s'", path), pathItem.Extensions)
+			checkParams(fmt.Sprintf("path '%s'", path), pathItem.Parameters)
+			for opPair := orderedmap.First(pathItem.GetOperations()); opPair != nil; opPair = opPair.Next() {
+				location := fmt.Sprintf("%s %s", strings.ToUpper(opPair.Key()), path)
+				if operation := opPair.Value(); operation != nil {
+					check(location, operation.Extensions)
+					checkParams(location, operation.Parameters)
+				}
+			}
+		}
+	}
+
+	if document.Components != nil {
+		for schemaPair := orderedmap.First(document.Components.Schemas); schemaPair != nil; schemaPair = schemaPair.Next() {
+			if schema := schemaPair.Value().Schema(); schema != nil {
+				check(fmt.Sprintf("schema '%s'", schemaPair.Key()), schema.Extensions)
+			}
+		}
+	}
+	return findings
+}
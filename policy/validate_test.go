@@ -0,0 +1,77 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+func TestValidate(t *testing.T) {
+	model := buildModel(t, `openapi: 3.1.0
+info:
+  title: Policies
+  version: 1.0.0
+x-validation: nope
+paths:
+  /pets:
+    x-validation:
+      strict: maybe
+    parameters:
+      - name: trace
+        in: header
+        x-validation:
+          mode: loud
+        schema:
+          type: string
+    get:
+      x-validation:
+        mode: quiet
+      parameters:
+        - name: limit
+          in: query
+          x-validation:
+            mode: off
+          schema:
+            type: integer
+      responses:
+        '200':
+          description: OK
+components:
+  schemas:
+    Broken:
+      type: object
+      x-validation:
+        mode: loud
+`)
+	findings := Validate(model)
+	require.Len(t, findings, 5)
+
+	var messages []string
+	for _, finding := range findings {
+		assert.Equal(t, helpers.DocumentValidation, finding.ValidationType)
+		assert.Equal(t, helpers.ValidationPolicyInvalid, finding.ValidationSubType)
+		assert.NotZero(t, finding.SpecLine)
+		messages = append(messages, finding.Message)
+	}
+	assert.Equal(t, []string{
+		"Validation policy for the document is invalid",
+		"Validation policy for path '/pets' is invalid",
+		"Validation policy for parameter 'trace' of path '/pets' is invalid",
+		"Validation policy for GET /pets is invalid",
+		"Validation policy for schema 'Broken' is invalid",
+	}, messages)
+}
+
+func TestValidate_Valid(t *testing.T) {
+	findings := Validate(buildModel(t, resolverSpec))
+	require.Len(t, findings, 1)
+	assert.Equal(t, "Validation policy for schema 'Broken' is invalid", findings[0].Message)
+
+	assert.Nil(t, Validate(nil))
+}
@@ -4,6 +4,8 @@
 package radix
 
 import (
+	"strings"
+
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 )
 
@@ -104,3 +106,24 @@ func BuildPathTree(doc *v3.Document) *PathTree {
 
 	return tree
 }
+
+// SlowPathSegments returns the segments of an OpenAPI path template that the tree can't match.
+// Templates with any such segment are still inserted, but requests for them are only resolved by the
+// regex fallback in paths.FindPath. Examples are custom regex parameters ("{id:[0-9]+}.json"),
+// adjacent parameters ("{a}{b}"), unbalanced braces and fragments ("/docs#section").
+func SlowPathSegments(path string) []string {
+	var unsupported []string
+	for _, seg := range splitPath(path) {
+		if strings.IndexByte(seg, '#') >= 0 {
+			unsupported = append(unsupported, seg)
+			continue
+		}
+		if isParam(seg) || !strings.ContainsAny(seg, "{}") {
+			continue
+		}
+		if _, ok := compileSegmentPattern(seg); !ok {
+			unsupported = append(unsupported, seg)
+		}
+	}
+	return unsupported
+}
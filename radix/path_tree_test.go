@@ -281,3 +281,22 @@ paths:
 		tree.Lookup("/api/v3/ad_accounts/acc123/campaigns/camp456")
 	}
 }
+
+func TestSlowPathSegments(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected []string
+	}{
+		{"/users", nil},
+		{"/users/{id}", nil},
+		{"/files/{name}.{ext}", nil},
+		{"/entities('{Entity}')/items", nil},
+		{"/items/{id:[0-9]+}.json", []string{"{id:[0-9]+}.json"}},
+		{"/a/{x}{y}/b/{z", []string{"{x}{y}", "{z"}},
+		{"/docs#intro", []string{"docs#intro"}},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, SlowPathSegments(tc.input), "SlowPathSegments(%q)", tc.input)
+	}
+}
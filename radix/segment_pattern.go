@@ -0,0 +1,130 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package radix
+
+import "strings"
+
+// segmentPattern is a compiled path segment that mixes literal text with one or more
+// parameters, such as "{name}.{ext}", "{year}-{month}" or "{id}:activate".
+//
+// Matching mirrors the regex fallback in helpers.GetRegexForPath: each parameter matches
+// any (possibly empty) run of characters, and earlier parameters are greedy, so
+// "archive.tar.gz" matched against "{name}.{ext}" yields name="archive.tar", ext="gz".
+type segmentPattern struct {
+	// raw is the segment exactly as written in the path template.
+	raw string
+
+	// shape is the segment with parameter names removed (e.g. "{}.{}").
+	// Segments with the same shape match exactly the same set of values.
+	shape string
+
+	// parts is the ordered list of literal and parameter parts.
+	parts []segmentPart
+
+	// literalLen is the number of literal characters, used to order sibling patterns
+	// so that more specific templates are tried first.
+	literalLen int
+}
+
+// segmentPart is one literal or parameter piece of a segmentPattern.
+type segmentPart struct {
+	literal string // literal text, empty for parameter parts
+	param   string // parameter name without braces, empty for literal parts
+}
+
+// compileSegmentPattern compiles a segment that contains parameters alongside literal text.
+// It returns false for plain literal segments, whole-segment parameters (handled by isParam),
+// and anything the tree cannot match exactly: unbalanced or nested braces, empty names,
+// adjacent parameters, and custom regex parameters like {id:[0-9]+}.
+// Those segments keep their previous behavior and are resolved by the regex fallback.
+func compileSegmentPattern(seg string) (*segmentPattern, bool) {
+	if isParam(seg) || !strings.ContainsAny(seg, "{}") {
+		return nil, false
+	}
+
+	p := &segmentPattern{raw: seg}
+	var shape strings.Builder
+	rest := seg
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			if strings.IndexByte(rest, '}') >= 0 {
+				return nil, false
+			}
+			p.addLiteral(rest)
+			shape.WriteString(rest)
+			break
+		}
+		if open > 0 {
+			lit := rest[:open]
+			if strings.IndexByte(lit, '}') >= 0 {
+				return nil, false
+			}
+			p.addLiteral(lit)
+			shape.WriteString(lit)
+		}
+		closing := strings.IndexByte(rest[open:], '}')
+		if closing < 0 {
+			return nil, false
+		}
+		name := rest[open+1 : open+closing]
+		if name == "" || strings.ContainsAny(name, "{:") {
+			return nil, false
+		}
+		// two parameters with nothing between them cannot be split deterministically
+		if n := len(p.parts); n > 0 && p.parts[n-1].param != "" {
+			return nil, false
+		}
+		p.parts = append(p.parts, segmentPart{param: name})
+		shape.WriteString("{}")
+		rest = rest[open+closing+1:]
+	}
+
+	p.shape = shape.String()
+	return p, true
+}
+
+func (p *segmentPattern) addLiteral(lit string) {
+	p.parts = append(p.parts, segmentPart{literal: lit})
+	p.literalLen += len(lit)
+}
+
+// match reports whether the request segment satisfies the pattern.
+// It does not allocate.
+func (p *segmentPattern) match(seg string) bool {
+	return matchParts(p.parts, seg)
+}
+
+func matchParts(parts []segmentPart, seg string) bool {
+	if len(parts) == 0 {
+		return seg == ""
+	}
+	part := parts[0]
+	if part.param == "" {
+		if !strings.HasPrefix(seg, part.literal) {
+			return false
+		}
+		return matchParts(parts[1:], seg[len(part.literal):])
+	}
+
+	// a trailing parameter consumes the remainder of the segment
+	if len(parts) == 1 {
+		return true
+	}
+
+	// parameters are always followed by a literal (adjacent parameters are rejected at
+	// compile time), so try each occurrence of that literal, longest parameter value first.
+	next := parts[1].literal
+	for end := len(seg); end >= 0; {
+		idx := strings.LastIndex(seg[:end], next)
+		if idx < 0 {
+			return false
+		}
+		if matchParts(parts[1:], seg[idx:]) {
+			return true
+		}
+		end = idx + len(next) - 1
+	}
+	return false
+}
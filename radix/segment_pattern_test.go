@@ -0,0 +1,124 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package radix
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+)
+
+func TestCompileSegmentPattern(t *testing.T) {
+	testCases := []struct {
+		input      string
+		ok         bool
+		shape      string
+		literalLen int
+	}{
+		{"{name}.{ext}", true, "{}.{}", 1},
+		{"{year}-{month}", true, "{}-{}", 1},
+		{"{id}:activate", true, "{}:activate", 9},
+		{"v{version}", true, "v{}", 1},
+		{"entities('{Entity}')", true, "entities('{}')", 12},
+		{"{a}.{b}.json", true, "{}.{}.json", 6},
+		{"users", false, "", 0},            // plain literal
+		{"{id}", false, "", 0},             // whole-segment parameter
+		{"{a}{b}", false, "", 0},           // adjacent parameters
+		{"{id:[0-9]+}.json", false, "", 0}, // custom regex
+		{"{}.json", false, "", 0},          // empty name
+		{"{a.json", false, "", 0},          // unbalanced
+		{"a}.{b}", false, "", 0},           // unbalanced
+		{"{a{b}}.json", false, "", 0},      // nested
+		{"{a}.json}", false, "", 0},        // trailing brace
+	}
+
+	for _, tc := range testCases {
+		p, ok := compileSegmentPattern(tc.input)
+		assert.Equal(t, tc.ok, ok, "compileSegmentPattern(%q)", tc.input)
+		if ok {
+			require.NotNil(t, p)
+			assert.Equal(t, tc.input, p.raw)
+			assert.Equal(t, tc.shape, p.shape, "shape of %q", tc.input)
+			assert.Equal(t, tc.literalLen, p.literalLen, "literalLen of %q", tc.input)
+		}
+	}
+}
+
+func TestSegmentPattern_Match(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		input    string
+		expected bool
+	}{
+		{"{name}.{ext}", "report.pdf", true},
+		{"{name}.{ext}", "archive.tar.gz", true},
+		{"{name}.{ext}", "report", false},
+		{"{name}.{ext}", ".", true},
+		{"{year}-{month}", "2024-06", true},
+		{"{year}-{month}", "202406", false},
+		{"{id}:activate", "42:activate", true},
+		{"{id}:activate", "42:deactivate", false},
+		{"{id}:activate", "42:activated", false},
+		{"v{version}", "v2", true},
+		{"v{version}", "x2", false},
+		{"entities('{Entity}')", "entities('1')", true},
+		{"entities('{Entity}')", "entities(1)", false},
+		{"{a}.{b}.json", "x.y.z.json", true},
+		{"{a}.{b}.json", "x.json", false},
+		{"{a}-{b}-{c}", "1-2-3-4", true},
+		{"{a}..{b}", "x...y", true},
+	}
+
+	for _, tc := range testCases {
+		p, ok := compileSegmentPattern(tc.pattern)
+		require.True(t, ok, tc.pattern)
+		assert.Equal(t, tc.expected, p.match(tc.input), "%q matching %q", tc.pattern, tc.input)
+	}
+}
+
+// fallbackRegex builds the same expression helpers.GetRegexForPath produces for a segment
+// (helpers can't be imported here without a cycle through config).
+func fallbackRegex(seg string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteByte('^')
+	for seg != "" {
+		open := strings.IndexByte(seg, '{')
+		if open < 0 {
+			b.WriteString(regexp.QuoteMeta(seg))
+			break
+		}
+		closing := strings.IndexByte(seg, '}')
+		b.WriteString(regexp.QuoteMeta(seg[:open]))
+		b.WriteString("([^/]*)")
+		seg = seg[closing+1:]
+	}
+	b.WriteByte('$')
+	return regexp.MustCompile(b.String())
+}
+
+// TestSegmentPattern_MatchesRegexFallback ensures the tree agrees with the regex slow path.
+func TestSegmentPattern_MatchesRegexFallback(t *testing.T) {
+	patterns := []string{"{name}.{ext}", "{year}-{month}", "{id}:activate", "{a}.{b}.json", "pre{x}post", "{a}ab{b}"}
+	inputs := []string{"", ".", "a.b", "a.b.c", "2024-06", "-", "1:activate", "x.y.json", "prepost", "preXpost", "aab", "abab", "xabyab", "abba"}
+
+	for _, pattern := range patterns {
+		p, ok := compileSegmentPattern(pattern)
+		require.True(t, ok, pattern)
+		rgx := fallbackRegex(pattern)
+		for _, input := range inputs {
+			assert.Equal(t, rgx.MatchString(input), p.match(input), "%q matching %q", pattern, input)
+		}
+	}
+}
+
+func BenchmarkSegmentPattern_Match(b *testing.B) {
+	p, _ := compileSegmentPattern("{name}.{ext}")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p.match("archive.tar.gz")
+	}
+}
@@ -4,6 +4,11 @@
 // Package radix provides a radix tree (prefix tree) implementation optimized for
 // URL path matching with support for parameterized segments.
 //
+// Segments may be fully parameterized ("{id}") or mix literal text with one or more
+// parameters ("{name}.{ext}", "{year}-{month}", "{id}:activate"). When several children
+// could match a segment, literals are preferred over mixed segments, which are preferred
+// over whole-segment parameters.
+//
 // The tree provides O(k) lookup complexity where k is the number of path segments
 // (typically 3-5 for REST APIs), making it ideal for routing and path matching.
 //
@@ -20,7 +25,8 @@ package radix
 import "strings"
 
 // Tree is a radix tree optimized for URL path matching.
-// It supports both literal path segments and parameterized segments like {id}.
+// It supports literal path segments, parameterized segments like {id}, and mixed
+// segments like {name}.{ext}.
 // T is the type of value stored at leaf nodes.
 type Tree[T any] struct {
 	root *node[T]
@@ -32,6 +38,13 @@ type node[T any] struct {
 	// children maps literal path segments to child nodes
 	children map[string]*node[T]
 
+	// patternChildren handles segments mixing literals and parameters like {name}.{ext}.
+	// Children are ordered from most to least literal text so the most specific is tried first.
+	patternChildren []*node[T]
+
+	// pattern is the compiled matcher for a node reached through patternChildren
+	pattern *segmentPattern
+
 	// paramChild handles parameterized segments like {id}
 	// Only one param child is allowed per node
 	paramChild *node[T]
@@ -60,7 +73,7 @@ func New[T any]() *Tree[T] {
 
 // Insert adds a path and its associated value to the tree.
 // The path should use {param} syntax for parameterized segments.
-// Examples: "/users", "/users/{id}", "/users/{userId}/posts/{postId}"
+// Examples: "/users", "/users/{id}", "/users/{userId}/posts/{postId}", "/files/{name}.{ext}"
 //
 // Returns true if a new path was inserted, false if an existing path was updated.
 func (t *Tree[T]) Insert(path string, value T) bool {
@@ -85,6 +98,9 @@ func (t *Tree[T]) Insert(path string, value T) bool {
 				}
 			}
 			n = n.paramChild
+		} else if pattern, ok := compileSegmentPattern(seg); ok {
+			// Mixed literal/parameter segment
+			n = n.patternChild(pattern)
 		} else {
 			// Literal segment
 			child, exists := n.children[seg]
@@ -112,11 +128,36 @@ func (t *Tree[T]) Insert(path string, value T) bool {
 	return isNew
 }
 
+// patternChild returns the child for a mixed segment, creating it if needed.
+// Segments with the same shape (e.g. "{name}.{ext}" and "{base}.{suffix}") share a node.
+func (n *node[T]) patternChild(pattern *segmentPattern) *node[T] {
+	for _, child := range n.patternChildren {
+		if child.pattern.shape == pattern.shape {
+			return child
+		}
+	}
+	child := &node[T]{
+		children: make(map[string]*node[T]),
+		pattern:  pattern,
+	}
+
+	// keep the slice ordered by literal length (descending), preserving insertion order for ties
+	i := len(n.patternChildren)
+	for i > 0 && n.patternChildren[i-1].pattern.literalLen < pattern.literalLen {
+		i--
+	}
+	n.patternChildren = append(n.patternChildren, nil)
+	copy(n.patternChildren[i+1:], n.patternChildren[i:])
+	n.patternChildren[i] = child
+	return child
+}
+
 // Lookup finds the value for a given URL path.
 // Returns the value, the matched path template, and whether a match was found.
 //
 // Literal matches take precedence over parameter matches per OpenAPI specification.
-// For example, "/users/admin" will match "/users/admin" before "/users/{id}".
+// For example, "/users/admin" will match "/users/admin" before "/users/{id}", and
+// "/files/{name}.json" will match before "/files/{name}.{ext}", which will match before "/files/{id}".
 func (t *Tree[T]) Lookup(urlPath string) (value T, matchedPath string, found bool) {
 	var zero T
 	if t == nil || t.root == nil {
@@ -149,6 +190,16 @@ func (t *Tree[T]) lookupRecursive(n *node[T], segments []string, depth int) *lea
 		}
 	}
 
+	// Then mixed literal/parameter segments, most literal text first
+	for _, child := range n.patternChildren {
+		if !child.pattern.match(seg) {
+			continue
+		}
+		if result := t.lookupRecursive(child, segments, depth+1); result != nil {
+			return result
+		}
+	}
+
 	// Fall back to parameter match
 	if n.paramChild != nil {
 		if result := t.lookupRecursive(n.paramChild, segments, depth+1); result != nil {
@@ -208,6 +259,12 @@ func (t *Tree[T]) walkRecursive(n *node[T], fn func(path string, value T) bool)
 		}
 	}
 
+	for _, child := range n.patternChildren {
+		if !t.walkRecursive(child, fn) {
+			return false
+		}
+	}
+
 	if n.paramChild != nil {
 		if !t.walkRecursive(n.paramChild, fn) {
 			return false
@@ -237,9 +294,11 @@ func splitPath(path string) []string {
 	return result
 }
 
-// isParam checks if a segment is a parameter (e.g., "{id}")
+// isParam checks if a segment is a single whole-segment parameter (e.g., "{id}").
+// Segments like "{name}.{ext}" contain more than one brace pair and are not parameters.
 func isParam(seg string) bool {
-	return len(seg) > 2 && seg[0] == '{' && seg[len(seg)-1] == '}'
+	return len(seg) > 2 && seg[0] == '{' && seg[len(seg)-1] == '}' &&
+		!strings.ContainsAny(seg[1:len(seg)-1], "{}")
 }
 
 // extractParamName extracts the parameter name from a segment.
@@ -524,16 +524,18 @@ func TestIsParam(t *testing.T) {
 		{"{id}", true},
 		{"{userId}", true},
 		{"{a}", true},
-		{"{}", false},  // empty param name
-		{"{a", false},  // missing close
-		{"a}", false},  // missing open
-		{"id", false},  // no braces
-		{"{", false},   // single char
-		{"}", false},   // single char
-		{"", false},    // empty
-		{"ab", false},  // two chars
-		{"{ab", false}, // three chars, missing close
-		{"ab}", false}, // three chars, missing open
+		{"{}", false},           // empty param name
+		{"{a", false},           // missing close
+		{"a}", false},           // missing open
+		{"id", false},           // no braces
+		{"{", false},            // single char
+		{"}", false},            // single char
+		{"", false},             // empty
+		{"ab", false},           // two chars
+		{"{ab", false},          // three chars, missing close
+		{"ab}", false},          // three chars, missing open
+		{"{name}.{ext}", false}, // mixed segment
+		{"{a}{b}", false},       // adjacent params
 	}
 
 	for _, tc := range testCases {
@@ -867,3 +869,136 @@ func BenchmarkTree_Lookup_VaryingIDs(b *testing.B) {
 		tree.Lookup(testPaths[i%1000])
 	}
 }
+
+func TestTree_MixedSegments(t *testing.T) {
+	tree := New[string]()
+
+	tree.Insert("/files/{name}.{ext}", "file")
+	tree.Insert("/reports/{year}-{month}", "report")
+	tree.Insert("/users/{id}:activate", "activate")
+	tree.Insert("/users/{id}", "user")
+	tree.Insert("/entities('{Entity}')/items", "odata")
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"/files/report.pdf", "/files/{name}.{ext}"},
+		{"/files/archive.tar.gz", "/files/{name}.{ext}"},
+		{"/reports/2024-06", "/reports/{year}-{month}"},
+		{"/users/42:activate", "/users/{id}:activate"},
+		{"/users/42", "/users/{id}"},
+		{"/entities('1')/items", "/entities('{Entity}')/items"},
+	}
+
+	for _, tc := range tests {
+		_, path, found := tree.Lookup(tc.input)
+		assert.True(t, found, tc.input)
+		assert.Equal(t, tc.expected, path, tc.input)
+	}
+
+	_, _, found := tree.Lookup("/files/readme")
+	assert.False(t, found)
+	_, _, found = tree.Lookup("/reports/202406")
+	assert.False(t, found)
+}
+
+func TestTree_MixedSegments_Precedence(t *testing.T) {
+	tree := New[string]()
+
+	// inserted least specific first to prove ordering doesn't depend on definition order
+	tree.Insert("/files/{id}", "param")
+	tree.Insert("/files/{name}.{ext}", "pattern")
+	tree.Insert("/files/{name}.json", "json pattern")
+	tree.Insert("/files/index.json", "literal")
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"/files/index.json", "/files/index.json"},
+		{"/files/data.json", "/files/{name}.json"},
+		{"/files/data.xml", "/files/{name}.{ext}"},
+		{"/files/data", "/files/{id}"},
+	}
+
+	for _, tc := range tests {
+		_, path, found := tree.Lookup(tc.input)
+		assert.True(t, found, tc.input)
+		assert.Equal(t, tc.expected, path, tc.input)
+	}
+}
+
+func TestTree_MixedSegments_Backtracking(t *testing.T) {
+	tree := New[string]()
+
+	tree.Insert("/files/{name}.{ext}/meta", "meta")
+	tree.Insert("/files/{id}/content", "content")
+
+	_, path, found := tree.Lookup("/files/a.b/content")
+	assert.True(t, found)
+	assert.Equal(t, "/files/{id}/content", path)
+
+	_, path, found = tree.Lookup("/files/a.b/meta")
+	assert.True(t, found)
+	assert.Equal(t, "/files/{name}.{ext}/meta", path)
+}
+
+func TestTree_MixedSegments_SameShapeSharesNode(t *testing.T) {
+	tree := New[string]()
+
+	assert.True(t, tree.Insert("/files/{name}.{ext}", "first"))
+	assert.False(t, tree.Insert("/files/{base}.{suffix}", "second"))
+	assert.Equal(t, 1, tree.Size())
+	assert.Len(t, tree.root.children["files"].patternChildren, 1)
+
+	val, path, found := tree.Lookup("/files/a.b")
+	assert.True(t, found)
+	assert.Equal(t, "second", val)
+	assert.Equal(t, "/files/{base}.{suffix}", path)
+}
+
+func TestTree_MixedSegments_Walk(t *testing.T) {
+	tree := New[string]()
+	tree.Insert("/files/{name}.{ext}", "file")
+	tree.Insert("/files/{id}", "param")
+
+	var paths []string
+	tree.Walk(func(path string, value string) bool {
+		paths = append(paths, path)
+		return true
+	})
+	sort.Strings(paths)
+	assert.Equal(t, []string{"/files/{id}", "/files/{name}.{ext}"}, paths)
+
+	count := 0
+	tree.Walk(func(path string, value string) bool {
+		count++
+		return false
+	})
+	assert.Equal(t, 1, count)
+}
+
+func TestTree_MixedSegments_UnsupportedStayLiteral(t *testing.T) {
+	tree := New[string]()
+	tree.Insert("/items/{id:[0-9]+}.json", "custom regex")
+
+	// left for the regex fallback, as before
+	_, _, found := tree.Lookup("/items/12.json")
+	assert.False(t, found)
+	_, ok := tree.root.children["items"].children["{id:[0-9]+}.json"]
+	assert.True(t, ok)
+}
+
+func BenchmarkTree_Lookup_MixedSegment(b *testing.B) {
+	tree := New[string]()
+	tree.Insert("/api/v1/files/{name}.{ext}", "file")
+	tree.Insert("/api/v1/reports/{year}-{month}", "report")
+	tree.Insert("/api/v1/users/{id}:activate", "activate")
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tree.Lookup("/api/v1/users/42:activate")
+	}
+}
@@ -0,0 +1,504 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pb33f/libopenapi"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/cache"
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+// HttpValidator is the request/response validation surface shared by Validator and Registry,
+// so middleware can accept either.
+type HttpValidator interface {
+	ValidateHttpRequest(request *http.Request) (bool, []*errors.ValidationError)
+	ValidateHttpRequestSync(request *http.Request) (bool, []*errors.ValidationError)
+	ValidateHttpResponse(request *http.Request, response *http.Response) (bool, []*errors.ValidationError)
+	ValidateHttpRequestResponse(request *http.Request, response *http.Response) (bool, []*errors.ValidationError)
+}
+
+var (
+	_ HttpValidator = (Validator)(nil)
+	_ HttpValidator = (*Registry)(nil)
+)
+
+// SpecMatcher reports whether a request belongs to a specification registered with a Registry.
+type SpecMatcher func(request *http.Request) bool
+
+// SpecSelector picks the name of a registered specification for a request. Returning false defers to the
+// matchers supplied at registration.
+type SpecSelector func(request *http.Request) (name string, ok bool)
+
+// Registry holds several Validator instances built from different OpenAPI documents (for example a v1 and a v2
+// spec, or several products behind one gateway) and routes each request to the right one.
+//
+// A spec is selected by the custom SpecSelector (if set), and otherwise by the first spec, in registration order,
+// whose matchers all match the request. A spec registered without matchers matches every request, so it acts as
+// the default when registered last.
+//
+// Validators built by the registry share a single cache.SchemaResourceCache. Resource cache entries are keyed by
+// parsed document identity, so sharing is safe across documents. A cache.SchemaCache supplied with the options is
+// shared by them as well, apart from a cache.PersistentSchemaCache such as a DiskCache, which holds the schemas of
+// a single document: Register returns an error, validators built with a persistent cache of their own are added
+// with RegisterValidator. Caches supplied with the options belong to the caller, and are not released by the
+// registry. The resource cache the registry creates is released with it, once the validations using it are done.
+type Registry struct {
+	mu            sync.RWMutex
+	specs         []*registeredSpec
+	selector      SpecSelector
+	options       []config.Option
+	resourceCache cache.SchemaResourceCache // created by the registry, nil when the options supply one
+	persistent    bool                      // the options supply a persistent schema cache
+	live          atomic.Int64              // specs whose validator is not released yet
+	released      atomic.Bool
+	releaseCaches sync.Once
+}
+
+// registeredSpec is a validator registered under a name. The registry holds a reference to it while it's
+// registered, and every validation in progress holds one while it uses it. Its validator is released when the
+// last of them is done, so replacing or unregistering a spec doesn't release it under a running validation.
+type registeredSpec struct {
+	owner     *Registry
+	name      string
+	validator Validator
+	matchers  []SpecMatcher
+	refs      atomic.Int64
+}
+
+// NewRegistry creates an empty Registry. The options are applied to every Validator built by Register.
+func NewRegistry(opts ...config.Option) *Registry {
+	r := &Registry{options: append([]config.Option{}, opts...)}
+
+	// share the caches the options supply between the validators, so releasing one validator doesn't clear them
+	// for the others. without a resource cache in the options, the registry shares one of its own.
+	supplied := config.NewValidationOptions(append([]config.Option{
+		config.WithSchemaCache(nil), config.WithSchemaResourceCache(nil),
+	}, opts...)...)
+	if supplied.SchemaCache != nil {
+		_, r.persistent = supplied.SchemaCache.(cache.PersistentSchemaCache)
+		r.options = append(r.options, config.WithSchemaCache(sharedSchemaCacheFor(supplied.SchemaCache)))
+	}
+	resourceCache := supplied.SchemaResourceCache
+	if resourceCache == nil {
+		r.resourceCache = cache.NewDefaultSchemaResourceCache()
+		resourceCache = r.resourceCache
+	}
+	r.options = append(r.options, config.WithSchemaResourceCache(sharedResourceCache{resourceCache}))
+	return r
+}
+
+// Register builds a Validator from the document and adds it to the registry under the given name.
+// Registering a name that already exists replaces (and releases) the previous validator.
+func (r *Registry) Register(name string, document libopenapi.Document, matchers ...SpecMatcher) []error {
+	if document == nil {
+		return []error{fmt.Errorf("cannot register spec '%s': document is nil", name)}
+	}
+	if r.released.Load() {
+		return []error{errRegistryReleased(name)}
+	}
+	// a persistent cache is bound to one document, and discards the schemas of any other it's bound to.
+	if r.persistent {
+		return []error{fmt.Errorf("cannot register spec '%s': a persistent schema cache holds the schemas of a "+
+			"single document and can't be shared by the specs of a registry, build the validator with a cache of "+
+			"its own and add it with RegisterValidator", name)}
+	}
+	v, errs := NewValidator(document, r.options...)
+	if errs != nil {
+		return errs
+	}
+	if err := r.RegisterValidator(name, v, matchers...); err != nil {
+		v.Release()
+		return []error{err}
+	}
+	return nil
+}
+
+// RegisterValidator adds an already built Validator to the registry under the given name, for example one built
+// with different options from the rest. Registering a name that already exists replaces (and releases) the
+// previous validator. A registry that has been released returns an error, and leaves the validator to the caller.
+func (r *Registry) RegisterValidator(name string, v Validator, matchers ...SpecMatcher) error {
+	spec := &registeredSpec{owner: r, name: name, validator: v, matchers: matchers}
+	spec.refs.Store(1)
+	replaced, err := r.add(spec)
+	if err != nil {
+		return err
+	}
+	// validators are released outside the lock, releasing one doesn't hold up the validations of the others.
+	if replaced != nil {
+		replaced.done()
+	}
+	return nil
+}
+
+// add adds a spec to the registry, in place of the spec registered under the same name, which it returns.
+func (r *Registry) add(spec *registeredSpec) (*registeredSpec, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.released.Load() {
+		return nil, errRegistryReleased(spec.name)
+	}
+	r.live.Add(1)
+	for i, existing := range r.specs {
+		if existing.name == spec.name {
+			r.specs[i] = spec
+			return existing, nil
+		}
+	}
+	r.specs = append(r.specs, spec)
+	return nil, nil
+}
+
+// Unregister removes the validator registered under the given name, and releases it once the validations
+// using it are done.
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	var removed *registeredSpec
+	for i, existing := range r.specs {
+		if existing.name == name {
+			r.specs = append(r.specs[:i], r.specs[i+1:]...)
+			removed = existing
+			break
+		}
+	}
+	r.mu.Unlock()
+	if removed == nil {
+		return false
+	}
+	removed.done()
+	return true
+}
+
+// SetSelector sets a custom function that picks a spec by name for each request.
+// It is consulted before the matchers supplied at registration.
+func (r *Registry) SetSelector(selector SpecSelector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.selector = selector
+}
+
+// Validator returns the validator registered under the given name. It is released when the name is registered
+// again or unregistered, so it must not be used after that.
+func (r *Registry) Validator(name string) (Validator, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, spec := range r.specs {
+		if spec.name == name {
+			return spec.validator, true
+		}
+	}
+	return nil, false
+}
+
+// Names returns the names of all registered specs, in registration order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, len(r.specs))
+	for i, spec := range r.specs {
+		names[i] = spec.name
+	}
+	return names
+}
+
+// Select returns the validator (and its registered name) that should handle the request. Like the one returned
+// by Validator, it must not be used after its name is registered again or unregistered. The validation methods
+// of the registry hold on to the validator they select until they are done with it.
+func (r *Registry) Select(request *http.Request) (Validator, string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if spec := r.selectSpec(request); spec != nil {
+		return spec.validator, spec.name, true
+	}
+	return nil, "", false
+}
+
+// acquire selects the spec for a request, counting the caller as one of its users until done is called.
+func (r *Registry) acquire(request *http.Request) *registeredSpec {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	spec := r.selectSpec(request)
+	if spec != nil {
+		// a registered spec holds the reference of the registry, which is only dropped under the write lock.
+		spec.refs.Add(1)
+	}
+	return spec
+}
+
+func (r *Registry) selectSpec(request *http.Request) *registeredSpec {
+	if r.selector != nil {
+		if name, ok := r.selector(request); ok {
+			for _, spec := range r.specs {
+				if spec.name == name {
+					return spec
+				}
+			}
+		}
+	}
+	for _, spec := range r.specs {
+		if spec.matches(request) {
+			return spec
+		}
+	}
+	return nil
+}
+
+// done drops a reference to the spec, and releases its validator when it was the last one. The caches of a
+// released registry are released along with the last of its validators.
+func (s *registeredSpec) done() {
+	if s.refs.Add(-1) != 0 {
+		return
+	}
+	s.validator.Release()
+	if s.owner.live.Add(-1) == 0 && s.owner.released.Load() {
+		s.owner.release()
+	}
+}
+
+func (s *registeredSpec) matches(request *http.Request) bool {
+	for _, m := range s.matchers {
+		if m != nil && !m(request) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateHttpRequest selects a spec for the request and validates it. See Validator.ValidateHttpRequest.
+func (r *Registry) ValidateHttpRequest(request *http.Request) (bool, []*errors.ValidationError) {
+	spec := r.acquire(request)
+	if spec == nil {
+		return false, noSpecForRequest(request)
+	}
+	defer spec.done()
+	return spec.validator.ValidateHttpRequest(request)
+}
+
+// ValidateHttpRequestSync selects a spec for the request and validates it synchronously.
+// See Validator.ValidateHttpRequestSync.
+func (r *Registry) ValidateHttpRequestSync(request *http.Request) (bool, []*errors.ValidationError) {
+	spec := r.acquire(request)
+	if spec == nil {
+		return false, noSpecForRequest(request)
+	}
+	defer spec.done()
+	return spec.validator.ValidateHttpRequestSync(request)
+}
+
+// ValidateHttpResponse selects a spec for the request and validates the response against it.
+// See Validator.ValidateHttpResponse.
+func (r *Registry) ValidateHttpResponse(request *http.Request, response *http.Response) (bool, []*errors.ValidationError) {
+	spec := r.acquire(request)
+	if spec == nil {
+		return false, noSpecForRequest(request)
+	}
+	defer spec.done()
+	return spec.validator.ValidateHttpResponse(request, response)
+}
+
+// ValidateHttpRequestResponse selects a spec for the request and validates both the request and response against it.
+// See Validator.ValidateHttpRequestResponse.
+func (r *Registry) ValidateHttpRequestResponse(request *http.Request, response *http.Response) (bool, []*errors.ValidationError) {
+	spec := r.acquire(request)
+	if spec == nil {
+		return false, noSpecForRequest(request)
+	}
+	defer spec.done()
+	return spec.validator.ValidateHttpRequestResponse(request, response)
+}
+
+// ValidateDocument validates every registered document. Errors are returned per spec name.
+func (r *Registry) ValidateDocument() (bool, map[string][]*errors.ValidationError) {
+	r.mu.RLock()
+	specs := append([]*registeredSpec{}, r.specs...)
+	for _, spec := range specs {
+		spec.refs.Add(1)
+	}
+	r.mu.RUnlock()
+
+	valid := true
+	results := make(map[string][]*errors.ValidationError)
+	for _, spec := range specs {
+		ok, errs := spec.validator.ValidateDocument()
+		spec.done()
+		if !ok {
+			valid = false
+			results[spec.name] = errs
+		}
+	}
+	return valid, results
+}
+
+// Release releases every registered validator once the validations using it are done, and after the last of
+// them the resource cache the registry created. Nothing can be registered once the registry is released.
+func (r *Registry) Release() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.released.Store(true)
+	specs := r.specs
+	r.specs = nil
+	r.selector = nil
+	r.mu.Unlock()
+
+	for _, spec := range specs {
+		spec.done()
+	}
+	if r.live.Load() == 0 {
+		r.release()
+	}
+}
+
+// release releases the caches owned by the registry, once.
+func (r *Registry) release() {
+	r.releaseCaches.Do(func() {
+		if r.resourceCache != nil {
+			r.resourceCache.Release()
+		}
+	})
+}
+
+// sharedResourceCache wraps the registry's resource cache so a single validator's Release doesn't clear
+// entries that other registered validators are still using.
+type sharedResourceCache struct {
+	cache.SchemaResourceCache
+}
+
+func (sharedResourceCache) Release() {}
+
+func errRegistryReleased(name string) error {
+	return fmt.Errorf("cannot register spec '%s': the registry has been released", name)
+}
+
+func noSpecForRequest(request *http.Request) []*errors.ValidationError {
+	validationErrors := []*errors.ValidationError{{
+		ValidationType:    helpers.PathValidation,
+		ValidationSubType: helpers.ValidationMissing,
+		Message:           fmt.Sprintf("%s Path '%s' not found", request.Method, request.URL.Path),
+		Reason: fmt.Sprintf("The %s request to '%s' does not match any registered specification",
+			request.Method, request.URL.Path),
+		SpecLine: -1,
+		SpecCol:  -1,
+		HowToFix: errors.HowToFixPath,
+	}}
+	errors.PopulateValidationErrors(validationErrors, request, "")
+	return validationErrors
+}
+
+// MatchPathPrefix matches requests whose URL path starts with the prefix, for example "/v2/".
+func MatchPathPrefix(prefix string) SpecMatcher {
+	return func(request *http.Request) bool {
+		return strings.HasPrefix(request.URL.Path, prefix)
+	}
+}
+
+// MatchHost matches requests sent to the given host (the port is ignored unless the host includes one).
+func MatchHost(host string) SpecMatcher {
+	return func(request *http.Request) bool {
+		return hostMatches(requestHost(request), host)
+	}
+}
+
+// MatchHeader matches requests where the named header equals one of the values (case-insensitive),
+// for example MatchHeader("API-Version", "2").
+func MatchHeader(name string, values ...string) SpecMatcher {
+	return func(request *http.Request) bool {
+		got := strings.TrimSpace(request.Header.Get(name))
+		for _, v := range values {
+			if strings.EqualFold(got, v) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchAccept matches requests whose Accept header lists the media type, for example
+// MatchAccept("application/vnd.acme.v2+json"). Media type parameters are ignored.
+func MatchAccept(mediaType string) SpecMatcher {
+	return func(request *http.Request) bool {
+		for _, header := range request.Header.Values("Accept") {
+			for _, accepted := range strings.Split(header, helpers.Comma) {
+				mt, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+				if err == nil && strings.EqualFold(mt, mediaType) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// MatchServers matches requests that fall under one of the document's servers. Absolute server URLs must match the
+// request host and path prefix, relative server URLs ("/api/v3") only the path prefix. Server variables are replaced
+// with their default values.
+func MatchServers(document *v3.Document) SpecMatcher {
+	type server struct {
+		host string
+		path string
+	}
+	var servers []server
+	if document != nil {
+		for _, s := range document.Servers {
+			if s == nil {
+				continue
+			}
+			raw := s.URL
+			for pair := s.Variables.First(); pair != nil; pair = pair.Next() {
+				raw = strings.ReplaceAll(raw, "{"+pair.Key()+"}", pair.Value().Default)
+			}
+			u, err := url.Parse(raw)
+			if err != nil {
+				continue
+			}
+			servers = append(servers, server{host: u.Host, path: strings.TrimSuffix(u.Path, helpers.Slash)})
+		}
+	}
+	return func(request *http.Request) bool {
+		host := requestHost(request)
+		for _, s := range servers {
+			if s.host != "" && !hostMatches(host, s.host) {
+				continue
+			}
+			if s.path == "" || request.URL.Path == s.path || strings.HasPrefix(request.URL.Path, s.path+helpers.Slash) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func requestHost(request *http.Request) string {
+	if request.Host != "" {
+		return request.Host
+	}
+	return request.URL.Host
+}
+
+func hostMatches(requestHost, host string) bool {
+	if strings.EqualFold(requestHost, host) {
+		return true
+	}
+	// compare without the port when the expected host doesn't specify one
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		if h, _, err := net.SplitHostPort(requestHost); err == nil {
+			return strings.EqualFold(h, host)
+		}
+	}
+	return false
+}
@@ -0,0 +1,400 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/cache"
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+func registrySpec(version, server, idType string) string {
+	return `openapi: 3.1.0
+info:
+  title: Registry ` + version + `
+  version: ` + version + `
+servers:
+  - url: ` + server + `
+paths:
+  /things/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: ` + idType + `
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [version]
+                properties:
+                  version:
+                    type: string
+`
+}
+
+func newTestRegistry(t *testing.T, opts ...config.Option) *Registry {
+	registry := NewRegistry(opts...)
+
+	v1, err := libopenapi.NewDocument([]byte(registrySpec("v1", "https://api.example.com/v1", "integer")))
+	require.NoError(t, err)
+	v2, err := libopenapi.NewDocument([]byte(registrySpec("v2", "https://api.example.com/v2", "string")))
+	require.NoError(t, err)
+
+	require.Empty(t, registry.Register("v1", v1, MatchPathPrefix("/v1/")))
+	require.Empty(t, registry.Register("v2", v2, MatchPathPrefix("/v2/")))
+	return registry
+}
+
+func TestRegistry_SelectByPathPrefix(t *testing.T) {
+	registry := newTestRegistry(t)
+	defer registry.Release()
+
+	assert.Equal(t, []string{"v1", "v2"}, registry.Names())
+
+	// v1 ids are integers
+	valid, errs := registry.ValidateHttpRequest(httptest.NewRequest(http.MethodGet, "https://api.example.com/v1/things/12", nil))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	valid, errs = registry.ValidateHttpRequest(httptest.NewRequest(http.MethodGet, "https://api.example.com/v1/things/abc", nil))
+	assert.False(t, valid)
+	assert.NotEmpty(t, errs)
+
+	// v2 ids are strings
+	valid, errs = registry.ValidateHttpRequestSync(httptest.NewRequest(http.MethodGet, "https://api.example.com/v2/things/abc", nil))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	// nothing registered for v3
+	valid, errs = registry.ValidateHttpRequest(httptest.NewRequest(http.MethodGet, "https://api.example.com/v3/things/abc", nil))
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.True(t, errs[0].IsPathMissingError())
+	assert.Contains(t, errs[0].Reason, "does not match any registered specification")
+}
+
+func TestRegistry_ValidateHttpResponse(t *testing.T) {
+	registry := newTestRegistry(t)
+	defer registry.Release()
+
+	request := httptest.NewRequest(http.MethodGet, "https://api.example.com/v2/things/abc", nil)
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{helpers.ContentTypeHeader: []string{helpers.JSONContentType}},
+		Body:       io.NopCloser(bytes.NewBufferString(`{"version":"v2"}`)),
+	}
+	valid, errs := registry.ValidateHttpResponse(request, response)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	response.Body = io.NopCloser(bytes.NewBufferString(`{}`))
+	valid, errs = registry.ValidateHttpRequestResponse(request, response)
+	assert.False(t, valid)
+	assert.NotEmpty(t, errs)
+
+	request = httptest.NewRequest(http.MethodGet, "https://api.example.com/nope", nil)
+	valid, _ = registry.ValidateHttpResponse(request, response)
+	assert.False(t, valid)
+	valid, _ = registry.ValidateHttpRequestResponse(request, response)
+	assert.False(t, valid)
+}
+
+func TestRegistry_Selector(t *testing.T) {
+	registry := newTestRegistry(t)
+	defer registry.Release()
+
+	registry.SetSelector(func(request *http.Request) (string, bool) {
+		if request.Header.Get("X-Force-Spec") != "" {
+			return request.Header.Get("X-Force-Spec"), true
+		}
+		return "", false
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "https://api.example.com/v1/things/abc", nil)
+	_, name, ok := registry.Select(request)
+	assert.True(t, ok)
+	assert.Equal(t, "v1", name)
+
+	request.Header.Set("X-Force-Spec", "v2")
+	_, name, ok = registry.Select(request)
+	assert.True(t, ok)
+	assert.Equal(t, "v2", name)
+
+	// unknown names fall back to the matchers
+	request.Header.Set("X-Force-Spec", "v9")
+	_, name, ok = registry.Select(request)
+	assert.True(t, ok)
+	assert.Equal(t, "v1", name)
+}
+
+func TestRegistry_ReplaceAndUnregister(t *testing.T) {
+	registry := newTestRegistry(t)
+	defer registry.Release()
+
+	first, ok := registry.Validator("v1")
+	require.True(t, ok)
+
+	doc, _ := libopenapi.NewDocument([]byte(registrySpec("v1", "https://api.example.com/v1", "string")))
+	require.Empty(t, registry.Register("v1", doc, MatchPathPrefix("/v1/")))
+
+	second, ok := registry.Validator("v1")
+	require.True(t, ok)
+	assert.NotSame(t, first, second)
+	assert.Equal(t, []string{"v1", "v2"}, registry.Names())
+
+	valid, _ := registry.ValidateHttpRequest(httptest.NewRequest(http.MethodGet, "https://api.example.com/v1/things/abc", nil))
+	assert.True(t, valid)
+
+	assert.True(t, registry.Unregister("v1"))
+	assert.False(t, registry.Unregister("v1"))
+	_, ok = registry.Validator("v1")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"v2"}, registry.Names())
+
+	assert.NotEmpty(t, registry.Register("nil", nil))
+}
+
+func TestRegistry_ConcurrentRegister(t *testing.T) {
+	registry := newTestRegistry(t)
+	defer registry.Release()
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for range 4 {
+		wg.Go(func() {
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				request := httptest.NewRequest(http.MethodGet, "https://api.example.com/v1/things/1", nil)
+				valid, errs := registry.ValidateHttpRequestSync(request)
+				assert.True(t, valid)
+				assert.Empty(t, errs)
+				request = httptest.NewRequest(http.MethodGet, "https://api.example.com/v1/things/1", nil)
+				valid, errs = registry.ValidateHttpRequest(request)
+				assert.True(t, valid)
+				assert.Empty(t, errs)
+			}
+		})
+	}
+	// replacing a spec doesn't release the validator under the validations in progress.
+	for range 50 {
+		doc, err := libopenapi.NewDocument([]byte(registrySpec("v1", "https://api.example.com/v1", "integer")))
+		require.NoError(t, err)
+		require.Empty(t, registry.Register("v1", doc, MatchPathPrefix("/v1/")))
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestRegistry_SharesResourceCache(t *testing.T) {
+	shared := cache.NewDefaultSchemaResourceCache()
+	registry := newTestRegistry(t, config.WithSchemaResourceCache(shared))
+
+	// releasing one validator must not clear the shared cache
+	shared.Store("sentinel", &cache.SchemaResourceCacheEntry{})
+	assert.True(t, registry.Unregister("v1"))
+	_, found := shared.Load("sentinel")
+	assert.True(t, found)
+
+	// a cache supplied with the options belongs to the caller, releasing the registry leaves it alone.
+	registry.Release()
+	_, found = shared.Load("sentinel")
+	assert.True(t, found)
+	assert.Empty(t, registry.Names())
+}
+
+func TestRegistry_SharesSchemaCache(t *testing.T) {
+	schemaCache := cache.NewDefaultCache()
+	registry := newTestRegistry(t, config.WithSchemaCache(schemaCache))
+
+	count := func() int {
+		n := 0
+		schemaCache.Range(func(uint64, *cache.SchemaCacheEntry) bool {
+			n++
+			return true
+		})
+		return n
+	}
+	require.NotZero(t, count())
+	before := count()
+
+	// replacing or unregistering a spec leaves the schemas of the others in the cache.
+	v1, err := libopenapi.NewDocument([]byte(registrySpec("v1", "https://api.example.com/v1", "integer")))
+	require.NoError(t, err)
+	require.Empty(t, registry.Register("v1", v1, MatchPathPrefix("/v1/")))
+	assert.True(t, registry.Unregister("v1"))
+	assert.Equal(t, before, count())
+
+	registry.Release()
+	assert.Equal(t, before, count())
+}
+
+func TestRegistry_RejectsSharedPersistentCache(t *testing.T) {
+	diskCache, err := cache.NewDiskCache(t.TempDir(), nil)
+	require.NoError(t, err)
+	registry := NewRegistry(config.WithSchemaCache(diskCache))
+	defer registry.Release()
+
+	// a disk cache holds the schemas of one document, the specs of a registry can't share it.
+	v1, err := libopenapi.NewDocument([]byte(registrySpec("v1", "https://api.example.com/v1", "integer")))
+	require.NoError(t, err)
+	errs := registry.Register("v1", v1, MatchPathPrefix("/v1/"))
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "persistent schema cache")
+	assert.Empty(t, registry.Names())
+
+	// a validator with a disk cache of its own can be registered.
+	own, err := cache.NewDiskCache(t.TempDir(), nil)
+	require.NoError(t, err)
+	v, errs := NewValidator(v1, config.WithSchemaCache(own))
+	require.Empty(t, errs)
+	require.NoError(t, registry.RegisterValidator("v1", v, MatchPathPrefix("/v1/")))
+	valid, _ := registry.ValidateHttpRequest(httptest.NewRequest(http.MethodGet, "https://api.example.com/v1/things/1", nil))
+	assert.True(t, valid)
+}
+
+func TestRegistry_ReleasesOwnCacheAfterValidations(t *testing.T) {
+	registry := newTestRegistry(t)
+	registry.resourceCache.Store("sentinel", &cache.SchemaResourceCacheEntry{})
+
+	// a validation in progress keeps the resource cache of the registry until it is done.
+	inFlight := registry.acquire(httptest.NewRequest(http.MethodGet, "https://api.example.com/v1/things/1", nil))
+	require.NotNil(t, inFlight)
+	registry.Release()
+	_, found := registry.resourceCache.Load("sentinel")
+	assert.True(t, found)
+
+	inFlight.done()
+	_, found = registry.resourceCache.Load("sentinel")
+	assert.False(t, found)
+}
+
+// lockingValidator is a validator that uses its registry while it's released.
+type lockingValidator struct {
+	Validator
+	registry *Registry
+	released chan struct{}
+}
+
+func (v *lockingValidator) Release() {
+	v.registry.Names()
+	close(v.released)
+}
+
+func TestRegistry_ReleasesOutsideLock(t *testing.T) {
+	registry := newTestRegistry(t)
+	v1, ok := registry.Validator("v1")
+	require.True(t, ok)
+
+	// releasing a validator doesn't hold the lock of the registry, so it can't block the registry.
+	replaced := &lockingValidator{Validator: v1, registry: registry, released: make(chan struct{})}
+	require.NoError(t, registry.RegisterValidator("v1", replaced, MatchPathPrefix("/v1/")))
+	go registry.Unregister("v1")
+	select {
+	case <-replaced.released:
+	case <-time.After(5 * time.Second):
+		t.Fatal("unregistering a validator deadlocked on the registry lock")
+	}
+
+	released := &lockingValidator{Validator: &validator{}, registry: registry, released: make(chan struct{})}
+	require.NoError(t, registry.RegisterValidator("empty", released))
+	go registry.Release()
+	select {
+	case <-released.released:
+	case <-time.After(5 * time.Second):
+		t.Fatal("releasing the registry deadlocked on the registry lock")
+	}
+
+	// nothing can be registered once the registry is released.
+	assert.Error(t, registry.RegisterValidator("empty", &validator{}))
+	doc, err := libopenapi.NewDocument([]byte(registrySpec("v1", "https://api.example.com/v1", "integer")))
+	require.NoError(t, err)
+	errs := registry.Register("v1", doc)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "released")
+	assert.Empty(t, registry.Names())
+}
+
+func TestRegistry_ValidateDocument(t *testing.T) {
+	registry := newTestRegistry(t)
+	defer registry.Release()
+
+	valid, results := registry.ValidateDocument()
+	assert.True(t, valid)
+	assert.Empty(t, results)
+
+	require.NoError(t, registry.RegisterValidator("empty", &validator{}))
+	valid, results = registry.ValidateDocument()
+	assert.False(t, valid)
+	assert.Len(t, results["empty"], 1)
+}
+
+func TestRegistry_Matchers(t *testing.T) {
+	doc, _ := libopenapi.NewDocument([]byte(registrySpec("v1", "https://api.example.com/v1", "integer")))
+	model, _ := doc.BuildV3Model()
+
+	request := httptest.NewRequest(http.MethodGet, "https://api.example.com:8443/v1/things/1", nil)
+	request.Header.Set("API-Version", " 2 ")
+	request.Header.Set("Accept", "text/html, application/vnd.acme.v2+json;q=0.9")
+
+	assert.True(t, MatchHost("api.example.com")(request))
+	assert.True(t, MatchHost("API.example.com:8443")(request))
+	assert.False(t, MatchHost("api.example.com:443")(request))
+	assert.False(t, MatchHost("other.example.com")(request))
+
+	assert.True(t, MatchHeader("api-version", "1", "2")(request))
+	assert.False(t, MatchHeader("api-version", "1")(request))
+
+	assert.True(t, MatchAccept("application/vnd.acme.v2+json")(request))
+	assert.False(t, MatchAccept("application/json")(request))
+
+	assert.True(t, MatchServers(&model.Model)(request))
+	assert.False(t, MatchServers(&model.Model)(httptest.NewRequest(http.MethodGet, "https://api.example.com/v10/things/1", nil)))
+	assert.False(t, MatchServers(&model.Model)(httptest.NewRequest(http.MethodGet, "https://other.com/v1/things/1", nil)))
+	assert.False(t, MatchServers(nil)(request))
+
+	varDoc, _ := libopenapi.NewDocument([]byte(`openapi: 3.1.0
+servers:
+  - url: /{base}
+    variables:
+      base:
+        default: api
+paths: {}`))
+	varModel, _ := varDoc.BuildV3Model()
+	assert.True(t, MatchServers(&varModel.Model)(httptest.NewRequest(http.MethodGet, "/api/things", nil)))
+	assert.False(t, MatchServers(&varModel.Model)(httptest.NewRequest(http.MethodGet, "/apis/things", nil)))
+}
+
+func TestRegistry_CatchAll(t *testing.T) {
+	registry := newTestRegistry(t)
+	defer registry.Release()
+
+	doc, _ := libopenapi.NewDocument([]byte(registrySpec("default", "/", "string")))
+	require.Empty(t, registry.Register("default", doc))
+
+	_, name, ok := registry.Select(httptest.NewRequest(http.MethodGet, "https://api.example.com/things/abc", nil))
+	assert.True(t, ok)
+	assert.Equal(t, "default", name)
+}
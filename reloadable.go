@@ -0,0 +1,543 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/cache"
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
+	"github.com/pb33f/libopenapi-validator/parameters"
+	"github.com/pb33f/libopenapi-validator/requests"
+	"github.com/pb33f/libopenapi-validator/responses"
+)
+
+var (
+	_ Validator        = (*ReloadableValidator)(nil)
+	_ MessageValidator = (*ReloadableValidator)(nil)
+	_ ContextValidator = (*ReloadableValidator)(nil)
+	_ ResultValidator  = (*ReloadableValidator)(nil)
+)
+
+// ReloadHandler is called after every reload of a ReloadableValidator, with the generation of the validator in
+// use and the errors that kept the new specification from being swapped in, or nil when it was.
+type ReloadHandler func(generation uint64, errs []error)
+
+// ReloadableValidator is a Validator whose specification can be replaced while it is in use, for deployments that
+// update their specifications independently of their binaries.
+//
+// A reload builds and warms a new validator off the request path, validates its document with ValidateDocument,
+// and only swaps it in when both succeed. Otherwise the validator in use is kept. Validations that started before
+// a swap finish on the validator they started with, which is released once the last of them is done.
+//
+// Path items passed to the WithPathItem methods, and the validators returned by the Get methods, belong to the
+// validator in use when they were found, so they should not be kept across reloads. After Release, validations
+// fail with a single error, and the Get methods return nil.
+type ReloadableValidator struct {
+	current       atomic.Pointer[reloadableInstance]
+	generation    atomic.Uint64
+	live          atomic.Int64 // instances not released yet, the shared caches are released after the last one
+	options       []config.Option
+	configuration *datamodel.DocumentConfiguration
+	schemaCache   cache.SchemaCache
+	resourceCache cache.SchemaResourceCache
+	reloading     sync.Mutex
+	onReload      atomic.Pointer[ReloadHandler]
+	watch         sync.Mutex // guards watchStop and watchClosed, held while watching starts and stops
+	watchStop     chan struct{}
+	watchClosed   bool
+	watching      sync.WaitGroup
+}
+
+// reloadableInstance is a validator built by a ReloadableValidator. It counts the validations using it, and the
+// reference held while it is in use, and is released when the last of them is done.
+type reloadableInstance struct {
+	owner     *ReloadableValidator
+	validator Validator
+	refs      atomic.Int64
+}
+
+// NewReloadableValidator creates a ReloadableValidator from an OpenAPI 3+ document. The options are applied to
+// every validator it builds, and specifications reloaded from bytes are parsed with the configuration of the
+// document.
+//
+// Caches supplied with the options are shared by those validators, they are released with the
+// ReloadableValidator rather than with the validators it retires.
+func NewReloadableValidator(document libopenapi.Document, opts ...config.Option) (*ReloadableValidator, []error) {
+	if document == nil {
+		return nil, []error{fmt.Errorf("cannot create a reloadable validator: document is nil")}
+	}
+	r := &ReloadableValidator{configuration: document.GetConfiguration()}
+
+	// share the caches the options supply, and one resource cache, between the validators that are built, so
+	// that retiring a validator does not clear them for the one that replaced it.
+	r.options = append([]config.Option{}, opts...)
+	supplied := config.NewValidationOptions(append([]config.Option{config.WithSchemaCache(nil)}, opts...)...)
+	if supplied.SchemaCache != nil {
+		r.schemaCache = supplied.SchemaCache
+		r.options = append(r.options, config.WithSchemaCache(sharedSchemaCacheFor(supplied.SchemaCache)))
+	}
+	if supplied.SchemaResourceCache != nil {
+		r.resourceCache = supplied.SchemaResourceCache
+		r.options = append(r.options, config.WithSchemaResourceCache(sharedResourceCache{supplied.SchemaResourceCache}))
+	}
+
+	instance, errs := r.build(document)
+	if errs != nil {
+		r.releaseCaches()
+		return nil, errs
+	}
+	r.current.Store(instance)
+	r.generation.Store(1)
+	return r, nil
+}
+
+// NewReloadableValidatorFromFile creates a ReloadableValidator from the OpenAPI 3+ specification in a file. Use
+// WatchFile to reload it when the file changes.
+func NewReloadableValidatorFromFile(path string, opts ...config.Option) (*ReloadableValidator, []error) {
+	spec, err := os.ReadFile(path)
+	if err != nil {
+		return nil, []error{err}
+	}
+	document, err := libopenapi.NewDocument(spec)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return NewReloadableValidator(document, opts...)
+}
+
+// Reload parses a new specification, and swaps in a validator built from it when it builds and its document is
+// valid. It returns the errors that kept it from being swapped in.
+func (r *ReloadableValidator) Reload(spec []byte) []error {
+	var document libopenapi.Document
+	var err error
+	if r.configuration != nil {
+		document, err = libopenapi.NewDocumentWithConfiguration(spec, r.configuration)
+	} else {
+		document, err = libopenapi.NewDocument(spec)
+	}
+	if err != nil {
+		return r.reloaded([]error{err})
+	}
+	return r.ReloadDocument(document)
+}
+
+// ReloadDocument swaps in a validator built from a document, when it builds and the document is valid. It
+// returns the errors that kept it from being swapped in. Reloads run one at a time.
+func (r *ReloadableValidator) ReloadDocument(document libopenapi.Document) []error {
+	if document == nil {
+		return r.reloaded([]error{fmt.Errorf("cannot reload: document is nil")})
+	}
+	r.reloading.Lock()
+	defer r.reloading.Unlock()
+	if r.current.Load() == nil {
+		return r.reloaded([]error{fmt.Errorf("cannot reload: validator released")})
+	}
+
+	instance, errs := r.build(document)
+	if errs != nil {
+		return r.reloaded(errs)
+	}
+	retired := r.current.Swap(instance)
+	r.generation.Add(1)
+	retired.done()
+	return r.reloaded(nil)
+}
+
+// WatchFile reloads the specification in a file whenever its content changes, checking it every interval (or
+// every second, when interval is not positive) until StopWatching or Release is called. Watching a file stops
+// watching the previous one. The outcome of each reload is reported to the handler set with OnReload.
+func (r *ReloadableValidator) WatchFile(path string, interval time.Duration) error {
+	r.watch.Lock()
+	defer r.watch.Unlock()
+	if r.watchClosed {
+		return fmt.Errorf("cannot watch '%s': validator released", path)
+	}
+	spec, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+	r.stopWatching()
+
+	stop := make(chan struct{})
+	r.watchStop = stop
+	r.watching.Add(1)
+	go func() {
+		defer r.watching.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		modified, size := info.ModTime(), info.Size()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			info, err := os.Stat(path)
+			if err != nil || (info.ModTime().Equal(modified) && info.Size() == size) {
+				continue
+			}
+			modified, size = info.ModTime(), info.Size()
+			changed, err := os.ReadFile(path)
+			if err != nil {
+				r.reloaded([]error{err})
+				continue
+			}
+			// a file that is touched, or written again with the same content, is not reloaded.
+			if bytes.Equal(changed, spec) {
+				continue
+			}
+			spec = changed
+			r.Reload(changed)
+		}
+	}()
+	return nil
+}
+
+// StopWatching stops watching the file passed to WatchFile, and waits for a reload in progress to finish.
+func (r *ReloadableValidator) StopWatching() {
+	r.watch.Lock()
+	defer r.watch.Unlock()
+	r.stopWatching()
+}
+
+// stopWatching stops watching a file, with the watch lock held.
+func (r *ReloadableValidator) stopWatching() {
+	if r.watchStop != nil {
+		close(r.watchStop)
+		r.watchStop = nil
+	}
+	r.watching.Wait()
+}
+
+// OnReload sets a handler that is called after every reload, also the reloads of a watched file.
+func (r *ReloadableValidator) OnReload(handler ReloadHandler) {
+	if handler == nil {
+		r.onReload.Store(nil)
+		return
+	}
+	r.onReload.Store(&handler)
+}
+
+// Generation returns the number of specifications that have been in use, starting at 1 for the one the
+// ReloadableValidator was created with. It grows by one with every reload that is swapped in.
+func (r *ReloadableValidator) Generation() uint64 {
+	return r.generation.Load()
+}
+
+// build creates a validator from a document, and validates the document. The validator is released when either
+// fails.
+func (r *ReloadableValidator) build(document libopenapi.Document) (*reloadableInstance, []error) {
+	v, errs := NewValidator(document, r.options...)
+	if errs != nil {
+		return nil, errs
+	}
+	if valid, validationErrors := v.ValidateDocument(); !valid {
+		v.Release()
+		errs = make([]error, 0, len(validationErrors))
+		for _, validationError := range validationErrors {
+			errs = append(errs, validationError)
+		}
+		if len(errs) == 0 {
+			errs = append(errs, fmt.Errorf("cannot reload: document is not valid"))
+		}
+		return nil, errs
+	}
+	instance := &reloadableInstance{owner: r, validator: v}
+	instance.refs.Store(1)
+	r.live.Add(1)
+	return instance, nil
+}
+
+func (r *ReloadableValidator) reloaded(errs []error) []error {
+	if handler := r.onReload.Load(); handler != nil {
+		(*handler)(r.generation.Load(), errs)
+	}
+	return errs
+}
+
+// acquire returns the validator in use, counting the caller as one of its users until done is called. It returns
+// nil once the ReloadableValidator has been released.
+func (r *ReloadableValidator) acquire() *reloadableInstance {
+	for {
+		instance := r.current.Load()
+		if instance == nil {
+			return nil
+		}
+		// an instance with no references left has been retired and released, the next load finds the one
+		// that replaced it.
+		for refs := instance.refs.Load(); refs > 0; refs = instance.refs.Load() {
+			if instance.refs.CompareAndSwap(refs, refs+1) {
+				return instance
+			}
+		}
+	}
+}
+
+// done drops a reference to the instance, and releases its validator when it was the last one. The caches shared
+// by the validators of the owner are released along with the last of its instances, which is only released once
+// the owner has been.
+func (i *reloadableInstance) done() {
+	if i == nil || i.refs.Add(-1) != 0 {
+		return
+	}
+	i.validator.Release()
+	if i.owner.live.Add(-1) == 0 {
+		i.owner.releaseCaches()
+	}
+}
+
+func (r *ReloadableValidator) releaseCaches() {
+	releaseIfSupported(r.schemaCache)
+	if r.resourceCache != nil {
+		r.resourceCache.Release()
+	}
+}
+
+// Release stops watching, and releases the validator in use once the validations using it are done, along with
+// the caches shared by the validators it built.
+func (r *ReloadableValidator) Release() {
+	if r == nil {
+		return
+	}
+	r.watch.Lock()
+	r.stopWatching()
+	r.watchClosed = true
+	r.watch.Unlock()
+
+	r.reloading.Lock()
+	defer r.reloading.Unlock()
+	r.current.Swap(nil).done()
+	r.options = nil
+	r.onReload.Store(nil)
+}
+
+// releasedErrors are the errors of validations after Release.
+func releasedErrors() []*errors.ValidationError {
+	return []*errors.ValidationError{{
+		ValidationType:    helpers.DocumentValidation,
+		ValidationSubType: helpers.ValidationMissing,
+		Message:           "Validator released",
+		Reason:            "The reloadable validator has been released, so there is no specification to validate against",
+		SpecLine:          1,
+		SpecCol:           1,
+		HowToFix:          "Only release a reloadable validator once it is no longer used",
+	}}
+}
+
+// releasedResult is the result of a validation after Release, with every phase skipped.
+func releasedResult(request *http.Request, phases []Phase) *ValidationResult {
+	result := newResult(message.FromHTTPRequest(request))
+	result.skip(append([]Phase{PhasePath}, phases...), skipReleased)
+	result.Errors = releasedErrors()
+	return result.finish(time.Now())
+}
+
+func (r *ReloadableValidator) ValidateHttpRequest(request *http.Request) (bool, []*errors.ValidationError) {
+	instance := r.acquire()
+	if instance == nil {
+		return false, releasedErrors()
+	}
+	defer instance.done()
+	return instance.validator.ValidateHttpRequest(request)
+}
+
+func (r *ReloadableValidator) ValidateHttpRequestSync(request *http.Request) (bool, []*errors.ValidationError) {
+	instance := r.acquire()
+	if instance == nil {
+		return false, releasedErrors()
+	}
+	defer instance.done()
+	return instance.validator.ValidateHttpRequestSync(request)
+}
+
+func (r *ReloadableValidator) ValidateHttpRequestWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	instance := r.acquire()
+	if instance == nil {
+		return false, releasedErrors()
+	}
+	defer instance.done()
+	return instance.validator.ValidateHttpRequestWithPathItem(request, pathItem, pathValue)
+}
+
+func (r *ReloadableValidator) ValidateHttpRequestSyncWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	instance := r.acquire()
+	if instance == nil {
+		return false, releasedErrors()
+	}
+	defer instance.done()
+	return instance.validator.ValidateHttpRequestSyncWithPathItem(request, pathItem, pathValue)
+}
+
+func (r *ReloadableValidator) ValidateHttpResponse(request *http.Request, response *http.Response) (bool, []*errors.ValidationError) {
+	instance := r.acquire()
+	if instance == nil {
+		return false, releasedErrors()
+	}
+	defer instance.done()
+	return instance.validator.ValidateHttpResponse(request, response)
+}
+
+func (r *ReloadableValidator) ValidateHttpRequestContext(ctx context.Context, request *http.Request) (bool, []*errors.ValidationError) {
+	instance := r.acquire()
+	if instance == nil {
+		return false, releasedErrors()
+	}
+	defer instance.done()
+	return instance.validator.(ContextValidator).ValidateHttpRequestContext(ctx, request)
+}
+
+func (r *ReloadableValidator) ValidateHttpResponseContext(
+	ctx context.Context,
+	request *http.Request,
+	response *http.Response,
+) (bool, []*errors.ValidationError) {
+	instance := r.acquire()
+	if instance == nil {
+		return false, releasedErrors()
+	}
+	defer instance.done()
+	return instance.validator.(ContextValidator).ValidateHttpResponseContext(ctx, request, response)
+}
+
+func (r *ReloadableValidator) ValidateHttpRequestResponse(request *http.Request, response *http.Response) (bool, []*errors.ValidationError) {
+	instance := r.acquire()
+	if instance == nil {
+		return false, releasedErrors()
+	}
+	defer instance.done()
+	return instance.validator.ValidateHttpRequestResponse(request, response)
+}
+
+func (r *ReloadableValidator) ValidateMessage(request message.Request) (bool, []*errors.ValidationError) {
+	instance := r.acquire()
+	if instance == nil {
+		return false, releasedErrors()
+	}
+	defer instance.done()
+	return instance.validator.(MessageValidator).ValidateMessage(request)
+}
+
+func (r *ReloadableValidator) ValidateMessageResponse(request message.Request, response message.Response) (bool, []*errors.ValidationError) {
+	instance := r.acquire()
+	if instance == nil {
+		return false, releasedErrors()
+	}
+	defer instance.done()
+	return instance.validator.(MessageValidator).ValidateMessageResponse(request, response)
+}
+
+func (r *ReloadableValidator) ValidateHttpRequestResult(request *http.Request) *ValidationResult {
+	instance := r.acquire()
+	if instance == nil {
+		return releasedResult(request, requestPhases)
+	}
+	defer instance.done()
+	return instance.validator.(ResultValidator).ValidateHttpRequestResult(request)
+}
+
+func (r *ReloadableValidator) ValidateHttpResponseResult(request *http.Request, response *http.Response) *ValidationResult {
+	instance := r.acquire()
+	if instance == nil {
+		return releasedResult(request, responsePhases)
+	}
+	defer instance.done()
+	return instance.validator.(ResultValidator).ValidateHttpResponseResult(request, response)
+}
+
+func (r *ReloadableValidator) ValidateDocument() (bool, []*errors.ValidationError) {
+	instance := r.acquire()
+	if instance == nil {
+		return false, releasedErrors()
+	}
+	defer instance.done()
+	return instance.validator.ValidateDocument()
+}
+
+func (r *ReloadableValidator) GetParameterValidator() parameters.ParameterValidator {
+	instance := r.acquire()
+	if instance == nil {
+		return nil
+	}
+	defer instance.done()
+	return instance.validator.GetParameterValidator()
+}
+
+func (r *ReloadableValidator) GetRequestBodyValidator() requests.RequestBodyValidator {
+	instance := r.acquire()
+	if instance == nil {
+		return nil
+	}
+	defer instance.done()
+	return instance.validator.GetRequestBodyValidator()
+}
+
+func (r *ReloadableValidator) GetResponseBodyValidator() responses.ResponseBodyValidator {
+	instance := r.acquire()
+	if instance == nil {
+		return nil
+	}
+	defer instance.done()
+	return instance.validator.GetResponseBodyValidator()
+}
+
+// SetDocument sets the document of the validator in use. Use ReloadDocument to validate requests against a
+// different document.
+func (r *ReloadableValidator) SetDocument(document libopenapi.Document) {
+	instance := r.acquire()
+	if instance == nil {
+		return
+	}
+	defer instance.done()
+	instance.validator.SetDocument(document)
+}
+
+// sharedSchemaCache wraps a schema cache supplied to a ReloadableValidator, so a retired validator's Release
+// doesn't clear the entries of the validator that replaced it.
+type sharedSchemaCache struct {
+	cache.SchemaCache
+}
+
+func (sharedSchemaCache) Release() {}
+
+func (c sharedSchemaCache) Evicts() bool { return cache.Evicts(c.SchemaCache) }
+
+// sharedPersistentSchemaCache is a sharedSchemaCache that is still bound to the document of each validator.
+type sharedPersistentSchemaCache struct {
+	sharedSchemaCache
+	persistent cache.PersistentSchemaCache
+}
+
+func (c sharedPersistentSchemaCache) Bind(documentHash string, compile cache.SchemaCompiler) error {
+	return c.persistent.Bind(documentHash, compile)
+}
+
+func sharedSchemaCacheFor(schemaCache cache.SchemaCache) cache.SchemaCache {
+	if persistent, ok := schemaCache.(cache.PersistentSchemaCache); ok {
+		return sharedPersistentSchemaCache{sharedSchemaCache{schemaCache}, persistent}
+	}
+	return sharedSchemaCache{schemaCache}
+}
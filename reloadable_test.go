@@ -0,0 +1,236 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/cache"
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+func newReloadable(t *testing.T, opts ...config.Option) *ReloadableValidator {
+	document, err := libopenapi.NewDocument([]byte(registrySpec("v1", "https://api.example.com", "integer")))
+	require.NoError(t, err)
+	r, errs := NewReloadableValidator(document, opts...)
+	require.Empty(t, errs)
+	t.Cleanup(r.Release)
+	return r
+}
+
+func thingRequest(id string) *http.Request {
+	request, _ := http.NewRequest(http.MethodGet, "https://api.example.com/things/"+id, nil)
+	return request
+}
+
+func TestReloadableValidator_Reload(t *testing.T) {
+	r := newReloadable(t)
+	assert.Equal(t, uint64(1), r.Generation())
+
+	valid, _ := r.ValidateHttpRequestSync(thingRequest("abc"))
+	assert.False(t, valid)
+
+	var generations []uint64
+	var reloadErrors [][]error
+	r.OnReload(func(generation uint64, errs []error) {
+		generations = append(generations, generation)
+		reloadErrors = append(reloadErrors, errs)
+	})
+
+	require.Empty(t, r.Reload([]byte(registrySpec("v2", "https://api.example.com", "string"))))
+	assert.Equal(t, uint64(2), r.Generation())
+	valid, errs := r.ValidateHttpRequest(thingRequest("abc"))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	// a specification that does not parse, or that is not valid, is not swapped in.
+	assert.NotEmpty(t, r.Reload([]byte("not: [an openapi document")))
+	assert.NotEmpty(t, r.Reload([]byte("openapi: 3.1.0\npaths: {}")))
+	assert.NotEmpty(t, r.ReloadDocument(nil))
+	assert.Equal(t, uint64(2), r.Generation())
+	valid, _ = r.ValidateHttpRequestSync(thingRequest("abc"))
+	assert.True(t, valid)
+
+	assert.Equal(t, []uint64{2, 2, 2, 2}, generations)
+	assert.Nil(t, reloadErrors[0])
+	for _, errs := range reloadErrors[1:] {
+		assert.NotEmpty(t, errs)
+	}
+}
+
+func TestReloadableValidator_InFlight(t *testing.T) {
+	r := newReloadable(t)
+
+	// a validation in progress holds on to the validator it started with.
+	inFlight := r.acquire()
+	require.Empty(t, r.Reload([]byte(registrySpec("v2", "https://api.example.com", "string"))))
+
+	valid, _ := inFlight.validator.ValidateHttpRequestSync(thingRequest("abc"))
+	assert.False(t, valid)
+	valid, _ = r.ValidateHttpRequestSync(thingRequest("abc"))
+	assert.True(t, valid)
+
+	// the retired validator is released once it is drained.
+	assert.NotNil(t, inFlight.validator.(*validator).v3Model)
+	inFlight.done()
+	assert.Nil(t, inFlight.validator.(*validator).v3Model)
+}
+
+func TestReloadableValidator_Concurrent(t *testing.T) {
+	r := newReloadable(t)
+	specs := [][]byte{
+		[]byte(registrySpec("v1", "https://api.example.com", "integer")),
+		[]byte(registrySpec("v2", "https://api.example.com", "string")),
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				valid, errs := r.ValidateHttpRequest(thingRequest("1"))
+				assert.True(t, valid)
+				assert.Empty(t, errs)
+			}
+		}()
+	}
+	for i := range 10 {
+		require.Empty(t, r.Reload(specs[i%2]))
+	}
+	close(stop)
+	wg.Wait()
+	assert.Equal(t, uint64(11), r.Generation())
+}
+
+func TestReloadableValidator_WatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(registrySpec("v1", "https://api.example.com", "integer")), 0o600))
+
+	r, errs := NewReloadableValidatorFromFile(path)
+	require.Empty(t, errs)
+	defer r.Release()
+
+	reloaded := make(chan []error, 4)
+	r.OnReload(func(_ uint64, errs []error) { reloaded <- errs })
+	require.NoError(t, r.WatchFile(path, 5*time.Millisecond))
+
+	require.NoError(t, os.WriteFile(path, []byte(registrySpec("v2.0.0", "https://api.example.com", "string")), 0o600))
+	select {
+	case errs := <-reloaded:
+		assert.Empty(t, errs)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the changed file was not reloaded")
+	}
+	assert.Equal(t, uint64(2), r.Generation())
+	valid, _ := r.ValidateHttpRequestSync(thingRequest("abc"))
+	assert.True(t, valid)
+
+	r.StopWatching()
+	assert.Error(t, r.WatchFile(filepath.Join(t.TempDir(), "missing.yaml"), 0))
+
+	_, errs = NewReloadableValidatorFromFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.NotEmpty(t, errs)
+	_, errs = NewReloadableValidator(nil)
+	assert.NotEmpty(t, errs)
+}
+
+func TestReloadableValidator_SharedSchemaCache(t *testing.T) {
+	schemaCache := cache.NewDefaultCache()
+	r := newReloadable(t, config.WithSchemaCache(schemaCache))
+
+	count := func() int {
+		n := 0
+		schemaCache.Range(func(uint64, *cache.SchemaCacheEntry) bool {
+			n++
+			return true
+		})
+		return n
+	}
+	require.NotZero(t, count())
+
+	// retiring a validator leaves the schemas of the one that replaced it in the cache.
+	require.Empty(t, r.Reload([]byte(registrySpec("v2", "https://api.example.com", "string"))))
+	assert.NotZero(t, count())
+
+	// validations still in progress keep the shared caches until they are done.
+	inFlight := r.acquire()
+	r.Release()
+	assert.NotZero(t, count())
+	valid, _ := inFlight.validator.ValidateHttpRequestSync(thingRequest("1"))
+	assert.True(t, valid)
+	inFlight.done()
+	assert.Zero(t, count())
+}
+
+func TestReloadableValidator_Released(t *testing.T) {
+	r := newReloadable(t)
+	r.Release()
+	r.Release()
+
+	valid, errs := r.ValidateHttpRequest(thingRequest("1"))
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Validator released", errs[0].Message)
+	valid, errs = r.ValidateHttpRequestSync(thingRequest("1"))
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	valid, errs = r.ValidateHttpResponse(thingRequest("1"), &http.Response{StatusCode: http.StatusOK})
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	valid, errs = r.ValidateDocument()
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+
+	result := r.ValidateHttpRequestResult(thingRequest("1"))
+	assert.False(t, result.Valid)
+	assert.Len(t, result.Errors, 1)
+	assert.Equal(t, PhaseSkipped, result.Phase(PhasePath).Status)
+
+	assert.Nil(t, r.GetParameterValidator())
+	assert.Nil(t, r.GetRequestBodyValidator())
+	assert.Nil(t, r.GetResponseBodyValidator())
+	r.SetDocument(nil)
+
+	assert.NotEmpty(t, r.Reload([]byte(registrySpec("v2", "https://api.example.com", "string"))))
+	assert.Error(t, r.WatchFile(filepath.Join(t.TempDir(), "spec.yaml"), 0))
+}
+
+func TestReloadableValidator_WatchFileConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(registrySpec("v1", "https://api.example.com", "integer")), 0o600))
+	r, errs := NewReloadableValidatorFromFile(path)
+	require.Empty(t, errs)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = r.WatchFile(path, time.Millisecond)
+		}()
+		go func() {
+			defer wg.Done()
+			r.StopWatching()
+		}()
+	}
+	wg.Wait()
+	r.Release()
+	assert.Error(t, r.WatchFile(path, 0))
+}
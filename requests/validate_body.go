@@ -11,11 +11,14 @@ import (
 
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 
+	"github.com/pb33f/libopenapi-validator/cache"
 	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
 	"github.com/pb33f/libopenapi-validator/paths"
 	"github.com/pb33f/libopenapi-validator/schema_validation"
+	"github.com/pb33f/libopenapi-validator/strict"
 )
 
 func (v *requestBodyValidator) ValidateRequestBody(request *http.Request) (bool, []*errors.ValidationError) {
@@ -27,29 +30,36 @@ func (v *requestBodyValidator) ValidateRequestBody(request *http.Request) (bool,
 }
 
 func (v *requestBodyValidator) ValidateRequestBodyWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	return v.ValidateMessageBodyWithPathItem(message.FromHTTPRequest(request), pathItem, pathValue)
+}
+
+func (v *requestBodyValidator) ValidateMessageBodyWithPathItem(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
 	if pathItem == nil {
 		return false, []*errors.ValidationError{{
 			ValidationType:    helpers.PathValidation,
 			ValidationSubType: helpers.ValidationMissing,
-			Message:           fmt.Sprintf("%s Path '%s' not found", request.Method, request.URL.Path),
+			Message:           fmt.Sprintf("%s Path '%s' not found", request.Method(), request.URL().Path),
 			Reason: fmt.Sprintf("The %s request contains a path of '%s' "+
 				"however that path, or the %s method for that path does not exist in the specification",
-				request.Method, request.URL.Path, request.Method),
+				request.Method(), request.URL().Path, request.Method()),
 			SpecLine: -1,
 			SpecCol:  -1,
 			HowToFix: errors.HowToFixPath,
 		}}
 	}
-	operation := helpers.ExtractOperation(request, pathItem)
+	if cancelled := errors.MessageContextDone(request); cancelled != nil {
+		return false, cancelled
+	}
+	operation := helpers.OperationForMethod(request.Method(), pathItem)
 	if operation == nil {
-		return false, []*errors.ValidationError{errors.OperationNotFound(pathItem, request, request.Method, pathValue)}
+		return false, []*errors.ValidationError{errors.MessageOperationNotFound(pathItem, request, request.Method(), pathValue)}
 	}
 	if operation.RequestBody == nil {
 		return true, nil
 	}
 
 	// extract the content type from the request
-	contentType := request.Header.Get(helpers.ContentTypeHeader)
+	contentType := request.Header().Get(helpers.ContentTypeHeader)
 	required := false
 	if operation.RequestBody.Required != nil {
 		required = *operation.RequestBody.Required
@@ -59,13 +69,25 @@ func (v *requestBodyValidator) ValidateRequestBodyWithPathItem(request *http.Req
 			// request body is not required, the validation stop there.
 			return true, nil
 		}
-		return false, []*errors.ValidationError{errors.RequestContentTypeNotFound(operation, request, pathValue)}
+		return false, []*errors.ValidationError{errors.MessageRequestContentTypeNotFound(operation, request, pathValue)}
 	}
 
-	// extract the media type from the content type header.
-	mediaType, ok := v.extractContentType(contentType, operation)
-	if !ok {
-		return false, []*errors.ValidationError{errors.RequestContentTypeNotFound(operation, request, pathValue)}
+	// extract the media type from the content type header, the plan of the operation matches it without
+	// splitting every media type of the request body again.
+	var mediaType *v3.MediaType
+	var compiled *cache.SchemaCacheEntry
+	if planned := v.options.Plans.For(pathItem, request.Method()); planned != nil {
+		ct, _, _ := helpers.ExtractContentType(contentType)
+		matched := planned.RequestBody.Match(ct)
+		if matched == nil {
+			return false, []*errors.ValidationError{errors.MessageRequestContentTypeNotFound(operation, request, pathValue)}
+		}
+		mediaType, compiled = matched.MediaType, matched.Schema.Compiled(v.options.SchemaCache)
+	} else {
+		var ok bool
+		if mediaType, ok = v.extractContentType(contentType, operation); !ok {
+			return false, []*errors.ValidationError{errors.MessageRequestContentTypeNotFound(operation, request, pathValue)}
+		}
 	}
 
 	// Nothing to validate
@@ -77,6 +99,8 @@ func (v *requestBodyValidator) ValidateRequestBodyWithPathItem(request *http.Req
 	schema := mediaType.Schema.Schema()
 
 	isJson := strings.Contains(strings.ToLower(contentType), helpers.JSONType)
+	bodyFormat := strict.BodyFormatJSON
+	var requestBody []byte
 
 	// we currently only support JSON, XML and URLEncoded validation for request bodies
 	if !isJson {
@@ -90,16 +114,18 @@ func (v *requestBodyValidator) ValidateRequestBodyWithPathItem(request *http.Req
 			return true, nil
 		}
 
-		if request != nil && (request.Body != nil || request.GetBody != nil) {
-			requestBody := readAndResetRequestBody(request)
+		var hasBody bool
+		if requestBody, hasBody = readMessageBody(request); hasBody {
 			stringedBody := string(requestBody)
 			var jsonBody any
 			var prevalidationErrors []*errors.ValidationError
 
 			switch {
 			case xmlValid:
+				bodyFormat = strict.BodyFormatXML
 				jsonBody, prevalidationErrors = schema_validation.TransformXMLToSchemaJSON(stringedBody, schema)
 			case urlEncodedValid:
+				bodyFormat = strict.BodyFormatURLEncoded
 				jsonBody, prevalidationErrors = schema_validation.TransformURLEncodedToSchemaJSON(stringedBody, schema, mediaType.Encoding)
 			}
 
@@ -117,19 +143,24 @@ func (v *requestBodyValidator) ValidateRequestBodyWithPathItem(request *http.Req
 				}
 			}
 
-			setRequestBody(request, transformedBytes)
+			// the transformed body is validated, and put back in place of the original.
+			requestBody = transformedBytes
+			setMessageBody(request, transformedBytes)
 		}
+	} else {
+		requestBody, _ = readMessageBody(request)
 	}
 
-	validationSucceeded, validationErrors := ValidateRequestSchema(&ValidateRequestSchemaInput{
-		Request:      request,
+	validationSucceeded, validationErrors := validateRequestSchema(&ValidateRequestSchemaInput{
 		Schema:       schema,
 		Version:      helpers.VersionToFloat(v.document.Version),
 		Options:      []config.Option{config.WithExistingOpts(v.options)},
 		BodyRequired: required,
-	})
+		BodyFormat:   bodyFormat,
+		Compiled:     compiled,
+	}, request, requestBody)
 
-	errors.PopulateValidationErrors(validationErrors, request, pathValue)
+	errors.PopulateMessageValidationErrors(validationErrors, request, pathValue)
 
 	return validationSucceeded, validationErrors
 }
@@ -5,19 +5,25 @@ package requests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"sync"
 	"testing"
 
 	"github.com/pb33f/libopenapi"
 	"github.com/pb33f/testify/assert"
 	"github.com/pb33f/testify/require"
+	"github.com/santhosh-tekuri/jsonschema/v6"
 
 	"github.com/pb33f/libopenapi-validator/config"
+	liberrors "github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
+	"github.com/pb33f/libopenapi-validator/openapi_vocabulary"
 	"github.com/pb33f/libopenapi-validator/paths"
 )
 
@@ -1931,6 +1937,106 @@ paths:
 	assert.Contains(t, errors[0].Message, "id")
 }
 
+func TestValidateBody_StrictMode_XMLRequest(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/xml:
+            schema:
+              type: object
+              xml:
+                name: burger
+              properties:
+                id:
+                  type: integer
+                  xml:
+                    attribute: true
+                name:
+                  type: string
+                toppings:
+                  type: array
+                  xml:
+                    wrapped: true
+                  items:
+                    type: object
+                    xml:
+                      name: topping
+                    properties:
+                      name:
+                        type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model, config.WithStrictMode(), config.WithXmlBodyValidation())
+
+	body := `<burger id="1" secret="x" xmlns:ex="https://example.com"><name>Big Mac</name><sauce>mayo</sauce>` +
+		`<toppings><topping><name>lettuce</name></topping><topping><name>onion</name><raw>true</raw></topping></toppings></burger>`
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger",
+		bytes.NewBuffer([]byte(body)))
+	request.Header.Set("Content-Type", "application/xml")
+
+	valid, errs := v.ValidateRequestBody(request)
+
+	assert.False(t, valid)
+	require.Len(t, errs, 3)
+
+	var messages []string
+	for _, e := range errs {
+		messages = append(messages, e.Message)
+		assert.Equal(t, "undeclared-property", e.ValidationSubType)
+	}
+	assert.Contains(t, strings.Join(messages, "\n"), "request property '@secret' at '/burger/@secret' is not declared")
+	assert.Contains(t, strings.Join(messages, "\n"), "request property 'sauce' at '/burger/sauce' is not declared")
+	assert.Contains(t, strings.Join(messages, "\n"), "request property 'raw' at '/burger/toppings/topping[2]/raw' is not declared")
+}
+
+func TestValidateBody_StrictMode_URLEncodedRequest(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                address:
+                  type: object
+                  properties:
+                    city:
+                      type: string
+            encoding:
+              address:
+                style: deepObject
+                explode: true`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model, config.WithStrictMode(), config.WithURLEncodedBodyValidation())
+
+	body := "name=cheeseburger&address[city]=Springfield&address[zip]=12345"
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger",
+		bytes.NewBuffer([]byte(body)))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	valid, errs := v.ValidateRequestBody(request)
+
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "request property 'zip' at 'address[zip]' is not declared in schema", errs[0].Message)
+	assert.Contains(t, errs[0].HowToFix, "$.body.address.zip")
+}
+
 func TestValidateRequestBody_XMLMarshalError(t *testing.T) {
 	spec := []byte(`
 openapi: 3.1.0
@@ -2171,3 +2277,120 @@ paths:
 	assert.True(t, valid)
 	assert.Len(t, errors, 0)
 }
+
+func TestValidateBody_CustomKeywords(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /accounts:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              x-must-match-field: [iban, confirmIban]
+              properties:
+                iban:
+                  type: string
+                  x-iban: true
+                confirmIban:
+                  type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	iban := func(_ *jsonschema.CompilerContext, value any, _ map[string]any) (jsonschema.SchemaExt, error) {
+		if enabled, ok := value.(bool); !ok || !enabled {
+			return nil, nil
+		}
+		return openapi_vocabulary.KeywordValidator("x-iban", func(instance any) error {
+			if s, ok := instance.(string); ok && !strings.HasPrefix(s, "GB") {
+				return fmt.Errorf("'%s' is not a valid IBAN", s)
+			}
+			return nil
+		}), nil
+	}
+	mustMatch := func(_ *jsonschema.CompilerContext, value any, _ map[string]any) (jsonschema.SchemaExt, error) {
+		fields := value.([]any)
+		return openapi_vocabulary.KeywordValidator("x-must-match-field", func(instance any) error {
+			obj, ok := instance.(map[string]any)
+			if ok && obj[fields[0].(string)] != obj[fields[1].(string)] {
+				return fmt.Errorf("'%s' must match '%s'", fields[1], fields[0])
+			}
+			return nil
+		}), nil
+	}
+
+	v := NewRequestBodyValidator(&m.Model,
+		config.WithKeyword("x-iban", iban), config.WithKeyword("x-must-match-field", mustMatch))
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/accounts",
+		bytes.NewBufferString(`{"iban":"GB82WEST12345698765432","confirmIban":"GB82WEST12345698765432"}`))
+	request.Header.Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+	valid, errs := v.ValidateRequestBody(request)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/accounts",
+		bytes.NewBufferString(`{"iban":"DE89370400440532013000","confirmIban":"GB82WEST12345698765432"}`))
+	request.Header.Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+	valid, errs = v.ValidateRequestBody(request)
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+
+	failures := make(map[string]*liberrors.SchemaValidationFailure)
+	for _, failure := range errs[0].SchemaValidationErrors {
+		failures[failure.KeywordLocation] = failure
+	}
+	require.Contains(t, failures, "/properties/iban/x-iban")
+	require.Contains(t, failures, "/x-must-match-field")
+
+	ibanFailure := failures["/properties/iban/x-iban"]
+	assert.Equal(t, "'DE89370400440532013000' is not a valid IBAN", ibanFailure.Reason)
+	assert.Equal(t, "$.iban", ibanFailure.FieldPath)
+	assert.NotZero(t, ibanFailure.Line)
+	assert.NotZero(t, ibanFailure.Column)
+
+	assert.Equal(t, "'confirmIban' must match 'iban'", failures["/x-must-match-field"].Reason)
+}
+
+func TestValidateBody_CancelledContext(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request, _ := http.NewRequestWithContext(ctx, http.MethodPost, "https://things.com/burgers/createBurger",
+		bytes.NewBufferString(`{"name":1}`))
+	request.Header.Set("Content-Type", "application/json")
+
+	valid, errs := v.(MessageRequestBodyValidator).ValidateMessageBody(message.WithContext(ctx, message.FromHTTPRequest(request)))
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Equal(t, helpers.ContextValidation, errs[0].ValidationType)
+	assert.Equal(t, helpers.ValidationCancelled, errs[0].ValidationSubType)
+
+	// only the context given to the message is checked, not the context of the request.
+	valid, errs = ValidateRequestSchema(&ValidateRequestSchemaInput{
+		Request: request,
+		Schema:  m.Model.Paths.PathItems.GetOrZero("/burgers/createBurger").Post.RequestBody.Content.GetOrZero("application/json").Schema.Schema(),
+		Version: 3.1,
+	})
+	assert.False(t, valid)
+	assert.False(t, liberrors.IsCancelled(errs))
+	assert.NotEmpty(t, errs)
+}
@@ -0,0 +1,37 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package requests
+
+import (
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/message"
+	"github.com/pb33f/libopenapi-validator/paths"
+)
+
+// MessageRequestBodyValidator validates the bodies of transport-neutral message.Request values, which are read
+// directly rather than converted to a *http.Request first. The validators created by NewRequestBodyValidator
+// implement it:
+//
+//	messageValidator := requestBodyValidator.(requests.MessageRequestBodyValidator)
+type MessageRequestBodyValidator interface {
+	// ValidateMessageBody will validate the request body of a message.Request, in the same way as
+	// ValidateRequestBody.
+	ValidateMessageBody(request message.Request) (bool, []*errors.ValidationError)
+
+	// ValidateMessageBodyWithPathItem will validate the request body of a message.Request, in the same way as
+	// ValidateRequestBodyWithPathItem.
+	ValidateMessageBodyWithPathItem(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError)
+}
+
+var _ MessageRequestBodyValidator = (*requestBodyValidator)(nil)
+
+func (v *requestBodyValidator) ValidateMessageBody(request message.Request) (bool, []*errors.ValidationError) {
+	pathItem, errs, foundPath := paths.FindMessagePath(request, v.document, v.options)
+	if len(errs) > 0 {
+		return false, errs
+	}
+	return v.ValidateMessageBodyWithPathItem(request, pathItem, foundPath)
+}
@@ -0,0 +1,126 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package requests
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+func TestValidateMessageBody_RawRequest(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pets:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+`
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model).(MessageRequestBodyValidator)
+
+	raw := "POST /pets HTTP/1.1\r\nHost: things.com\r\nContent-Type: application/json\r\nContent-Length: 15\r\n\r\n{\"name\":\"fido\"}"
+	request, err := message.ParseRawRequest([]byte(raw))
+	require.NoError(t, err)
+
+	valid, errs := v.ValidateMessageBody(request)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	raw = "POST /pets HTTP/1.1\r\nHost: things.com\r\nContent-Type: application/json\r\nContent-Length: 11\r\n\r\n{\"name\":12}"
+	request, err = message.ParseRawRequest([]byte(raw))
+	require.NoError(t, err)
+
+	valid, errs = v.ValidateMessageBody(request)
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+
+	raw = "POST /dogs HTTP/1.1\r\nHost: things.com\r\n\r\n"
+	request, _ = message.ParseRawRequest([]byte(raw))
+	valid, errs = v.ValidateMessageBody(request)
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+}
+
+// streamRequest is a message.Request with a body that can only be read once, and can't be replaced.
+type streamRequest struct {
+	header http.Header
+	body   io.ReadCloser
+}
+
+func (r *streamRequest) Method() string      { return http.MethodPost }
+func (r *streamRequest) URL() *url.URL       { return &url.URL{Path: "/pets"} }
+func (r *streamRequest) Header() http.Header { return r.header }
+func (r *streamRequest) Body() io.ReadCloser { return r.body }
+
+func (r *streamRequest) Cookie(string) (*http.Cookie, error) { return nil, http.ErrNoCookie }
+
+func TestValidateMessageBody_NativeMessages(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pets:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+`
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model, config.WithStrictMode(), config.WithStrictSanitize()).(MessageRequestBodyValidator)
+
+	// a body that can only be read once is read a single time.
+	request := &streamRequest{
+		header: http.Header{"Content-Type": []string{"application/json"}},
+		body:   io.NopCloser(strings.NewReader(`{"name":"fido"}`)),
+	}
+	valid, errs := v.ValidateMessageBody(request)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	request.body = io.NopCloser(strings.NewReader(`{}`))
+	valid, errs = v.ValidateMessageBody(request)
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+
+	// adapters that allow it have their sanitized body put back.
+	gatewayRequest, err := message.NewAPIGatewayRequest(&message.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodPost,
+		Path:       "/pets",
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       `{"name":"fido","extra":true}`,
+	})
+	require.NoError(t, err)
+	valid, errs = v.ValidateMessageBody(gatewayRequest)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+	body, _ := io.ReadAll(gatewayRequest.Body())
+	assert.JSONEq(t, `{"name":"fido"}`, string(body))
+}
@@ -11,8 +11,6 @@ import (
 	"io"
 	"net/http"
 	"reflect"
-	"regexp"
-	"strconv"
 
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/santhosh-tekuri/jsonschema/v6"
@@ -20,22 +18,27 @@ import (
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 
+	"github.com/pb33f/libopenapi-validator/cache"
 	"github.com/pb33f/libopenapi-validator/config"
 	liberrors "github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	libmessage "github.com/pb33f/libopenapi-validator/message"
 	"github.com/pb33f/libopenapi-validator/schema_validation"
 	"github.com/pb33f/libopenapi-validator/strict"
 )
 
-var instanceLocationRegex = regexp.MustCompile(`^/(\d+)`)
-
 // ValidateRequestSchemaInput contains parameters for request schema validation.
 type ValidateRequestSchemaInput struct {
-	Request      *http.Request   // Required: The HTTP request to validate
-	Schema       *base.Schema    // Required: The OpenAPI schema to validate against
-	Version      float32         // Required: OpenAPI version (3.0 or 3.1)
-	Options      []config.Option // Optional: Functional options (defaults applied if empty/nil)
-	BodyRequired bool            // Optional: Whether the request body is required (default false)
+	Request      *http.Request     // Required: The HTTP request to validate
+	Schema       *base.Schema      // Required: The OpenAPI schema to validate against
+	Version      float32           // Required: OpenAPI version (3.0 or 3.1)
+	Options      []config.Option   // Optional: Functional options (defaults applied if empty/nil)
+	BodyRequired bool              // Optional: Whether the request body is required (default false)
+	BodyFormat   strict.BodyFormat // Optional: Format the body was converted from, for strict mode locations (default JSON)
+
+	// Optional: Schema already compiled, such as the schema of a validation plan, which is used without looking
+	// it up in the schema cache.
+	Compiled *cache.SchemaCacheEntry
 }
 
 type replayableBody interface {
@@ -129,10 +132,46 @@ func readAndResetRequestBody(request *http.Request) []byte {
 	return requestBody
 }
 
+// readMessageBody reads the body of a request, and puts it back so it can be read again. ok is false when the
+// request has no body.
+func readMessageBody(request libmessage.Request) (body []byte, ok bool) {
+	if provider, isHTTP := request.(libmessage.HTTPRequestProvider); isHTTP {
+		httpRequest := provider.HTTPRequest()
+		if httpRequest == nil || (httpRequest.Body == nil && httpRequest.GetBody == nil) {
+			return nil, false
+		}
+		return readAndResetRequestBody(httpRequest), true
+	}
+	reader := request.Body()
+	if reader == nil {
+		return nil, false
+	}
+	body, _ = io.ReadAll(reader)
+	_ = reader.Close()
+	setMessageBody(request, body)
+	return body, true
+}
+
+// setMessageBody replaces the body of a request, when its adapter allows it.
+func setMessageBody(request libmessage.Request, body []byte) {
+	if setter, ok := request.(libmessage.BodySetter); ok {
+		setter.SetBody(body)
+	}
+}
+
 // ValidateRequestSchema will validate a http.Request pointer against a schema.
 // If validation fails, it will return a list of validation errors as the second return value.
 // The schema will be stored and reused from cache if available, otherwise it will be compiled on each call.
 func ValidateRequestSchema(input *ValidateRequestSchemaInput) (bool, []*liberrors.ValidationError) {
+	return validateRequestSchema(input, libmessage.FromHTTPRequest(input.Request), readAndResetRequestBody(input.Request))
+}
+
+// validateRequestSchema validates the body of a request, which has already been read, against the schema of input.
+func validateRequestSchema(
+	input *ValidateRequestSchemaInput,
+	request libmessage.Request,
+	requestBody []byte,
+) (bool, []*liberrors.ValidationError) {
 	validationOptions := config.NewValidationOptions(input.Options...)
 	var validationErrors []*liberrors.ValidationError
 	var renderedSchema, jsonSchema []byte
@@ -157,36 +196,49 @@ func ValidateRequestSchema(input *ValidateRequestSchemaInput) (bool, []*liberror
 		}}
 	}
 
-	if validationOptions.SchemaCache != nil {
-		hash := schema_validation.SchemaCacheKey(
+	var hash uint64
+	cached := input.Compiled
+	if cached == nil || cached.CompiledSchema == nil {
+		cached = nil
+		hash = schema_validation.SchemaCacheKey(
 			input.Schema.GoLow().Hash(),
 			input.Version,
 			schema_validation.SchemaValidationPurposeRequestBody,
 		)
-		if cached, ok := validationOptions.SchemaCache.Load(hash); ok && cached != nil && cached.CompiledSchema != nil {
-			renderedSchema = cached.RenderedInline
-			referenceSchema = cached.ReferenceSchema
-			jsonSchema = cached.RenderedJSON
-			compiledSchema = cached.CompiledSchema
-			cachedNode = cached.RenderedNode
-			resourceNodes = cached.ResourceNodes
+		if validationOptions.SchemaCache != nil {
+			if entry, ok := validationOptions.SchemaCache.Load(hash); ok && entry != nil && entry.CompiledSchema != nil {
+				cached = entry
+			}
+			validationOptions.ObserveSchemaCache(hash, "requestBody", cached != nil)
 		}
+	} else {
+		// compiled by the validation plan of the operation, which found it in the cache before.
+		validationOptions.ObserveSchemaCache(0, "requestBody", true)
+	}
+	if cached != nil {
+		renderedSchema = cached.RenderedInline
+		referenceSchema = cached.ReferenceSchema
+		jsonSchema = cached.RenderedJSON
+		compiledSchema = cached.CompiledSchema
+		cachedNode = cached.RenderedNode
+		resourceNodes = cached.ResourceNodes
 	}
 
 	// Cache miss or no cache - render and compile
 	if compiledSchema == nil {
-		compiled, err := schema_validation.CompileSchemaForValidation(
+		compiled, err := schema_validation.CompileSchemaOnce(
 			input.Schema,
 			schema_validation.SchemaValidationPurposeRequestBody,
 			validationOptions,
 			input.Version,
+			hash,
 		)
 		if err != nil {
 			validationErrors = append(validationErrors, &liberrors.ValidationError{
 				ValidationType:    helpers.RequestBodyValidation,
 				ValidationSubType: helpers.Schema,
 				Message: fmt.Sprintf("%s request body for '%s' failed schema compilation",
-					input.Request.Method, input.Request.URL.Path),
+					request.Method(), request.URL().Path),
 				Reason:   fmt.Sprintf("The request schema failed to compile: %s", err.Error()),
 				SpecLine: 1,
 				SpecCol:  0,
@@ -201,33 +253,21 @@ func ValidateRequestSchema(input *ValidateRequestSchemaInput) (bool, []*liberror
 		cachedNode = compiled.RenderedNode
 		resourceNodes = compiled.ResourceNodes
 		compiledSchema = compiled.CompiledSchema
-
-		if validationOptions.SchemaCache != nil {
-			hash := schema_validation.SchemaCacheKey(
-				input.Schema.GoLow().Hash(),
-				input.Version,
-				schema_validation.SchemaValidationPurposeRequestBody,
-			)
-			validationOptions.SchemaCache.Store(hash, compiled.ToCacheEntry(input.Schema))
-		}
 	}
 
-	request := input.Request
 	schema := input.Schema
 
-	requestBody := readAndResetRequestBody(request)
-
 	var decodedObj interface{}
 
 	if len(requestBody) > 0 {
-		err := json.Unmarshal(requestBody, &decodedObj)
+		err := helpers.DecodeJSON(requestBody, &decodedObj)
 		if err != nil {
 			// cannot decode the request body, so it's not valid
 			validationErrors = append(validationErrors, &liberrors.ValidationError{
 				ValidationType:    helpers.RequestBodyValidation,
 				ValidationSubType: helpers.Schema,
 				Message: fmt.Sprintf("%s request body for '%s' failed to validate schema",
-					request.Method, request.URL.Path),
+					request.Method(), request.URL().Path),
 				Reason:   fmt.Sprintf("The request body cannot be decoded: %s", err.Error()),
 				SpecLine: 1,
 				SpecCol:  0,
@@ -263,7 +303,7 @@ func ValidateRequestSchema(input *ValidateRequestSchemaInput) (bool, []*liberror
 			ValidationType:    helpers.RequestBodyValidation,
 			ValidationSubType: helpers.Schema,
 			Message: fmt.Sprintf("%s request body is empty for '%s'",
-				request.Method, request.URL.Path),
+				request.Method(), request.URL().Path),
 			Reason:   "The request body is empty but there is a schema defined",
 			SpecLine: line,
 			SpecCol:  col,
@@ -273,6 +313,11 @@ func ValidateRequestSchema(input *ValidateRequestSchemaInput) (bool, []*liberror
 		return false, validationErrors
 	}
 
+	// schema validation can be expensive, don't start it when nobody is waiting for the result.
+	if cancelled := liberrors.MessageContextDone(request); cancelled != nil {
+		return false, cancelled
+	}
+
 	// validate the object against the schema
 	scErrs := compiledSchema.Validate(decodedObj)
 	if scErrs != nil {
@@ -283,8 +328,11 @@ func ValidateRequestSchema(input *ValidateRequestSchemaInput) (bool, []*liberror
 			// flatten the validationErrors
 			schFlatErrs := helpers.FlattenSchemaOutputErrors(jk.DetailedOutput())
 
-			renderedNode, resourceNodes := schema_validation.DiagnosticLocationNodes(renderedSchema, cachedNode, resourceNodes)
+			locationNodes := schema_validation.DiagnosticLocator(renderedSchema, cachedNode, resourceNodes)
 			for q := range schFlatErrs {
+				if validationOptions.PhaseBudgetSpent(len(schemaValidationErrors)) {
+					break
+				}
 				er := schFlatErrs[q]
 
 				errMsg := er.Error.Kind.LocalizedString(message.NewPrinter(language.Tag{}))
@@ -293,62 +341,22 @@ func ValidateRequestSchema(input *ValidateRequestSchemaInput) (bool, []*liberror
 					continue // ignore this error, it's useless tbh, utter noise.
 				}
 				if er.Error != nil {
-
-					// locate the violated property in the schema
-					var located *yaml.Node
-					if renderedNode != nil {
-						located = schema_validation.LocateSchemaPropertyNodeByJSONPathWithResources(
-							renderedNode,
-							resourceNodes,
-							er.KeywordLocation,
-							er.AbsoluteKeywordLocation,
-						)
-					}
-
-					// extract the element specified by the instance
-					val := instanceLocationRegex.FindStringSubmatch(er.InstanceLocation)
-					var referenceObject string
-
-					if len(val) > 0 {
-						referenceIndex, _ := strconv.Atoi(val[1])
-						if reflect.ValueOf(decodedObj).Type().Kind() == reflect.Slice {
-							found := decodedObj.([]any)[referenceIndex]
-							recoded, _ := json.MarshalIndent(found, "", "  ")
-							referenceObject = string(recoded)
-						}
-					}
-					if referenceObject == "" {
-						referenceObject = string(requestBody)
-					}
-
-					errMsg := er.Error.Kind.LocalizedString(message.NewPrinter(language.Tag{}))
-
 					violation := &liberrors.SchemaValidationFailure{
 						Reason:                  errMsg,
 						FieldName:               helpers.ExtractFieldNameFromStringLocation(er.InstanceLocation),
 						FieldPath:               helpers.ExtractJSONPathFromStringLocation(er.InstanceLocation),
 						InstancePath:            helpers.ConvertStringLocationToPathSegments(er.InstanceLocation),
 						KeywordLocation:         er.KeywordLocation,
-						ReferenceSchema:         referenceSchema,
-						ReferenceObject:         referenceObject,
 						OriginalJsonSchemaError: jk,
 					}
-					// if we have a location within the schema, add it to the error
-					if located != nil {
-
-						line := located.Line
-						// if the located node is a map or an array, then the actual human interpretable
-						// line on which the violation occurred is the line of the key, not the value.
-						if located.Kind == yaml.MappingNode || located.Kind == yaml.SequenceNode {
-							if line > 0 {
-								line--
-							}
-						}
-
-						// location of the violation within the rendered schema.
-						violation.Line = line
-						violation.Column = located.Column
-					}
+					schema_validation.AddFailureDetails(violation, validationOptions, func(violation *liberrors.SchemaValidationFailure) {
+						violation.ReferenceSchema = referenceSchema
+						violation.ReferenceObject = schema_validation.ReferenceObject(er.InstanceLocation, decodedObj, requestBody)
+
+						// if we have a location within the schema, add it to the error
+						renderedNode, resourceNodes := locationNodes()
+						schema_validation.LocateFailure(violation, renderedNode, resourceNodes, er.KeywordLocation, er.AbsoluteKeywordLocation)
+					})
 					schemaValidationErrors = append(schemaValidationErrors, violation)
 				}
 			}
@@ -366,7 +374,7 @@ func ValidateRequestSchema(input *ValidateRequestSchemaInput) (bool, []*liberror
 			ValidationType:    helpers.RequestBodyValidation,
 			ValidationSubType: helpers.Schema,
 			Message: fmt.Sprintf("%s request body for '%s' failed to validate schema",
-				request.Method, request.URL.Path),
+				request.Method(), request.URL().Path),
 			Reason: "The request body is defined as an object. " +
 				"However, it does not meet the schema requirements of the specification",
 			SpecLine:               line,
@@ -383,41 +391,54 @@ func ValidateRequestSchema(input *ValidateRequestSchemaInput) (bool, []*liberror
 	// strict mode: check for undeclared properties in request body
 	if validationOptions.StrictMode && decodedObj != nil {
 		strictValidator := strict.NewValidator(validationOptions, input.Version)
-		strictResult := strictValidator.Validate(strict.Input{
+		strictInput := strict.Input{
 			Schema:    schema,
 			Data:      decodedObj,
 			Direction: strict.DirectionRequest,
 			Options:   validationOptions,
 			BasePath:  "$.body",
 			Version:   input.Version,
-		})
+			Format:    input.BodyFormat,
+			Context:   libmessage.Context(request),
+		}
 
-		if !strictResult.Valid {
+		if validationOptions.StrictSanitize && input.BodyFormat == strict.BodyFormatJSON {
+			// sanitize mode: drop everything strict mode would report, and put the cleaned body back. XML and
+			// urlencoded bodies can't be written back in their own format, so their values are reported instead.
+			if sanitized := strictValidator.Sanitize(strictInput); len(sanitized.Removed) > 0 {
+				if cleaned, err := json.Marshal(sanitized.Data); err == nil {
+					setMessageBody(request, cleaned)
+				}
+			}
+		} else if strictResult := strictValidator.Validate(strictInput); !strictResult.Valid {
 			for _, undeclared := range strictResult.UndeclaredValues {
 				switch undeclared.Type {
 				case strict.TypeReadOnlyProperty:
-					validationErrors = append(validationErrors,
+					validationErrors = append(validationErrors, liberrors.RelocateStrictError(
 						liberrors.ReadOnlyPropertyError(
 							undeclared.Path, undeclared.Name, undeclared.Value,
-							request.URL.Path, request.Method,
+							request.URL().Path, request.Method(),
 							undeclared.SpecLine, undeclared.SpecCol,
-						))
+						), undeclared.Location, undeclared.Path))
 				default:
-					validationErrors = append(validationErrors,
+					validationErrors = append(validationErrors, liberrors.RelocateStrictError(
 						liberrors.UndeclaredPropertyError(
 							undeclared.Path,
 							undeclared.Name,
 							undeclared.Value,
 							undeclared.DeclaredProperties,
 							undeclared.Direction.String(),
-							request.URL.Path,
-							request.Method,
+							request.URL().Path,
+							request.Method(),
 							undeclared.SpecLine,
 							undeclared.SpecCol,
-						))
+						), undeclared.Location, undeclared.Path))
 				}
 			}
 		}
+		if cancelled := liberrors.MessageContextDone(request); cancelled != nil {
+			return false, cancelled
+		}
 	}
 
 	if len(validationErrors) > 0 {
@@ -699,3 +699,49 @@ func TestValidateRequestSchema_NilParentProxy(t *testing.T) {
 	assert.False(t, valid)
 	assert.NotEmpty(t, errs)
 }
+
+func TestValidateRequestSchema_ErrorBudgetAndLazyDetails(t *testing.T) {
+	schema := parseSchemaFromSpec(t, `type: object
+properties:
+  name:
+    type: string
+  age:
+    type: integer
+  tags:
+    type: array`, 3.1)
+	payload := `{"name":1,"age":"old","tags":"none"}`
+
+	valid, errors := ValidateRequestSchema(&ValidateRequestSchemaInput{
+		Request: postRequestWithBody(payload),
+		Schema:  schema,
+		Version: 3.1,
+	})
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 3)
+	// without lazy details, they are filled in straight away.
+	assert.NotEmpty(t, errors[0].SchemaValidationErrors[0].ReferenceSchema)
+	assert.Equal(t, payload, errors[0].SchemaValidationErrors[0].ReferenceObject)
+	assert.NotZero(t, errors[0].SchemaValidationErrors[0].Line)
+
+	valid, errors = ValidateRequestSchema(&ValidateRequestSchemaInput{
+		Request: postRequestWithBody(payload),
+		Schema:  schema,
+		Version: 3.1,
+		Options: []config.Option{config.WithErrorBudget(2, 0), config.WithLazyErrorDetails()},
+	})
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	require.Len(t, errors[0].SchemaValidationErrors, 2)
+
+	// details are only computed when they are asked for.
+	failure := errors[0].SchemaValidationErrors[0]
+	assert.Empty(t, failure.ReferenceSchema)
+	assert.Empty(t, failure.ReferenceObject)
+	assert.Zero(t, failure.Line)
+
+	errors[0].Enrich()
+	assert.NotEmpty(t, failure.ReferenceSchema)
+	assert.Equal(t, payload, failure.ReferenceObject)
+	assert.NotZero(t, failure.Line)
+}
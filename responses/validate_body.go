@@ -4,7 +4,6 @@
 package responses
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,11 +15,15 @@ import (
 
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 
+	"github.com/pb33f/libopenapi-validator/cache"
 	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
 	"github.com/pb33f/libopenapi-validator/paths"
+	"github.com/pb33f/libopenapi-validator/plan"
 	"github.com/pb33f/libopenapi-validator/schema_validation"
+	"github.com/pb33f/libopenapi-validator/strict"
 )
 
 func (v *responseBodyValidator) ValidateResponseBody(
@@ -35,27 +38,34 @@ func (v *responseBodyValidator) ValidateResponseBody(
 }
 
 func (v *responseBodyValidator) ValidateResponseBodyWithPathItem(request *http.Request, response *http.Response, pathItem *v3.PathItem, pathFound string) (bool, []*errors.ValidationError) {
+	return v.ValidateMessageResponseBodyWithPathItem(message.FromHTTPRequest(request), message.FromHTTPResponse(response), pathItem, pathFound)
+}
+
+func (v *responseBodyValidator) ValidateMessageResponseBodyWithPathItem(request message.Request, response message.Response, pathItem *v3.PathItem, pathFound string) (bool, []*errors.ValidationError) {
 	if pathItem == nil {
 		return false, []*errors.ValidationError{{
 			ValidationType:    helpers.PathValidation,
 			ValidationSubType: helpers.ValidationMissing,
-			Message:           fmt.Sprintf("%s Path '%s' not found", request.Method, request.URL.Path),
+			Message:           fmt.Sprintf("%s Path '%s' not found", request.Method(), request.URL().Path),
 			Reason: fmt.Sprintf("The %s request contains a path of '%s' "+
 				"however that path, or the %s method for that path does not exist in the specification",
-				request.Method, request.URL.Path, request.Method),
+				request.Method(), request.URL().Path, request.Method()),
 			SpecLine: -1,
 			SpecCol:  -1,
 			HowToFix: errors.HowToFixPath,
 		}}
 	}
+	if cancelled := errors.MessageContextDone(request); cancelled != nil {
+		return false, cancelled
+	}
 	var validationErrors []*errors.ValidationError
-	operation := helpers.ExtractOperation(request, pathItem)
+	operation := helpers.OperationForMethod(request.Method(), pathItem)
 	if operation == nil {
-		return false, []*errors.ValidationError{errors.OperationNotFound(pathItem, request, request.Method, pathFound)}
+		return false, []*errors.ValidationError{errors.MessageOperationNotFound(pathItem, request, request.Method(), pathFound)}
 	}
 	// extract the response code from the response
-	httpCode := response.StatusCode
-	contentType := response.Header.Get(helpers.ContentTypeHeader)
+	httpCode := response.StatusCode()
+	contentType := response.Header().Get(helpers.ContentTypeHeader)
 	codeStr := strconv.Itoa(httpCode)
 
 	// extract the media type from the content type header.
@@ -66,28 +76,37 @@ func (v *responseBodyValidator) ValidateResponseBodyWithPathItem(request *http.R
 		return true, nil
 	}
 
-	// check if the response code is in the contract
-	foundResponse := operation.Responses.Codes.GetOrZero(codeStr)
-	if foundResponse == nil {
-		// check range definition for response codes
-		foundResponse = operation.Responses.Codes.GetOrZero(fmt.Sprintf("%dXX", httpCode/100))
-		if foundResponse != nil {
-			codeStr = fmt.Sprintf("%dXX", httpCode/100)
+	// check if the response code is in the contract, the plan of the operation has a table of its codes.
+	planned := v.options.Plans.For(pathItem, request.Method())
+	var foundResponse *v3.Response
+	var responsePlan *plan.Response
+	if planned != nil {
+		if responsePlan = planned.Responses.Find(httpCode); responsePlan != nil {
+			foundResponse, codeStr = responsePlan.Response, responsePlan.Code
+		}
+	} else {
+		foundResponse = operation.Responses.Codes.GetOrZero(codeStr)
+		if foundResponse == nil {
+			// check range definition for response codes
+			foundResponse = operation.Responses.Codes.GetOrZero(fmt.Sprintf("%dXX", httpCode/100))
+			if foundResponse != nil {
+				codeStr = fmt.Sprintf("%dXX", httpCode/100)
+			}
 		}
 	}
 
 	if foundResponse != nil {
 		if foundResponse.Content != nil { // only validate if we have content types.
 			// check content type has been defined in the contract
-			if mediaType, ok := foundResponse.Content.Get(mediaTypeSting); ok {
+			if mediaType, compiled, ok := v.responseMediaType(foundResponse, responsePlan, mediaTypeSting); ok {
 				validationErrors = append(validationErrors,
-					v.checkResponseSchema(request, response, mediaTypeSting, mediaType)...)
+					v.checkResponseSchema(request, response, mediaTypeSting, mediaType, compiled)...)
 			} else {
 				// check that the operation *actually* returns a body. (i.e. a 204 response)
 				if foundResponse.Content != nil && orderedmap.Len(foundResponse.Content) > 0 {
 					// content type not found in the contract
 					validationErrors = append(validationErrors,
-						errors.ResponseContentTypeNotFound(operation, request, response, codeStr, false))
+						errors.MessageResponseContentTypeNotFound(operation, request, response, codeStr, false))
 				}
 			}
 		}
@@ -95,36 +114,40 @@ func (v *responseBodyValidator) ValidateResponseBodyWithPathItem(request *http.R
 		// no code match, check for default response
 		if operation.Responses.Default != nil && operation.Responses.Default.Content != nil {
 			// check content type has been defined in the contract
-			if mediaType, ok := operation.Responses.Default.Content.Get(mediaTypeSting); ok {
+			var defaultPlan *plan.Response
+			if planned != nil {
+				defaultPlan = planned.Responses.Default
+			}
+			if mediaType, compiled, ok := v.responseMediaType(operation.Responses.Default, defaultPlan, mediaTypeSting); ok {
 				foundResponse = operation.Responses.Default
 				validationErrors = append(validationErrors,
-					v.checkResponseSchema(request, response, contentType, mediaType)...)
+					v.checkResponseSchema(request, response, contentType, mediaType, compiled)...)
 			} else {
 				// check that the operation *actually* returns a body. (i.e. a 204 response)
 				if operation.Responses.Default.Content != nil && orderedmap.Len(operation.Responses.Default.Content) > 0 {
 					// content type not found in the contract
 					validationErrors = append(validationErrors,
-						errors.ResponseContentTypeNotFound(operation, request, response, codeStr, true))
+						errors.MessageResponseContentTypeNotFound(operation, request, response, codeStr, true))
 				}
 			}
 		} else {
 			// TODO: add support for '2XX' and '3XX' responses in the contract
 			// no default, no code match, nothing!
 			validationErrors = append(validationErrors,
-				errors.ResponseCodeNotFound(operation, request, httpCode))
+				errors.MessageResponseCodeNotFound(operation, request, httpCode))
 		}
 	}
 
 	if foundResponse != nil {
 		// check for headers in the response
 		if foundResponse.Headers != nil {
-			if ok, hErrs := ValidateResponseHeaders(request, response, foundResponse.Headers, pathFound, codeStr, config.WithExistingOpts(v.options)); !ok {
+			if ok, hErrs := validateResponseHeaders(request, response, foundResponse.Headers, pathFound, codeStr, config.WithExistingOpts(v.options)); !ok {
 				validationErrors = append(validationErrors, hErrs...)
 			}
 		}
 	}
 
-	errors.PopulateValidationErrors(validationErrors, request, pathFound)
+	errors.PopulateMessageValidationErrors(validationErrors, request, pathFound)
 
 	if len(validationErrors) > 0 {
 		return false, validationErrors
@@ -132,11 +155,30 @@ func (v *responseBodyValidator) ValidateResponseBodyWithPathItem(request *http.R
 	return true, nil
 }
 
+// responseMediaType finds the media type of a response for a content type, and its compiled schema when the
+// response has a validation plan.
+func (v *responseBodyValidator) responseMediaType(
+	response *v3.Response,
+	planned *plan.Response,
+	contentType string,
+) (*v3.MediaType, *cache.SchemaCacheEntry, bool) {
+	if planned == nil {
+		mediaType, ok := response.Content.Get(contentType)
+		return mediaType, nil, ok
+	}
+	mediaType := planned.MediaType(contentType)
+	if mediaType == nil {
+		return nil, nil, false
+	}
+	return mediaType.MediaType, mediaType.Schema.Compiled(v.options.SchemaCache), true
+}
+
 func (v *responseBodyValidator) checkResponseSchema(
-	request *http.Request,
-	response *http.Response,
+	request message.Request,
+	response message.Response,
 	contentType string,
 	mediaType *v3.MediaType,
+	compiled *cache.SchemaCacheEntry,
 ) []*errors.ValidationError {
 	var validationErrors []*errors.ValidationError
 
@@ -160,11 +202,13 @@ func (v *responseBodyValidator) checkResponseSchema(
 	}
 
 	schema := mediaType.Schema.Schema()
+	bodyFormat := strict.BodyFormatJSON
+	var transformed []byte
 
 	if !isJson {
-		if response != nil && response.Body != http.NoBody {
-			responseBody, _ := io.ReadAll(response.Body)
-			_ = response.Body.Close()
+		if body := response.Body(); body != nil && body != http.NoBody {
+			responseBody, _ := io.ReadAll(body)
+			_ = body.Close()
 
 			stringedBody := string(responseBody)
 			var jsonBody any
@@ -172,8 +216,10 @@ func (v *responseBodyValidator) checkResponseSchema(
 
 			switch {
 			case xmlValid:
+				bodyFormat = strict.BodyFormatXML
 				jsonBody, prevalidationErrors = schema_validation.TransformXMLToSchemaJSON(stringedBody, schema)
 			case urlEncodedValid:
+				bodyFormat = strict.BodyFormatURLEncoded
 				jsonBody, prevalidationErrors = schema_validation.TransformURLEncodedToSchemaJSON(stringedBody, schema, mediaType.Encoding)
 			}
 
@@ -191,18 +237,19 @@ func (v *responseBodyValidator) checkResponseSchema(
 				}
 			}
 
-			response.Body = io.NopCloser(bytes.NewBuffer(transformedBytes))
+			setResponseBody(response, transformedBytes)
+			transformed = transformedBytes
 		}
 	}
 
 	// Validate response schema
-	valid, vErrs := ValidateResponseSchema(&ValidateResponseSchemaInput{
-		Request:  request,
-		Response: response,
-		Schema:   schema,
-		Version:  helpers.VersionToFloat(v.document.Version),
-		Options:  []config.Option{config.WithExistingOpts(v.options)},
-	})
+	valid, vErrs := validateResponseSchema(&ValidateResponseSchemaInput{
+		Schema:     schema,
+		Version:    helpers.VersionToFloat(v.document.Version),
+		Options:    []config.Option{config.WithExistingOpts(v.options)},
+		BodyFormat: bodyFormat,
+		Compiled:   compiled,
+	}, request, response, transformed)
 
 	if !valid {
 		validationErrors = append(validationErrors, vErrs...)
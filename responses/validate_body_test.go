@@ -21,6 +21,7 @@ import (
 
 	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
 	"github.com/pb33f/libopenapi-validator/paths"
 )
 
@@ -1573,6 +1574,83 @@ paths:
 	assert.Contains(t, errs[0].Message, "not declared")
 }
 
+func TestValidateBody_StrictMode_XMLResponse(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/getBurger:
+    get:
+      responses:
+        '200':
+          content:
+            application/xml:
+              schema:
+                type: object
+                xml:
+                  name: burger
+                properties:
+                  name:
+                    type: string
+                  patties:
+                    type: integer`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	v := NewResponseBodyValidator(&m.Model, config.WithStrictMode(), config.WithXmlBodyValidation())
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/getBurger", nil)
+
+	responseBody := `<burger lang="en"><name>Big Mac</name><patties>2</patties></burger>`
+	response := &http.Response{
+		Header:     http.Header{},
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(responseBody)),
+	}
+	response.Header.Set("Content-Type", "application/xml")
+
+	valid, errs := v.ValidateResponseBody(request, response)
+
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "response property '@lang' at '/burger/@lang' is not declared in schema", errs[0].Message)
+}
+
+func TestValidateBody_StrictMode_URLEncodedResponse(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/getBurger:
+    get:
+      responses:
+        '200':
+          content:
+            application/x-www-form-urlencoded:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	v := NewResponseBodyValidator(&m.Model, config.WithStrictMode(), config.WithURLEncodedBodyValidation())
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/getBurger", nil)
+
+	response := &http.Response{
+		Header:     http.Header{},
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("name=cheeseburger&debug=1")),
+	}
+	response.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	valid, errs := v.ValidateResponseBody(request, response)
+
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "response property 'debug' at 'debug' is not declared in schema", errs[0].Message)
+}
+
 func TestValidateBody_StrictMode_ValidResponse(t *testing.T) {
 	spec := `openapi: 3.1.0
 paths:
@@ -1982,3 +2060,45 @@ func (er *errorReader) Read(p []byte) (n int, err error) {
 func (er *errorReader) Close() error {
 	return nil
 }
+
+func TestValidateBody_CancelledContext(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers:
+    get:
+      responses:
+        '200':
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewResponseBodyValidator(&m.Model)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://things.com/burgers", nil)
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"name":1}`)),
+	}
+
+	// only the context given to the message is checked, not the context of the request.
+	valid, errs := v.ValidateResponseBody(request, response)
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Equal(t, helpers.ResponseBodyValidation, errs[0].ValidationType)
+
+	response.Body = io.NopCloser(strings.NewReader(`{"name":1}`))
+	valid, errs = v.(MessageResponseBodyValidator).ValidateMessageResponseBody(
+		message.WithContext(ctx, message.FromHTTPRequest(request)), message.FromHTTPResponse(response))
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Equal(t, helpers.ContextValidation, errs[0].ValidationType)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
 	"github.com/pb33f/libopenapi-validator/parameters"
 	"github.com/pb33f/libopenapi-validator/strict"
 )
@@ -28,6 +29,18 @@ func ValidateResponseHeaders(
 	pathTemplate string,
 	statusCode string,
 	opts ...config.Option,
+) (bool, []*errors.ValidationError) {
+	return validateResponseHeaders(message.FromHTTPRequest(request), message.FromHTTPResponse(response), headers, pathTemplate, statusCode, opts...)
+}
+
+// validateResponseHeaders validates the headers of a message.Response against the OpenAPI spec.
+func validateResponseHeaders(
+	request message.Request,
+	response message.Response,
+	headers *orderedmap.Map[string, *v3.Header],
+	pathTemplate string,
+	statusCode string,
+	opts ...config.Option,
 ) (bool, []*errors.ValidationError) {
 	options := config.NewValidationOptions(opts...)
 
@@ -40,7 +53,7 @@ func ValidateResponseHeaders(
 	locatedHeaders := make(map[string]headerPair)
 	var validationErrors []*errors.ValidationError
 	// iterate through the response headers
-	for name, v := range response.Header {
+	for name, v := range response.Header() {
 		// check if the model is in the spec
 		for pair := headers.First(); pair != nil; pair = pair.Next() {
 			k := pair.Key()
@@ -61,7 +74,7 @@ func ValidateResponseHeaders(
 		header := pair.Value()
 		if header.Required {
 			if _, ok := locatedHeaders[strings.ToLower(name)]; !ok {
-				keywordLocation := helpers.ConstructResponseHeaderJSONPointer(pathTemplate, request.Method, statusCode, name, "required")
+				keywordLocation := helpers.ConstructResponseHeaderJSONPointer(pathTemplate, request.Method(), statusCode, name, "required")
 
 				specLine, specCol := 1, 0
 				if low := header.GoLow(); low != nil && low.KeyNode != nil {
@@ -77,8 +90,8 @@ func ValidateResponseHeaders(
 					SpecLine:          specLine,
 					SpecCol:           specCol,
 					HowToFix:          errors.HowToFixMissingHeader,
-					RequestPath:       request.URL.Path,
-					RequestMethod:     request.Method,
+					RequestPath:       request.URL().Path,
+					RequestMethod:     request.Method(),
 					SchemaValidationErrors: []*errors.SchemaValidationFailure{{
 						Reason:          fmt.Sprintf("Required header '%s' is missing", name),
 						FieldName:       name,
@@ -116,7 +129,11 @@ func ValidateResponseHeaders(
 			declaredMap[name] = header
 		}
 
-		undeclaredHeaders := strict.ValidateResponseHeaders(response.Header, &declaredMap, options)
+		undeclaredHeaders := strict.ValidateResponseHeaders(response.Header(), &declaredMap, options)
+		if options.StrictSanitize {
+			strict.SanitizeHeaders(response.Header(), undeclaredHeaders)
+			undeclaredHeaders = nil
+		}
 		for _, undeclared := range undeclaredHeaders {
 			validationErrors = append(validationErrors,
 				errors.UndeclaredHeaderError(
@@ -124,8 +141,8 @@ func ValidateResponseHeaders(
 					undeclared.Value.(string),
 					undeclared.DeclaredProperties,
 					undeclared.Direction.String(),
-					request.URL.Path,
-					request.Method,
+					request.URL().Path,
+					request.Method(),
 				))
 		}
 	}
@@ -0,0 +1,37 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package responses
+
+import (
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/message"
+	"github.com/pb33f/libopenapi-validator/paths"
+)
+
+// MessageResponseBodyValidator validates the bodies of transport-neutral message.Response values, which are read
+// directly rather than converted to a *http.Response first. The validators created by NewResponseBodyValidator
+// implement it:
+//
+//	messageValidator := responseBodyValidator.(responses.MessageResponseBodyValidator)
+type MessageResponseBodyValidator interface {
+	// ValidateMessageResponseBody will validate the response body of a message.Response, in the same way as
+	// ValidateResponseBody.
+	ValidateMessageResponseBody(request message.Request, response message.Response) (bool, []*errors.ValidationError)
+
+	// ValidateMessageResponseBodyWithPathItem will validate the response body of a message.Response, in the same
+	// way as ValidateResponseBodyWithPathItem.
+	ValidateMessageResponseBodyWithPathItem(request message.Request, response message.Response, pathItem *v3.PathItem, pathFound string) (bool, []*errors.ValidationError)
+}
+
+var _ MessageResponseBodyValidator = (*responseBodyValidator)(nil)
+
+func (v *responseBodyValidator) ValidateMessageResponseBody(request message.Request, response message.Response) (bool, []*errors.ValidationError) {
+	pathItem, errs, foundPath := paths.FindMessagePath(request, v.document, v.options)
+	if len(errs) > 0 {
+		return false, errs
+	}
+	return v.ValidateMessageResponseBodyWithPathItem(request, response, pathItem, foundPath)
+}
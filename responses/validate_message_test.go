@@ -0,0 +1,109 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package responses
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+func TestValidateMessageResponseBody_APIGateway(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [name]
+                properties:
+                  name:
+                    type: string
+`
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+	v, ok := NewResponseBodyValidator(&m.Model).(MessageResponseBodyValidator)
+	require.True(t, ok)
+
+	request, err := message.NewAPIGatewayRequest(&message.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/pets"})
+	require.NoError(t, err)
+
+	response, err := message.NewAPIGatewayResponse(&message.APIGatewayProxyResponse{
+		StatusCode: http.StatusOK,
+		Headers:    map[string]string{"content-type": "application/json"},
+		Body:       `{"name":"fido"}`,
+	})
+	require.NoError(t, err)
+
+	valid, errs := v.ValidateMessageResponseBody(request, response)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	response, _ = message.NewAPIGatewayResponse(&message.APIGatewayProxyResponse{
+		StatusCode: http.StatusOK,
+		Headers:    map[string]string{"content-type": "application/json"},
+		Body:       `{}`,
+	})
+	valid, errs = v.ValidateMessageResponseBody(request, response)
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+
+	request, _ = message.NewAPIGatewayRequest(&message.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/cats"})
+	valid, errs = v.ValidateMessageResponseBody(request, response)
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+}
+
+func TestValidateMessageResponseBody_SanitizedBody(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+`
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+	v := NewResponseBodyValidator(&m.Model, config.WithStrictMode(), config.WithStrictSanitize()).(MessageResponseBodyValidator)
+
+	request, err := message.NewAPIGatewayRequest(&message.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/pets"})
+	require.NoError(t, err)
+	response, err := message.NewAPIGatewayResponse(&message.APIGatewayProxyResponse{
+		StatusCode: http.StatusOK,
+		Headers:    map[string]string{"content-type": "application/json"},
+		Body:       `{"name":"fido","secret":"s3cr3t"}`,
+	})
+	require.NoError(t, err)
+
+	valid, errs := v.ValidateMessageResponseBody(request, response)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	// the body is read from the message itself, and the sanitized body is put back into it.
+	body, err := io.ReadAll(response.Body())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"fido"}`, string(body))
+}
@@ -10,9 +10,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"reflect"
-	"regexp"
 	"strconv"
+	"sync"
 
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/santhosh-tekuri/jsonschema/v6"
@@ -20,22 +19,27 @@ import (
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 
+	"github.com/pb33f/libopenapi-validator/cache"
 	"github.com/pb33f/libopenapi-validator/config"
 	liberrors "github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	libmessage "github.com/pb33f/libopenapi-validator/message"
 	"github.com/pb33f/libopenapi-validator/schema_validation"
 	"github.com/pb33f/libopenapi-validator/strict"
 )
 
-var instanceLocationRegex = regexp.MustCompile(`^/(\d+)`)
-
 // ValidateResponseSchemaInput contains parameters for response schema validation.
 type ValidateResponseSchemaInput struct {
-	Request  *http.Request   // Required: The HTTP request (for context)
-	Response *http.Response  // Required: The HTTP response to validate
-	Schema   *base.Schema    // Required: The OpenAPI schema to validate against
-	Version  float32         // Required: OpenAPI version (3.0 or 3.1)
-	Options  []config.Option // Optional: Functional options (defaults applied if empty/nil)
+	Request    *http.Request     // Required: The HTTP request (for context)
+	Response   *http.Response    // Required: The HTTP response to validate
+	Schema     *base.Schema      // Required: The OpenAPI schema to validate against
+	Version    float32           // Required: OpenAPI version (3.0 or 3.1)
+	Options    []config.Option   // Optional: Functional options (defaults applied if empty/nil)
+	BodyFormat strict.BodyFormat // Optional: Format the body was converted from, for strict mode locations (default JSON)
+
+	// Optional: Schema already compiled, such as the schema of a validation plan, which is used without looking
+	// it up in the schema cache.
+	Compiled *cache.SchemaCacheEntry
 }
 
 // ValidateResponseSchema will validate the response body for a http.Response pointer. The request is used to
@@ -45,6 +49,25 @@ type ValidateResponseSchemaInput struct {
 // This function is used by the ValidateResponseBody function, but can be used independently.
 // The schema will be compiled from cache if available, otherwise it will be compiled and cached.
 func ValidateResponseSchema(input *ValidateResponseSchemaInput) (bool, []*liberrors.ValidationError) {
+	var request libmessage.Request
+	if input.Request != nil {
+		request = libmessage.FromHTTPRequest(input.Request)
+	}
+	var response libmessage.Response
+	if input.Response != nil {
+		response = libmessage.FromHTTPResponse(input.Response)
+	}
+	return validateResponseSchema(input, request, response, nil)
+}
+
+// validateResponseSchema validates the body of a message.Response, which is read from the response unless the
+// body has already been read and transformed to JSON by the caller.
+func validateResponseSchema(
+	input *ValidateResponseSchemaInput,
+	request libmessage.Request,
+	response libmessage.Response,
+	responseBody []byte,
+) (bool, []*liberrors.ValidationError) {
 	validationOptions := config.NewValidationOptions(input.Options...)
 	var validationErrors []*liberrors.ValidationError
 	var renderedSchema []byte
@@ -69,37 +92,50 @@ func ValidateResponseSchema(input *ValidateResponseSchemaInput) (bool, []*liberr
 		}}
 	}
 
-	if validationOptions.SchemaCache != nil {
-		hash := schema_validation.SchemaCacheKey(
+	var hash uint64
+	cached := input.Compiled
+	if cached == nil || cached.CompiledSchema == nil {
+		cached = nil
+		hash = schema_validation.SchemaCacheKey(
 			input.Schema.GoLow().Hash(),
 			input.Version,
 			schema_validation.SchemaValidationPurposeResponseBody,
 		)
-		if cached, ok := validationOptions.SchemaCache.Load(hash); ok && cached != nil && cached.CompiledSchema != nil {
-			renderedSchema = cached.RenderedInline
-			referenceSchema = cached.ReferenceSchema
-			compiledSchema = cached.CompiledSchema
-			cachedNode = cached.RenderedNode
-			resourceNodes = cached.ResourceNodes
+		if validationOptions.SchemaCache != nil {
+			if entry, ok := validationOptions.SchemaCache.Load(hash); ok && entry != nil && entry.CompiledSchema != nil {
+				cached = entry
+			}
+			validationOptions.ObserveSchemaCache(hash, "responseBody", cached != nil)
 		}
+	} else {
+		// compiled by the validation plan of the operation, which found it in the cache before.
+		validationOptions.ObserveSchemaCache(0, "responseBody", true)
+	}
+	if cached != nil {
+		renderedSchema = cached.RenderedInline
+		referenceSchema = cached.ReferenceSchema
+		compiledSchema = cached.CompiledSchema
+		cachedNode = cached.RenderedNode
+		resourceNodes = cached.ResourceNodes
 	}
 
 	// Cache miss or no cache - render and compile
 	if compiledSchema == nil {
-		compiled, err := schema_validation.CompileSchemaForValidation(
+		compiled, err := schema_validation.CompileSchemaOnce(
 			input.Schema,
 			schema_validation.SchemaValidationPurposeResponseBody,
 			validationOptions,
 			input.Version,
+			hash,
 		)
 		if err != nil {
 			validationErrors = append(validationErrors, &liberrors.ValidationError{
 				ValidationType:    helpers.ResponseBodyValidation,
 				ValidationSubType: helpers.Schema,
 				Message: fmt.Sprintf("%d response body for '%s' failed schema compilation",
-					input.Response.StatusCode, input.Request.URL.Path),
+					response.StatusCode(), request.URL().Path),
 				Reason: fmt.Sprintf("The response schema for status code '%d' failed to compile: %s",
-					input.Response.StatusCode, err.Error()),
+					response.StatusCode(), err.Error()),
 				SpecLine: 1,
 				SpecCol:  0,
 				HowToFix: "check the response schema for invalid JSON Schema syntax, complex regex patterns, or unsupported schema constructs",
@@ -112,68 +148,64 @@ func ValidateResponseSchema(input *ValidateResponseSchemaInput) (bool, []*liberr
 		cachedNode = compiled.RenderedNode
 		resourceNodes = compiled.ResourceNodes
 		compiledSchema = compiled.CompiledSchema
-
-		if validationOptions.SchemaCache != nil {
-			hash := schema_validation.SchemaCacheKey(
-				input.Schema.GoLow().Hash(),
-				input.Version,
-				schema_validation.SchemaValidationPurposeResponseBody,
-			)
-			validationOptions.SchemaCache.Store(hash, compiled.ToCacheEntry(input.Schema))
-		}
 	}
 
-	request := input.Request
-	response := input.Response
 	schema := input.Schema
 
-	if response == nil || response.Body == http.NoBody {
+	if responseBody == nil {
+		var body io.ReadCloser
+		if response != nil {
+			body = response.Body()
+		}
+		if body == nil || body == http.NoBody {
 
-		// skip response body validation for head request after processing schema
-		if response != nil && request != nil && request.Method == http.MethodHead {
-			return len(validationErrors) == 0, validationErrors
+			// skip response body validation for head request after processing schema
+			if response != nil && request != nil && request.Method() == http.MethodHead {
+				return len(validationErrors) == 0, validationErrors
+			}
+			// cannot decode the response body, so it's not valid
+			validationErrors = append(validationErrors, &liberrors.ValidationError{
+				ValidationType:    "response",
+				ValidationSubType: "object",
+				Message: fmt.Sprintf("%s response object is missing for '%s'",
+					request.Method(), request.URL().Path),
+				Reason:   "The response object is completely missing",
+				SpecLine: 1,
+				SpecCol:  0,
+				HowToFix: "ensure response object has been set",
+				Context:  schema,
+			})
+			return false, validationErrors
 		}
-		// cannot decode the response body, so it's not valid
-		validationErrors = append(validationErrors, &liberrors.ValidationError{
-			ValidationType:    "response",
-			ValidationSubType: "object",
-			Message: fmt.Sprintf("%s response object is missing for '%s'",
-				request.Method, request.URL.Path),
-			Reason:   "The response object is completely missing",
-			SpecLine: 1,
-			SpecCol:  0,
-			HowToFix: "ensure response object has been set",
-			Context:  schema,
-		})
-		return false, validationErrors
-	}
 
-	responseBody, ioErr := io.ReadAll(response.Body)
-	if ioErr != nil {
-		// cannot decode the response body, so it's not valid
-		validationErrors = append(validationErrors, &liberrors.ValidationError{
-			ValidationType:    helpers.ResponseBodyValidation,
-			ValidationSubType: helpers.Schema,
-			Message: fmt.Sprintf("%s response body for '%s' cannot be read, it's empty or malformed",
-				request.Method, request.URL.Path),
-			Reason:   fmt.Sprintf("The response body cannot be decoded: %s", ioErr.Error()),
-			SpecLine: 1,
-			SpecCol:  0,
-			HowToFix: "ensure body is not empty",
-			Context:  schema,
-		})
-		return false, validationErrors
-	}
+		var ioErr error
+		responseBody, ioErr = io.ReadAll(body)
+		if ioErr != nil {
+			// cannot decode the response body, so it's not valid
+			validationErrors = append(validationErrors, &liberrors.ValidationError{
+				ValidationType:    helpers.ResponseBodyValidation,
+				ValidationSubType: helpers.Schema,
+				Message: fmt.Sprintf("%s response body for '%s' cannot be read, it's empty or malformed",
+					request.Method(), request.URL().Path),
+				Reason:   fmt.Sprintf("The response body cannot be decoded: %s", ioErr.Error()),
+				SpecLine: 1,
+				SpecCol:  0,
+				HowToFix: "ensure body is not empty",
+				Context:  schema,
+			})
+			return false, validationErrors
+		}
 
-	// close the request body, so it can be re-read later by another player in the chain
-	_ = response.Body.Close()
-	response.Body = io.NopCloser(bytes.NewBuffer(responseBody))
+		// close the request body, so it can be re-read later by another player in the chain
+		_ = body.Close()
+		setResponseBody(response, responseBody)
+	}
 
 	var decodedObj interface{}
 
 	if len(responseBody) > 0 {
 		// Per RFC7231, a response to a HEAD request MUST NOT include a message body.
-		if request != nil && request.Method == http.MethodHead {
+		if request != nil && request.Method() == http.MethodHead {
 			violation := &liberrors.SchemaValidationFailure{
 				Reason:          "HEAD responses must not include a message body",
 				ReferenceObject: string(responseBody),
@@ -183,7 +215,7 @@ func ValidateResponseSchema(input *ValidateResponseSchemaInput) (bool, []*liberr
 				ValidationType:    helpers.ResponseBodyValidation,
 				ValidationSubType: helpers.Schema,
 				Message: fmt.Sprintf("%s response for '%s' must not include a body",
-					request.Method, request.URL.Path),
+					request.Method(), request.URL().Path),
 				Reason:                 "The response to a HEAD request must not contain a body",
 				SpecLine:               1,
 				SpecCol:                0,
@@ -193,14 +225,14 @@ func ValidateResponseSchema(input *ValidateResponseSchemaInput) (bool, []*liberr
 			})
 			return false, validationErrors
 		}
-		err := json.Unmarshal(responseBody, &decodedObj)
+		err := helpers.DecodeJSON(responseBody, &decodedObj)
 		if err != nil {
 			// cannot decode the response body, so it's not valid
 			validationErrors = append(validationErrors, &liberrors.ValidationError{
 				ValidationType:    helpers.ResponseBodyValidation,
 				ValidationSubType: helpers.Schema,
 				Message: fmt.Sprintf("%s response body for '%s' failed to validate schema",
-					request.Method, request.URL.Path),
+					request.Method(), request.URL().Path),
 				Reason:   fmt.Sprintf("The response body cannot be decoded: %s", err.Error()),
 				SpecLine: 1,
 				SpecCol:  0,
@@ -216,6 +248,11 @@ func ValidateResponseSchema(input *ValidateResponseSchemaInput) (bool, []*liberr
 		return true, nil
 	}
 
+	// schema validation can be expensive, don't start it when nobody is waiting for the result.
+	if cancelled := liberrors.MessageContextDone(request); cancelled != nil {
+		return false, cancelled
+	}
+
 	// validate the object against the schema
 	scErrs := compiledSchema.Validate(decodedObj)
 	if scErrs != nil {
@@ -226,13 +263,24 @@ func ValidateResponseSchema(input *ValidateResponseSchemaInput) (bool, []*liberr
 			// flatten the validationErrors
 			schFlatErrs := helpers.FlattenSchemaOutputErrors(jk.DetailedOutput())
 
-			renderedNode := cachedNode
-			if renderedNode == nil {
-				renderedNode = new(yaml.Node)
-				_ = yaml.Unmarshal(renderedSchema, renderedNode)
+			// the rendered node is only needed to locate failures, which can be deferred.
+			var renderedNode *yaml.Node
+			var locateOnce sync.Once
+			locationNode := func() *yaml.Node {
+				locateOnce.Do(func() {
+					renderedNode = cachedNode
+					if renderedNode == nil {
+						renderedNode = new(yaml.Node)
+						_ = yaml.Unmarshal(renderedSchema, renderedNode)
+					}
+				})
+				return renderedNode
 			}
 
 			for q := range schFlatErrs {
+				if validationOptions.PhaseBudgetSpent(len(schemaValidationErrors)) {
+					break
+				}
 				er := schFlatErrs[q]
 
 				errMsg := er.Error.Kind.LocalizedString(message.NewPrinter(language.Tag{}))
@@ -240,59 +288,21 @@ func ValidateResponseSchema(input *ValidateResponseSchemaInput) (bool, []*liberr
 					continue // ignore this error, it's useless tbh, utter noise.
 				}
 				if er.Error != nil {
-					// locate the violated property in the schema
-					var located *yaml.Node
-					if renderedNode != nil {
-						located = schema_validation.LocateSchemaPropertyNodeByJSONPathWithResources(
-							renderedNode,
-							resourceNodes,
-							er.KeywordLocation,
-							er.AbsoluteKeywordLocation,
-						)
-					}
-
-					// extract the element specified by the instance
-					val := instanceLocationRegex.FindStringSubmatch(er.InstanceLocation)
-					var referenceObject string
-
-					if len(val) > 0 {
-						referenceIndex, _ := strconv.Atoi(val[1])
-						if reflect.ValueOf(decodedObj).Type().Kind() == reflect.Slice {
-							found := decodedObj.([]any)[referenceIndex]
-							recoded, _ := json.MarshalIndent(found, "", "  ")
-							referenceObject = string(recoded)
-						}
-					}
-					if referenceObject == "" {
-						referenceObject = string(responseBody)
-					}
-
 					violation := &liberrors.SchemaValidationFailure{
 						Reason:                  errMsg,
 						FieldName:               helpers.ExtractFieldNameFromStringLocation(er.InstanceLocation),
 						FieldPath:               helpers.ExtractJSONPathFromStringLocation(er.InstanceLocation),
 						InstancePath:            helpers.ConvertStringLocationToPathSegments(er.InstanceLocation),
 						KeywordLocation:         er.KeywordLocation,
-						ReferenceSchema:         referenceSchema,
-						ReferenceObject:         referenceObject,
 						OriginalJsonSchemaError: jk,
 					}
-					// if we have a location within the schema, add it to the error
-					if located != nil {
-
-						line := located.Line
-						// if the located node is a map or an array, then the actual human interpretable
-						// line on which the violation occurred is the line of the key, not the value.
-						if located.Kind == yaml.MappingNode || located.Kind == yaml.SequenceNode {
-							if line > 0 {
-								line--
-							}
-						}
-
-						// location of the violation within the rendered schema.
-						violation.Line = line
-						violation.Column = located.Column
-					}
+					schema_validation.AddFailureDetails(violation, validationOptions, func(violation *liberrors.SchemaValidationFailure) {
+						violation.ReferenceSchema = referenceSchema
+						violation.ReferenceObject = schema_validation.ReferenceObject(er.InstanceLocation, decodedObj, responseBody)
+
+						// if we have a location within the schema, add it to the error
+						schema_validation.LocateFailure(violation, locationNode(), resourceNodes, er.KeywordLocation, er.AbsoluteKeywordLocation)
+					})
 					schemaValidationErrors = append(schemaValidationErrors, violation)
 				}
 			}
@@ -310,9 +320,9 @@ func ValidateResponseSchema(input *ValidateResponseSchemaInput) (bool, []*liberr
 			ValidationType:    helpers.ResponseBodyValidation,
 			ValidationSubType: helpers.Schema,
 			Message: fmt.Sprintf("%d response body for '%s' failed to validate schema",
-				response.StatusCode, request.URL.Path),
+				response.StatusCode(), request.URL().Path),
 			Reason: fmt.Sprintf("The response body for status code '%d' is defined as an object. "+
-				"However, it does not meet the schema requirements of the specification", response.StatusCode),
+				"However, it does not meet the schema requirements of the specification", response.StatusCode()),
 			SpecLine:               line,
 			SpecCol:                col,
 			SchemaValidationErrors: schemaValidationErrors,
@@ -327,41 +337,59 @@ func ValidateResponseSchema(input *ValidateResponseSchemaInput) (bool, []*liberr
 	// strict mode: check for undeclared properties in response body
 	if validationOptions.StrictMode && decodedObj != nil {
 		strictValidator := strict.NewValidator(validationOptions, input.Version)
-		strictResult := strictValidator.Validate(strict.Input{
+		strictInput := strict.Input{
 			Schema:    schema,
 			Data:      decodedObj,
 			Direction: strict.DirectionResponse,
 			Options:   validationOptions,
 			BasePath:  "$.body",
 			Version:   input.Version,
-		})
+			Format:    input.BodyFormat,
+			Context:   libmessage.Context(request),
+		}
 
-		if !strictResult.Valid {
+		if validationOptions.StrictSanitize && input.BodyFormat == strict.BodyFormatJSON {
+			// sanitize mode: drop everything strict mode would report, and put the cleaned body back. XML and
+			// urlencoded bodies can't be written back in their own format, so their values are reported instead.
+			if sanitized := strictValidator.Sanitize(strictInput); len(sanitized.Removed) > 0 {
+				if cleaned, err := json.Marshal(sanitized.Data); err == nil {
+					if setter, ok := response.(libmessage.BodySetter); ok {
+						setter.SetBody(cleaned)
+					}
+					if header := response.Header(); header != nil && header.Get(helpers.ContentLengthHeader) != "" {
+						header.Set(helpers.ContentLengthHeader, strconv.Itoa(len(cleaned)))
+					}
+				}
+			}
+		} else if strictResult := strictValidator.Validate(strictInput); !strictResult.Valid {
 			for _, undeclared := range strictResult.UndeclaredValues {
 				switch undeclared.Type {
 				case strict.TypeWriteOnlyProperty:
-					validationErrors = append(validationErrors,
+					validationErrors = append(validationErrors, liberrors.RelocateStrictError(
 						liberrors.WriteOnlyPropertyError(
 							undeclared.Path, undeclared.Name, undeclared.Value,
-							request.URL.Path, request.Method,
+							request.URL().Path, request.Method(),
 							undeclared.SpecLine, undeclared.SpecCol,
-						))
+						), undeclared.Location, undeclared.Path))
 				default:
-					validationErrors = append(validationErrors,
+					validationErrors = append(validationErrors, liberrors.RelocateStrictError(
 						liberrors.UndeclaredPropertyError(
 							undeclared.Path,
 							undeclared.Name,
 							undeclared.Value,
 							undeclared.DeclaredProperties,
 							undeclared.Direction.String(),
-							request.URL.Path,
-							request.Method,
+							request.URL().Path,
+							request.Method(),
 							undeclared.SpecLine,
 							undeclared.SpecCol,
-						))
+						), undeclared.Location, undeclared.Path))
 				}
 			}
 		}
+		if cancelled := liberrors.MessageContextDone(request); cancelled != nil {
+			return false, cancelled
+		}
 	}
 
 	if len(validationErrors) > 0 {
@@ -369,3 +397,14 @@ func ValidateResponseSchema(input *ValidateResponseSchemaInput) (bool, []*liberr
 	}
 	return true, nil
 }
+
+// setResponseBody puts a body back into a response so it can be read again, when its adapter allows it.
+func setResponseBody(response libmessage.Response, body []byte) {
+	if provider, ok := response.(libmessage.HTTPResponseProvider); ok && provider.HTTPResponse() != nil {
+		provider.HTTPResponse().Body = io.NopCloser(bytes.NewBuffer(body))
+		return
+	}
+	if setter, ok := response.(libmessage.BodySetter); ok {
+		setter.SetBody(body)
+	}
+}
@@ -549,3 +549,34 @@ func TestValidateResponseSchema_IgnoresEmptyKeywordLocationErrors(t *testing.T)
 	require.Len(t, errors, 1)
 	assert.Empty(t, errors[0].SchemaValidationErrors)
 }
+
+func TestValidateResponseSchema_ErrorBudgetAndLazyDetails(t *testing.T) {
+	schema := parseSchemaFromSpec(t, `type: object
+properties:
+  name:
+    type: string
+  age:
+    type: integer`, 3.1)
+	payload := `{"name":1,"age":"old"}`
+
+	valid, errors := ValidateResponseSchema(&ValidateResponseSchemaInput{
+		Request:  postRequest(),
+		Response: responseWithBody(payload),
+		Schema:   schema,
+		Version:  3.1,
+		Options:  []config.Option{config.WithFailFast(), config.WithLazyErrorDetails()},
+	})
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	require.Len(t, errors[0].SchemaValidationErrors, 1)
+
+	// details are only computed when they are asked for.
+	failure := errors[0].SchemaValidationErrors[0]
+	assert.Empty(t, failure.ReferenceSchema)
+	assert.Zero(t, failure.Line)
+
+	errors[0].Enrich()
+	assert.NotEmpty(t, failure.ReferenceSchema)
+	assert.Equal(t, payload, failure.ReferenceObject)
+	assert.NotZero(t, failure.Line)
+}
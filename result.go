@@ -0,0 +1,567 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"net/http"
+	"time"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	lowv3 "github.com/pb33f/libopenapi/datamodel/low/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/deprecation"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
+	"github.com/pb33f/libopenapi-validator/paths"
+)
+
+// Phase names a step of validating a request or a response.
+type Phase string
+
+const (
+	PhasePath            Phase = "path"
+	PhasePathParams      Phase = "pathParams"
+	PhaseCookieParams    Phase = "cookieParams"
+	PhaseHeaderParams    Phase = "headerParams"
+	PhaseQueryParams     Phase = "queryParams"
+	PhaseSecurity        Phase = "security"
+	PhaseRequestBody     Phase = "requestBody"
+	PhaseDeprecation     Phase = "deprecation"
+	PhaseStrict          Phase = "strict"
+	PhaseResponseHeaders Phase = "responseHeaders"
+	PhaseResponseBody    Phase = "responseBody"
+)
+
+// PhaseStatus is the outcome of a Phase.
+type PhaseStatus string
+
+const (
+	PhasePassed  PhaseStatus = "passed"
+	PhaseFailed  PhaseStatus = "failed"
+	PhaseSkipped PhaseStatus = "skipped"
+)
+
+// PhaseResult is the outcome of a single Phase of validation.
+type PhaseResult struct {
+	// Phase is the step of validation this result is for.
+	Phase Phase `json:"phase" yaml:"phase"`
+
+	// Status is passed, failed or skipped. A phase with only warnings has passed.
+	Status PhaseStatus `json:"status" yaml:"status"`
+
+	// SkipReason explains why a skipped phase did not run.
+	SkipReason string `json:"skipReason,omitempty" yaml:"skipReason,omitempty"`
+
+	// Duration is how long the phase took. Strict checks and response headers are checked in the same pass as
+	// the phases around them, so their time is part of those phases and their own Duration is zero.
+	Duration time.Duration `json:"duration" yaml:"duration"`
+
+	// Errors are the validation errors found by the phase.
+	Errors []*errors.ValidationError `json:"errors,omitempty" yaml:"errors,omitempty"`
+
+	// Warnings are the validation errors found by the phase that an 'x-validation' policy in 'warn' mode
+	// does not enforce.
+	Warnings []*errors.ValidationError `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// ValidationResult is a detailed outcome of validating a request or a response, broken down by Phase.
+type ValidationResult struct {
+	// Valid is true when no phase has errors.
+	Valid bool `json:"valid" yaml:"valid"`
+
+	// Method and RequestPath are the method and path of the request.
+	Method      string `json:"method" yaml:"method"`
+	RequestPath string `json:"requestPath" yaml:"requestPath"`
+
+	// StatusCode is the status code of the response, for response validation.
+	StatusCode int `json:"statusCode,omitempty" yaml:"statusCode,omitempty"`
+
+	// PathTemplate is the path of the specification the request matched, empty when it did not match.
+	PathTemplate string `json:"pathTemplate,omitempty" yaml:"pathTemplate,omitempty"`
+
+	// PathItem and Operation are the parts of the specification the request matched.
+	PathItem  *v3.PathItem  `json:"-" yaml:"-"`
+	Operation *v3.Operation `json:"-" yaml:"-"`
+
+	// OperationID is the operationId of the matched operation, if it has one.
+	OperationID string `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+
+	// Phases are the results of each phase, in the order they ran.
+	Phases []*PhaseResult `json:"phases" yaml:"phases"`
+
+	// Errors are the errors of all phases, in the order they were found. When validation was cancelled, it
+	// only holds the cancellation error.
+	Errors []*errors.ValidationError `json:"errors,omitempty" yaml:"errors,omitempty"`
+
+	// Warnings are the warnings of all phases, kept apart from Errors.
+	Warnings []*errors.ValidationError `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+
+	// SampledOut is true when the exchange was not sampled for validation, and every phase was skipped.
+	SampledOut bool `json:"sampledOut,omitempty" yaml:"sampledOut,omitempty"`
+
+	// Duration is how long validation took overall.
+	Duration time.Duration `json:"duration" yaml:"duration"`
+}
+
+// Phase returns the result of a phase, or nil when the phase is not part of the result.
+func (r *ValidationResult) Phase(phase Phase) *PhaseResult {
+	if r == nil {
+		return nil
+	}
+	for _, p := range r.Phases {
+		if p.Phase == phase {
+			return p
+		}
+	}
+	return nil
+}
+
+// Failed returns the phases that have errors.
+func (r *ValidationResult) Failed() []*PhaseResult {
+	if r == nil {
+		return nil
+	}
+	var failed []*PhaseResult
+	for _, p := range r.Phases {
+		if p.Status == PhaseFailed {
+			failed = append(failed, p)
+		}
+	}
+	return failed
+}
+
+const (
+	skipPathNotMatched   = "the request did not match a path and operation in the specification"
+	skipPolicyOff        = "the 'x-validation' policy of the operation turns validation off"
+	skipCancelled        = "validation was cancelled"
+	skipSecurityDisabled = "security validation is disabled"
+	skipNoRequestBody    = "the operation does not define a request body"
+	skipStrictDisabled   = "strict mode is disabled"
+	skipDeprecationOff   = "deprecation warnings are disabled"
+	skipBudgetSpent      = "the error budget was spent by earlier phases"
+	skipNotSampled       = "the exchange was not sampled for validation"
+	skipReleased         = "the validator was released"
+)
+
+var (
+	requestPhases  = []Phase{PhasePathParams, PhaseCookieParams, PhaseHeaderParams, PhaseQueryParams, PhaseSecurity, PhaseRequestBody, PhaseDeprecation, PhaseStrict}
+	responsePhases = []Phase{PhaseResponseHeaders, PhaseResponseBody, PhaseDeprecation, PhaseStrict}
+)
+
+func newResult(request message.Request) *ValidationResult {
+	return &ValidationResult{Method: request.Method(), RequestPath: request.URL().Path}
+}
+
+// matchPath runs the path phase. It returns false when the path did not match, after skipping the rest.
+func (v *validator) matchPath(result *ValidationResult, request message.Request, rest []Phase) (*v3.PathItem, string, bool) {
+	start := time.Now()
+	pathItem, errs, pathValue := paths.FindMessagePath(request, v.v3Model, v.options)
+	phase := &PhaseResult{Phase: PhasePath, Status: PhasePassed, Duration: time.Since(start)}
+	result.Phases = append(result.Phases, phase)
+	if len(errs) > 0 {
+		phase.Status = PhaseFailed
+		phase.Errors = errs
+		result.Errors = errs
+		result.skip(rest, skipPathNotMatched)
+		return nil, "", false
+	}
+	result.PathTemplate = pathValue
+	return pathItem, pathValue, true
+}
+
+// matched records a path that was matched by the caller, as a path phase that passed.
+func (r *ValidationResult) matched(request message.Request, pathItem *v3.PathItem, pathValue string) {
+	r.PathItem = pathItem
+	r.PathTemplate = pathValue
+	if pathItem != nil {
+		r.Operation = helpers.OperationForMethod(request.Method(), pathItem)
+		if r.Operation != nil {
+			r.OperationID = r.Operation.OperationId
+		}
+	}
+	if r.Phase(PhasePath) == nil {
+		r.Phases = append(r.Phases, &PhaseResult{Phase: PhasePath, Status: PhasePassed})
+	}
+}
+
+func (r *ValidationResult) skip(phases []Phase, reason string) {
+	for _, phase := range phases {
+		if existing := r.Phase(phase); existing != nil {
+			continue
+		}
+		r.Phases = append(r.Phases, &PhaseResult{Phase: phase, Status: PhaseSkipped, SkipReason: reason})
+	}
+}
+
+// cancel replaces the errors of the result with the cancellation error, and skips the phases that did not run.
+func (r *ValidationResult) cancel(cancelled []*errors.ValidationError, phases []Phase) {
+	r.Errors = cancelled
+	r.Warnings = nil
+	r.skip(phases, skipCancelled)
+}
+
+// record adds the outcome of a phase to the result. Strict errors found during the phase are moved to the
+// strict phase, and errors the policy does not enforce become warnings.
+func (r *ValidationResult) record(phase *PhaseResult, strictPhase *PhaseResult, kept, warned []*errors.ValidationError) {
+	for _, validationError := range kept {
+		if validationError.ValidationType == errors.StrictValidationType && strictPhase != nil {
+			strictPhase.Errors = append(strictPhase.Errors, validationError)
+			continue
+		}
+		phase.Errors = append(phase.Errors, validationError)
+	}
+	for _, validationError := range warned {
+		if validationError.ValidationType == errors.StrictValidationType && strictPhase != nil {
+			strictPhase.Warnings = append(strictPhase.Warnings, validationError)
+			continue
+		}
+		phase.Warnings = append(phase.Warnings, validationError)
+	}
+	r.Errors = append(r.Errors, kept...)
+	r.Warnings = append(r.Warnings, warned...)
+}
+
+func (r *ValidationResult) finish(start time.Time) *ValidationResult {
+	for _, p := range r.Phases {
+		if p.Status != PhaseSkipped {
+			p.Status = PhasePassed
+			if len(p.Errors) > 0 {
+				p.Status = PhaseFailed
+			}
+		}
+	}
+	r.Valid = len(r.Errors) == 0
+	r.Duration = time.Since(start)
+	return r
+}
+
+// sampleOut skips every phase of an exchange that was not sampled, those after the path when it was matched.
+func (r *ValidationResult) sampleOut(phases []Phase) {
+	r.SampledOut = true
+	if r.Phase(PhasePath) == nil {
+		r.skip([]Phase{PhasePath}, skipNotSampled)
+	}
+	r.skip(phases, skipNotSampled)
+}
+
+func (v *validator) ValidateHttpRequestResult(request *http.Request) *ValidationResult {
+	return v.requestMessageResult(message.FromHTTPRequest(request))
+}
+
+// requestMessageResult samples a request, finds its path and runs the request phases.
+func (v *validator) requestMessageResult(request message.Request) *ValidationResult {
+	start := time.Now()
+	result := newResult(request)
+	pathItem, pathValue, ok := v.sampleResult(result, request, requestPhases)
+	if !ok {
+		return result.finish(start)
+	}
+	return v.requestResult(result, request, pathItem, pathValue, false).finish(start)
+}
+
+// sampleResult samples a request and finds its path, recording the outcome in result. ok is false when the
+// request is sampled out, or its path is not found.
+func (v *validator) sampleResult(
+	result *ValidationResult,
+	request message.Request,
+	phases []Phase,
+) (pathItem *v3.PathItem, pathValue string, ok bool) {
+	decision := v.sampler.sample(request, nil, "")
+	if decision == sampledOut {
+		result.sampleOut(phases)
+		return nil, "", false
+	}
+	if pathItem, pathValue, ok = v.matchPath(result, request, phases); !ok {
+		return nil, "", false
+	}
+	if decision == sampleUndecided && v.sampler.sample(request, pathItem, pathValue) == sampledOut {
+		result.matched(request, pathItem, pathValue)
+		result.sampleOut(phases)
+		return nil, "", false
+	}
+	return pathItem, pathValue, true
+}
+
+// requestResult runs the request phases in order, so each can be timed. When parallel is set, a large enough
+// request body is validated on the shared worker pool while the parameters are validated, and recorded in its
+// place among the phases once they are done.
+func (v *validator) requestResult(
+	result *ValidationResult,
+	request message.Request,
+	pathItem *v3.PathItem,
+	pathValue string,
+	parallel bool,
+) *ValidationResult {
+	result.matched(request, pathItem, pathValue)
+
+	ov := v.validatorsFor(request, pathItem)
+	if ov.policy.Policy.SkipsRequest() {
+		result.skip(requestPhases, skipPolicyOff)
+		return result
+	}
+	cancelled := errors.MessageContextDone(request)
+	if cancelled != nil {
+		result.cancel(cancelled, requestPhases)
+		return result
+	}
+
+	options := ov.policy.Options
+	perPhase, overall := errorLimits(options)
+	strictPhase := &PhaseResult{Phase: PhaseStrict}
+	if options == nil || !options.StrictMode {
+		strictPhase.Status = PhaseSkipped
+		strictPhase.SkipReason = skipStrictDisabled
+	}
+
+	phases := []struct {
+		phase    Phase
+		validate validationFunction
+		skip     string
+	}{
+		{PhasePathParams, ov.paramValidator.ValidateMessagePathParamsWithPathItem, ""},
+		{PhaseCookieParams, ov.paramValidator.ValidateMessageCookieParamsWithPathItem, ""},
+		{PhaseHeaderParams, ov.paramValidator.ValidateMessageHeaderParamsWithPathItem, ""},
+		{PhaseQueryParams, ov.paramValidator.ValidateMessageQueryParamsWithPathItem, ""},
+		{PhaseSecurity, ov.paramValidator.ValidateMessageSecurityWithPathItem, ""},
+		{PhaseRequestBody, ov.requestValidator.ValidateMessageBodyWithPathItem, ""},
+	}
+	if options != nil && !options.SecurityValidation {
+		phases[4].skip = skipSecurityDisabled
+	}
+	if result.Operation != nil && result.Operation.RequestBody == nil {
+		phases[5].skip = skipNoRequestBody
+	}
+
+	// sanitizing modifies the request, so it can't be shared between goroutines, and an error budget can only
+	// skip the work of later phases when they run one after another. a body validated on the request itself is
+	// always waited for, it must be done with the request before the request is handed back. the body of a
+	// request with a context is validated on a copy, so it can be left behind once the context is done.
+	ctx := message.Context(request)
+	var body *pooledPhase
+	if parallel && phases[5].skip == "" && (options == nil || !options.StrictSanitize) &&
+		!options.HasErrorBudget() && parallelBody(request, options) {
+		if ctx == nil {
+			body = submitPhase(options, PhaseRequestBody, phases[5].validate, request, pathItem, pathValue)
+		} else if body, cancelled = detachPhase(ctx, options, PhaseRequestBody, phases[5].validate, request,
+			pathItem, pathValue); cancelled != nil {
+			result.cancel(cancelled, requestPhases)
+			return result
+		}
+	}
+	defer func() { body.settle() }()
+
+	for _, p := range phases {
+		if options.BudgetSpent(len(result.Errors)) {
+			p.skip = skipBudgetSpent
+		}
+		if p.skip != "" {
+			result.Phases = append(result.Phases, &PhaseResult{Phase: p.phase, Status: PhaseSkipped, SkipReason: p.skip})
+			continue
+		}
+		var errs []*errors.ValidationError
+		var duration time.Duration
+		if p.phase == PhaseRequestBody && body == nil && ctx != nil {
+			if body, errs = detachPhase(ctx, options, p.phase, p.validate, request, pathItem, pathValue); errs != nil {
+				result.cancel(errs, requestPhases)
+				return result
+			}
+		}
+		if p.phase == PhaseRequestBody && body != nil {
+			if !body.waitContext(ctx) {
+				result.cancel(errors.MessageContextDone(request), requestPhases)
+				return result
+			}
+			errs, duration = body.errs, body.duration
+			if body.detached != nil && options != nil && options.StrictSanitize {
+				if setter, ok := request.(message.BodySetter); ok {
+					setter.SetBody(message.ReadRequestBody(body.detached))
+				}
+			}
+		} else {
+			errs, duration = runPhase(options, p.phase, p.validate, request, pathItem, pathValue)
+		}
+		phase := &PhaseResult{Phase: p.phase, Duration: duration}
+		result.Phases = append(result.Phases, phase)
+		if errors.IsCancelled(errs) {
+			phase.Errors = errs
+			result.cancel(errs, requestPhases)
+			return result
+		}
+		kept, warned := v.splitByPolicy(ov.policy, errs)
+		result.record(phase, strictPhase, limitErrors(kept, perPhase), warned)
+	}
+	v.checkDeprecations(result, options, func() []*errors.ValidationError {
+		return deprecation.Request(request, pathItem, pathValue, options, helpers.VersionToFloat(v.v3Model.Version))
+	})
+	result.Phases = append(result.Phases, strictPhase)
+
+	if cancelled := errors.MessageContextDone(request); cancelled != nil {
+		result.cancel(cancelled, requestPhases)
+		return result
+	}
+	result.Errors = limitErrors(result.Errors, overall)
+	if len(result.Errors) == 0 {
+		v.injectDefaults(request, pathItem, options)
+	}
+	recordRequest(options, request, pathItem, pathValue)
+	return result
+}
+
+// runPhase runs one phase of a request, observed and timed.
+func runPhase(
+	options *config.ValidationOptions,
+	phase Phase,
+	validate validationFunction,
+	request message.Request,
+	pathItem *v3.PathItem,
+	pathValue string,
+) ([]*errors.ValidationError, time.Duration) {
+	start := time.Now()
+	observed := observePhase(options, phase, request, pathItem, pathValue)
+	_, errs := validate(request, pathItem, pathValue)
+	observed.finish(errs)
+	return errs, time.Since(start)
+}
+
+func (v *validator) ValidateHttpResponseResult(request *http.Request, response *http.Response) *ValidationResult {
+	return v.responseMessageResult(message.FromHTTPRequest(request), message.FromHTTPResponse(response))
+}
+
+// responseMessageResult samples the exchange of a response, finds its path and runs the response phases.
+func (v *validator) responseMessageResult(request message.Request, response message.Response) *ValidationResult {
+	start := time.Now()
+	result := newResult(request)
+	result.StatusCode = response.StatusCode()
+	pathItem, pathValue, ok := v.sampleResult(result, request, responsePhases)
+	if !ok {
+		return result.finish(start)
+	}
+	return v.responseResult(result, request, response, pathItem, pathValue).finish(start)
+}
+
+// responseResult runs the response phases. Response headers and body are checked in a single pass, which is
+// timed as the response body phase.
+func (v *validator) responseResult(
+	result *ValidationResult,
+	request message.Request,
+	response message.Response,
+	pathItem *v3.PathItem,
+	pathValue string,
+) *ValidationResult {
+	result.matched(request, pathItem, pathValue)
+	result.StatusCode = response.StatusCode()
+
+	ov := v.validatorsFor(request, pathItem)
+	if ov.policy.Policy.SkipsResponse() {
+		result.skip(responsePhases, skipPolicyOff)
+		return result
+	}
+
+	strictPhase := &PhaseResult{Phase: PhaseStrict}
+	if ov.policy.Options == nil || !ov.policy.Options.StrictMode {
+		strictPhase.Status = PhaseSkipped
+		strictPhase.SkipReason = skipStrictDisabled
+	}
+
+	start := time.Now()
+	observed := observePhase(ov.policy.Options, PhaseResponseBody, request, pathItem, pathValue)
+	_, errs := ov.responseValidator.ValidateMessageResponseBodyWithPathItem(request, response, pathItem, pathValue)
+	observed.finish(errs)
+	headersPhase := &PhaseResult{Phase: PhaseResponseHeaders}
+	bodyPhase := &PhaseResult{Phase: PhaseResponseBody, Duration: time.Since(start)}
+	result.Phases = append(result.Phases, headersPhase, bodyPhase)
+
+	if cancelled := errors.MessageContextDone(request); cancelled != nil {
+		result.cancel(cancelled, responsePhases)
+		return result
+	}
+
+	kept, warned := v.splitByPolicy(ov.policy, errs)
+	options := ov.policy.Options
+	perPhase, overall := errorLimits(options)
+	var headerKept, headerWarned, bodyKept, bodyWarned, ordered []*errors.ValidationError
+	for _, validationError := range kept {
+		if isResponseHeaderError(validationError) {
+			if perPhase > 0 && len(headerKept) >= perPhase {
+				continue
+			}
+			headerKept = append(headerKept, validationError)
+		} else {
+			if perPhase > 0 && len(bodyKept) >= perPhase {
+				continue
+			}
+			bodyKept = append(bodyKept, validationError)
+		}
+		ordered = append(ordered, validationError)
+	}
+	for _, validationError := range warned {
+		if isResponseHeaderError(validationError) {
+			headerWarned = append(headerWarned, validationError)
+		} else {
+			bodyWarned = append(bodyWarned, validationError)
+		}
+	}
+	result.record(bodyPhase, strictPhase, bodyKept, bodyWarned)
+	result.record(headersPhase, strictPhase, headerKept, headerWarned)
+	// keep the errors of the result in the order they were found.
+	result.Errors = ordered
+	result.Warnings = warned
+
+	v.checkDeprecations(result, options, func() []*errors.ValidationError {
+		return deprecation.Response(request, response, pathItem, options, helpers.VersionToFloat(v.v3Model.Version))
+	})
+	result.Phases = append(result.Phases, strictPhase)
+	result.Errors = limitErrors(result.Errors, overall)
+	recordResponse(options, request, response, pathItem, pathValue)
+	return result
+}
+
+// checkDeprecations runs the deprecation phase. Deprecations reported as errors fail validation, the rest
+// are warnings.
+func (v *validator) checkDeprecations(result *ValidationResult, options *config.ValidationOptions, check func() []*errors.ValidationError) {
+	if options == nil || !options.DeprecationWarnings {
+		result.Phases = append(result.Phases, &PhaseResult{Phase: PhaseDeprecation, Status: PhaseSkipped, SkipReason: skipDeprecationOff})
+		return
+	}
+	if options.BudgetSpent(len(result.Errors)) {
+		result.Phases = append(result.Phases, &PhaseResult{Phase: PhaseDeprecation, Status: PhaseSkipped, SkipReason: skipBudgetSpent})
+		return
+	}
+	start := time.Now()
+	observed := observe(options, config.PhaseEvent{
+		Phase:        string(PhaseDeprecation),
+		Method:       result.Method,
+		PathTemplate: result.PathTemplate,
+		OperationID:  result.OperationID,
+	})
+	found := check()
+	phase := &PhaseResult{Phase: PhaseDeprecation, Duration: time.Since(start)}
+	result.Phases = append(result.Phases, phase)
+	kept, warned := v.splitDeprecations(found)
+	observed.finish(kept)
+	result.record(phase, nil, kept, warned)
+}
+
+// limitErrors trims errors to a limit of an error budget, zero means no limit.
+func limitErrors(validationErrors []*errors.ValidationError, limit int) []*errors.ValidationError {
+	if limit > 0 && len(validationErrors) > limit {
+		return validationErrors[:limit]
+	}
+	return validationErrors
+}
+
+// errorLimits returns the limits of the error budget of the options, per phase and overall.
+func errorLimits(options *config.ValidationOptions) (perPhase, overall int) {
+	if options == nil {
+		return 0, 0
+	}
+	return options.MaxPhaseErrors, options.MaxErrors
+}
+
+func isResponseHeaderError(validationError *errors.ValidationError) bool {
+	return validationError.ValidationType == helpers.ResponseBodyValidation &&
+		(validationError.ValidationSubType == helpers.ParameterValidationHeader || validationError.ValidationSubType == lowv3.HeadersLabel)
+}
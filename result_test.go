@@ -0,0 +1,385 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+const resultSpec = `openapi: 3.1.0
+info:
+  title: Results
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    post:
+      operationId: updatePet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+      responses:
+        '200':
+          description: OK
+          headers:
+            X-Rate:
+              required: true
+              schema:
+                type: integer
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: integer
+    get:
+      operationId: getPet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        '200':
+          description: OK
+  /warned:
+    post:
+      x-validation:
+        mode: warn
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+`
+
+func newResultValidator(t *testing.T, opts ...config.Option) Validator {
+	t.Helper()
+	doc, err := libopenapi.NewDocument([]byte(resultSpec))
+	require.NoError(t, err)
+	v, errs := NewValidator(doc, opts...)
+	require.Empty(t, errs)
+	return v
+}
+
+func TestValidateHttpRequestResult(t *testing.T) {
+	v := newResultValidator(t)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/pets/12?limit=5", strings.NewReader(`{"name":"fido"}`))
+	request.Header.Set("Content-Type", "application/json")
+
+	result := v.(ResultValidator).ValidateHttpRequestResult(request)
+	assert.True(t, result.Valid)
+	assert.Empty(t, result.Errors)
+	assert.Empty(t, result.Failed())
+	assert.Equal(t, "/pets/{id}", result.PathTemplate)
+	assert.Equal(t, "updatePet", result.OperationID)
+	assert.NotNil(t, result.Operation)
+	assert.NotNil(t, result.PathItem)
+
+	var phases []Phase
+	for _, p := range result.Phases {
+		phases = append(phases, p.Phase)
+	}
+	assert.Equal(t, []Phase{
+		PhasePath, PhasePathParams, PhaseCookieParams, PhaseHeaderParams,
+		PhaseQueryParams, PhaseSecurity, PhaseRequestBody, PhaseDeprecation, PhaseStrict,
+	}, phases)
+	assert.Equal(t, PhasePassed, result.Phase(PhaseRequestBody).Status)
+	assert.Equal(t, PhaseSkipped, result.Phase(PhaseStrict).Status)
+	assert.Equal(t, skipStrictDisabled, result.Phase(PhaseStrict).SkipReason)
+	assert.Nil(t, result.Phase(PhaseResponseBody))
+
+	// failures are reported by the phase that found them.
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/pets/12?limit=many", strings.NewReader(`{"name":1}`))
+	request.Header.Set("Content-Type", "application/json")
+
+	result = v.(ResultValidator).ValidateHttpRequestResult(request)
+	assert.False(t, result.Valid)
+	assert.Len(t, result.Errors, 2)
+	assert.Equal(t, PhaseFailed, result.Phase(PhaseQueryParams).Status)
+	assert.Len(t, result.Phase(PhaseQueryParams).Errors, 1)
+	assert.Equal(t, PhaseFailed, result.Phase(PhaseRequestBody).Status)
+	assert.Equal(t, PhasePassed, result.Phase(PhasePathParams).Status)
+	assert.Len(t, result.Failed(), 2)
+
+	// the result agrees with the sync validator.
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/pets/12?limit=many", strings.NewReader(`{"name":1}`))
+	request.Header.Set("Content-Type", "application/json")
+	valid, validationErrors := v.ValidateHttpRequestSync(request)
+	assert.False(t, valid)
+	assert.Len(t, validationErrors, 2)
+}
+
+func TestValidateHttpRequestResult_Skipped(t *testing.T) {
+	v := newResultValidator(t, config.WithoutSecurityValidation(), config.WithStrictMode())
+
+	// an unmatched path skips every other phase.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/nope", nil)
+	result := v.(ResultValidator).ValidateHttpRequestResult(request)
+	assert.False(t, result.Valid)
+	assert.Equal(t, PhaseFailed, result.Phase(PhasePath).Status)
+	assert.Len(t, result.Errors, 1)
+	assert.Empty(t, result.OperationID)
+	for _, p := range result.Phases[1:] {
+		assert.Equal(t, PhaseSkipped, p.Status)
+		assert.Equal(t, skipPathNotMatched, p.SkipReason)
+	}
+
+	// operations without a body, and disabled security, are skipped with a reason.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/pets/1", nil)
+	result = v.(ResultValidator).ValidateHttpRequestResult(request)
+	assert.True(t, result.Valid)
+	assert.Equal(t, "getPet", result.OperationID)
+	assert.Equal(t, skipNoRequestBody, result.Phase(PhaseRequestBody).SkipReason)
+	assert.Equal(t, skipSecurityDisabled, result.Phase(PhaseSecurity).SkipReason)
+	assert.Equal(t, PhasePassed, result.Phase(PhaseStrict).Status)
+
+	// strict errors are reported by the strict phase.
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/pets/1", strings.NewReader(`{"name":"a","extra":1}`))
+	request.Header.Set("Content-Type", "application/json")
+	result = v.(ResultValidator).ValidateHttpRequestResult(request)
+	assert.False(t, result.Valid)
+	assert.Equal(t, PhaseFailed, result.Phase(PhaseStrict).Status)
+	assert.Equal(t, PhasePassed, result.Phase(PhaseRequestBody).Status)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, errors.StrictValidationType, result.Errors[0].ValidationType)
+
+	// a cancelled validation skips the phases that did not run.
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/pets/1", nil)
+	result = v.(*validator).requestMessageResult(message.WithContext(cancelled, message.FromHTTPRequest(request)))
+	assert.False(t, result.Valid)
+	assert.True(t, errors.IsCancelled(result.Errors))
+	assert.Equal(t, skipCancelled, result.Phase(PhaseQueryParams).SkipReason)
+}
+
+func TestValidateHttpRequestResult_Warnings(t *testing.T) {
+	v := newResultValidator(t)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/warned", strings.NewReader(`{"name":1}`))
+	request.Header.Set("Content-Type", "application/json")
+
+	result := v.(ResultValidator).ValidateHttpRequestResult(request)
+	assert.True(t, result.Valid)
+	assert.Empty(t, result.Errors)
+	assert.Len(t, result.Warnings, 1)
+	assert.Equal(t, PhasePassed, result.Phase(PhaseRequestBody).Status)
+	assert.Len(t, result.Phase(PhaseRequestBody).Warnings, 1)
+	assert.Equal(t, errors.SeverityWarning, result.Warnings[0].Severity)
+
+	encoded, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"phase":"requestBody"`)
+	assert.Contains(t, string(encoded), `"warnings"`)
+}
+
+func TestValidateHttpResponseResult(t *testing.T) {
+	v := newResultValidator(t)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/pets/1", nil)
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}, "X-Rate": {"10"}},
+		Body:       io.NopCloser(strings.NewReader(`{"id":1}`)),
+	}
+
+	result := v.(ResultValidator).ValidateHttpResponseResult(request, response)
+	assert.True(t, result.Valid)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.Equal(t, PhasePassed, result.Phase(PhaseResponseHeaders).Status)
+	assert.Equal(t, PhasePassed, result.Phase(PhaseResponseBody).Status)
+	assert.Nil(t, result.Phase(PhaseRequestBody))
+
+	// header errors and body errors are reported apart.
+	response = &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"id":"one"}`)),
+	}
+	result = v.(ResultValidator).ValidateHttpResponseResult(request, response)
+	assert.False(t, result.Valid)
+	assert.Equal(t, PhaseFailed, result.Phase(PhaseResponseHeaders).Status)
+	assert.Equal(t, PhaseFailed, result.Phase(PhaseResponseBody).Status)
+	assert.Len(t, result.Errors, 2)
+
+	// an unmatched path skips the response phases.
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/nope", nil)
+	result = v.(ResultValidator).ValidateHttpResponseResult(request, response)
+	assert.False(t, result.Valid)
+	assert.Equal(t, PhaseSkipped, result.Phase(PhaseResponseBody).Status)
+
+	var nilResult *ValidationResult
+	assert.Nil(t, nilResult.Phase(PhasePath))
+	assert.Nil(t, nilResult.Failed())
+}
+
+func TestValidateHttpRequestResult_Deprecations(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: Deprecations
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      deprecated: true
+      parameters:
+        - name: legacy
+          in: query
+          deprecated: true
+          schema:
+            type: string
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                nickname:
+                  type: string
+                  deprecated: true
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  old:
+                    type: string
+                    deprecated: true
+`
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+
+	var logs bytes.Buffer
+	v, errs := NewValidator(doc, config.WithDeprecationWarnings(), config.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	require.Empty(t, errs)
+
+	newRequest := func() *http.Request {
+		request, _ := http.NewRequest(http.MethodPost, "https://things.com/pets?legacy=1", strings.NewReader(`{"nickname":"fido"}`))
+		request.Header.Set("Content-Type", "application/json")
+		return request
+	}
+
+	// deprecations are warnings, they don't fail validation.
+	result := v.(ResultValidator).ValidateHttpRequestResult(newRequest())
+	assert.True(t, result.Valid)
+	assert.Empty(t, result.Errors)
+	assert.Len(t, result.Warnings, 3)
+	phase := result.Phase(PhaseDeprecation)
+	assert.Equal(t, PhasePassed, phase.Status)
+	assert.Len(t, phase.Warnings, 3)
+	for _, warning := range phase.Warnings {
+		assert.Equal(t, errors.SeverityWarning, warning.Severity)
+	}
+	assert.Contains(t, logs.String(), "deprecated API used")
+
+	valid, validationErrors := v.ValidateHttpRequest(newRequest())
+	assert.True(t, valid)
+	assert.Empty(t, validationErrors)
+
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"old":"x"}`)),
+	}
+	result = v.(ResultValidator).ValidateHttpResponseResult(newRequest(), response)
+	assert.True(t, result.Valid)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, "$.body.old", result.Warnings[0].SchemaValidationErrors[0].FieldPath)
+
+	// deprecations fail validation once they are reported as errors.
+	v, errs = NewValidator(doc, config.WithDeprecationsAsErrors())
+	require.Empty(t, errs)
+	valid, validationErrors = v.ValidateHttpRequest(newRequest())
+	assert.False(t, valid)
+	assert.Len(t, validationErrors, 3)
+	valid, validationErrors = v.ValidateHttpRequestSync(newRequest())
+	assert.False(t, valid)
+	assert.Len(t, validationErrors, 3)
+	result = v.(ResultValidator).ValidateHttpRequestResult(newRequest())
+	assert.Equal(t, PhaseFailed, result.Phase(PhaseDeprecation).Status)
+
+	// without the option, deprecations are not checked.
+	v, errs = NewValidator(doc)
+	require.Empty(t, errs)
+	result = v.(ResultValidator).ValidateHttpRequestResult(newRequest())
+	assert.Equal(t, skipDeprecationOff, result.Phase(PhaseDeprecation).SkipReason)
+}
+
+func TestValidateHttpRequestResult_ErrorBudget(t *testing.T) {
+	newRequest := func() *http.Request {
+		request, _ := http.NewRequest(http.MethodPost, "https://things.com/pets/12?limit=many", strings.NewReader(`{"name":1}`))
+		request.Header.Set("Content-Type", "application/json")
+		return request
+	}
+
+	// fail fast stops at the first error, and skips the phases after it.
+	v := newResultValidator(t, config.WithFailFast())
+	result := v.(ResultValidator).ValidateHttpRequestResult(newRequest())
+	assert.False(t, result.Valid)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, PhaseFailed, result.Phase(PhaseQueryParams).Status)
+	assert.Equal(t, PhaseSkipped, result.Phase(PhaseRequestBody).Status)
+	assert.Equal(t, skipBudgetSpent, result.Phase(PhaseRequestBody).SkipReason)
+
+	valid, validationErrors := v.ValidateHttpRequest(newRequest())
+	assert.False(t, valid)
+	assert.Len(t, validationErrors, 1)
+
+	// an overall budget lets later phases run until it is spent.
+	v = newResultValidator(t, config.WithErrorBudget(0, 2))
+	result = v.(ResultValidator).ValidateHttpRequestResult(newRequest())
+	assert.Len(t, result.Errors, 2)
+	assert.Equal(t, PhaseFailed, result.Phase(PhaseRequestBody).Status)
+
+	// responses are limited the same way.
+	v = newResultValidator(t, config.WithFailFast())
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/pets/1", nil)
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"id":"one"}`)),
+	}
+	result = v.(ResultValidator).ValidateHttpResponseResult(request, response)
+	assert.False(t, result.Valid)
+	assert.Len(t, result.Errors, 1)
+}
@@ -0,0 +1,95 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
+	"github.com/pb33f/libopenapi-validator/paths"
+	"github.com/pb33f/libopenapi-validator/policy"
+)
+
+// sampleDecision is whether an exchange is validated.
+type sampleDecision int
+
+const (
+	sampledIn sampleDecision = iota
+	sampledOut
+	// sampleUndecided is the decision before the path lookup, when the rate depends on the operation.
+	sampleUndecided
+)
+
+// sampler decides which exchanges are validated, from the sampling options and the 'sampleRate' of
+// 'x-validation' policies. It is nil when every exchange is validated.
+type sampler struct {
+	sampling    *config.Sampling
+	policies    *policy.Resolver
+	rate        float64 // the rate of operations without their own
+	byOperation bool    // operations set their own rates, so the path has to be looked up first
+}
+
+func newSampler(options *config.ValidationOptions, policies *policy.Resolver) *sampler {
+	documentRate, documentSets := policies.SampleRate()
+	if options.Sampling == nil && !documentSets && !policies.SamplesOperations() {
+		return nil
+	}
+	s := &sampler{sampling: options.Sampling, policies: policies, rate: 1}
+	if options.Sampling != nil {
+		s.rate = options.Sampling.Rate
+		s.byOperation = len(options.Sampling.Operations) > 0
+	}
+	if documentSets {
+		s.rate = documentRate
+	}
+	s.byOperation = s.byOperation || policies.SamplesOperations()
+	return s
+}
+
+// sample decides whether the exchange of a request is validated. Before the path lookup pathItem is nil, and
+// the decision is undecided when it depends on the operation. Rates set in code for an operation win over the
+// policy of the operation, which wins over the global rate.
+func (s *sampler) sample(request message.Request, pathItem *v3.PathItem, pathValue string) sampleDecision {
+	if s == nil {
+		return sampledIn
+	}
+	rate := s.rate
+	if s.byOperation {
+		if pathItem == nil {
+			return sampleUndecided
+		}
+		if operation := helpers.OperationForMethod(request.Method(), pathItem); operation != nil {
+			if operationRate, ok := s.sampling.OperationRate(request.Method(), pathValue, operation.OperationId); ok {
+				rate = operationRate
+			} else if resolved := s.policies.Resolve(pathItem, operation); resolved.Policy != nil && resolved.Policy.SampleRate != nil {
+				rate = *resolved.Policy.SampleRate
+			}
+		}
+	}
+	if s.sampling.SampleHeader(request.Header(), rate) {
+		return sampledIn
+	}
+	return sampledOut
+}
+
+// lookup samples the exchange of a request and finds its path. sampled is false when the exchange was sampled
+// out, which skips the path lookup when the rate doesn't depend on the operation. The path lookup errors are
+// returned when it didn't match.
+func (v *validator) lookup(request message.Request) (pathItem *v3.PathItem, pathValue string, errs []*errors.ValidationError, sampled bool) {
+	decision := v.sampler.sample(request, nil, "")
+	if decision == sampledOut {
+		return nil, "", nil, false
+	}
+	pathItem, errs, pathValue = paths.FindMessagePath(request, v.v3Model, v.options)
+	if len(errs) > 0 {
+		return pathItem, pathValue, errs, true
+	}
+	if decision == sampleUndecided && v.sampler.sample(request, pathItem, pathValue) == sampledOut {
+		return nil, "", nil, false
+	}
+	return pathItem, pathValue, nil, true
+}
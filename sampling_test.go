@@ -0,0 +1,182 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+const samplingSpec = `openapi: 3.1.0
+info:
+  title: Sampling
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      x-validation:
+        sampleRate: 0
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+    post:
+      operationId: createPet
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        '201':
+          description: created
+          content:
+            application/json:
+              schema:
+                type: object`
+
+func samplingValidator(t *testing.T, spec string, opts ...config.Option) (Validator, *recordingObserver) {
+	t.Helper()
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	observer := &recordingObserver{}
+	v, errs := NewValidator(doc, append(opts, config.WithObserver(observer))...)
+	require.Empty(t, errs)
+	observer.reset()
+	return v, observer
+}
+
+// invalidExchange returns a request with an invalid query parameter, and a response that is not an array or
+// an object.
+func invalidExchange(method, id string) (*http.Request, *http.Response) {
+	request, _ := http.NewRequest(method, "https://api.com/pets?limit=ten", nil)
+	if id != "" {
+		request.Header.Set(config.DefaultSamplingRequestIDHeader, id)
+	}
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewBufferString(`"nope"`)),
+	}
+	if method == http.MethodPost {
+		response.StatusCode = http.StatusCreated
+	}
+	return request, response
+}
+
+func TestSampling_GlobalRate(t *testing.T) {
+	// without the policy of listPets, every operation is sampled at the same rate.
+	spec := strings.Replace(samplingSpec, "      x-validation:\n        sampleRate: 0\n", "", 1)
+	v, observer := samplingValidator(t, spec, config.WithSampling(0), config.WithSamplingDebugHeader("X-Debug"))
+
+	request, response := invalidExchange(http.MethodPost, "one")
+	valid, errs := v.ValidateHttpRequest(request)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+	valid, errs = v.ValidateHttpRequestSync(request)
+	assert.True(t, valid)
+	assert.NotNil(t, errs)
+	assert.Empty(t, errs)
+	valid, _ = v.ValidateHttpResponse(request, response)
+	assert.True(t, valid)
+	valid, _ = v.ValidateHttpRequestResponse(request, response)
+	assert.True(t, valid)
+
+	// the rate doesn't depend on the operation, so sampled out calls don't even look the path up.
+	assert.Empty(t, observer.matched)
+	assert.Empty(t, observer.started)
+
+	result := v.(ResultValidator).ValidateHttpRequestResult(request)
+	assert.True(t, result.Valid)
+	assert.True(t, result.SampledOut)
+	require.NotNil(t, result.Phase(PhasePath))
+	assert.Equal(t, PhaseSkipped, result.Phase(PhasePath).Status)
+	assert.Equal(t, skipNotSampled, result.Phase(PhaseQueryParams).SkipReason)
+
+	// requests carrying the debug header are always validated.
+	request, response = invalidExchange(http.MethodPost, "one")
+	request.Header.Set("X-Debug", "true")
+	valid, errs = v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	valid, _ = v.ValidateHttpResponse(request, response)
+	assert.False(t, valid)
+	assert.False(t, v.(ResultValidator).ValidateHttpRequestResult(request).SampledOut)
+}
+
+func TestSampling_OperationRates(t *testing.T) {
+	// listPets is sampled out by its policy, createPet by the rate set in code.
+	v, observer := samplingValidator(t, samplingSpec, config.WithOperationSampling("POST /pets", 0))
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		request, response := invalidExchange(method, "")
+		valid, _ := v.ValidateHttpRequest(request)
+		assert.True(t, valid, method)
+		valid, _ = v.ValidateHttpResponse(request, response)
+		assert.True(t, valid, method)
+
+		result := v.(ResultValidator).ValidateHttpResponseResult(request, response)
+		assert.True(t, result.SampledOut, method)
+		assert.Equal(t, PhasePassed, result.Phase(PhasePath).Status)
+		assert.Equal(t, skipNotSampled, result.Phase(PhaseResponseBody).SkipReason)
+	}
+	// only the path was looked up.
+	assert.NotEmpty(t, observer.matched)
+	assert.Empty(t, observer.started)
+
+	// rates set in code win over policies.
+	v, _ = samplingValidator(t, samplingSpec, config.WithOperationSampling("listPets", 1))
+	request, _ := invalidExchange(http.MethodGet, "")
+	valid, _ := v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	pathItem := v.(ResultValidator).ValidateHttpRequestResult(request).PathItem
+	require.NotNil(t, pathItem)
+	valid, _ = v.ValidateHttpRequestWithPathItem(request, pathItem, "/pets")
+	assert.False(t, valid)
+}
+
+func TestSampling_RequestID(t *testing.T) {
+	v, _ := samplingValidator(t, samplingSpec, config.WithSampling(0.5))
+
+	// the request and the response of an exchange are always sampled together.
+	sampled := 0
+	for i := range 200 {
+		request, response := invalidExchange(http.MethodPost, fmt.Sprintf("exchange-%d", i))
+		requestValid, _ := v.ValidateHttpRequest(request)
+		responseValid, _ := v.ValidateHttpResponse(request, response)
+		assert.Equal(t, requestValid, responseValid)
+		if !requestValid {
+			sampled++
+		}
+	}
+	assert.InDelta(t, 100, sampled, 40)
+}
+
+func BenchmarkValidateHttpRequest_SampledOut(b *testing.B) {
+	doc, _ := libopenapi.NewDocument([]byte(samplingSpec))
+	v, _ := NewValidator(doc, config.WithSampling(0))
+	request, _ := invalidExchange(http.MethodPost, "one")
+	b.ReportAllocs()
+	for b.Loop() {
+		v.ValidateHttpRequest(request)
+	}
+}
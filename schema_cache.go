@@ -0,0 +1,74 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/index"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"go.yaml.in/yaml/v4"
+
+	"github.com/pb33f/libopenapi-validator/cache"
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/schema_validation"
+)
+
+// bindSchemaCache binds a persistent schema cache to the document, so schemas persisted for it by an earlier
+// start are only compiled when the caches are warmed. A cache that can't be bound is used in memory only.
+func bindSchemaCache(m *v3.Document, options *config.ValidationOptions) {
+	persistent, ok := options.SchemaCache.(cache.PersistentSchemaCache)
+	if !ok {
+		return
+	}
+	documentHash := documentHash(m)
+	if documentHash == "" {
+		return
+	}
+	err := persistent.Bind(documentHash, func(entry *cache.SchemaCacheEntry) (*jsonschema.Schema, error) {
+		return schema_validation.CompileSchemaSource(entry, options)
+	})
+	if err != nil && options.Logger != nil {
+		options.Logger.Warn("unable to bind persistent schema cache", "error", err)
+	}
+}
+
+// documentHash returns a hash of the content of every file in a document, or an empty string when the
+// document has no index to read them from.
+func documentHash(m *v3.Document) string {
+	if m == nil || m.Index == nil {
+		return ""
+	}
+	indexes := []*index.SpecIndex{m.Index}
+	if m.Rolodex != nil {
+		for _, idx := range m.Rolodex.GetIndexes() {
+			if idx != nil {
+				indexes = append(indexes, idx)
+			}
+		}
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return indexes[i].GetSpecAbsolutePath() < indexes[j].GetSpecAbsolutePath()
+	})
+
+	hash := sha256.New()
+	seen := make(map[*index.SpecIndex]struct{}, len(indexes))
+	for _, idx := range indexes {
+		if _, done := seen[idx]; done {
+			continue
+		}
+		seen[idx] = struct{}{}
+		hash.Write([]byte(idx.GetSpecAbsolutePath()))
+		if indexConfig := idx.GetConfig(); indexConfig != nil && indexConfig.SpecInfo != nil && indexConfig.SpecInfo.SpecBytes != nil {
+			hash.Write(*indexConfig.SpecInfo.SpecBytes)
+		} else if rootNode := idx.GetRootNode(); rootNode != nil {
+			rendered, _ := yaml.Marshal(rootNode)
+			hash.Write(rendered)
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,105 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/cache"
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+const diskCacheSpec = `openapi: 3.1.0
+info:
+  title: Disk
+  version: 1.0.0
+paths:
+  /errors:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Error'
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: integer
+components:
+  schemas:
+    Error:
+      type: object
+      properties:
+        code:
+          type: string
+        details:
+          type: array
+          items:
+            $ref: '#/components/schemas/Error'
+`
+
+func newDiskCacheValidator(t *testing.T, spec, dir string) (Validator, *cache.DiskCache) {
+	t.Helper()
+	diskCache, err := cache.NewDiskCache(dir, nil)
+	require.NoError(t, err)
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	v, errs := NewValidator(doc, config.WithSchemaCache(diskCache))
+	require.Empty(t, errs)
+	return v, diskCache
+}
+
+func TestDiskCache_ColdStart(t *testing.T) {
+	dir := t.TempDir()
+
+	_, first := newDiskCacheValidator(t, diskCacheSpec, dir)
+	persisted, err := os.ReadDir(filepath.Join(dir, "entries"))
+	require.NoError(t, err)
+	assert.Len(t, persisted, 2)
+
+	// a later start loads the persisted schemas, they have no document behind them.
+	v, second := newDiskCacheValidator(t, diskCacheSpec, dir)
+	count := 0
+	second.Range(func(_ uint64, entry *cache.SchemaCacheEntry) bool {
+		count++
+		assert.Nil(t, entry.Schema)
+		assert.NotNil(t, entry.CompiledSchema)
+		return true
+	})
+	assert.Equal(t, 2, count)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/errors",
+		strings.NewReader(`{"code":"a","details":[{"code":1}]}`))
+	request.Header.Set("Content-Type", "application/json")
+	valid, validationErrors := v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	require.Len(t, validationErrors, 1)
+	require.NotEmpty(t, validationErrors[0].SchemaValidationErrors)
+	assert.Equal(t, "$.details[0].code", validationErrors[0].SchemaValidationErrors[0].FieldPath)
+
+	// changing the specification discards the persisted schemas.
+	first.Release()
+	_, third := newDiskCacheValidator(t, strings.Replace(diskCacheSpec, "title: Disk", "title: Changed", 1), dir)
+	count = 0
+	third.Range(func(_ uint64, entry *cache.SchemaCacheEntry) bool {
+		count++
+		assert.NotNil(t, entry.Schema)
+		return true
+	})
+	assert.Equal(t, 2, count)
+}
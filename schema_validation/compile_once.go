@@ -0,0 +1,63 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"fmt"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/pb33f/libopenapi-validator/cache"
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+// compileGroup shares compilations between goroutines that compile the same schema into the same cache, such as
+// background warming and the requests that arrive before it is done.
+var compileGroup singleflight.Group
+
+// CompileSchemaOnce compiles a schema for validation and stores it in the schema cache of the options under key.
+// Concurrent callers compiling the same key into the same cache share one compilation, and a schema stored by
+// another caller in the meantime is used without compiling it again. Without a schema cache, or low-level
+// information to key the schema, it is the same as CompileSchemaForValidation.
+func CompileSchemaOnce(
+	schema *base.Schema,
+	purpose SchemaValidationPurpose,
+	options *config.ValidationOptions,
+	version float32,
+	key uint64,
+) (*CompiledValidationSchema, error) {
+	if options == nil || options.SchemaCache == nil || schema == nil || schema.GoLow() == nil {
+		return CompileSchemaForValidation(schema, purpose, options, version)
+	}
+	schemaCache := options.SchemaCache
+
+	compiled, err, _ := compileGroup.Do(fmt.Sprintf("%p:%x", schemaCache, key), func() (any, error) {
+		if cached, ok := schemaCache.Load(key); ok && cached != nil && cached.CompiledSchema != nil {
+			return compiledFromCacheEntry(cached), nil
+		}
+		compiled, err := CompileSchemaForValidation(schema, purpose, options, version)
+		if err != nil || compiled == nil || compiled.CompiledSchema == nil {
+			return compiled, err
+		}
+		schemaCache.Store(key, compiled.ToCacheEntry(schema))
+		return compiled, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return compiled.(*CompiledValidationSchema), nil
+}
+
+func compiledFromCacheEntry(entry *cache.SchemaCacheEntry) *CompiledValidationSchema {
+	return &CompiledValidationSchema{
+		RenderedInline:  entry.RenderedInline,
+		ReferenceSchema: entry.ReferenceSchema,
+		RenderedJSON:    entry.RenderedJSON,
+		RenderedNode:    entry.RenderedNode,
+		ResourceNodes:   entry.ResourceNodes,
+		CompiledSchema:  entry.CompiledSchema,
+		Source:          entry.Source,
+	}
+}
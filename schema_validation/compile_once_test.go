@@ -0,0 +1,69 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	validatorcache "github.com/pb33f/libopenapi-validator/cache"
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+type countingStoreCache struct {
+	validatorcache.SchemaCache
+	stores atomic.Int32
+}
+
+func (c *countingStoreCache) Store(key uint64, value *validatorcache.SchemaCacheEntry) {
+	c.stores.Add(1)
+	c.SchemaCache.Store(key, value)
+}
+
+func TestCompileSchemaOnce(t *testing.T) {
+	schema := parseDirectionalTestSchema(t, `type: object
+properties:
+  name:
+    type: string`)
+	schemaCache := &countingStoreCache{SchemaCache: validatorcache.NewDefaultCache()}
+	options := config.NewValidationOptions(config.WithSchemaCache(schemaCache))
+	key := SchemaCacheKey(schema.GoLow().Hash(), 3.1, SchemaValidationPurposeRequestBody)
+
+	var wg sync.WaitGroup
+	compiled := make([]*CompiledValidationSchema, 8)
+	for i := range compiled {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := CompileSchemaOnce(schema, SchemaValidationPurposeRequestBody, options, 3.1, key)
+			assert.NoError(t, err)
+			compiled[i] = result
+		}()
+	}
+	wg.Wait()
+
+	// every caller gets the schema, it is only compiled and stored once.
+	assert.Equal(t, int32(1), schemaCache.stores.Load())
+	for _, result := range compiled {
+		require.NotNil(t, result)
+		assert.Same(t, compiled[0].CompiledSchema, result.CompiledSchema)
+	}
+	cached, ok := schemaCache.Load(key)
+	require.True(t, ok)
+	assert.Same(t, cached.CompiledSchema, compiled[0].CompiledSchema)
+
+	// without a cache, or a low-level schema to key it by, nothing is stored.
+	result, err := CompileSchemaOnce(schema, SchemaValidationPurposeRequestBody, config.NewValidationOptions(config.WithSchemaCache(nil)), 3.1, key)
+	require.NoError(t, err)
+	assert.NotNil(t, result.CompiledSchema)
+
+	_, err = CompileSchemaOnce(&base.Schema{Type: []string{"object"}}, SchemaValidationPurposeGeneric, options, 3.1, 1)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), schemaCache.stores.Load())
+}
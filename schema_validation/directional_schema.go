@@ -26,6 +26,18 @@ const (
 
 const schemaCachePurposeSalt uint64 = 0x9e3779b97f4a7c15
 
+// location names what schemas compiled for the purpose validate, for observers.
+func (p SchemaValidationPurpose) location() string {
+	switch p {
+	case SchemaValidationPurposeRequestBody:
+		return "requestBody"
+	case SchemaValidationPurposeResponseBody:
+		return "responseBody"
+	default:
+		return "schema"
+	}
+}
+
 // RenderedValidationSchema contains a rendered schema and its JSON equivalent.
 type RenderedValidationSchema struct {
 	RenderedInline  []byte
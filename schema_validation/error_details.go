@@ -0,0 +1,87 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	liberrors "github.com/pb33f/libopenapi-validator/errors"
+)
+
+// DiagnosticLocator returns a function that computes DiagnosticLocationNodes the first time it is called, so the
+// failures of a validation share the work, and skip it entirely when their details are never inspected.
+func DiagnosticLocator(
+	renderedSchema []byte,
+	renderedNode *yaml.Node,
+	resourceNodes map[string]*yaml.Node,
+) func() (*yaml.Node, map[string]*yaml.Node) {
+	var once sync.Once
+	var rootNode *yaml.Node
+	var diagnosticNodes map[string]*yaml.Node
+	return func() (*yaml.Node, map[string]*yaml.Node) {
+		once.Do(func() {
+			rootNode, diagnosticNodes = DiagnosticLocationNodes(renderedSchema, renderedNode, resourceNodes)
+		})
+		return rootNode, diagnosticNodes
+	}
+}
+
+// LocateFailure sets the Line and Column of a failure to the location of its keyword in a rendered schema.
+// It returns false when the keyword could not be located.
+func LocateFailure(
+	failure *liberrors.SchemaValidationFailure,
+	rootNode *yaml.Node,
+	resourceNodes map[string]*yaml.Node,
+	keywordLocation string,
+	absoluteKeywordLocation string,
+) bool {
+	if rootNode == nil {
+		return false
+	}
+	located := LocateSchemaPropertyNodeByJSONPathWithResources(rootNode, resourceNodes, keywordLocation, absoluteKeywordLocation)
+	if located == nil {
+		return false
+	}
+	line := located.Line
+	// if the located node is a map or an array, then the actual human interpretable
+	// line on which the violation occurred is the line of the key, not the value.
+	if located.Kind == yaml.MappingNode || located.Kind == yaml.SequenceNode {
+		if line > 0 {
+			line--
+		}
+	}
+
+	// location of the violation within the rendered schema.
+	failure.Line = line
+	failure.Column = located.Column
+	return true
+}
+
+// ReferenceObject returns the object a failure refers to. When the instance location points into a top level
+// array, it is the item of the array, otherwise it is the whole payload.
+func ReferenceObject(instanceLocation string, decodedObject any, payload []byte) string {
+	if val := instanceLocationRegex.FindStringSubmatch(instanceLocation); len(val) > 0 {
+		referenceIndex, _ := strconv.Atoi(val[1])
+		if items, ok := decodedObject.([]any); ok && referenceIndex < len(items) {
+			recoded, _ := json.MarshalIndent(items[referenceIndex], "", "  ")
+			return string(recoded)
+		}
+	}
+	return string(payload)
+}
+
+// AddFailureDetails computes the details of a failure now, or defers them until the failure is inspected when
+// the options ask for lazy error details.
+func AddFailureDetails(failure *liberrors.SchemaValidationFailure, options *config.ValidationOptions, details func(*liberrors.SchemaValidationFailure)) {
+	if options != nil && options.LazyErrorDetails {
+		failure.SetLazyDetails(details)
+		return
+	}
+	details(failure)
+}
@@ -0,0 +1,63 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"testing"
+
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	liberrors "github.com/pb33f/libopenapi-validator/errors"
+)
+
+func TestDiagnosticLocator(t *testing.T) {
+	rendered := []byte("type: object\nproperties:\n  name:\n    type: string\n")
+	locate := DiagnosticLocator(rendered, nil, nil)
+
+	rootNode, _ := locate()
+	require.NotNil(t, rootNode)
+	again, _ := locate()
+	assert.Same(t, rootNode, again)
+
+	failure := &liberrors.SchemaValidationFailure{}
+	assert.True(t, LocateFailure(failure, rootNode, nil, "/properties/name/type", ""))
+	assert.Equal(t, 4, failure.Line)
+	assert.Equal(t, 11, failure.Column)
+
+	// mapping nodes are located at their key.
+	failure = &liberrors.SchemaValidationFailure{}
+	assert.True(t, LocateFailure(failure, rootNode, nil, "/properties/name", ""))
+	assert.Equal(t, 3, failure.Line)
+
+	assert.False(t, LocateFailure(failure, nil, nil, "/properties/name", ""))
+	assert.False(t, LocateFailure(failure, rootNode, nil, "/properties/missing", ""))
+}
+
+func TestReferenceObject(t *testing.T) {
+	payload := []byte(`[{"a":1},{"b":2}]`)
+	decoded := []any{map[string]any{"a": 1}, map[string]any{"b": 2}}
+
+	assert.Equal(t, "{\n  \"b\": 2\n}", ReferenceObject("/1/b", decoded, payload))
+	assert.Equal(t, string(payload), ReferenceObject("/5", decoded, payload))
+	assert.Equal(t, string(payload), ReferenceObject("/name", decoded, payload))
+	assert.Equal(t, `{"a":1}`, ReferenceObject("/0", map[string]any{"a": 1}, []byte(`{"a":1}`)))
+}
+
+func TestAddFailureDetails(t *testing.T) {
+	details := func(f *liberrors.SchemaValidationFailure) {
+		f.ReferenceSchema = "type: string"
+	}
+
+	failure := &liberrors.SchemaValidationFailure{}
+	AddFailureDetails(failure, nil, details)
+	assert.Equal(t, "type: string", failure.ReferenceSchema)
+
+	failure = &liberrors.SchemaValidationFailure{}
+	AddFailureDetails(failure, config.NewValidationOptions(config.WithLazyErrorDetails()), details)
+	assert.Empty(t, failure.ReferenceSchema)
+	failure.Enrich()
+	assert.Equal(t, "type: string", failure.ReferenceSchema)
+}
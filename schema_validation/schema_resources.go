@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pb33f/libopenapi-validator/cache"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
@@ -36,6 +37,7 @@ type CompiledValidationSchema struct {
 	RenderedNode    *yaml.Node
 	ResourceNodes   map[string]*yaml.Node
 	CompiledSchema  *jsonschema.Schema
+	Source          *cache.SchemaSource
 }
 
 // schemaDocumentResourceSet is the in-memory set of JSON Schema resources passed to jsonschema.
@@ -59,6 +61,25 @@ func CompileSchemaForValidation(
 	purpose SchemaValidationPurpose,
 	options *config.ValidationOptions,
 	version float32,
+) (*CompiledValidationSchema, error) {
+	if options == nil || options.Observer == nil || schema == nil {
+		return compileSchemaForValidation(schema, purpose, options, version)
+	}
+	start := time.Now()
+	compiled, err := compileSchemaForValidation(schema, purpose, options, version)
+	options.Observer.SchemaCompiled(config.SchemaCompiledEvent{
+		Location: purpose.location(),
+		Duration: time.Since(start),
+		Err:      err,
+	})
+	return compiled, err
+}
+
+func compileSchemaForValidation(
+	schema *base.Schema,
+	purpose SchemaValidationPurpose,
+	options *config.ValidationOptions,
+	version float32,
 ) (*CompiledValidationSchema, error) {
 	if schema == nil {
 		return nil, nil
@@ -104,6 +125,11 @@ func CompileSchemaForValidation(
 		RenderedNode:    resourceSet.entryNode,
 		ResourceNodes:   resourceSet.resourceNodes,
 		CompiledSchema:  compiled,
+		Source: &cache.SchemaSource{
+			Version:   version,
+			Name:      resourceSet.entryName,
+			Resources: resourceSet.resources,
+		},
 	}, nil
 }
 
@@ -120,7 +146,21 @@ func (c *CompiledValidationSchema) ToCacheEntry(schema *base.Schema) *cache.Sche
 		CompiledSchema:  c.CompiledSchema,
 		RenderedNode:    c.RenderedNode,
 		ResourceNodes:   c.ResourceNodes,
+		Source:          c.Source,
+	}
+}
+
+// CompileSchemaSource compiles a cached schema again from the rendered JSON it was compiled from, without
+// rendering its document. It is used to compile schemas loaded by a persistent schema cache.
+func CompileSchemaSource(entry *cache.SchemaCacheEntry, options *config.ValidationOptions) (*jsonschema.Schema, error) {
+	if entry == nil || entry.Source == nil {
+		return nil, fmt.Errorf("schema cache entry has no source to compile")
+	}
+	source := entry.Source
+	if len(source.Resources) > 0 {
+		return helpers.NewCompiledSchemaResourcesWithVersion(source.Name, source.Resources, options, source.Version)
 	}
+	return helpers.NewCompiledSchemaWithVersion(source.Name, entry.RenderedJSON, options, source.Version)
 }
 
 // compileSingleValidationSchema compiles one rendered schema document without building a resource graph.
@@ -153,6 +193,7 @@ func compileSingleValidationSchema(
 		RenderedNode:    rendered.RenderedNode,
 		ResourceNodes:   sourceNodesForRenderedSchema(schemaName, rendered.RenderedNode),
 		CompiledSchema:  compiled,
+		Source:          &cache.SchemaSource{Version: version, Name: schemaName},
 	}, nil
 }
 
@@ -1301,3 +1301,51 @@ components:
 		"name": "Desk",
 	}))
 }
+
+func TestCompileSchemaSource(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: Test
+  version: 1.0.0
+components:
+  schemas:
+    Name:
+      type: string
+    Error:
+      type: object
+      properties:
+        code:
+          type: string
+        details:
+          type: array
+          items:
+            $ref: '#/components/schemas/Error'`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	model, errs := doc.BuildV3Model()
+	require.Empty(t, errs)
+	options := config.NewValidationOptions()
+
+	for _, name := range []string{"Name", "Error"} {
+		schema := model.Model.Components.Schemas.GetOrZero(name).Schema()
+		compiled, err := CompileSchemaForValidation(schema, SchemaValidationPurposeGeneric, options, 3.1)
+		require.NoError(t, err)
+		require.NotNil(t, compiled.Source)
+		assert.Equal(t, float32(3.1), compiled.Source.Version)
+		// circular schemas are compiled from document resources.
+		assert.Equal(t, name == "Error", len(compiled.Source.Resources) > 0)
+
+		// the entry compiles again without its schema or document.
+		entry := compiled.ToCacheEntry(nil)
+		assert.Same(t, compiled.Source, entry.Source)
+		recompiled, err := CompileSchemaSource(entry, options)
+		require.NoError(t, err)
+		assert.Error(t, recompiled.Validate(42))
+	}
+
+	_, err = CompileSchemaSource(&validatorcache.SchemaCacheEntry{}, options)
+	assert.Error(t, err)
+	_, err = CompileSchemaSource(nil, options)
+	assert.Error(t, err)
+}
@@ -4,15 +4,12 @@
 package schema_validation
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"math"
 	"os"
-	"reflect"
 	"regexp"
-	"strconv"
 
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/santhosh-tekuri/jsonschema/v6"
@@ -152,15 +149,17 @@ func (s *schemaValidator) validateSchemaWithVersion(schema *base.Schema, payload
 			resourceNodes = cached.ResourceNodes
 			compiledSchema = cached.CompiledSchema
 		}
+		s.options.ObserveSchemaCache(cacheKey, "schema", compiledSchema != nil)
 	}
 
 	// Cache miss — render, convert to JSON, and compile.
 	if compiledSchema == nil {
-		compiled, compileErr := CompileSchemaForValidation(
+		compiled, compileErr := CompileSchemaOnce(
 			schema,
 			SchemaValidationPurposeGeneric,
 			s.options,
 			version,
+			cacheKey,
 		)
 		if compileErr != nil {
 			line, col := schemaLineColumn(schema)
@@ -180,15 +179,10 @@ func (s *schemaValidator) validateSchemaWithVersion(schema *base.Schema, payload
 		renderedNode = compiled.RenderedNode
 		resourceNodes = compiled.ResourceNodes
 		compiledSchema = compiled.CompiledSchema
-
-		// Store in cache for subsequent validations of the same schema.
-		if canCache && compiledSchema != nil {
-			s.options.SchemaCache.Store(cacheKey, compiled.ToCacheEntry(schema))
-		}
 	}
 
 	if decodedObject == nil && len(payload) > 0 {
-		err := json.Unmarshal(payload, &decodedObject)
+		err := helpers.DecodeJSON(payload, &decodedObject)
 		if err != nil {
 			// cannot decode the request body, so it's not valid
 			line, col := schemaLineColumn(schema)
@@ -218,7 +212,7 @@ func (s *schemaValidator) validateSchemaWithVersion(schema *base.Schema, payload
 				// flatten the validationErrors
 				schFlatErr := helpers.FlattenSchemaOutputErrors(jk.DetailedOutput())
 				schemaValidationErrors = extractBasicErrors(schFlatErr, renderedSchema,
-					renderedNode, resourceNodes, decodedObject, payload, jk, schemaValidationErrors)
+					renderedNode, resourceNodes, decodedObject, payload, jk, schemaValidationErrors, s.options)
 			}
 			line, col := schemaLineColumn(schema)
 
@@ -253,13 +247,17 @@ func extractBasicErrors(schFlatErrs []jsonschema.OutputUnit,
 	decodedObject interface{},
 	payload []byte, jk *jsonschema.ValidationError,
 	schemaValidationErrors []*liberrors.SchemaValidationFailure,
+	options *config.ValidationOptions,
 ) []*liberrors.SchemaValidationFailure {
 	// Extract property name info once before processing errors (performance optimization)
 	propertyInfo := extractPropertyNameFromError(jk)
 
-	rootNode, resourceNodes := DiagnosticLocationNodes(renderedSchema, renderedNode, resourceNodes)
+	locationNodes := DiagnosticLocator(renderedSchema, renderedNode, resourceNodes)
 
 	for q := range schFlatErrs {
+		if options.PhaseBudgetSpent(len(schemaValidationErrors)) {
+			break
+		}
 		er := schFlatErrs[q]
 
 		errMsg := er.Error.Kind.LocalizedString(message.NewPrinter(language.Tag{}))
@@ -267,62 +265,25 @@ func extractBasicErrors(schFlatErrs []jsonschema.OutputUnit,
 			continue // ignore this error, it's useless tbh, utter noise.
 		}
 		if er.Error != nil {
-
-			// locate the violated property in the schema
-			var located *yaml.Node
-			if rootNode != nil {
-				located = LocateSchemaPropertyNodeByJSONPathWithResources(
-					rootNode,
-					resourceNodes,
-					er.KeywordLocation,
-					er.AbsoluteKeywordLocation,
-				)
-			}
-
-			// extract the element specified by the instance
-			val := instanceLocationRegex.FindStringSubmatch(er.InstanceLocation)
-			var referenceObject string
-
-			if len(val) > 0 {
-				referenceIndex, _ := strconv.Atoi(val[1])
-				if reflect.ValueOf(decodedObject).Type().Kind() == reflect.Slice {
-					found := decodedObject.([]any)[referenceIndex]
-					recoded, _ := json.MarshalIndent(found, "", "  ")
-					referenceObject = string(recoded)
-				}
-			}
-			if referenceObject == "" {
-				referenceObject = string(payload)
-			}
-
 			violation := &liberrors.SchemaValidationFailure{
 				Reason:                  errMsg,
 				FieldName:               helpers.ExtractFieldNameFromStringLocation(er.InstanceLocation),
 				FieldPath:               helpers.ExtractJSONPathFromStringLocation(er.InstanceLocation),
 				InstancePath:            helpers.ConvertStringLocationToPathSegments(er.InstanceLocation),
 				KeywordLocation:         er.KeywordLocation,
-				ReferenceSchema:         string(renderedSchema),
-				ReferenceObject:         referenceObject,
 				OriginalJsonSchemaError: jk,
 			}
-			// if we have a location within the schema, add it to the error
-			if located != nil {
-				line := located.Line
-				// if the located node is a map or an array, then the actual human interpretable
-				// line on which the violation occurred is the line of the key, not the value.
-				if located.Kind == yaml.MappingNode || located.Kind == yaml.SequenceNode {
-					if line > 0 {
-						line--
-					}
+			AddFailureDetails(violation, options, func(violation *liberrors.SchemaValidationFailure) {
+				violation.ReferenceSchema = string(renderedSchema)
+				violation.ReferenceObject = ReferenceObject(er.InstanceLocation, decodedObject, payload)
+
+				// if we have a location within the schema, add it to the error
+				rootNode, resourceNodes := locationNodes()
+				if !LocateFailure(violation, rootNode, resourceNodes, er.KeywordLocation, er.AbsoluteKeywordLocation) && rootNode != nil {
+					// handles property name validation errors that don't provide useful InstanceLocation
+					applyPropertyNameFallback(propertyInfo, rootNode, violation)
 				}
-
-				// location of the violation within the rendered schema.
-				violation.Line = line
-				violation.Column = located.Column
-			} else if rootNode != nil {
-				// handles property name validation errors that don't provide useful InstanceLocation
-				applyPropertyNameFallback(propertyInfo, rootNode, violation)
-			}
+			})
 			schemaValidationErrors = append(schemaValidationErrors, violation)
 		}
 	}
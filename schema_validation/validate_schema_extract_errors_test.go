@@ -70,6 +70,7 @@ properties:
 		payload,
 		nil,
 		nil,
+		nil,
 	)
 	assert.Len(t, failures, 2)
 
@@ -128,6 +129,7 @@ func TestExtractBasicErrors_UsesExternalResourceNodeForAbsoluteKeywordLocation(t
 		payload,
 		nil,
 		nil,
+		nil,
 	)
 
 	require.Len(t, failures, 1)
@@ -154,6 +156,7 @@ func TestExtractBasicErrors_IgnoresGenericSchemaNoise(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 	)
 
 	assert.Empty(t, failures)
@@ -4,6 +4,7 @@ package schema_validation
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"log/slog"
 	"strconv"
@@ -62,7 +63,55 @@ func TransformXMLToSchemaJSON(xmlString string, schema *base.Schema) (any, []*li
 	xmlNsMap := make(map[string]string, 2)
 
 	// apply openapi xml object transformations
-	return applyXMLTransformations(rawJSON, schema, &xmlNsMap)
+	transformed, xmlNsErrors := applyXMLTransformations(rawJSON, schema, &xmlNsMap)
+
+	// namespace declarations are xml syntax, not data. any left over after the xml.prefix checks
+	// would otherwise be seen as undeclared properties.
+	removeNamespaceDeclarations(transformed, collectNamespaceDeclarations(xmlString))
+
+	return transformed, xmlNsErrors
+}
+
+// collectNamespaceDeclarations returns the 'xmlns:prefix' declarations of an xml document, keyed by the
+// attribute key they are converted to ("-prefix"), with the namespace as the value.
+func collectNamespaceDeclarations(xmlString string) map[string]string {
+	declarations := make(map[string]string)
+	decoder := xml.NewDecoder(strings.NewReader(xmlString))
+	for {
+		token, err := decoder.RawToken()
+		if err != nil {
+			return declarations
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			for _, attr := range start.Attr {
+				if attr.Name.Space == "xmlns" {
+					declarations["-"+attr.Name.Local] = attr.Value
+				}
+			}
+		}
+	}
+}
+
+// removeNamespaceDeclarations deletes converted namespace declarations from data, matching both the key
+// and the namespace so that regular attributes that share a prefix name are left alone.
+func removeNamespaceDeclarations(data any, declarations map[string]string) {
+	if len(declarations) == 0 {
+		return
+	}
+	switch val := data.(type) {
+	case map[string]any:
+		for key, child := range val {
+			if ns, ok := declarations[key]; ok && child == ns {
+				delete(val, key)
+				continue
+			}
+			removeNamespaceDeclarations(child, declarations)
+		}
+	case []any:
+		for _, child := range val {
+			removeNamespaceDeclarations(child, declarations)
+		}
+	}
 }
 
 func validateXmlNs(dataMap *map[string]any, schema *base.Schema, propName string, xmlNsMap *map[string]string) []*liberrors.ValidationError {
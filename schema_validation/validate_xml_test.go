@@ -1252,3 +1252,18 @@ func TestApplyXMLTransformations_NilPropSchema(t *testing.T) {
 	assert.Len(t, errs, 0)
 	assert.NotNil(t, result)
 }
+
+func TestTransformXMLToSchemaJSON_RemovesNamespaceDeclarations(t *testing.T) {
+	schema := &base.Schema{
+		Type:       []string{"object"},
+		XML:        &base.XML{Name: "pet"},
+		Properties: orderedmap.New[string, *base.SchemaProxy](),
+	}
+	schema.Properties.Set("name", base.CreateSchemaProxy(&base.Schema{Type: []string{"string"}}))
+
+	transformed, errs := TransformXMLToSchemaJSON(
+		`<pet xmlns:ex="https://example.com" lang="en"><name>fido</name><ex:tag>x</ex:tag></pet>`, schema)
+
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]any{"name": "fido", "-lang": "en", "tag": "x"}, transformed)
+}
@@ -0,0 +1,527 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/pb33f/libopenapi"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/cache"
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
+	"github.com/pb33f/libopenapi-validator/parameters"
+	"github.com/pb33f/libopenapi-validator/requests"
+	"github.com/pb33f/libopenapi-validator/responses"
+)
+
+var (
+	_ Validator        = (*ShadowValidator)(nil)
+	_ MessageValidator = (*ShadowValidator)(nil)
+	_ ContextValidator = (*ShadowValidator)(nil)
+	_ ResultValidator  = (*ShadowValidator)(nil)
+)
+
+// ShadowDifference is an exchange that is valid under one of the specifications of a ShadowValidator, and
+// invalid under the other.
+type ShadowDifference struct {
+	Method       string
+	RequestPath  string
+	PathTemplate string // the path of the primary specification that matched, empty when none did
+	OperationID  string // the operationId of the primary operation that matched
+	StatusCode   int    // the status code of the response, zero when only the request was validated
+
+	PrimaryValid    bool
+	PrimaryErrors   []*errors.ValidationError
+	CandidateValid  bool
+	CandidateErrors []*errors.ValidationError
+}
+
+// DifferenceHandler is called for every exchange a ShadowValidator finds a difference in. It's called on the
+// goroutines that validate, possibly concurrently, so it must be safe for concurrent use.
+type DifferenceHandler func(difference *ShadowDifference)
+
+// ShadowStats counts the exchanges a ShadowValidator compared, and the differences it found.
+type ShadowStats struct {
+	Compared    uint64
+	Differences uint64
+}
+
+// ShadowValidator is a Validator that validates every exchange against a primary and a candidate specification,
+// to find the breaking changes of a new version of a specification from live traffic before publishing it.
+//
+// Only the outcome of the primary specification is returned, the candidate is reported through the handler set
+// with OnDifference when the two disagree on whether an exchange is valid. Both are validated on the calling
+// goroutine, one after the other, so shadowing about doubles the cost of validation.
+//
+// The candidate validator is built with the same options, without their observer and coverage recorder, with a
+// schema cache of its own, and without sampling: an exchange the primary validator samples is always validated by both. Path items passed to
+// the WithPathItem methods, and the validators returned by the Get methods, belong to the primary validator.
+type ShadowValidator struct {
+	primary      *validator
+	candidate    *validator
+	onDifference atomic.Pointer[DifferenceHandler]
+	compared     atomic.Uint64
+	differences  atomic.Uint64
+}
+
+// NewShadowValidator creates a ShadowValidator that enforces the primary document, and compares it with the
+// candidate document. The options are applied to the validators of both.
+func NewShadowValidator(primary, candidate libopenapi.Document, opts ...config.Option) (*ShadowValidator, []error) {
+	if primary == nil || candidate == nil {
+		return nil, []error{fmt.Errorf("cannot create a shadow validator: both documents are required")}
+	}
+	primaryModel, err := primary.BuildV3Model()
+	if err != nil {
+		return nil, []error{fmt.Errorf("cannot build the primary document: %w", err)}
+	}
+	candidateModel, err := candidate.BuildV3Model()
+	if err != nil {
+		return nil, []error{fmt.Errorf("cannot build the candidate document: %w", err)}
+	}
+
+	// the candidate compiles its schemas into a cache of its own: a persistent cache is bound to one document,
+	// and would discard the schemas of the primary.
+	s := &ShadowValidator{}
+	s.primary = NewValidatorFromV3Model(&primaryModel.Model, opts...).(*validator)
+	s.primary.document = primary
+	candidateOpts := append(append([]config.Option{}, opts...),
+		config.WithObserver(nil), config.WithCoverage(nil), config.WithSchemaCache(cache.NewDefaultCache()))
+	s.candidate = NewValidatorFromV3Model(&candidateModel.Model, candidateOpts...).(*validator)
+	s.candidate.document = candidate
+	s.candidate.sampler = nil
+	return s, nil
+}
+
+// OnDifference sets a handler that is called for every exchange the two specifications disagree on.
+func (s *ShadowValidator) OnDifference(handler DifferenceHandler) {
+	if handler == nil {
+		s.onDifference.Store(nil)
+		return
+	}
+	s.onDifference.Store(&handler)
+}
+
+// Stats returns the number of exchanges compared so far, and the number of differences found.
+func (s *ShadowValidator) Stats() ShadowStats {
+	return ShadowStats{Compared: s.compared.Load(), Differences: s.differences.Load()}
+}
+
+// Candidate returns the validator of the candidate specification, for instance to check its document with
+// ValidateDocument.
+func (s *ShadowValidator) Candidate() Validator {
+	return s.candidate
+}
+
+// compare validates the exchange against the candidate, and reports a difference with the outcome of the
+// primary. Cancelled validations are not compared.
+func (s *ShadowValidator) compare(
+	request message.Request,
+	response message.Response,
+	pathItem *v3.PathItem,
+	pathValue string,
+	primaryValid bool,
+	primaryErrors []*errors.ValidationError,
+	validateCandidate func() (bool, []*errors.ValidationError),
+) {
+	if errors.IsCancelled(primaryErrors) {
+		return
+	}
+	candidateValid, candidateErrors := validateCandidate()
+	if errors.IsCancelled(candidateErrors) {
+		return
+	}
+	s.compared.Add(1)
+	if primaryValid == candidateValid {
+		return
+	}
+	s.differences.Add(1)
+	handler := s.onDifference.Load()
+	if handler == nil {
+		return
+	}
+	difference := &ShadowDifference{
+		Method:          request.Method(),
+		RequestPath:     request.URL().Path,
+		PathTemplate:    pathValue,
+		PrimaryValid:    primaryValid,
+		PrimaryErrors:   primaryErrors,
+		CandidateValid:  candidateValid,
+		CandidateErrors: candidateErrors,
+	}
+	if response != nil {
+		difference.StatusCode = response.StatusCode()
+	}
+	if pathItem != nil {
+		if operation := helpers.OperationForMethod(request.Method(), pathItem); operation != nil {
+			difference.OperationID = operation.OperationId
+		}
+	}
+	(*handler)(difference)
+}
+
+// compareResult compares a result of the primary validator, unless it was sampled out.
+func (s *ShadowValidator) compareResult(
+	request message.Request,
+	response message.Response,
+	result *ValidationResult,
+	validateCandidate func() *ValidationResult,
+) *ValidationResult {
+	if result.SampledOut {
+		return result
+	}
+	s.compare(request, response, result.PathItem, result.PathTemplate, result.Valid, result.Errors,
+		func() (bool, []*errors.ValidationError) {
+			candidate := validateCandidate()
+			return candidate.Valid, candidate.Errors
+		})
+	return result
+}
+
+// snapshotRequest keeps the body of a request as it was received, and returns a function that copies the request
+// with it. The primary validator may sanitize the body or inject defaults into it, while the candidate has to
+// validate what the client sent.
+func snapshotRequest(request *http.Request) func() *http.Request {
+	if request.Body == nil || request.Body == http.NoBody {
+		return func() *http.Request { return request.Clone(request.Context()) }
+	}
+	body, _ := io.ReadAll(request.Body)
+	_ = request.Body.Close()
+	request.Body = io.NopCloser(bytes.NewReader(body))
+	return func() *http.Request {
+		copied := request.Clone(request.Context())
+		copied.Body = io.NopCloser(bytes.NewReader(body))
+		copied.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+		copied.ContentLength = int64(len(body))
+		return copied
+	}
+}
+
+// snapshotResponse keeps the body of a response as it was received, and returns a function that copies the
+// response with it.
+func snapshotResponse(response *http.Response) func() *http.Response {
+	if response == nil {
+		return func() *http.Response { return nil }
+	}
+	var body []byte
+	if response.Body != nil && response.Body != http.NoBody {
+		body, _ = io.ReadAll(response.Body)
+		_ = response.Body.Close()
+		response.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	return func() *http.Response {
+		copied := *response
+		copied.Header = response.Header.Clone()
+		if body != nil {
+			copied.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		return &copied
+	}
+}
+
+// snapshotMessage keeps the body of a request as it was received, in the same way as snapshotRequest. A body
+// that can't be put back into the request is held in memory, and primary is the request the primary validator
+// validates, which shares the URL and headers of the request.
+func snapshotMessage(request message.Request) (primary message.Request, original func() message.Request) {
+	ctx := message.Context(request)
+	withContext := func(copied message.Request) message.Request {
+		if ctx == nil {
+			return copied
+		}
+		return message.WithContext(ctx, copied)
+	}
+	if provider, ok := request.(message.HTTPRequestProvider); ok {
+		snapshot := snapshotRequest(provider.HTTPRequest())
+		return request, func() message.Request { return withContext(message.FromHTTPRequest(snapshot())) }
+	}
+	body := readMessage(request.Body())
+	primary = request
+	if setter, ok := request.(message.BodySetter); ok && body != nil {
+		setter.SetBody(body)
+	} else if body != nil {
+		primary = withContext(&bufferedRequest{method: request.Method(), url: request.URL(), header: request.Header(), body: body})
+	}
+	return primary, func() message.Request {
+		var u *url.URL
+		if request.URL() != nil {
+			copied := *request.URL()
+			u = &copied
+		}
+		return withContext(&bufferedRequest{method: request.Method(), url: u, header: request.Header().Clone(), body: body})
+	}
+}
+
+// snapshotMessageResponse keeps the body of a response as it was received, in the same way as
+// snapshotMessage.
+func snapshotMessageResponse(response message.Response) (primary message.Response, original func() message.Response) {
+	if response == nil {
+		return nil, func() message.Response { return nil }
+	}
+	if provider, ok := response.(message.HTTPResponseProvider); ok {
+		snapshot := snapshotResponse(provider.HTTPResponse())
+		return response, func() message.Response { return message.FromHTTPResponse(snapshot()) }
+	}
+	body := readMessage(response.Body())
+	primary = response
+	if setter, ok := response.(message.BodySetter); ok && body != nil {
+		setter.SetBody(body)
+	} else if body != nil {
+		primary = &bufferedResponse{statusCode: response.StatusCode(), header: response.Header(), body: body}
+	}
+	return primary, func() message.Response {
+		return &bufferedResponse{statusCode: response.StatusCode(), header: response.Header().Clone(), body: body}
+	}
+}
+
+// readMessage reads and closes the body of a message, nil when it has none.
+func readMessage(body io.ReadCloser) []byte {
+	if body == nil || body == http.NoBody {
+		return nil
+	}
+	read, _ := io.ReadAll(body)
+	_ = body.Close()
+	return read
+}
+
+// bufferedRequest is a message.Request with its body held in memory, so it can be read any number of times. A nil
+// body is a request without one.
+type bufferedRequest struct {
+	method string
+	url    *url.URL
+	header http.Header
+	body   []byte
+}
+
+func (r *bufferedRequest) Method() string      { return r.method }
+func (r *bufferedRequest) URL() *url.URL       { return r.url }
+func (r *bufferedRequest) Header() http.Header { return r.header }
+func (r *bufferedRequest) SetBody(body []byte) { r.body = body }
+
+func (r *bufferedRequest) Body() io.ReadCloser {
+	if r.body == nil {
+		return nil
+	}
+	return io.NopCloser(bytes.NewReader(r.body))
+}
+
+func (r *bufferedRequest) Cookie(name string) (*http.Cookie, error) {
+	return (&http.Request{Header: r.header}).Cookie(name)
+}
+
+// bufferedResponse is a message.Response with its body held in memory, so it can be read any number of times.
+type bufferedResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+func (r *bufferedResponse) StatusCode() int     { return r.statusCode }
+func (r *bufferedResponse) Header() http.Header { return r.header }
+func (r *bufferedResponse) Body() io.ReadCloser { return io.NopCloser(bytes.NewReader(r.body)) }
+func (r *bufferedResponse) SetBody(body []byte) { r.body = body }
+
+// Release releases the validators of both specifications.
+func (s *ShadowValidator) Release() {
+	if s == nil {
+		return
+	}
+	s.primary.Release()
+	s.candidate.Release()
+	s.onDifference.Store(nil)
+}
+
+func (s *ShadowValidator) ValidateHttpRequest(request *http.Request) (bool, []*errors.ValidationError) {
+	return s.ValidateMessage(message.FromHTTPRequest(request))
+}
+
+func (s *ShadowValidator) ValidateMessage(request message.Request) (bool, []*errors.ValidationError) {
+	pathItem, pathValue, errs, sampled := s.primary.lookup(request)
+	if !sampled {
+		return true, nil
+	}
+	request, original := snapshotMessage(request)
+	valid := len(errs) == 0
+	if valid {
+		valid, errs = s.primary.validateRequestWithPathItem(request, pathItem, pathValue)
+	}
+	s.compare(request, nil, pathItem, pathValue, valid, errs, func() (bool, []*errors.ValidationError) {
+		return s.candidate.ValidateMessage(original())
+	})
+	return valid, errs
+}
+
+func (s *ShadowValidator) ValidateHttpRequestSync(request *http.Request) (bool, []*errors.ValidationError) {
+	validated := message.FromHTTPRequest(request)
+	pathItem, pathValue, errs, sampled := s.primary.lookup(validated)
+	if !sampled {
+		return true, make([]*errors.ValidationError, 0)
+	}
+	_, original := snapshotMessage(validated)
+	valid := len(errs) == 0
+	if valid {
+		valid, errs = s.primary.validateRequestSyncWithPathItem(validated, pathItem, pathValue)
+	}
+	s.compare(validated, nil, pathItem, pathValue, valid, errs, func() (bool, []*errors.ValidationError) {
+		return s.candidate.validateMessageSync(original())
+	})
+	return valid, errs
+}
+
+// ValidateHttpRequestWithPathItem validates the request against the path item of the primary specification, the
+// candidate specification finds its own.
+func (s *ShadowValidator) ValidateHttpRequestWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	validated := message.FromHTTPRequest(request)
+	if s.primary.sampler.sample(validated, pathItem, pathValue) == sampledOut {
+		return true, nil
+	}
+	_, original := snapshotMessage(validated)
+	valid, errs := s.primary.validateRequestWithPathItem(validated, pathItem, pathValue)
+	s.compare(validated, nil, pathItem, pathValue, valid, errs, func() (bool, []*errors.ValidationError) {
+		return s.candidate.ValidateMessage(original())
+	})
+	return valid, errs
+}
+
+// ValidateHttpRequestSyncWithPathItem validates the request against the path item of the primary specification,
+// the candidate specification finds its own.
+func (s *ShadowValidator) ValidateHttpRequestSyncWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	validated := message.FromHTTPRequest(request)
+	if s.primary.sampler.sample(validated, pathItem, pathValue) == sampledOut {
+		return true, make([]*errors.ValidationError, 0)
+	}
+	_, original := snapshotMessage(validated)
+	valid, errs := s.primary.validateRequestSyncWithPathItem(validated, pathItem, pathValue)
+	s.compare(validated, nil, pathItem, pathValue, valid, errs, func() (bool, []*errors.ValidationError) {
+		return s.candidate.validateMessageSync(original())
+	})
+	return valid, errs
+}
+
+func (s *ShadowValidator) ValidateHttpResponse(request *http.Request, response *http.Response) (bool, []*errors.ValidationError) {
+	return s.ValidateMessageResponse(message.FromHTTPRequest(request), message.FromHTTPResponse(response))
+}
+
+func (s *ShadowValidator) ValidateMessageResponse(request message.Request, response message.Response) (bool, []*errors.ValidationError) {
+	pathItem, pathValue, errs, sampled := s.primary.lookup(request)
+	if !sampled {
+		return true, nil
+	}
+	request, original := snapshotMessage(request)
+	response, originalResponse := snapshotMessageResponse(response)
+	valid := pathItem != nil && errs == nil
+	if valid {
+		valid, errs = s.primary.validateResponseWithPathItem(request, response, pathItem, pathValue)
+	}
+	s.compare(request, response, pathItem, pathValue, valid, errs, func() (bool, []*errors.ValidationError) {
+		return s.candidate.ValidateMessageResponse(original(), originalResponse())
+	})
+	return valid, errs
+}
+
+func (s *ShadowValidator) ValidateHttpRequestContext(ctx context.Context, request *http.Request) (bool, []*errors.ValidationError) {
+	return s.ValidateMessage(message.WithContext(ctx, message.FromHTTPRequest(request)))
+}
+
+func (s *ShadowValidator) ValidateHttpResponseContext(
+	ctx context.Context,
+	request *http.Request,
+	response *http.Response,
+) (bool, []*errors.ValidationError) {
+	return s.ValidateMessageResponse(message.WithContext(ctx, message.FromHTTPRequest(request)), message.FromHTTPResponse(response))
+}
+
+func (s *ShadowValidator) ValidateHttpRequestResponse(request *http.Request, response *http.Response) (bool, []*errors.ValidationError) {
+	validated, validatedResponse := message.FromHTTPRequest(request), message.FromHTTPResponse(response)
+	pathItem, pathValue, errs, sampled := s.primary.lookup(validated)
+	if !sampled {
+		return true, nil
+	}
+	_, original := snapshotMessage(validated)
+	_, originalResponse := snapshotMessageResponse(validatedResponse)
+	valid := pathItem != nil && errs == nil
+	if valid {
+		_, requestErrors := s.primary.validateRequestWithPathItem(validated, pathItem, pathValue)
+		if errors.IsCancelled(requestErrors) {
+			return false, requestErrors
+		}
+		_, responseErrors := s.primary.validateResponseWithPathItem(validated, validatedResponse, pathItem, pathValue)
+		errs = append(requestErrors, responseErrors...)
+		valid = len(errs) == 0
+	}
+	s.compare(validated, validatedResponse, pathItem, pathValue, valid, errs, func() (bool, []*errors.ValidationError) {
+		return s.candidate.validateRequestResponse(original(), originalResponse())
+	})
+	return valid, errs
+}
+
+func (s *ShadowValidator) ValidateHttpRequestResult(request *http.Request) *ValidationResult {
+	start := time.Now()
+	validated := message.FromHTTPRequest(request)
+	result := newResult(validated)
+	pathItem, pathValue, ok := s.primary.sampleResult(result, validated, requestPhases)
+	if result.SampledOut {
+		return result.finish(start)
+	}
+	_, original := snapshotMessage(validated)
+	if ok {
+		s.primary.requestResult(result, validated, pathItem, pathValue, false)
+	}
+	return s.compareResult(validated, nil, result.finish(start), func() *ValidationResult {
+		return s.candidate.requestMessageResult(original())
+	})
+}
+
+func (s *ShadowValidator) ValidateHttpResponseResult(request *http.Request, response *http.Response) *ValidationResult {
+	start := time.Now()
+	validated, validatedResponse := message.FromHTTPRequest(request), message.FromHTTPResponse(response)
+	result := newResult(validated)
+	result.StatusCode = validatedResponse.StatusCode()
+	pathItem, pathValue, ok := s.primary.sampleResult(result, validated, responsePhases)
+	if result.SampledOut {
+		return result.finish(start)
+	}
+	_, original := snapshotMessage(validated)
+	_, originalResponse := snapshotMessageResponse(validatedResponse)
+	if ok {
+		s.primary.responseResult(result, validated, validatedResponse, pathItem, pathValue)
+	}
+	return s.compareResult(validated, validatedResponse, result.finish(start), func() *ValidationResult {
+		return s.candidate.responseMessageResult(original(), originalResponse())
+	})
+}
+
+// ValidateDocument validates the primary document, use Candidate to validate the candidate document.
+func (s *ShadowValidator) ValidateDocument() (bool, []*errors.ValidationError) {
+	return s.primary.ValidateDocument()
+}
+
+func (s *ShadowValidator) GetParameterValidator() parameters.ParameterValidator {
+	return s.primary.GetParameterValidator()
+}
+
+func (s *ShadowValidator) GetRequestBodyValidator() requests.RequestBodyValidator {
+	return s.primary.GetRequestBodyValidator()
+}
+
+func (s *ShadowValidator) GetResponseBodyValidator() responses.ResponseBodyValidator {
+	return s.primary.GetResponseBodyValidator()
+}
+
+// SetDocument sets the primary document.
+func (s *ShadowValidator) SetDocument(document libopenapi.Document) {
+	s.primary.SetDocument(document)
+}
@@ -0,0 +1,293 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/cache"
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+func shadowSpec(version, nameType string) string {
+	return `openapi: 3.1.0
+info:
+  title: Pets ` + version + `
+  version: ` + version + `
+paths:
+  /pets:
+    post:
+      operationId: createPet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: ` + nameType + `
+      responses:
+        '201':
+          description: created
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [name]
+                properties:
+                  name:
+                    type: ` + nameType + `
+`
+}
+
+func newShadow(t *testing.T, opts ...config.Option) (*ShadowValidator, *[]*ShadowDifference) {
+	t.Helper()
+	primary, err := libopenapi.NewDocument([]byte(shadowSpec("v1", "string")))
+	require.NoError(t, err)
+	candidate, err := libopenapi.NewDocument([]byte(shadowSpec("v2", "integer")))
+	require.NoError(t, err)
+	s, errs := NewShadowValidator(primary, candidate, opts...)
+	require.Empty(t, errs)
+	t.Cleanup(s.Release)
+
+	var mu sync.Mutex
+	differences := &[]*ShadowDifference{}
+	s.OnDifference(func(difference *ShadowDifference) {
+		mu.Lock()
+		defer mu.Unlock()
+		*differences = append(*differences, difference)
+	})
+	return s, differences
+}
+
+func petRequest(body string) *http.Request {
+	request, _ := http.NewRequest(http.MethodPost, "https://api.example.com/pets", bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", "application/json")
+	return request
+}
+
+func petResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+	}
+}
+
+func TestNewShadowValidator_Errors(t *testing.T) {
+	document, err := libopenapi.NewDocument([]byte(shadowSpec("v1", "string")))
+	require.NoError(t, err)
+	_, errs := NewShadowValidator(document, nil)
+	assert.NotEmpty(t, errs)
+	_, errs = NewShadowValidator(nil, document)
+	assert.NotEmpty(t, errs)
+}
+
+func TestShadowValidator_Request(t *testing.T) {
+	s, differences := newShadow(t)
+
+	// valid under the primary, invalid under the candidate: the primary is enforced.
+	request := petRequest(`{"name": "fido"}`)
+	valid, errs := s.ValidateHttpRequest(request)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+	require.Len(t, *differences, 1)
+	difference := (*differences)[0]
+	assert.Equal(t, http.MethodPost, difference.Method)
+	assert.Equal(t, "/pets", difference.RequestPath)
+	assert.Equal(t, "/pets", difference.PathTemplate)
+	assert.Equal(t, "createPet", difference.OperationID)
+	assert.Zero(t, difference.StatusCode)
+	assert.True(t, difference.PrimaryValid)
+	assert.Empty(t, difference.PrimaryErrors)
+	assert.False(t, difference.CandidateValid)
+	assert.NotEmpty(t, difference.CandidateErrors)
+
+	// the body can still be read by the caller.
+	body, _ := io.ReadAll(request.Body)
+	assert.JSONEq(t, `{"name": "fido"}`, string(body))
+
+	// invalid under the primary, valid under the candidate.
+	valid, errs = s.ValidateHttpRequestSync(petRequest(`{"name": 1}`))
+	assert.False(t, valid)
+	assert.NotEmpty(t, errs)
+	require.Len(t, *differences, 2)
+	assert.False(t, (*differences)[1].PrimaryValid)
+	assert.Equal(t, errs, (*differences)[1].PrimaryErrors)
+	assert.True(t, (*differences)[1].CandidateValid)
+
+	// both agree, so there's no difference.
+	valid, _ = s.ValidateHttpRequest(petRequest(`{}`))
+	assert.False(t, valid)
+	result := s.ValidateHttpRequestResult(petRequest(`{"name": true}`))
+	assert.False(t, result.Valid)
+	assert.Len(t, *differences, 2)
+
+	assert.Equal(t, ShadowStats{Compared: 4, Differences: 2}, s.Stats())
+}
+
+func TestShadowValidator_Response(t *testing.T) {
+	s, differences := newShadow(t)
+
+	valid, errs := s.ValidateHttpResponse(petRequest(`{"name": "fido"}`), petResponse(`{"name": 1}`))
+	assert.False(t, valid)
+	assert.NotEmpty(t, errs)
+	require.Len(t, *differences, 1)
+	assert.Equal(t, http.StatusCreated, (*differences)[0].StatusCode)
+	assert.True(t, (*differences)[0].CandidateValid)
+
+	// the request is invalid under the candidate, the response is invalid under the primary.
+	valid, errs = s.ValidateHttpRequestResponse(petRequest(`{"name": "fido"}`), petResponse(`{"name": 1}`))
+	assert.False(t, valid)
+	assert.NotEmpty(t, errs)
+	assert.Len(t, *differences, 1)
+
+	valid, _ = s.ValidateHttpRequestResponse(petRequest(`{"name": "fido"}`), petResponse(`{"name": "fido"}`))
+	assert.True(t, valid)
+	require.Len(t, *differences, 2)
+	assert.False(t, (*differences)[1].CandidateValid)
+	assert.Len(t, (*differences)[1].CandidateErrors, 2)
+
+	result := s.ValidateHttpResponseResult(petRequest(`{}`), petResponse(`{"name": "fido"}`))
+	assert.True(t, result.Valid)
+	assert.Len(t, *differences, 3)
+}
+
+func TestShadowValidator_Sampling(t *testing.T) {
+	s, differences := newShadow(t, config.WithSampling(0), config.WithSamplingDebugHeader("X-Validate"))
+
+	valid, errs := s.ValidateHttpRequest(petRequest(`{"name": 1}`))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+	result := s.ValidateHttpRequestResult(petRequest(`{"name": 1}`))
+	assert.True(t, result.SampledOut)
+	assert.Empty(t, *differences)
+	assert.Zero(t, s.Stats().Compared)
+
+	// the body of an exchange that is sampled out is not read.
+	request := petRequest("")
+	request.Body = &onceRequestBody{}
+	assert.True(t, s.ValidateHttpRequestResult(request).SampledOut)
+	response := petResponse("")
+	response.Body = &onceRequestBody{}
+	assert.True(t, s.ValidateHttpResponseResult(request, response).SampledOut)
+	assert.False(t, request.Body.(*onceRequestBody).read)
+	assert.False(t, response.Body.(*onceRequestBody).read)
+
+	// an exchange the primary samples is validated by both.
+	request = petRequest(`{"name": 1}`)
+	request.Header.Set("X-Validate", "true")
+	valid, _ = s.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	assert.Len(t, *differences, 1)
+	request = petRequest(`{"name": 1}`)
+	request.Header.Set("X-Validate", "true")
+	assert.False(t, s.ValidateHttpRequestResult(request).SampledOut)
+	assert.Len(t, *differences, 2)
+}
+
+// onceRequestBody is a body that records whether it was read.
+type onceRequestBody struct {
+	read bool
+}
+
+func (b *onceRequestBody) Read([]byte) (int, error) {
+	b.read = true
+	return 0, io.EOF
+}
+
+func (b *onceRequestBody) Close() error { return nil }
+
+func TestShadowValidator_CandidateCache(t *testing.T) {
+	diskCache, err := cache.NewDiskCache(t.TempDir(), nil)
+	require.NoError(t, err)
+	s, differences := newShadow(t, config.WithSchemaCache(diskCache))
+
+	// the disk cache is bound to the primary document, the candidate compiles into a cache of its own.
+	assert.Same(t, diskCache, s.primary.options.SchemaCache)
+	_, persistent := s.candidate.options.SchemaCache.(cache.PersistentSchemaCache)
+	assert.False(t, persistent)
+
+	valid, _ := s.ValidateHttpRequest(petRequest(`{"name": "fido"}`))
+	assert.True(t, valid)
+	assert.Len(t, *differences, 1)
+
+	// the schemas the primary compiled are still in the disk cache.
+	s.primary.options.Plans.Release()
+	assert.Zero(t, s.primary.options.Plans.Uncompiled(diskCache))
+}
+
+func TestShadowValidator_Document(t *testing.T) {
+	s, differences := newShadow(t)
+	s.OnDifference(nil)
+
+	valid, errs := s.ValidateDocument()
+	assert.True(t, valid, errs)
+	valid, errs = s.Candidate().ValidateDocument()
+	assert.True(t, valid, errs)
+	assert.NotNil(t, s.GetParameterValidator())
+	assert.NotNil(t, s.GetRequestBodyValidator())
+	assert.NotNil(t, s.GetResponseBodyValidator())
+
+	// differences are still counted without a handler.
+	s.ValidateHttpRequest(petRequest(`{"name": 1}`))
+	assert.Empty(t, *differences)
+	assert.Equal(t, uint64(1), s.Stats().Differences)
+}
+
+// onceRequest is a message.Request with a body that can only be read once, and can't be replaced.
+type onceRequest struct {
+	body io.ReadCloser
+}
+
+func (r *onceRequest) Method() string { return http.MethodPost }
+func (r *onceRequest) URL() *url.URL  { return &url.URL{Path: "/pets"} }
+func (r *onceRequest) Header() http.Header {
+	return http.Header{"Content-Type": []string{"application/json"}}
+}
+func (r *onceRequest) Body() io.ReadCloser { return r.body }
+
+func (r *onceRequest) Cookie(string) (*http.Cookie, error) { return nil, http.ErrNoCookie }
+
+func TestShadowValidator_Message(t *testing.T) {
+	s, differences := newShadow(t)
+
+	// both specifications read the body of a message that can only be read once.
+	valid, errs := s.ValidateMessage(&onceRequest{body: io.NopCloser(bytes.NewBufferString(`{"name": "fido"}`))})
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+	require.Len(t, *differences, 1)
+	assert.False(t, (*differences)[0].CandidateValid)
+
+	request, err := message.NewAPIGatewayRequest(&message.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodPost,
+		Path:       "/pets",
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       `{"name": "fido"}`,
+	})
+	require.NoError(t, err)
+	response, err := message.NewAPIGatewayResponse(&message.APIGatewayProxyResponse{
+		StatusCode: http.StatusCreated,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       `{"name": 1}`,
+	})
+	require.NoError(t, err)
+	valid, errs = s.ValidateMessageResponse(request, response)
+	assert.False(t, valid)
+	assert.NotEmpty(t, errs)
+	require.Len(t, *differences, 2)
+	assert.Equal(t, http.StatusCreated, (*differences)[1].StatusCode)
+	assert.True(t, (*differences)[1].CandidateValid)
+}
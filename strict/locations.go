@@ -0,0 +1,217 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package strict
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+)
+
+// BodyFormat identifies the wire format a body was decoded from before strict validation.
+// XML and urlencoded bodies are converted into JSON compatible data by the schema_validation package,
+// so the walker sees the same shapes for every format. The format is only used to translate the
+// JSONPath of each undeclared value back into a location in the original payload.
+type BodyFormat int
+
+const (
+	// BodyFormatJSON is the default, locations are the JSONPath of the value.
+	BodyFormatJSON BodyFormat = iota
+
+	// BodyFormatXML is a body converted by schema_validation.TransformXMLToSchemaJSON.
+	// locations are XPath expressions, e.g. "/pet/tags/tag[2]" or "/pet/@id".
+	BodyFormatXML
+
+	// BodyFormatURLEncoded is a body converted by schema_validation.TransformURLEncodedToSchemaJSON.
+	// locations are form keys, e.g. "name" or "address[city]".
+	BodyFormatURLEncoded
+)
+
+// xml attributes and text content are keyed like this by the xml to json conversion.
+const (
+	xmlAttributePrefix = "-"
+	xmlContentKey      = "#content"
+	xmlNamespaceAttr   = "-xmlns"
+)
+
+// pathToken is a single step of an instance path created by buildPath or buildArrayPath.
+type pathToken struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+// tokenizePath splits an instance path relative to basePath back into property names and array indexes.
+func tokenizePath(path, basePath string) []pathToken {
+	rest := strings.TrimPrefix(path, basePath)
+	var tokens []pathToken
+	for len(rest) > 0 {
+		switch {
+		case strings.HasPrefix(rest, "['"):
+			end := strings.Index(rest, "']")
+			if end < 0 {
+				return append(tokens, pathToken{name: rest[2:]})
+			}
+			tokens = append(tokens, pathToken{name: rest[2:end]})
+			rest = rest[end+2:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return tokens
+			}
+			idx, err := strconv.Atoi(rest[1:end])
+			if err != nil {
+				return tokens
+			}
+			tokens = append(tokens, pathToken{index: idx, isIndex: true})
+			rest = rest[end+1:]
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				return append(tokens, pathToken{name: rest[1:]})
+			}
+			tokens = append(tokens, pathToken{name: rest[1 : end+1]})
+			rest = rest[end+1:]
+		default:
+			return tokens
+		}
+	}
+	return tokens
+}
+
+// isXMLNamespaceDeclaration returns true for xmlns attributes, which are part of the XML syntax
+// rather than the payload, so are never reported as undeclared.
+func isXMLNamespaceDeclaration(name string) bool {
+	return name == xmlNamespaceAttr || strings.HasPrefix(name, xmlNamespaceAttr+":")
+}
+
+// xmlLocation translates the instance path of a converted XML body into an XPath, using the xml
+// objects of the schema to recover element names, attributes and wrapped arrays.
+func xmlLocation(schema *base.Schema, path, basePath string) string {
+	var loc strings.Builder
+	if schema != nil && schema.XML != nil && schema.XML.Name != "" {
+		loc.WriteString("/" + schema.XML.Name)
+	}
+
+	current := schema
+	wrappedItem := ""
+	for _, token := range tokenizePath(path, basePath) {
+		if token.isIndex {
+			if wrappedItem != "" {
+				loc.WriteString("/" + wrappedItem)
+				wrappedItem = ""
+			}
+			loc.WriteString("[" + strconv.Itoa(token.index+1) + "]")
+			current = itemsSchema(current)
+			continue
+		}
+
+		propSchema := findPropertySchema(current, token.name)
+		wrappedItem = ""
+		switch {
+		case propSchema != nil:
+			xmlName := token.name
+			if propSchema.XML != nil && propSchema.XML.Name != "" {
+				xmlName = propSchema.XML.Name
+			}
+			if propSchema.XML != nil && propSchema.XML.Attribute {
+				loc.WriteString("/@" + xmlName)
+			} else {
+				loc.WriteString("/" + xmlName)
+			}
+			if propSchema.XML != nil && propSchema.XML.Wrapped {
+				wrappedItem = xmlName
+				if items := itemsSchema(propSchema); items != nil && items.XML != nil && items.XML.Name != "" {
+					wrappedItem = items.XML.Name
+				}
+			}
+		case token.name == xmlContentKey:
+			loc.WriteString("/text()")
+		case strings.HasPrefix(token.name, xmlAttributePrefix):
+			loc.WriteString("/@" + strings.TrimPrefix(token.name, xmlAttributePrefix))
+		default:
+			loc.WriteString("/" + token.name)
+		}
+		current = propSchema
+	}
+
+	if loc.Len() == 0 {
+		return "/"
+	}
+	return loc.String()
+}
+
+// formLocation translates the instance path of a converted urlencoded body into the form key that
+// carried the value. Nested objects use deepObject notation and array indexes are dropped, as
+// repeated keys carry array values.
+func formLocation(path, basePath string) string {
+	var loc strings.Builder
+	for _, token := range tokenizePath(path, basePath) {
+		if token.isIndex {
+			continue
+		}
+		if loc.Len() == 0 {
+			loc.WriteString(token.name)
+		} else {
+			loc.WriteString("[" + token.name + "]")
+		}
+	}
+	return loc.String()
+}
+
+// findPropertySchema looks up a property in a schema and its allOf, oneOf and anyOf sub schemas.
+func findPropertySchema(schema *base.Schema, name string) *base.Schema {
+	if schema == nil {
+		return nil
+	}
+	if schema.Properties != nil {
+		if proxy, ok := schema.Properties.Get(name); ok && proxy != nil {
+			return proxy.Schema()
+		}
+	}
+	for _, group := range [][]*base.SchemaProxy{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, proxy := range group {
+			if proxy == nil {
+				continue
+			}
+			if found := findPropertySchema(proxy.Schema(), name); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+func itemsSchema(schema *base.Schema) *base.Schema {
+	if schema == nil || schema.Items == nil || !schema.Items.IsA() || schema.Items.A == nil {
+		return nil
+	}
+	return schema.Items.A.Schema()
+}
+
+// locate fills in the Location of undeclared body values for non JSON formats, and drops
+// values that only exist because of the format (xmlns declarations).
+func locate(undeclared []UndeclaredValue, input Input) []UndeclaredValue {
+	if input.Format == BodyFormatJSON {
+		return undeclared
+	}
+	located := undeclared[:0]
+	for _, value := range undeclared {
+		switch input.Format {
+		case BodyFormatXML:
+			if isXMLNamespaceDeclaration(value.Name) {
+				continue
+			}
+			if strings.HasPrefix(value.Name, xmlAttributePrefix) {
+				value.Name = "@" + strings.TrimPrefix(value.Name, xmlAttributePrefix)
+			}
+			value.Location = xmlLocation(input.Schema, value.Path, input.BasePath)
+		case BodyFormatURLEncoded:
+			value.Location = formLocation(value.Path, input.BasePath)
+		}
+		located = append(located, value)
+	}
+	return located
+}
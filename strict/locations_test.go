@@ -0,0 +1,190 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package strict
+
+import (
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+)
+
+func buildLocationSchema(t *testing.T, yml string) *base.Schema {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+` + yml
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	model, errs := doc.BuildV3Model()
+	require.NoError(t, errs)
+	return model.Model.Components.Schemas.GetOrZero("Pet").Schema()
+}
+
+func TestTokenizePath(t *testing.T) {
+	tokens := tokenizePath("$.body.a['b.c'][2].d", "$.body")
+	assert.Equal(t, []pathToken{
+		{name: "a"},
+		{name: "b.c"},
+		{index: 2, isIndex: true},
+		{name: "d"},
+	}, tokens)
+
+	assert.Empty(t, tokenizePath("$.body", "$.body"))
+}
+
+func TestXMLLocation(t *testing.T) {
+	schema := buildLocationSchema(t, `      type: object
+      xml:
+        name: pet
+      properties:
+        id:
+          type: integer
+          xml:
+            attribute: true
+        petName:
+          type: string
+          xml:
+            name: name
+        photos:
+          type: array
+          xml:
+            wrapped: true
+          items:
+            type: object
+            xml:
+              name: photo
+            properties:
+              url:
+                type: string
+        tags:
+          type: array
+          items:
+            type: object
+            properties:
+              label:
+                type: string`)
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"$.body.extra", "/pet/extra"},
+		{"$.body.-lang", "/pet/@lang"},
+		{"$.body.#content", "/pet/text()"},
+		{"$.body.id", "/pet/@id"},
+		{"$.body.petName.extra", "/pet/name/extra"},
+		{"$.body.photos[1].extra", "/pet/photos/photo[2]/extra"},
+		{"$.body.photos[0].-size", "/pet/photos/photo[1]/@size"},
+		{"$.body.tags[2].extra", "/pet/tags[3]/extra"},
+		{"$.body", "/pet"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, xmlLocation(schema, tt.path, "$.body"))
+		})
+	}
+}
+
+func TestXMLLocation_NoRootName(t *testing.T) {
+	schema := buildLocationSchema(t, `      type: object
+      allOf:
+        - type: object
+          properties:
+            size:
+              type: string
+              xml:
+                attribute: true`)
+
+	assert.Equal(t, "/@size", xmlLocation(schema, "$.body.size", "$.body"))
+	assert.Equal(t, "/", xmlLocation(schema, "$.body", "$.body"))
+}
+
+func TestFormLocation(t *testing.T) {
+	assert.Equal(t, "name", formLocation("$.body.name", "$.body"))
+	assert.Equal(t, "address[city]", formLocation("$.body.address.city", "$.body"))
+	assert.Equal(t, "a.b[c]", formLocation("$.body['a.b'].c", "$.body"))
+	assert.Equal(t, "tags[extra]", formLocation("$.body.tags[1].extra", "$.body"))
+}
+
+func TestValidate_BodyFormatXML(t *testing.T) {
+	schema := buildLocationSchema(t, `      type: object
+      xml:
+        name: pet
+      properties:
+        name:
+          type: string`)
+
+	v := NewValidator(nil, 3.1)
+	result := v.Validate(Input{
+		Schema:    schema,
+		Data:      map[string]any{"name": "fido", "-xmlns": "https://example.com", "-lang": "en"},
+		Direction: DirectionRequest,
+		BasePath:  "$.body",
+		Version:   3.1,
+		Format:    BodyFormatXML,
+	})
+
+	assert.False(t, result.Valid)
+	require.Len(t, result.UndeclaredValues, 1)
+	assert.Equal(t, "@lang", result.UndeclaredValues[0].Name)
+	assert.Equal(t, "$.body.-lang", result.UndeclaredValues[0].Path)
+	assert.Equal(t, "/pet/@lang", result.UndeclaredValues[0].Location)
+}
+
+func TestValidate_BodyFormatXML_OnlyNamespaces(t *testing.T) {
+	schema := buildLocationSchema(t, `      type: object
+      properties:
+        name:
+          type: string`)
+
+	v := NewValidator(nil, 3.1)
+	result := v.Validate(Input{
+		Schema:   schema,
+		Data:     map[string]any{"name": "fido", "-xmlns": "https://example.com"},
+		BasePath: "$.body",
+		Format:   BodyFormatXML,
+	})
+
+	assert.True(t, result.Valid)
+	assert.Empty(t, result.UndeclaredValues)
+}
+
+func TestValidate_BodyFormatURLEncoded(t *testing.T) {
+	schema := buildLocationSchema(t, `      type: object
+      properties:
+        name:
+          type: string`)
+
+	v := NewValidator(nil, 3.1)
+	result := v.Validate(Input{
+		Schema:   schema,
+		Data:     map[string]any{"name": "fido", "debug": "1"},
+		BasePath: "$.body",
+		Format:   BodyFormatURLEncoded,
+	})
+
+	require.Len(t, result.UndeclaredValues, 1)
+	assert.Equal(t, "debug", result.UndeclaredValues[0].Location)
+}
+
+func TestValidate_BodyFormatJSON_NoLocation(t *testing.T) {
+	schema := buildLocationSchema(t, `      type: object
+      properties:
+        name:
+          type: string`)
+
+	v := NewValidator(nil, 3.1)
+	result := v.Validate(Input{
+		Schema:   schema,
+		Data:     map[string]any{"name": "fido", "debug": "1"},
+		BasePath: "$.body",
+	})
+
+	require.Len(t, result.UndeclaredValues, 1)
+	assert.Empty(t, result.UndeclaredValues[0].Location)
+}
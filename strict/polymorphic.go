@@ -113,7 +113,7 @@ func (v *Validator) validateAllOf(ctx *traversalContext, schema *base.Schema, da
 			// Recurse into the property
 			propSchema := v.findPropertySchemaInAllOf(schema.AllOf, propName, allDeclared)
 			if propSchema != nil {
-				if violation, ok := v.checkReadWriteOnlyViolation(propPath, propName, propValue, propSchema, ctx.direction); ok {
+				if violation, ok := v.checkReadWriteOnlyViolation(propPath, propName, propValue, propSchema, ctx); ok {
 					undeclared = append(undeclared, violation)
 					continue
 				}
@@ -136,18 +136,7 @@ func (v *Validator) validateAllOf(ctx *traversalContext, schema *base.Schema, da
 // validateOneOf finds the matching oneOf variant and validates against it.
 // Parent schema properties are merged with the variant's properties.
 func (v *Validator) validateOneOf(ctx *traversalContext, schema *base.Schema, data map[string]any) []UndeclaredValue {
-	var matchingVariant *base.Schema
-
-	// discriminator is present, use it to select the variant
-	if schema.Discriminator != nil {
-		matchingVariant = v.selectByDiscriminator(schema, schema.OneOf, data)
-	}
-
-	// no discriminator or no match: find matching variant by validation
-	if matchingVariant == nil {
-		matchingVariant = v.findMatchingVariant(schema.OneOf, data)
-	}
-
+	matchingVariant := v.SelectVariant(schema, schema.OneOf, data)
 	if matchingVariant == nil {
 		// No match found - base validation would report this error
 		return nil
@@ -160,18 +149,7 @@ func (v *Validator) validateOneOf(ctx *traversalContext, schema *base.Schema, da
 // validateAnyOf finds matching anyOf variants and validates against them.
 // Parent schema properties are merged with the variant's properties.
 func (v *Validator) validateAnyOf(ctx *traversalContext, schema *base.Schema, data map[string]any) []UndeclaredValue {
-	var matchingVariant *base.Schema
-
-	// If discriminator is present, use it to select the variant
-	if schema.Discriminator != nil {
-		matchingVariant = v.selectByDiscriminator(schema, schema.AnyOf, data)
-	}
-
-	// No discriminator or no match: find matching variant by validation
-	if matchingVariant == nil {
-		matchingVariant = v.findMatchingVariant(schema.AnyOf, data)
-	}
-
+	matchingVariant := v.SelectVariant(schema, schema.AnyOf, data)
 	if matchingVariant == nil {
 		// No match found - base validation would report this error
 		return nil
@@ -223,7 +201,7 @@ func (v *Validator) validateVariantWithParent(ctx *traversalContext, parent *bas
 			// Find the property schema (prefer variant, fallback to parent)
 			propSchema := v.findPropertySchemaInMerged(variant, parent, propName, allDeclared)
 			if propSchema != nil {
-				if violation, ok := v.checkReadWriteOnlyViolation(propPath, propName, propValue, propSchema, ctx.direction); ok {
+				if violation, ok := v.checkReadWriteOnlyViolation(propPath, propName, propValue, propSchema, ctx); ok {
 					undeclared = append(undeclared, violation)
 					continue
 				}
@@ -301,7 +279,7 @@ func (v *Validator) recurseIntoDeclaredPropertiesWithMerged(ctx *traversalContex
 
 		propSchema := v.findPropertySchemaInMerged(variant, parent, propName, declared)
 		if propSchema != nil {
-			if violation, ok := v.checkReadWriteOnlyViolation(propPath, propName, propValue, propSchema, ctx.direction); ok {
+			if violation, ok := v.checkReadWriteOnlyViolation(propPath, propName, propValue, propSchema, ctx); ok {
 				undeclared = append(undeclared, violation)
 				continue
 			}
@@ -315,6 +293,24 @@ func (v *Validator) recurseIntoDeclaredPropertiesWithMerged(ctx *traversalContex
 	return undeclared
 }
 
+// SelectVariant returns the oneOf or anyOf variant of schema that data matches. The discriminator
+// selects the variant when there is one, otherwise the first variant the data validates against is used.
+// Returns nil when no variant matches.
+func (v *Validator) SelectVariant(schema *base.Schema, variants []*base.SchemaProxy, data map[string]any) *base.Schema {
+	var matchingVariant *base.Schema
+
+	// discriminator is present, use it to select the variant
+	if schema.Discriminator != nil {
+		matchingVariant = v.selectByDiscriminator(schema, variants, data)
+	}
+
+	// no discriminator or no match: find matching variant by validation
+	if matchingVariant == nil {
+		matchingVariant = v.findMatchingVariant(variants, data)
+	}
+	return matchingVariant
+}
+
 // selectByDiscriminator uses the discriminator to select the appropriate variant.
 func (v *Validator) selectByDiscriminator(schema *base.Schema, variants []*base.SchemaProxy, data map[string]any) *base.Schema {
 	if schema.Discriminator == nil {
@@ -475,7 +471,7 @@ func (v *Validator) recurseIntoAllOfDeclaredProperties(ctx *traversalContext, al
 
 		propSchema := v.findPropertySchemaInAllOf(allOf, propName, declared)
 		if propSchema != nil {
-			if violation, ok := v.checkReadWriteOnlyViolation(propPath, propName, propValue, propSchema, ctx.direction); ok {
+			if violation, ok := v.checkReadWriteOnlyViolation(propPath, propName, propValue, propSchema, ctx); ok {
 				undeclared = append(undeclared, violation)
 				continue
 			}
@@ -148,21 +148,27 @@ func getPropertySchema(name string, declared map[string]*declaredProperty) *base
 }
 
 // checkReadWriteOnlyViolation checks if a property violates readOnly/writeOnly rules
-// when the corresponding rejection flag is enabled. Returns a violation and true if so.
+// when the corresponding rejection flag is enabled, or the traversal rejects them regardless
+// of the flags. Returns a violation and true if so.
 func (v *Validator) checkReadWriteOnlyViolation(
 	path string, name string, value any,
-	schema *base.Schema, direction Direction,
+	schema *base.Schema, ctx *traversalContext,
 ) (UndeclaredValue, bool) {
-	if schema == nil || v.options == nil {
+	if schema == nil {
 		return UndeclaredValue{}, false
 	}
-	if direction == DirectionRequest && v.options.StrictRejectReadOnly &&
+	rejectReadOnly, rejectWriteOnly := ctx.rejectReadWriteOnly, ctx.rejectReadWriteOnly
+	if v.options != nil {
+		rejectReadOnly = rejectReadOnly || v.options.StrictRejectReadOnly
+		rejectWriteOnly = rejectWriteOnly || v.options.StrictRejectWriteOnly
+	}
+	if ctx.direction == DirectionRequest && rejectReadOnly &&
 		schema.ReadOnly != nil && *schema.ReadOnly {
-		return newReadWriteOnlyViolation(path, name, value, direction, schema), true
+		return newReadWriteOnlyViolation(path, name, value, ctx.direction, schema), true
 	}
-	if direction == DirectionResponse && v.options.StrictRejectWriteOnly &&
+	if ctx.direction == DirectionResponse && rejectWriteOnly &&
 		schema.WriteOnly != nil && *schema.WriteOnly {
-		return newReadWriteOnlyViolation(path, name, value, direction, schema), true
+		return newReadWriteOnlyViolation(path, name, value, ctx.direction, schema), true
 	}
 	return UndeclaredValue{}, false
 }
@@ -0,0 +1,225 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package strict
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+// SanitizeResult contains the output of strict sanitizing.
+type SanitizeResult struct {
+	// Data is a copy of the input data with every removed value dropped.
+	// the input data is never modified.
+	Data any
+
+	// Removed lists the values that were dropped from Data.
+	Removed []UndeclaredValue
+}
+
+// removedItem marks array items that are dropped once all removals are applied,
+// so that the indexes of the remaining removals stay valid.
+type removedItem struct{}
+
+// Sanitize walks the input data exactly like Validate, and returns a copy of it with
+// undeclared properties, readOnly properties in requests and writeOnly properties in
+// responses dropped. Values matching StrictIgnorePaths are kept.
+//
+// This is the data-minimisation counterpart of Validate: rather than reporting
+// values that are not in the contract, they are removed before the data is passed on.
+func (v *Validator) Sanitize(input Input) *SanitizeResult {
+	result := &SanitizeResult{Data: input.Data}
+	if input.Schema == nil || input.Data == nil {
+		return result
+	}
+
+	// readOnly and writeOnly properties are always dropped, regardless of the reject flags.
+	validated := v.validate(input, true)
+
+	// a partial traversal can't tell everything that should be dropped, so leave the data as it is.
+	if validated.Valid || validated.Err != nil {
+		return result
+	}
+
+	data := copyData(input.Data)
+	for _, value := range validated.UndeclaredValues {
+		removeAtPath(data, tokenizePath(value.Path, input.BasePath))
+	}
+	result.Data = compactData(data)
+	result.Removed = validated.UndeclaredValues
+	return result
+}
+
+// SanitizeBody is a convenience method for sanitizing request/response bodies.
+func SanitizeBody(schema *base.Schema, data any, direction Direction, options *config.ValidationOptions, version float32) *SanitizeResult {
+	v := NewValidator(options, version)
+	return v.Sanitize(Input{
+		Schema:    schema,
+		Data:      data,
+		Direction: direction,
+		Options:   options,
+		BasePath:  "$.body",
+		Version:   version,
+	})
+}
+
+// SanitizeQueryParams removes the undeclared query parameters found by ValidateQueryParams
+// from the URL of a request. Only the key=value pairs of undeclared parameters are removed,
+// the others are kept exactly as they were sent, in the same order and with the same escaping.
+func SanitizeQueryParams(u *url.URL, undeclared []UndeclaredValue) {
+	if u == nil {
+		return
+	}
+	drop := make(map[string]bool)
+	for _, value := range undeclared {
+		if value.Type == "query" {
+			drop[value.Name] = true
+		}
+	}
+	if len(drop) == 0 {
+		return
+	}
+
+	pairs := strings.Split(u.RawQuery, "&")
+	kept := pairs[:0]
+	for _, pair := range pairs {
+		key, _, _ := strings.Cut(pair, "=")
+		// pairs that can't be unescaped are not parsed as parameters, so they are never undeclared.
+		if name, err := url.QueryUnescape(key); err == nil && drop[name] {
+			continue
+		}
+		kept = append(kept, pair)
+	}
+	u.RawQuery = strings.Join(kept, "&")
+}
+
+// SanitizeHeaders removes the undeclared headers found by ValidateRequestHeaders or
+// ValidateResponseHeaders.
+func SanitizeHeaders(headers http.Header, undeclared []UndeclaredValue) {
+	if headers == nil {
+		return
+	}
+	for _, value := range undeclared {
+		if value.Type == "header" {
+			headers.Del(value.Name)
+		}
+	}
+}
+
+// SanitizeCookies removes the undeclared cookies found by ValidateCookies from the
+// Cookie header of a request. Only the name=value pairs of undeclared cookies are removed,
+// the others are kept exactly as they were sent.
+func SanitizeCookies(header http.Header, undeclared []UndeclaredValue) {
+	if header == nil {
+		return
+	}
+	drop := make(map[string]bool)
+	for _, value := range undeclared {
+		if value.Type == "cookie" {
+			drop[value.Name] = true
+		}
+	}
+	if len(drop) == 0 {
+		return
+	}
+
+	var kept []string
+	for _, header := range header.Values("Cookie") {
+		for _, pair := range strings.Split(header, ";") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+			name, _, _ := strings.Cut(pair, "=")
+			if !drop[strings.TrimSpace(name)] {
+				kept = append(kept, pair)
+			}
+		}
+	}
+	header.Del("Cookie")
+	if len(kept) > 0 {
+		header.Set("Cookie", strings.Join(kept, "; "))
+	}
+}
+
+// copyData creates a deep copy of unmarshalled JSON data.
+func copyData(data any) any {
+	switch val := data.(type) {
+	case map[string]any:
+		cp := make(map[string]any, len(val))
+		for k, item := range val {
+			cp[k] = copyData(item)
+		}
+		return cp
+	case []any:
+		cp := make([]any, len(val))
+		for i, item := range val {
+			cp[i] = copyData(item)
+		}
+		return cp
+	default:
+		return val
+	}
+}
+
+// removeAtPath drops the value the tokens point at. Object properties are deleted
+// straight away, array items are marked and dropped by compactData.
+func removeAtPath(data any, tokens []pathToken) {
+	if len(tokens) == 0 {
+		return
+	}
+	current := data
+	for _, token := range tokens[:len(tokens)-1] {
+		switch val := current.(type) {
+		case map[string]any:
+			current = val[token.name]
+		case []any:
+			if !token.isIndex || token.index >= len(val) {
+				return
+			}
+			current = val[token.index]
+		default:
+			return
+		}
+	}
+
+	last := tokens[len(tokens)-1]
+	switch val := current.(type) {
+	case map[string]any:
+		if !last.isIndex {
+			delete(val, last.name)
+		}
+	case []any:
+		if last.isIndex && last.index < len(val) {
+			val[last.index] = removedItem{}
+		}
+	}
+}
+
+// compactData drops array items marked by removeAtPath.
+func compactData(data any) any {
+	switch val := data.(type) {
+	case map[string]any:
+		for k, item := range val {
+			val[k] = compactData(item)
+		}
+		return val
+	case []any:
+		kept := val[:0]
+		for _, item := range val {
+			if _, removed := item.(removedItem); removed {
+				continue
+			}
+			kept = append(kept, compactData(item))
+		}
+		return kept
+	default:
+		return val
+	}
+}
@@ -0,0 +1,317 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package strict
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+const sanitizeSpec = `openapi: "3.1.0"
+info:
+  title: Test
+  version: "1.0"
+paths: {}
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        id:
+          type: string
+          readOnly: true
+        name:
+          type: string
+        password:
+          type: string
+          writeOnly: true
+        address:
+          type: object
+          properties:
+            city:
+              type: string
+        tags:
+          type: array
+          items:
+            type: object
+            properties:
+              label:
+                type: string
+        metadata:
+          type: object
+    Pet:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+        - $ref: '#/components/schemas/Dog'
+      discriminator:
+        propertyName: kind
+    Cat:
+      type: object
+      properties:
+        kind:
+          type: string
+        meow:
+          type: boolean
+    Dog:
+      type: object
+      properties:
+        kind:
+          type: string
+        bark:
+          type: boolean
+    NoItems:
+      type: object
+      properties:
+        list:
+          type: array
+          items: false`
+
+func TestSanitize_Request(t *testing.T) {
+	model := buildSchemaFromYAML(t, sanitizeSpec)
+	schema := getSchema(t, model, "User")
+
+	data := map[string]any{
+		"id":       "123",
+		"name":     "dave",
+		"password": "secret",
+		"extra":    "remove me",
+		"address":  map[string]any{"city": "Springfield", "zip": "12345"},
+		"tags": []any{
+			map[string]any{"label": "a", "color": "red"},
+			map[string]any{"label": "b"},
+		},
+	}
+
+	result := SanitizeBody(schema, data, DirectionRequest, config.NewValidationOptions(), 3.1)
+
+	assert.Equal(t, map[string]any{
+		"name":     "dave",
+		"password": "secret",
+		"address":  map[string]any{"city": "Springfield"},
+		"tags": []any{
+			map[string]any{"label": "a"},
+			map[string]any{"label": "b"},
+		},
+	}, result.Data)
+	assert.Len(t, result.Removed, 4)
+
+	// the input is left untouched
+	assert.Equal(t, "remove me", data["extra"])
+	assert.Equal(t, "red", data["tags"].([]any)[0].(map[string]any)["color"])
+}
+
+func TestSanitize_Response(t *testing.T) {
+	model := buildSchemaFromYAML(t, sanitizeSpec)
+	schema := getSchema(t, model, "User")
+
+	data := map[string]any{"id": "123", "name": "dave", "password": "secret"}
+
+	result := SanitizeBody(schema, data, DirectionResponse, config.NewValidationOptions(), 3.1)
+
+	assert.Equal(t, map[string]any{"id": "123", "name": "dave"}, result.Data)
+	require.Len(t, result.Removed, 1)
+	assert.Equal(t, TypeWriteOnlyProperty, result.Removed[0].Type)
+}
+
+func TestSanitize_IgnorePaths(t *testing.T) {
+	model := buildSchemaFromYAML(t, sanitizeSpec)
+	schema := getSchema(t, model, "User")
+
+	data := map[string]any{"name": "dave", "metadata": map[string]any{"trace": "abc"}, "extra": true}
+	opts := config.NewValidationOptions(config.WithStrictIgnorePaths("$.body.metadata.*"))
+
+	result := SanitizeBody(schema, data, DirectionRequest, opts, 3.1)
+
+	assert.Equal(t, map[string]any{"name": "dave", "metadata": map[string]any{"trace": "abc"}}, result.Data)
+}
+
+func TestSanitize_Polymorphic(t *testing.T) {
+	model := buildSchemaFromYAML(t, sanitizeSpec)
+	schema := getSchema(t, model, "Pet")
+
+	data := map[string]any{"kind": "Dog", "bark": true, "meow": true}
+
+	result := SanitizeBody(schema, data, DirectionRequest, config.NewValidationOptions(), 3.1)
+
+	assert.Equal(t, map[string]any{"kind": "Dog", "bark": true}, result.Data)
+}
+
+func TestSanitize_ArrayItems(t *testing.T) {
+	model := buildSchemaFromYAML(t, sanitizeSpec)
+	schema := getSchema(t, model, "NoItems")
+
+	data := map[string]any{"list": []any{"a", "b"}}
+
+	result := SanitizeBody(schema, data, DirectionRequest, config.NewValidationOptions(), 3.1)
+
+	assert.Equal(t, map[string]any{"list": []any{}}, result.Data)
+	assert.Len(t, result.Removed, 2)
+}
+
+func TestSanitize_Clean(t *testing.T) {
+	model := buildSchemaFromYAML(t, sanitizeSpec)
+	schema := getSchema(t, model, "User")
+
+	data := map[string]any{"name": "dave"}
+	result := SanitizeBody(schema, data, DirectionRequest, nil, 3.1)
+
+	assert.Equal(t, data, result.Data)
+	assert.Empty(t, result.Removed)
+
+	result = SanitizeBody(nil, data, DirectionRequest, nil, 3.1)
+	assert.Equal(t, data, result.Data)
+}
+
+func TestSanitize_KeepsRejectFlags(t *testing.T) {
+	model := buildSchemaFromYAML(t, sanitizeSpec)
+	schema := getSchema(t, model, "User")
+	opts := config.NewValidationOptions()
+
+	v := NewValidator(opts, 3.1)
+	v.Sanitize(Input{Schema: schema, Data: map[string]any{"id": "1"}, BasePath: "$.body"})
+
+	assert.Same(t, opts, v.options)
+	assert.False(t, opts.StrictRejectReadOnly)
+
+	// readOnly properties are dropped without options too.
+	result := SanitizeBody(schema, map[string]any{"id": "1", "name": "dave"}, DirectionRequest, nil, 3.1)
+	assert.Equal(t, map[string]any{"name": "dave"}, result.Data)
+}
+
+func TestSanitize_ConcurrentWithValidate(t *testing.T) {
+	model := buildSchemaFromYAML(t, sanitizeSpec)
+	schema := getSchema(t, model, "User")
+	v := NewValidator(config.NewValidationOptions(config.WithStrictMode()), 3.1)
+	input := Input{Schema: schema, Data: map[string]any{"id": "1", "name": "dave"}, BasePath: "$.body"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				assert.Equal(t, map[string]any{"name": "dave"}, v.Sanitize(input).Data)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				// sanitizing never makes validation reject readOnly properties.
+				assert.True(t, v.Validate(input).Valid)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSanitizeQueryParams(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/pets?limit=10&debug=true&trace=1", nil)
+	opts := config.NewValidationOptions(config.WithStrictMode())
+
+	undeclared := ValidateQueryParams(request, nil, opts)
+	require.Len(t, undeclared, 3)
+
+	SanitizeQueryParams(request.URL, undeclared[:2])
+	assert.Len(t, request.URL.Query(), 1)
+
+	SanitizeQueryParams(request.URL, undeclared)
+	assert.Empty(t, request.URL.RawQuery)
+
+	SanitizeQueryParams(nil, undeclared)
+}
+
+func TestSanitizeQueryParams_KeepsRawValues(t *testing.T) {
+	u, err := url.Parse("https://things.com/pets?z=1&tag=a%20b&debug=true&tag=c+d&filter%5Bname%5D=%E2%9C%93&de%62ug=1&a=")
+	require.NoError(t, err)
+
+	SanitizeQueryParams(u, []UndeclaredValue{{Type: "query", Name: "debug"}, {Type: "header", Name: "z"}})
+	assert.Equal(t, "z=1&tag=a%20b&tag=c+d&filter%5Bname%5D=%E2%9C%93&a=", u.RawQuery)
+
+	// nothing to remove leaves the query as it is.
+	SanitizeQueryParams(u, []UndeclaredValue{{Type: "cookie", Name: "tag"}})
+	assert.Equal(t, "z=1&tag=a%20b&tag=c+d&filter%5Bname%5D=%E2%9C%93&a=", u.RawQuery)
+}
+
+func TestSanitizeHeaders(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("X-Debug", "true")
+	headers.Set("Content-Type", "application/json")
+	opts := config.NewValidationOptions(config.WithStrictMode())
+
+	SanitizeHeaders(headers, ValidateRequestHeaders(headers, nil, nil, opts))
+
+	assert.Empty(t, headers.Get("X-Debug"))
+	assert.Equal(t, "application/json", headers.Get("Content-Type"))
+
+	SanitizeHeaders(nil, nil)
+}
+
+func TestSanitizeCookies(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/pets", nil)
+	request.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+	request.AddCookie(&http.Cookie{Name: "tracker", Value: "xyz"})
+	opts := config.NewValidationOptions(config.WithStrictMode())
+
+	undeclared := ValidateCookies(request, nil, opts)
+	require.Len(t, undeclared, 2)
+
+	SanitizeCookies(request.Header, undeclared[1:])
+	require.Len(t, request.Cookies(), 1)
+
+	SanitizeCookies(request.Header, ValidateCookies(request, nil, opts))
+	assert.Empty(t, request.Header.Get("Cookie"))
+
+	SanitizeCookies(request.Header, nil)
+	SanitizeCookies(nil, undeclared)
+}
+
+func TestSanitizeCookies_KeepsRawValues(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/pets", nil)
+	request.Header.Set("Cookie", `session="a b,c"; tracker=xyz; prefs={"theme":"dark"}`)
+
+	SanitizeCookies(request.Header, []UndeclaredValue{{Type: "cookie", Name: "tracker"}})
+	assert.Equal(t, `session="a b,c"; prefs={"theme":"dark"}`, request.Header.Get("Cookie"))
+}
+
+func TestSanitize_CancelledContext(t *testing.T) {
+	model := buildSchemaFromYAML(t, sanitizeSpec)
+	schema := getSchema(t, model, "User")
+	options := config.NewValidationOptions(config.WithStrictMode())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	data := map[string]any{"name": "dave", "extra": "remove me"}
+	input := Input{
+		Schema:    schema,
+		Data:      data,
+		Direction: DirectionRequest,
+		Options:   options,
+		BasePath:  "$.body",
+		Version:   3.1,
+		Context:   ctx,
+	}
+
+	validated := NewValidator(options, 3.1).Validate(input)
+	assert.ErrorIs(t, validated.Err, context.Canceled)
+	assert.Empty(t, validated.UndeclaredValues)
+
+	// a partial traversal leaves the data alone.
+	result := NewValidator(options, 3.1).Sanitize(input)
+	assert.Equal(t, data, result.Data)
+	assert.Empty(t, result.Removed)
+
+	input.Context = context.Background()
+	validated = NewValidator(options, 3.1).Validate(input)
+	assert.NoError(t, validated.Err)
+	assert.Len(t, validated.UndeclaredValues, 1)
+}
@@ -5,6 +5,8 @@ package strict
 
 import (
 	"github.com/pb33f/libopenapi/datamodel/high/base"
+
+	"github.com/pb33f/libopenapi-validator/policy"
 )
 
 // validateValue is the main entry point for validating a value against a schema.
@@ -14,7 +16,12 @@ func (v *Validator) validateValue(ctx *traversalContext, schema *base.Schema, da
 		return nil
 	}
 
-	if ctx.shouldIgnore() {
+	if ctx.cancelled() || ctx.shouldIgnore() {
+		return nil
+	}
+
+	// a schema can opt itself, and everything below it, out of strict mode.
+	if policy.StrictDisabled(schema) {
 		return nil
 	}
 
@@ -90,7 +97,7 @@ func (v *Validator) validateObject(ctx *traversalContext, schema *base.Schema, d
 		if propProxy != nil {
 			propSchema := propProxy.Schema()
 			if propSchema != nil {
-				if violation, ok := v.checkReadWriteOnlyViolation(propPath, propName, propValue, propSchema, ctx.direction); ok {
+				if violation, ok := v.checkReadWriteOnlyViolation(propPath, propName, propValue, propSchema, ctx); ok {
 					undeclared = append(undeclared, violation)
 					continue
 				}
@@ -194,7 +201,7 @@ func (v *Validator) recurseIntoDeclaredProperties(ctx *traversalContext, schema
 
 			propSchema := propProxy.Schema()
 			if propSchema != nil {
-				if violation, ok := v.checkReadWriteOnlyViolation(propPath, propName, propValue, propSchema, ctx.direction); ok {
+				if violation, ok := v.checkReadWriteOnlyViolation(propPath, propName, propValue, propSchema, ctx); ok {
 					undeclared = append(undeclared, violation)
 					continue
 				}
@@ -229,7 +236,7 @@ func (v *Validator) recurseIntoDeclaredProperties(ctx *traversalContext, schema
 
 			propSchema := propProxy.Schema()
 			if propSchema != nil {
-				if violation, ok := v.checkReadWriteOnlyViolation(propPath, propName, propValue, propSchema, ctx.direction); ok {
+				if violation, ok := v.checkReadWriteOnlyViolation(propPath, propName, propValue, propSchema, ctx); ok {
 					undeclared = append(undeclared, violation)
 					continue
 				}
@@ -12,12 +12,18 @@
 //
 // # Key Features
 //
-//   - Detects undeclared properties in request/response bodies (JSON only)
+//   - Detects undeclared properties in request/response bodies (JSON, XML and urlencoded)
 //   - Detects undeclared query parameters, headers, and cookies
 //   - Supports ignore paths with glob patterns (e.g., "$.body.metadata.*")
 //   - Handles polymorphic schemas (oneOf/anyOf) via per-branch validation
 //   - Respects readOnly/writeOnly based on request vs response direction
 //   - Configurable header ignore list with sensible defaults
+//   - Sanitizing mode that removes undeclared values instead of reporting them
+//
+// XML and urlencoded bodies are validated after conversion to JSON compatible data, so
+// StrictIgnorePaths always match the JSONPath of a value. Undeclared values in those bodies
+// also carry a Location in the original payload format: an XPath for XML elements and
+// attributes, or the form key for urlencoded fields.
 //
 // # Known Limitations
 //
@@ -77,6 +83,11 @@ type UndeclaredValue struct {
 	// examples: "$.body.user.extra", "$.body['a.b'].value", "$.query.debug"
 	Path string
 
+	// Location is where the value was found in the original body when it was not JSON,
+	// see BodyFormat. examples: "/pet/@id", "/pet/tags/tag[2]", "address[city]".
+	// empty for JSON bodies, parameters, headers and cookies.
+	Location string
+
 	// Name is the property, parameter, header, or cookie name.
 	Name string
 
@@ -200,12 +211,23 @@ type Input struct {
 	// Version is the OpenAPI version (3.0 or 3.1).
 	// affects nullable handling in schema matching.
 	Version float32
+
+	// Format is the format the body was decoded from, defaults to BodyFormatJSON.
+	// used to report the Location of undeclared values in XML and urlencoded bodies.
+	Format BodyFormat
+
+	// Context stops traversal early when it is done. Optional, defaults to no cancellation.
+	Context context.Context
 }
 
 // Result contains the output of strict validation.
 type Result struct {
 	Valid bool
 
+	// Err is the error of Input.Context when traversal was stopped before it completed.
+	// UndeclaredValues only holds what was found before it stopped.
+	Err error
+
 	// UndeclaredValues lists all undeclared properties, parameters,
 	// headers, or cookies found during validation.
 	UndeclaredValues []UndeclaredValue
@@ -239,6 +261,13 @@ type traversalContext struct {
 
 	// path is the current instance path being validated.
 	path string
+
+	// done is closed when traversal should stop, nil when it can't be cancelled.
+	done <-chan struct{}
+
+	// rejectReadWriteOnly reports readOnly and writeOnly properties whatever the reject flags
+	// of the options are, as sanitizing always drops them.
+	rejectReadWriteOnly bool
 }
 
 // newTraversalContext creates a new context for schema traversal.
@@ -262,6 +291,22 @@ func (c *traversalContext) withPath(path string) *traversalContext {
 		direction:   c.direction,
 		ignorePaths: c.ignorePaths,
 		path:        path,
+		done:        c.done,
+
+		rejectReadWriteOnly: c.rejectReadWriteOnly,
+	}
+}
+
+// cancelled checks if the context of the validation is done.
+func (c *traversalContext) cancelled() bool {
+	if c.done == nil {
+		return false
+	}
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
 	}
 }
 
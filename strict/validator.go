@@ -5,6 +5,7 @@ package strict
 
 import (
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/pb33f/libopenapi/datamodel/high/base"
@@ -20,6 +21,12 @@ import (
 // would normally allow them. This is useful for API governance scenarios
 // where you want to ensure clients only send explicitly documented properties.
 func (v *Validator) Validate(input Input) *Result {
+	return v.validate(input, false)
+}
+
+// validate runs Validate, reporting readOnly and writeOnly properties regardless of the options
+// when rejectReadWriteOnly is set.
+func (v *Validator) validate(input Input, rejectReadWriteOnly bool) *Result {
 	result := &Result{Valid: true}
 
 	if input.Schema == nil || input.Data == nil {
@@ -27,9 +34,16 @@ func (v *Validator) Validate(input Input) *Result {
 	}
 
 	ctx := newTraversalContext(input.Direction, v.compiledIgnorePaths, input.BasePath)
+	ctx.rejectReadWriteOnly = rejectReadWriteOnly
+	if input.Context != nil {
+		ctx.done = input.Context.Done()
+	}
 
-	undeclared := v.validateValue(ctx, input.Schema, input.Data)
+	undeclared := locate(v.validateValue(ctx, input.Schema, input.Data), input)
 
+	if input.Context != nil && input.Context.Err() != nil {
+		result.Err = input.Context.Err()
+	}
 	if len(undeclared) > 0 {
 		result.Valid = false
 		result.UndeclaredValues = undeclared
@@ -62,8 +76,19 @@ func ValidateQueryParams(
 	if request == nil || options == nil || !options.StrictMode {
 		return nil
 	}
+	return ValidateQueryValues(request.URL.Query(), declaredParams, options)
+}
 
-	v := NewValidator(options, 3.2)
+// ValidateQueryValues checks for undeclared parameters in the parsed query of a request, in the same way as
+// ValidateQueryParams.
+func ValidateQueryValues(
+	query url.Values,
+	declaredParams []*v3.Parameter,
+	options *config.ValidationOptions,
+) []UndeclaredValue {
+	if options == nil || !options.StrictMode {
+		return nil
+	}
 
 	// build set of declared query params (case-sensitive)
 	declared := make(map[string]bool)
@@ -72,11 +97,28 @@ func ValidateQueryParams(
 			declared[param.Name] = true
 		}
 	}
+	return ValidateDeclaredQueryParams(query, declared, getParamNames(declaredParams, "query"), options)
+}
+
+// ValidateDeclaredQueryParams checks for undeclared parameters in the parsed query of a request, against a set
+// of declared names built ahead of time, such as the names in the validation plan of an operation.
+// declaredNames are reported as the declared properties of undeclared parameters.
+func ValidateDeclaredQueryParams(
+	query url.Values,
+	declared map[string]bool,
+	declaredNames []string,
+	options *config.ValidationOptions,
+) []UndeclaredValue {
+	if options == nil || !options.StrictMode {
+		return nil
+	}
+
+	v := NewValidator(options, 3.2)
 
 	var undeclared []UndeclaredValue
 
 	// check each query parameter in the request
-	for paramName := range request.URL.Query() {
+	for paramName := range query {
 		if !declared[paramName] {
 			// build path using proper notation for special characters
 			path := buildPath("$.query", paramName)
@@ -85,7 +127,7 @@ func ValidateQueryParams(
 			}
 
 			undeclared = append(undeclared,
-				newUndeclaredParam(path, paramName, request.URL.Query().Get(paramName), "query", getParamNames(declaredParams, "query"), DirectionRequest))
+				newUndeclaredParam(path, paramName, query.Get(paramName), "query", declaredNames, DirectionRequest))
 		}
 	}
 
@@ -109,8 +151,6 @@ func ValidateRequestHeaders(
 		return nil
 	}
 
-	v := NewValidator(options, 3.2)
-
 	// build set of declared headers (case-insensitive)
 	declared := make(map[string]bool)
 	for _, param := range declaredParams {
@@ -123,6 +163,23 @@ func ValidateRequestHeaders(
 	for _, h := range securityHeaders {
 		declared[strings.ToLower(h)] = true
 	}
+	return ValidateDeclaredRequestHeaders(headers, declared, getParamNames(declaredParams, "header"), options)
+}
+
+// ValidateDeclaredRequestHeaders checks for undeclared headers in an HTTP request, against a set of lowercase
+// declared names built ahead of time, including the headers of security schemes. declaredNames are reported as
+// the declared properties of undeclared headers.
+func ValidateDeclaredRequestHeaders(
+	headers http.Header,
+	declared map[string]bool,
+	declaredNames []string,
+	options *config.ValidationOptions,
+) []UndeclaredValue {
+	if headers == nil || options == nil || !options.StrictMode {
+		return nil
+	}
+
+	v := NewValidator(options, 3.2)
 
 	var undeclared []UndeclaredValue
 
@@ -147,7 +204,7 @@ func ValidateRequestHeaders(
 		}
 
 		undeclared = append(undeclared,
-			newUndeclaredParam(path, headerName, headers.Get(headerName), "header", getParamNames(declaredParams, "header"), DirectionRequest))
+			newUndeclaredParam(path, headerName, headers.Get(headerName), "header", declaredNames, DirectionRequest))
 	}
 
 	return undeclared
@@ -162,8 +219,19 @@ func ValidateCookies(
 	if request == nil || options == nil || !options.StrictMode {
 		return nil
 	}
+	return ValidateCookieValues(request.Cookies(), declaredParams, options)
+}
 
-	v := NewValidator(options, 3.2)
+// ValidateCookieValues checks for undeclared cookies among the parsed cookies of a request, in the same way as
+// ValidateCookies.
+func ValidateCookieValues(
+	cookies []*http.Cookie,
+	declaredParams []*v3.Parameter,
+	options *config.ValidationOptions,
+) []UndeclaredValue {
+	if options == nil || !options.StrictMode {
+		return nil
+	}
 
 	// build set of declared cookies
 	declared := make(map[string]bool)
@@ -172,11 +240,27 @@ func ValidateCookies(
 			declared[param.Name] = true
 		}
 	}
+	return ValidateDeclaredCookies(cookies, declared, getParamNames(declaredParams, "cookie"), options)
+}
+
+// ValidateDeclaredCookies checks for undeclared cookies among the parsed cookies of a request, against a set of
+// declared names built ahead of time. declaredNames are reported as the declared properties of undeclared cookies.
+func ValidateDeclaredCookies(
+	cookies []*http.Cookie,
+	declared map[string]bool,
+	declaredNames []string,
+	options *config.ValidationOptions,
+) []UndeclaredValue {
+	if options == nil || !options.StrictMode {
+		return nil
+	}
+
+	v := NewValidator(options, 3.2)
 
 	var undeclared []UndeclaredValue
 
 	// check each cookie in the request
-	for _, cookie := range request.Cookies() {
+	for _, cookie := range cookies {
 		if !declared[cookie.Name] {
 			// build path using proper notation for special characters
 			path := buildPath("$.cookies", cookie.Name)
@@ -185,7 +269,7 @@ func ValidateCookies(
 			}
 
 			undeclared = append(undeclared,
-				newUndeclaredParam(path, cookie.Name, cookie.Value, "cookie", getParamNames(declaredParams, "cookie"), DirectionRequest))
+				newUndeclaredParam(path, cookie.Name, cookie.Value, "cookie", declaredNames, DirectionRequest))
 		}
 	}
 
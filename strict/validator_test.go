@@ -1459,6 +1459,38 @@ func TestValidateQueryParams_NilInputs(t *testing.T) {
 	assert.Nil(t, ValidateQueryParams(req, nil, optsNoStrict))
 }
 
+func TestValidateDeclared(t *testing.T) {
+	opts := config.NewValidationOptions(config.WithStrictMode())
+	declaredNames := []string{"limit"}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/test?limit=10&extra=undeclared", nil)
+	req.AddCookie(&http.Cookie{Name: "limit", Value: "1"})
+	req.AddCookie(&http.Cookie{Name: "tracking", Value: "undeclared"})
+	req.Header.Set("Limit", "10")
+	req.Header.Set("X-API-Key", "secret")
+	req.Header.Set("X-Extra", "undeclared")
+
+	undeclared := ValidateDeclaredQueryParams(req.URL.Query(), map[string]bool{"limit": true}, declaredNames, opts)
+	assert.Len(t, undeclared, 1)
+	assert.Equal(t, "extra", undeclared[0].Name)
+	assert.Equal(t, declaredNames, undeclared[0].DeclaredProperties)
+
+	undeclared = ValidateDeclaredCookies(req.Cookies(), map[string]bool{"limit": true}, declaredNames, opts)
+	assert.Len(t, undeclared, 1)
+	assert.Equal(t, "tracking", undeclared[0].Name)
+
+	// header sets are lowercase, and hold the headers of security schemes too.
+	undeclared = ValidateDeclaredRequestHeaders(req.Header, map[string]bool{"limit": true, "x-api-key": true}, declaredNames, opts)
+	assert.Len(t, undeclared, 1)
+	assert.Equal(t, "X-Extra", undeclared[0].Name)
+
+	assert.Nil(t, ValidateDeclaredQueryParams(req.URL.Query(), nil, nil, config.NewValidationOptions()))
+	assert.Nil(t, ValidateDeclaredCookies(nil, nil, nil, opts))
+	assert.Nil(t, ValidateQueryValues(req.URL.Query(), nil, nil))
+	assert.Len(t, ValidateCookieValues(req.Cookies(), nil, opts), 2)
+	assert.Nil(t, ValidateDeclaredRequestHeaders(req.Header, nil, nil, nil))
+}
+
 func TestValidateCookies_Basic(t *testing.T) {
 	opts := config.NewValidationOptions(config.WithStrictMode())
 
@@ -6900,6 +6932,6 @@ func TestStrictValidator_CheckReadWriteOnlyViolation_NilSchema(t *testing.T) {
 	opts := config.NewValidationOptions(config.WithStrictMode(), config.WithStrictRejectReadOnly())
 	v := NewValidator(opts, 3.1)
 
-	_, ok := v.checkReadWriteOnlyViolation("$.body.x", "x", "val", nil, DirectionRequest)
+	_, ok := v.checkReadWriteOnlyViolation("$.body.x", "x", "val", nil, newTraversalContext(DirectionRequest, nil, "$.body"))
 	assert.False(t, ok)
 }
@@ -4,8 +4,11 @@
 package validator
 
 import (
+	"context"
 	"net/http"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/pb33f/libopenapi"
@@ -15,10 +18,13 @@ import (
 
 	"github.com/pb33f/libopenapi-validator/cache"
 	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/defaults"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
 	"github.com/pb33f/libopenapi-validator/parameters"
 	"github.com/pb33f/libopenapi-validator/paths"
+	"github.com/pb33f/libopenapi-validator/policy"
 	"github.com/pb33f/libopenapi-validator/radix"
 	"github.com/pb33f/libopenapi-validator/requests"
 	"github.com/pb33f/libopenapi-validator/responses"
@@ -55,7 +61,8 @@ type Validator interface {
 	// The path, query, cookie and header parameters and request and response body are validated.
 	ValidateHttpRequestResponse(request *http.Request, response *http.Response) (bool, []*errors.ValidationError)
 
-	// ValidateDocument will validate an OpenAPI 3+ document against the 3.0 or 3.1 OpenAPI 3+ specification
+	// ValidateDocument will validate an OpenAPI 3+ document against the 3.0 or 3.1 OpenAPI 3+ specification.
+	// When config.WithPathTemplateAnalysis is set, path template findings from paths.AnalyzePathTemplates are included.
 	ValidateDocument() (bool, []*errors.ValidationError)
 
 	// GetParameterValidator will return a parameters.ParameterValidator instance used to validate parameters
@@ -74,6 +81,60 @@ type Validator interface {
 	Release()
 }
 
+// MessageValidator validates transport-neutral message.Request and message.Response values against an OpenAPI 3+
+// document. Messages are validated directly, without being converted to *http.Request or *http.Response first.
+// Adapters for net/http, API Gateway proxy events and raw HTTP/1.1 messages are available in the message package.
+// The validators created by NewValidator implement it, as do ShadowValidator and ReloadableValidator:
+//
+//	messageValidator := v.(validator.MessageValidator)
+type MessageValidator interface {
+	// ValidateMessage will validate a message.Request against an OpenAPI 3+ document, in the same way as
+	// ValidateHttpRequest.
+	ValidateMessage(request message.Request) (bool, []*errors.ValidationError)
+
+	// ValidateMessageResponse will validate a message.Response against an OpenAPI 3+ document, in the same way
+	// as ValidateHttpResponse.
+	ValidateMessageResponse(request message.Request, response message.Response) (bool, []*errors.ValidationError)
+}
+
+// ContextValidator validates requests and responses with a context, stopping early when it is cancelled or its
+// deadline passes. Only the context passed in is checked, the context of the *http.Request is not. The validators
+// created by NewValidator implement it, as do ShadowValidator and ReloadableValidator:
+//
+//	contextValidator := v.(validator.ContextValidator)
+type ContextValidator interface {
+	// ValidateHttpRequestContext will validate an *http.Request object against an OpenAPI 3+ document, in the same
+	// way as ValidateHttpRequest, stopping early when ctx is cancelled or its deadline passes. A stopped validation
+	// returns a single error with a ValidationType of helpers.ContextValidation, see errors.IsCancelled.
+	ValidateHttpRequestContext(ctx context.Context, request *http.Request) (bool, []*errors.ValidationError)
+
+	// ValidateHttpResponseContext will validate an *http.Response object against an OpenAPI 3+ document, in the
+	// same way as ValidateHttpResponse, stopping early when ctx is cancelled or its deadline passes.
+	ValidateHttpResponseContext(ctx context.Context, request *http.Request, response *http.Response) (bool, []*errors.ValidationError)
+}
+
+// ResultValidator validates requests and responses and returns a ValidationResult, with the outcome and timing of
+// each phase, rather than a flat list of errors. The validators created by NewValidator implement it, as do
+// ShadowValidator and ReloadableValidator:
+//
+//	resultValidator := v.(validator.ResultValidator)
+type ResultValidator interface {
+	// ValidateHttpRequestResult will validate an *http.Request object against an OpenAPI 3+ document, in the same way
+	// as ValidateHttpRequestSync, and return a ValidationResult with the outcome and timing of each phase, the
+	// matched operation, and warnings kept apart from errors.
+	ValidateHttpRequestResult(request *http.Request) *ValidationResult
+
+	// ValidateHttpResponseResult will validate an *http.Response object against an OpenAPI 3+ document, in the same
+	// way as ValidateHttpResponse, and return a ValidationResult with the outcome of each phase.
+	ValidateHttpResponseResult(request *http.Request, response *http.Response) *ValidationResult
+}
+
+var (
+	_ MessageValidator = (*validator)(nil)
+	_ ContextValidator = (*validator)(nil)
+	_ ResultValidator  = (*validator)(nil)
+)
+
 // NewValidator will create a new Validator from an OpenAPI 3+ document
 func NewValidator(document libopenapi.Document, opts ...config.Option) (Validator, []error) {
 	m, errs := document.BuildV3Model()
@@ -95,11 +156,33 @@ func NewValidatorFromV3Model(m *v3.Document, opts ...config.Option) Validator {
 		options.PathTree = radix.BuildPathTree(m)
 	}
 
-	// warm the schema caches by pre-compiling all schemas in the document
-	// (warmSchemaCaches checks for nil cache and skips if disabled)
-	warmSchemaCaches(m, options)
+	// bind a persistent schema cache to the document, so warming loads the schemas it persisted
+	bindSchemaCache(m, options)
 
-	v := &validator{options: options, v3Model: m}
+	// plan the validation of each operation, requests walk their plan instead of deriving it again
+	if options.Plans == nil && !options.IsValidationPlansDisabled() {
+		options.Plans = buildPlans(m)
+	}
+
+	v := &validator{options: options, v3Model: m, policies: policy.NewResolver(m, options)}
+	v.sampler = newSampler(options, v.policies)
+
+	// warm the schema caches by pre-compiling all schemas in the document, or keep warming them in the
+	// background while requests compile the schemas they need on demand
+	// (warmSchemaCaches checks for nil cache and skips if disabled)
+	if options.WarmInBackground {
+		stop := make(chan struct{})
+		v.warmStop = stop
+		v.warming.Add(1)
+		go func() {
+			defer v.warming.Done()
+			runWarmTasks(warmTasks(m, options), options, stop)
+			options.Plans.Hold(options.SchemaCache)
+		}()
+	} else {
+		warmSchemaCaches(m, options)
+		options.Plans.Hold(options.SchemaCache)
+	}
 
 	// create a new parameter validator
 	v.paramValidator = parameters.NewParameterValidator(m, config.WithExistingOpts(options))
@@ -121,10 +204,27 @@ func (v *validator) Release() {
 	if v == nil {
 		return
 	}
+	if v.warmStop != nil {
+		close(v.warmStop)
+		v.warmStop = nil
+	}
+	v.warming.Wait()
 	releaseIfSupported(v.paramValidator)
 	releaseIfSupported(v.requestValidator)
 	releaseIfSupported(v.responseValidator)
+	v.validatorSets.Range(func(key, value any) bool {
+		value.(*operationValidators).release()
+		v.validatorSets.Delete(key)
+		return true
+	})
+	v.operationValidators.Range(func(key, value any) bool {
+		v.operationValidators.Delete(key)
+		return true
+	})
+	v.policies = nil
+	v.sampler = nil
 	if v.options != nil {
+		v.options.Plans.Release()
 		v.options.Release()
 		v.options = nil
 	}
@@ -169,51 +269,63 @@ func (v *validator) ValidateDocument() (bool, []*errors.ValidationError) {
 	if v.options != nil {
 		validationOpts = append(validationOpts, config.WithRegexEngine(v.options.RegexEngine))
 	}
-	return schema_validation.ValidateOpenAPIDocument(v.document, validationOpts...)
+	valid, validationErrors := schema_validation.ValidateOpenAPIDocument(v.document, validationOpts...)
+	if findings := policy.Validate(v.v3Model); len(findings) > 0 {
+		valid = false
+		validationErrors = append(validationErrors, findings...)
+	}
+	if v.options != nil && v.options.PathTemplateAnalysis {
+		if findings := paths.AnalyzePathTemplates(v.v3Model); len(findings) > 0 {
+			return false, append(validationErrors, findings...)
+		}
+	}
+	return valid, validationErrors
 }
 
 func (v *validator) ValidateHttpResponse(
 	request *http.Request,
 	response *http.Response,
 ) (bool, []*errors.ValidationError) {
-	var pathItem *v3.PathItem
-	var pathValue string
-	var errs []*errors.ValidationError
+	return v.ValidateMessageResponse(message.FromHTTPRequest(request), message.FromHTTPResponse(response))
+}
 
-	pathItem, errs, pathValue = paths.FindPath(request, v.v3Model, v.options)
+func (v *validator) ValidateMessageResponse(request message.Request, response message.Response) (bool, []*errors.ValidationError) {
+	pathItem, pathValue, errs, sampled := v.lookup(request)
+	if !sampled {
+		return true, nil
+	}
 	if pathItem == nil || errs != nil {
 		return false, errs
 	}
 
-	responseBodyValidator := v.responseValidator
-
 	// validate response
-	_, responseErrors := responseBodyValidator.ValidateResponseBodyWithPathItem(request, response, pathItem, pathValue)
-
-	if len(responseErrors) > 0 {
-		return false, responseErrors
-	}
-	return true, nil
+	return v.validateResponseWithPathItem(request, response, pathItem, pathValue)
 }
 
 func (v *validator) ValidateHttpRequestResponse(
 	request *http.Request,
 	response *http.Response,
 ) (bool, []*errors.ValidationError) {
-	var pathItem *v3.PathItem
-	var pathValue string
-	var errs []*errors.ValidationError
+	return v.validateRequestResponse(message.FromHTTPRequest(request), message.FromHTTPResponse(response))
+}
 
-	pathItem, errs, pathValue = paths.FindPath(request, v.v3Model, v.options)
+// validateRequestResponse validates both the request and the response of an exchange.
+func (v *validator) validateRequestResponse(request message.Request, response message.Response) (bool, []*errors.ValidationError) {
+	// the request and the response of the exchange are sampled together.
+	pathItem, pathValue, errs, sampled := v.lookup(request)
+	if !sampled {
+		return true, nil
+	}
 	if pathItem == nil || errs != nil {
 		return false, errs
 	}
 
-	responseBodyValidator := v.responseValidator
-
 	// validate request and response
-	_, requestErrors := v.ValidateHttpRequestWithPathItem(request, pathItem, pathValue)
-	_, responseErrors := responseBodyValidator.ValidateResponseBodyWithPathItem(request, response, pathItem, pathValue)
+	_, requestErrors := v.validateRequestWithPathItem(request, pathItem, pathValue)
+	if errors.IsCancelled(requestErrors) {
+		return false, requestErrors
+	}
+	_, responseErrors := v.validateResponseWithPathItem(request, response, pathItem, pathValue)
 
 	if len(requestErrors) > 0 || len(responseErrors) > 0 {
 		return false, append(requestErrors, responseErrors...)
@@ -221,194 +333,274 @@ func (v *validator) ValidateHttpRequestResponse(
 	return true, nil
 }
 
+func (v *validator) ValidateHttpRequestContext(ctx context.Context, request *http.Request) (bool, []*errors.ValidationError) {
+	return v.ValidateMessage(message.WithContext(ctx, message.FromHTTPRequest(request)))
+}
+
+func (v *validator) ValidateHttpResponseContext(
+	ctx context.Context,
+	request *http.Request,
+	response *http.Response,
+) (bool, []*errors.ValidationError) {
+	return v.ValidateMessageResponse(message.WithContext(ctx, message.FromHTTPRequest(request)), message.FromHTTPResponse(response))
+}
+
+func (v *validator) validateResponseWithPathItem(
+	request message.Request,
+	response message.Response,
+	pathItem *v3.PathItem,
+	pathValue string,
+) (bool, []*errors.ValidationError) {
+	result := v.responseResult(newResult(request), request, response, pathItem, pathValue)
+	return len(result.Errors) == 0, result.Errors
+}
+
 func (v *validator) ValidateHttpRequest(request *http.Request) (bool, []*errors.ValidationError) {
-	pathItem, errs, foundPath := paths.FindPath(request, v.v3Model, v.options)
+	return v.ValidateMessage(message.FromHTTPRequest(request))
+}
+
+func (v *validator) ValidateMessage(request message.Request) (bool, []*errors.ValidationError) {
+	pathItem, foundPath, errs, sampled := v.lookup(request)
+	if !sampled {
+		return true, nil
+	}
 	if len(errs) > 0 {
 		return false, errs
 	}
-	return v.ValidateHttpRequestWithPathItem(request, pathItem, foundPath)
+	return v.validateRequestWithPathItem(request, pathItem, foundPath)
 }
 
 func (v *validator) ValidateHttpRequestWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
-	// create a new parameter validator
-	paramValidator := v.paramValidator
-
-	// create a new request body validator
-	reqBodyValidator := v.requestValidator
-
-	// create some channels to handle async validation
-	doneChan := make(chan struct{})
-	errChan := make(chan []*errors.ValidationError)
-	controlChan := make(chan struct{})
-
-	// async param validation function.
-	parameterValidationFunc := func(control chan struct{}, errorChan chan []*errors.ValidationError) {
-		paramErrs := make(chan []*errors.ValidationError)
-		paramControlChan := make(chan struct{})
-		paramFunctionControlChan := make(chan struct{})
-		var paramValidationErrors []*errors.ValidationError
-
-		validations := []validationFunction{
-			paramValidator.ValidatePathParamsWithPathItem,
-			paramValidator.ValidateCookieParamsWithPathItem,
-			paramValidator.ValidateHeaderParamsWithPathItem,
-			paramValidator.ValidateQueryParamsWithPathItem,
-			paramValidator.ValidateSecurityWithPathItem,
-		}
+	return v.validateMessageWithPathItem(message.FromHTTPRequest(request), pathItem, pathValue)
+}
 
-		// listen for validation errors on parameters. everything will run async.
-		paramListener := func(control chan struct{}, errorChan chan []*errors.ValidationError) {
-			completedValidations := 0
-			for {
-				select {
-				case vErrs := <-errorChan:
-					paramValidationErrors = append(paramValidationErrors, vErrs...)
-				case <-control:
-					completedValidations++
-					if completedValidations == len(validations) {
-						paramFunctionControlChan <- struct{}{}
-						return
-					}
-				}
-			}
-		}
+// validateMessageWithPathItem samples a request for a path item, and validates it when it has been sampled.
+func (v *validator) validateMessageWithPathItem(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	if v.sampler.sample(request, pathItem, pathValue) == sampledOut {
+		return true, nil
+	}
+	return v.validateRequestWithPathItem(request, pathItem, pathValue)
+}
 
-		validateParamFunction := func(
-			control chan struct{},
-			errorChan chan []*errors.ValidationError,
-			validatorFunc validationFunction,
-		) {
-			valid, pErrs := validatorFunc(request, pathItem, pathValue)
-			if !valid {
-				errorChan <- pErrs
-			}
-			control <- struct{}{}
-		}
-		go paramListener(paramControlChan, paramErrs)
-		for i := range validations {
-			go validateParamFunction(paramControlChan, paramErrs, validations[i])
-		}
+// validateRequestWithPathItem validates a request that has been sampled. A large enough request body is
+// validated on the shared worker pool, in parallel with the parameters.
+func (v *validator) validateRequestWithPathItem(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	result := v.requestResult(newResult(request), request, pathItem, pathValue, true)
+	if len(result.Errors) == 0 {
+		return true, nil
+	}
+	// sort errors for deterministic ordering
+	sortValidationErrors(result.Errors)
+	return false, result.Errors
+}
 
-		// wait for all the validations to complete
-		<-paramFunctionControlChan
-		if len(paramValidationErrors) > 0 {
-			errorChan <- paramValidationErrors
-		}
+func (v *validator) ValidateHttpRequestSync(request *http.Request) (bool, []*errors.ValidationError) {
+	return v.validateMessageSync(message.FromHTTPRequest(request))
+}
 
-		// let runValidation know we are done with this part.
-		controlChan <- struct{}{}
+// validateMessageSync finds the path of a request and validates it, one phase after another.
+func (v *validator) validateMessageSync(request message.Request) (bool, []*errors.ValidationError) {
+	pathItem, foundPath, errs, sampled := v.lookup(request)
+	if !sampled {
+		return true, make([]*errors.ValidationError, 0)
 	}
-
-	requestBodyValidationFunc := func(control chan struct{}, errorChan chan []*errors.ValidationError) {
-		valid, pErrs := reqBodyValidator.ValidateRequestBodyWithPathItem(request, pathItem, pathValue)
-		if !valid {
-			errorChan <- pErrs
-		}
-		control <- struct{}{}
+	if len(errs) > 0 {
+		return false, errs
 	}
+	return v.validateRequestSyncWithPathItem(request, pathItem, foundPath)
+}
 
-	// build async functions
-	asyncFunctions := []validationFunctionAsync{
-		parameterValidationFunc,
-		requestBodyValidationFunc,
+func (v *validator) ValidateHttpRequestSyncWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	validated := message.FromHTTPRequest(request)
+	if v.sampler.sample(validated, pathItem, pathValue) == sampledOut {
+		return true, make([]*errors.ValidationError, 0)
 	}
+	return v.validateRequestSyncWithPathItem(validated, pathItem, pathValue)
+}
 
-	var validationErrors []*errors.ValidationError
+// validateRequestSyncWithPathItem validates a request that has been sampled, one phase after another.
+func (v *validator) validateRequestSyncWithPathItem(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	validationErrors := make([]*errors.ValidationError, 0)
+	result := v.requestResult(newResult(request), request, pathItem, pathValue, false)
+	validationErrors = append(validationErrors, result.Errors...)
+	return len(validationErrors) == 0, validationErrors
+}
 
-	// sit and wait for everything to report back.
-	go runValidation(controlChan, doneChan, errChan, &validationErrors, len(asyncFunctions))
+type validator struct {
+	options             *config.ValidationOptions
+	v3Model             *v3.Document
+	document            libopenapi.Document
+	paramValidator      parameters.ParameterValidator
+	requestValidator    requests.RequestBodyValidator
+	responseValidator   responses.ResponseBodyValidator
+	policies            *policy.Resolver
+	sampler             *sampler       // nil when every exchange is validated
+	operationValidators sync.Map       // *policy.Operation -> *operationValidators
+	validatorSets       sync.Map       // *config.ValidationOptions -> *operationValidators, built for a policy
+	warmStop            chan struct{}  // closed to stop background warming
+	warming             sync.WaitGroup // background warming, waited for on Release
+}
 
-	// run async functions
-	for i := range asyncFunctions {
-		go asyncFunctions[i](controlChan, errChan)
+// operationValidators are the validators used for an operation, built with the effective options of
+// its 'x-validation' policy. Operations with the same options share their validators, those without a policy
+// share the validators of the validator.
+type operationValidators struct {
+	policy            *policy.Operation
+	paramValidator    parameters.MessageParameterValidator
+	requestValidator  requests.MessageRequestBodyValidator
+	responseValidator responses.MessageResponseBodyValidator
+}
+
+// validatorsFor resolves the validation policy of the operation a request is for.
+func (v *validator) validatorsFor(request message.Request, pathItem *v3.PathItem) *operationValidators {
+	var operation *v3.Operation
+	if request != nil && pathItem != nil {
+		operation = helpers.OperationForMethod(request.Method(), pathItem)
+	}
+	if v.policies == nil {
+		return v.newOperationValidators(&policy.Operation{Options: v.options})
 	}
 
-	// wait for all the validations to complete
-	<-doneChan
+	resolved := v.policies.Resolve(pathItem, operation)
+	if cached, ok := v.operationValidators.Load(resolved); ok {
+		return cached.(*operationValidators)
+	}
+	ov := v.newOperationValidators(resolved)
+	if resolved.Options != v.options {
+		set := v.validatorSet(resolved)
+		ov.paramValidator, ov.requestValidator, ov.responseValidator = set.paramValidator, set.requestValidator, set.responseValidator
+	}
+	actual, _ := v.operationValidators.LoadOrStore(resolved, ov)
+	return actual.(*operationValidators)
+}
 
-	// sort errors for deterministic ordering (async validation can return errors in any order)
-	sortValidationErrors(validationErrors)
+// validatorSet returns the validators built with the options of a policy, building them the first time.
+func (v *validator) validatorSet(resolved *policy.Operation) *operationValidators {
+	if cached, ok := v.validatorSets.Load(resolved.Options); ok {
+		return cached.(*operationValidators)
+	}
+	set := &operationValidators{
+		paramValidator:    parameters.NewParameterValidator(v.v3Model, config.WithExistingOpts(resolved.Options)).(parameters.MessageParameterValidator),
+		requestValidator:  requests.NewRequestBodyValidator(v.v3Model, config.WithExistingOpts(resolved.Options)).(requests.MessageRequestBodyValidator),
+		responseValidator: responses.NewResponseBodyValidator(v.v3Model, config.WithExistingOpts(resolved.Options)).(responses.MessageResponseBodyValidator),
+	}
+	// a set built by a concurrent request is dropped, releasing it would clear the caches its options share.
+	actual, _ := v.validatorSets.LoadOrStore(resolved.Options, set)
+	return actual.(*operationValidators)
+}
 
-	return len(validationErrors) == 0, validationErrors
+func (ov *operationValidators) release() {
+	releaseIfSupported(ov.paramValidator)
+	releaseIfSupported(ov.requestValidator)
+	releaseIfSupported(ov.responseValidator)
 }
 
-func (v *validator) ValidateHttpRequestSync(request *http.Request) (bool, []*errors.ValidationError) {
-	pathItem, errs, foundPath := paths.FindPath(request, v.v3Model, v.options)
-	if len(errs) > 0 {
-		return false, errs
+// newOperationValidators returns the validators of the validator for an operation, which validate messages.
+func (v *validator) newOperationValidators(resolved *policy.Operation) *operationValidators {
+	return &operationValidators{
+		policy:            resolved,
+		paramValidator:    v.paramValidator.(parameters.MessageParameterValidator),
+		requestValidator:  v.requestValidator.(requests.MessageRequestBodyValidator),
+		responseValidator: v.responseValidator.(responses.MessageResponseBodyValidator),
 	}
-	return v.ValidateHttpRequestSyncWithPathItem(request, pathItem, foundPath)
 }
 
-func (v *validator) ValidateHttpRequestSyncWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
-	// create a new parameter validator
-	paramValidator := v.paramValidator
+// injectDefaults adds schema defaults to a request that passed validation, when default injection is enabled.
+func (v *validator) injectDefaults(request message.Request, pathItem *v3.PathItem, options *config.ValidationOptions) {
+	if options == nil || !options.InjectDefaults {
+		return
+	}
+	injected := defaults.Inject(request, pathItem, options, helpers.VersionToFloat(v.v3Model.Version))
+	if len(injected) > 0 && options.DefaultsInjected != nil {
+		options.DefaultsInjected(request, injected)
+	}
+}
 
-	// create a new request body validator
-	reqBodyValidator := v.requestValidator
+// applyPolicy drops validation errors of operations and parameters in 'warn' or 'off' mode.
+// errors in 'warn' mode are logged as warnings.
+func (v *validator) applyPolicy(resolved *policy.Operation, validationErrors []*errors.ValidationError) (bool, []*errors.ValidationError) {
+	kept, _ := v.splitByPolicy(resolved, validationErrors)
+	return len(kept) == 0, kept
+}
 
-	validationErrors := make([]*errors.ValidationError, 0)
+// splitByPolicy splits validation errors into those the policy enforces and those it only warns about,
+// dropping errors in 'off' mode. warnings are logged.
+func (v *validator) splitByPolicy(resolved *policy.Operation, validationErrors []*errors.ValidationError) (kept, warned []*errors.ValidationError) {
+	if resolved == nil || len(validationErrors) == 0 ||
+		(resolved.Policy.EffectiveMode() == policy.ModeEnforce && len(resolved.Parameters) == 0) {
+		return validationErrors, nil
+	}
 
-	paramValidationErrors := make([]*errors.ValidationError, 0)
-	for _, validateFunc := range []validationFunction{
-		paramValidator.ValidatePathParamsWithPathItem,
-		paramValidator.ValidateCookieParamsWithPathItem,
-		paramValidator.ValidateHeaderParamsWithPathItem,
-		paramValidator.ValidateQueryParamsWithPathItem,
-		paramValidator.ValidateSecurityWithPathItem,
-	} {
-		valid, pErrs := validateFunc(request, pathItem, pathValue)
-		if !valid {
-			paramValidationErrors = append(paramValidationErrors, pErrs...)
+	kept = make([]*errors.ValidationError, 0, len(validationErrors))
+	for _, validationError := range validationErrors {
+		mode := resolved.Policy.EffectiveMode()
+		if validationError.ValidationType == helpers.ParameterValidation {
+			// the sub type of a parameter error is the location of the parameter.
+			key := policy.NewParameterKey(validationError.ValidationSubType, validationError.ParameterName)
+			if paramMode, ok := resolved.Parameters[key]; ok {
+				mode = paramMode
+			}
 		}
+		switch mode {
+		case policy.ModeOff:
+			continue
+		case policy.ModeWarn:
+			if v.options != nil && v.options.Logger != nil {
+				v.options.Logger.Warn("validation error ignored by policy",
+					"message", validationError.Message,
+					"type", validationError.ValidationType,
+					"subType", validationError.ValidationSubType,
+					"requestPath", validationError.RequestPath,
+					"requestMethod", validationError.RequestMethod)
+			}
+			validationError.Severity = errors.SeverityWarning
+			warned = append(warned, validationError)
+			continue
+		}
+		kept = append(kept, validationError)
 	}
+	return kept, warned
+}
 
-	valid, pErrs := reqBodyValidator.ValidateRequestBodyWithPathItem(request, pathItem, pathValue)
-	if !valid {
-		paramValidationErrors = append(paramValidationErrors, pErrs...)
+// splitDeprecations splits deprecations into those reported as errors and warnings. warnings are logged.
+func (v *validator) splitDeprecations(found []*errors.ValidationError) (kept, warned []*errors.ValidationError) {
+	for _, validationError := range found {
+		if !validationError.IsWarning() {
+			kept = append(kept, validationError)
+			continue
+		}
+		if v.options != nil && v.options.Logger != nil {
+			v.options.Logger.Warn("deprecated API used",
+				"message", validationError.Message,
+				"subType", validationError.ValidationSubType,
+				"requestPath", validationError.RequestPath,
+				"requestMethod", validationError.RequestMethod)
+		}
+		warned = append(warned, validationError)
 	}
-
-	validationErrors = append(validationErrors, paramValidationErrors...)
-	return len(validationErrors) == 0, validationErrors
+	return kept, warned
 }
 
-type validator struct {
-	options           *config.ValidationOptions
-	v3Model           *v3.Document
-	document          libopenapi.Document
-	paramValidator    parameters.ParameterValidator
-	requestValidator  requests.RequestBodyValidator
-	responseValidator responses.ResponseBodyValidator
-}
-
-func runValidation(control, doneChan chan struct{},
-	errorChan chan []*errors.ValidationError,
-	validationErrors *[]*errors.ValidationError,
-	total int,
-) {
-	var validationLock sync.Mutex
-	completedValidations := 0
-	for {
-		select {
-		case vErrs := <-errorChan:
-			validationLock.Lock()
-			*validationErrors = append(*validationErrors, vErrs...)
-			validationLock.Unlock()
-		case <-control:
-			completedValidations++
-			if completedValidations == total {
-				doneChan <- struct{}{}
-				return
-			}
+// validationFunction validates one phase of a request.
+type validationFunction func(request message.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError)
+
+// parallelBody returns true when the body of a request is worth validating in parallel with its parameters.
+func parallelBody(request message.Request, options *config.ValidationOptions) bool {
+	if provider, ok := request.(message.HTTPRequestProvider); ok {
+		httpRequest := provider.HTTPRequest()
+		if httpRequest.Body == nil || httpRequest.Body == http.NoBody {
+			return false
 		}
+		return options.ParallelBody(httpRequest.ContentLength)
 	}
+	// the length of other messages is only known from their headers, reading the body would consume it.
+	contentLength, err := strconv.ParseInt(request.Header().Get(helpers.ContentLengthHeader), 10, 64)
+	if err != nil {
+		contentLength = -1
+	}
+	return options.ParallelBody(contentLength)
 }
 
-type (
-	validationFunction      func(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError)
-	validationFunctionAsync func(control chan struct{}, errorChan chan []*errors.ValidationError)
-)
-
 // sortValidationErrors sorts validation errors for deterministic ordering.
 // Errors are sorted by validation type first, then by message.
 func sortValidationErrors(errs []*errors.ValidationError) {
@@ -426,31 +618,58 @@ func warmSchemaCaches(
 	doc *v3.Document,
 	options *config.ValidationOptions,
 ) {
+	runWarmTasks(warmTasks(doc, options), options, nil)
+}
+
+// warmTask compiles one schema of the document into the schema cache.
+type warmTask struct {
+	method   string
+	path     string
+	location string
+	warm     func() error
+}
+
+// warmTasks lists the schemas of the operations chosen by the options, or of every operation.
+func warmTasks(doc *v3.Document, options *config.ValidationOptions) []warmTask {
 	// Skip warming if cache is nil (explicitly disabled via WithSchemaCache(nil))
-	if doc == nil || doc.Paths == nil || doc.Paths.PathItems == nil || options.SchemaCache == nil {
-		return
+	if doc == nil || doc.Paths == nil || doc.Paths.PathItems == nil || options == nil || options.SchemaCache == nil {
+		return nil
 	}
 
 	schemaCache := options.SchemaCache
 	version := helpers.VersionToFloat(doc.Version)
+	selected := warmOperationsSelected(options.WarmOperations)
+	var tasks []warmTask
 
 	// Walk through all paths and operations
 	for pathPair := doc.Paths.PathItems.First(); pathPair != nil; pathPair = pathPair.Next() {
+		pathValue := pathPair.Key()
 		pathItem := pathPair.Value()
+		pathSelected := false
 
 		// Get all operations for this path (handles all HTTP methods including OpenAPI 3.2+ extensions)
 		operations := pathItem.GetOperations()
 
 		for opPair := operations.First(); opPair != nil; opPair = opPair.Next() {
+			method := strings.ToUpper(opPair.Key())
 			operation := opPair.Value()
+			if operation == nil || !warmOperationSelected(selected, method, pathValue, operation.OperationId) {
+				continue
+			}
+			pathSelected = true
+			add := func(location string, warm func() error) {
+				tasks = append(tasks, warmTask{method: method, path: pathValue, location: location, warm: warm})
+			}
 
 			// Warm request body schemas
 			if operation.RequestBody != nil && operation.RequestBody.Content != nil {
 				for contentPair := operation.RequestBody.Content.First(); contentPair != nil; contentPair = contentPair.Next() {
 					mediaType := contentPair.Value()
 					if mediaType.Schema != nil {
-						warmMediaTypeSchema(mediaType, schemaCache, options, version,
-							schema_validation.SchemaValidationPurposeRequestBody)
+						add("requestBody "+contentPair.Key(), func() error {
+							return warmMediaTypeSchema(mediaType, schemaCache, options, version,
+								schema_validation.SchemaValidationPurposeRequestBody)
+						})
 					}
 				}
 			}
@@ -465,8 +684,10 @@ func warmSchemaCaches(
 							for contentPair := response.Content.First(); contentPair != nil; contentPair = contentPair.Next() {
 								mediaType := contentPair.Value()
 								if mediaType.Schema != nil {
-									warmMediaTypeSchema(mediaType, schemaCache, options, version,
-										schema_validation.SchemaValidationPurposeResponseBody)
+									add("response "+codePair.Key()+" "+contentPair.Key(), func() error {
+										return warmMediaTypeSchema(mediaType, schemaCache, options, version,
+											schema_validation.SchemaValidationPurposeResponseBody)
+									})
 								}
 							}
 						}
@@ -478,8 +699,10 @@ func warmSchemaCaches(
 					for contentPair := operation.Responses.Default.Content.First(); contentPair != nil; contentPair = contentPair.Next() {
 						mediaType := contentPair.Value()
 						if mediaType.Schema != nil {
-							warmMediaTypeSchema(mediaType, schemaCache, options, version,
-								schema_validation.SchemaValidationPurposeResponseBody)
+							add("response default "+contentPair.Key(), func() error {
+								return warmMediaTypeSchema(mediaType, schemaCache, options, version,
+									schema_validation.SchemaValidationPurposeResponseBody)
+							})
 						}
 					}
 				}
@@ -489,21 +712,109 @@ func warmSchemaCaches(
 			if operation.Parameters != nil {
 				for _, param := range operation.Parameters {
 					if param != nil {
-						warmParameterSchema(param, schemaCache, options, version)
+						add("parameter "+param.Name, func() error {
+							return warmParameterSchema(param, schemaCache, options, version)
+						})
 					}
 				}
 			}
 		}
 
 		// Warm path-level parameters
-		if pathItem.Parameters != nil {
+		if pathItem.Parameters != nil && pathSelected {
 			for _, param := range pathItem.Parameters {
 				if param != nil {
-					warmParameterSchema(param, schemaCache, options, version)
+					tasks = append(tasks, warmTask{path: pathValue, location: "parameter " + param.Name, warm: func() error {
+						return warmParameterSchema(param, schemaCache, options, version)
+					}})
 				}
 			}
 		}
 	}
+	return tasks
+}
+
+// warmOperationsSelected indexes the operations chosen by WithWarmOperations, nil selects every operation.
+func warmOperationsSelected(operations []string) map[string]struct{} {
+	if len(operations) == 0 {
+		return nil
+	}
+	selected := make(map[string]struct{}, len(operations))
+	for _, operation := range operations {
+		if method, pathValue, ok := strings.Cut(strings.TrimSpace(operation), " "); ok {
+			operation = strings.ToUpper(method) + " " + strings.TrimSpace(pathValue)
+		}
+		selected[operation] = struct{}{}
+	}
+	return selected
+}
+
+func warmOperationSelected(selected map[string]struct{}, method, pathValue, operationID string) bool {
+	if selected == nil {
+		return true
+	}
+	if _, ok := selected[method+" "+pathValue]; ok {
+		return true
+	}
+	_, ok := selected[operationID]
+	return ok && operationID != ""
+}
+
+// runWarmTasks runs the warm tasks on the number of workers set by the options, reporting progress after each
+// one, until they are done or stop is closed.
+func runWarmTasks(tasks []warmTask, options *config.ValidationOptions, stop <-chan struct{}) {
+	var progressFunc config.WarmProgressFunc
+	workers := 1
+	if options != nil {
+		progressFunc = options.WarmProgress
+		workers = max(options.WarmWorkers, 1)
+	}
+	workers = min(workers, max(len(tasks), 1))
+
+	var mu sync.Mutex
+	progress := config.WarmProgress{Total: len(tasks)}
+	report := func(task warmTask, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		progress.Completed++
+		if err != nil {
+			progress.Failed++
+		}
+		if progressFunc != nil {
+			update := progress
+			update.Method, update.Path, update.Location, update.Err = task.method, task.path, task.location, err
+			progressFunc(update)
+		}
+	}
+
+	queue := make(chan warmTask)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range queue {
+				report(task, task.warm())
+			}
+		}()
+	}
+
+feed:
+	for _, task := range tasks {
+		select {
+		case queue <- task:
+		case <-stop:
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if progressFunc != nil {
+		done := progress
+		done.Done = true
+		progressFunc(done)
+	}
 }
 
 // warmMediaTypeSchema warms the cache for a media type schema
@@ -513,32 +824,24 @@ func warmMediaTypeSchema(
 	options *config.ValidationOptions,
 	version float32,
 	purpose schema_validation.SchemaValidationPurpose,
-) {
+) error {
 	if mediaType != nil && mediaType.Schema != nil {
 		schema := mediaType.Schema.Schema()
 		if schema == nil || schema.GoLow() == nil {
-			return
+			return nil
 		}
 		hash := schema_validation.SchemaCacheKey(schema.GoLow().Hash(), version, purpose)
 
 		if _, exists := schemaCache.Load(hash); !exists {
-			compiled, err := schema_validation.CompileSchemaForValidation(
-				schema,
-				purpose,
-				options,
-				version,
-			)
-			if err != nil || compiled == nil || compiled.CompiledSchema == nil {
-				return
-			}
-
-			schemaCache.Store(hash, compiled.ToCacheEntry(schema))
+			_, err := schema_validation.CompileSchemaOnce(schema, purpose, options, version, hash)
+			return err
 		}
 	}
+	return nil
 }
 
 // warmParameterSchema warms the cache for a parameter schema
-func warmParameterSchema(param *v3.Parameter, schemaCache cache.SchemaCache, options *config.ValidationOptions, version float32) {
+func warmParameterSchema(param *v3.Parameter, schemaCache cache.SchemaCache, options *config.ValidationOptions, version float32) error {
 	if param != nil {
 		var schema *base.Schema
 
@@ -560,19 +863,16 @@ func warmParameterSchema(param *v3.Parameter, schemaCache cache.SchemaCache, opt
 			hash := schema_validation.SchemaCacheKey(schema.GoLow().Hash(), version,
 				schema_validation.SchemaValidationPurposeGeneric)
 			if _, exists := schemaCache.Load(hash); !exists {
-				compiled, err := schema_validation.CompileSchemaForValidation(
+				_, err := schema_validation.CompileSchemaOnce(
 					schema,
 					schema_validation.SchemaValidationPurposeGeneric,
 					options,
 					version,
+					hash,
 				)
-				if err != nil || compiled == nil || compiled.CompiledSchema == nil {
-					return
-				}
-
-				// Store in cache using the shared SchemaCache type
-				schemaCache.Store(hash, compiled.ToCacheEntry(schema))
+				return err
 			}
 		}
 	}
+	return nil
 }
@@ -5,9 +5,11 @@ package validator
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -15,6 +17,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 	"unicode"
 
 	"github.com/dlclark/regexp2"
@@ -30,6 +33,7 @@ import (
 	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/message"
 	"github.com/pb33f/libopenapi-validator/schema_validation"
 )
 
@@ -148,6 +152,647 @@ func TestNewValidator_ValidateDocument(t *testing.T) {
 	assert.Len(t, errs, 0)
 }
 
+func TestNewValidator_StrictSanitize(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: Sanitize
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+        - name: X-Request-Id
+          in: header
+          schema:
+            type: string
+        - name: session
+          in: cookie
+          schema:
+            type: string
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                id:
+                  type: string
+                  readOnly: true
+                name:
+                  type: string
+      responses:
+        '200':
+          description: OK
+          headers:
+            X-Rate-Limit:
+              schema:
+                type: integer
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+                  secret:
+                    type: string
+                    writeOnly: true
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	v, errs := NewValidator(doc, config.WithStrictSanitize())
+	require.Empty(t, errs)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/pets?limit=10&debug=true",
+		bytes.NewBufferString(`{"id":"1","name":"fido","extra":true}`))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Request-Id", "abc")
+	request.Header.Set("X-Debug", "1")
+	request.AddCookie(&http.Cookie{Name: "session", Value: "s"})
+	request.AddCookie(&http.Cookie{Name: "tracker", Value: "t"})
+
+	valid, validationErrs := v.ValidateHttpRequest(request)
+	assert.True(t, valid)
+	assert.Empty(t, validationErrs)
+
+	assert.Equal(t, "limit=10", request.URL.RawQuery)
+	assert.Equal(t, "abc", request.Header.Get("X-Request-Id"))
+	assert.Empty(t, request.Header.Get("X-Debug"))
+	assert.Equal(t, "session=s", request.Header.Get("Cookie"))
+
+	body, _ := io.ReadAll(request.Body)
+	assert.JSONEq(t, `{"name":"fido"}`, string(body))
+
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewBufferString(`{"name":"fido","secret":"s","extra":1}`)),
+	}
+	response.Header.Set("Content-Type", "application/json")
+	response.Header.Set("X-Rate-Limit", "10")
+	response.Header.Set("X-Internal", "yes")
+
+	valid, validationErrs = v.ValidateHttpResponse(request, response)
+	assert.True(t, valid)
+	assert.Empty(t, validationErrs)
+
+	assert.Empty(t, response.Header.Get("X-Internal"))
+	assert.Equal(t, "10", response.Header.Get("X-Rate-Limit"))
+	body, _ = io.ReadAll(response.Body)
+	assert.JSONEq(t, `{"name":"fido"}`, string(body))
+	assert.Equal(t, int64(len(body)), response.ContentLength)
+}
+
+func TestNewValidator_StrictSanitize_NonJSONBodies(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: Sanitize
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/xml:
+            schema:
+              $ref: '#/components/schemas/Pet'
+          application/x-www-form-urlencoded:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        '200':
+          description: OK
+          content:
+            application/xml:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      type: object
+      xml:
+        name: pet
+      properties:
+        name:
+          type: string
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	v, errs := NewValidator(doc, config.WithStrictSanitize(), config.WithXmlBodyValidation(),
+		config.WithURLEncodedBodyValidation())
+	require.Empty(t, errs)
+
+	// XML and urlencoded bodies can't be written back in their own format, so their undeclared values are
+	// reported rather than removed.
+	for contentType, body := range map[string]string{
+		"application/xml":                   `<pet><name>rex</name><extra>1</extra></pet>`,
+		"application/x-www-form-urlencoded": `name=rex&extra=1`,
+	} {
+		request, _ := http.NewRequest(http.MethodPost, "https://things.com/pets", bytes.NewBufferString(body))
+		request.Header.Set("Content-Type", contentType)
+
+		valid, validationErrs := v.ValidateHttpRequest(request)
+		assert.False(t, valid, contentType)
+		require.Len(t, validationErrs, 1, contentType)
+		assert.Contains(t, validationErrs[0].Message, "extra", contentType)
+	}
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/pets", nil)
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/xml"}},
+		Body:       io.NopCloser(bytes.NewBufferString(`<pet><name>rex</name><extra>1</extra></pet>`)),
+	}
+	valid, validationErrs := v.ValidateHttpResponse(request, response)
+	assert.False(t, valid)
+	require.Len(t, validationErrs, 1)
+	assert.Contains(t, validationErrs[0].Message, "extra")
+}
+
+func TestNewValidator_ValidationPolicy(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: Policies
+  version: 1.0.0
+paths:
+  /strict:
+    post:
+      x-validation:
+        strict: true
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                meta:
+                  $ref: '#/components/schemas/Meta'
+      responses:
+        '200':
+          description: OK
+  /loose:
+    post:
+      parameters:
+        - name: limit
+          in: query
+          x-validation:
+            mode: off
+          schema:
+            type: integer
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: integer
+  /warn:
+    post:
+      x-validation:
+        mode: warn
+        skipResponse: true
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: integer
+components:
+  schemas:
+    Meta:
+      type: object
+      x-validation:
+        strict: false
+      properties:
+        tag:
+          type: string
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	var logs bytes.Buffer
+	v, errs := NewValidator(doc, config.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	require.Empty(t, errs)
+
+	post := func(path, body string) *http.Request {
+		request, _ := http.NewRequest(http.MethodPost, "https://things.com"+path, bytes.NewBufferString(body))
+		request.Header.Set("Content-Type", "application/json")
+		return request
+	}
+
+	// strict mode is only on for /strict, and the Meta schema opts out of it.
+	valid, validationErrors := v.ValidateHttpRequest(post("/strict", `{"name":"a","extra":1,"meta":{"tag":"x","other":1}}`))
+	assert.False(t, valid)
+	require.Len(t, validationErrors, 1)
+	assert.Contains(t, validationErrors[0].Message, "$.body.extra")
+
+	valid, validationErrors = v.ValidateHttpRequestSync(post("/strict", `{"name":"a","extra":1}`))
+	assert.False(t, valid)
+	assert.Len(t, validationErrors, 1)
+
+	valid, _ = v.ValidateHttpRequest(post("/loose", `{"name":"a","extra":1}`))
+	assert.True(t, valid)
+
+	// the limit parameter is off, the body is still enforced.
+	valid, _ = v.ValidateHttpRequest(post("/loose?limit=many", `{"name":"a"}`))
+	assert.True(t, valid)
+	valid, validationErrors = v.ValidateHttpRequest(post("/loose?limit=many", `{"name":1}`))
+	assert.False(t, valid)
+	require.Len(t, validationErrors, 1)
+	assert.Equal(t, helpers.RequestBodyValidation, validationErrors[0].ValidationType)
+
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewBufferString(`{"id":"not a number"}`)),
+	}
+	valid, _ = v.ValidateHttpResponse(post("/loose", `{}`), response)
+	assert.False(t, valid)
+
+	// warn mode logs the errors, and responses are skipped.
+	valid, validationErrors = v.ValidateHttpRequest(post("/warn", `{"name":1}`))
+	assert.True(t, valid)
+	assert.Empty(t, validationErrors)
+	assert.Contains(t, logs.String(), "validation error ignored by policy")
+
+	response.Body = io.NopCloser(bytes.NewBufferString(`{"id":"not a number"}`))
+	valid, validationErrors = v.ValidateHttpRequestResponse(post("/warn", `{"name":"a"}`), response)
+	assert.True(t, valid)
+	assert.Empty(t, validationErrors)
+}
+
+func TestNewValidator_DocumentPolicyValidatorsCached(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: Policies
+  version: 1.0.0
+x-validation:
+  strict: true
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: OK
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	v, errs := NewValidator(doc)
+	require.Empty(t, errs)
+	impl := v.(*validator)
+	pathItem := impl.v3Model.Paths.PathItems.GetOrZero("/pets")
+
+	// a request without an operation gets the document policy, its validators are built once.
+	request, _ := http.NewRequest(http.MethodDelete, "https://things.com/pets", nil)
+	first := impl.validatorsFor(message.FromHTTPRequest(request), pathItem)
+	require.NotNil(t, first.policy.Policy)
+	assert.NotSame(t, impl.paramValidator, first.paramValidator)
+	assert.Same(t, first, impl.validatorsFor(message.FromHTTPRequest(request), pathItem))
+
+	// operations without a policy of their own share the validators of the document policy.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/pets", nil)
+	get := impl.validatorsFor(message.FromHTTPRequest(request), pathItem)
+	assert.NotSame(t, first, get)
+	assert.Same(t, first.paramValidator, get.paramValidator)
+	assert.Same(t, first.requestValidator, get.requestValidator)
+}
+
+func TestNewValidator_ParameterPolicyByLocation(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: Policies
+  version: 1.0.0
+paths:
+  /things:
+    get:
+      parameters:
+        - name: id
+          in: header
+          x-validation:
+            mode: off
+          schema:
+            type: integer
+        - name: id
+          in: query
+          schema:
+            type: integer
+      responses:
+        '200':
+          description: OK
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	v, errs := NewValidator(doc)
+	require.Empty(t, errs)
+
+	// the policy of the header 'id' leaves the query 'id' enforced.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/things?id=one", nil)
+	request.Header.Set("ID", "two")
+	valid, validationErrors := v.ValidateHttpRequestSync(request)
+	assert.False(t, valid)
+	require.Len(t, validationErrors, 1)
+	assert.Equal(t, helpers.ParameterValidationQuery, validationErrors[0].ValidationSubType)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/things?id=1", nil)
+	request.Header.Set("ID", "two")
+	valid, _ = v.ValidateHttpRequestSync(request)
+	assert.True(t, valid)
+}
+
+func TestNewValidator_FormatRegistry(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: Formats
+  version: 1.0.0
+paths:
+  /orders:
+    post:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            format: int32
+        - name: X-Priority
+          in: header
+          schema:
+            type: integer
+            format: uint8
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                quantity:
+                  type: integer
+                  format: int32
+                code:
+                  type: string
+                  format: char
+      responses:
+        '200':
+          description: OK
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	v, errs := NewValidator(doc, config.WithFormatRegistry())
+	require.Empty(t, errs)
+
+	post := func(query, priority, body string) *http.Request {
+		request, _ := http.NewRequest(http.MethodPost, "https://things.com/orders"+query, bytes.NewBufferString(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("X-Priority", priority)
+		return request
+	}
+
+	valid, validationErrors := v.ValidateHttpRequest(post("?limit=10", "7", `{"quantity":3,"code":"A"}`))
+	assert.True(t, valid)
+	assert.Empty(t, validationErrors)
+
+	valid, validationErrors = v.ValidateHttpRequest(post("?limit=3000000000", "7", `{"quantity":3,"code":"A"}`))
+	assert.False(t, valid)
+	require.Len(t, validationErrors, 1)
+	assert.Equal(t, "limit", validationErrors[0].ParameterName)
+
+	valid, validationErrors = v.ValidateHttpRequest(post("?limit=10", "300", `{"quantity":3,"code":"A"}`))
+	assert.False(t, valid)
+	require.Len(t, validationErrors, 1)
+	assert.Equal(t, "X-Priority", validationErrors[0].ParameterName)
+
+	valid, validationErrors = v.ValidateHttpRequest(post("?limit=10", "7", `{"quantity":3000000000,"code":"AB"}`))
+	assert.False(t, valid)
+	require.Len(t, validationErrors, 1)
+	assert.Len(t, validationErrors[0].SchemaValidationErrors, 2)
+}
+
+func TestNewValidator_FormatRegistryIntegerBounds(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: Formats
+  version: 1.0.0
+paths:
+  /counters:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                signed:
+                  type: integer
+                  format: int64
+                unsigned:
+                  type: integer
+                  format: uint64
+      responses:
+        '200':
+          description: OK
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	v, errs := NewValidator(doc, config.WithFormatRegistry())
+	require.Empty(t, errs)
+
+	validate := func(body string) (bool, []*errors.ValidationError) {
+		request, _ := http.NewRequest(http.MethodPost, "https://things.com/counters", bytes.NewBufferString(body))
+		request.Header.Set("Content-Type", "application/json")
+		return v.ValidateHttpRequest(request)
+	}
+
+	// the bounds are exact, they aren't rounded to the nearest float64.
+	for _, body := range []string{
+		`{"signed": 9223372036854775807}`,
+		`{"signed": 9223372036854775806}`,
+		`{"signed": -9223372036854775808}`,
+		`{"unsigned": 18446744073709551615}`,
+		`{"unsigned": 18446744073709551614}`,
+		`{"unsigned": 0}`,
+	} {
+		valid, validationErrors := validate(body)
+		assert.True(t, valid, body)
+		assert.Empty(t, validationErrors, body)
+	}
+
+	for _, body := range []string{
+		`{"signed": 9223372036854775808}`,
+		`{"signed": -9223372036854775809}`,
+		`{"unsigned": 18446744073709551616}`,
+		`{"unsigned": -1}`,
+	} {
+		valid, validationErrors := validate(body)
+		assert.False(t, valid, body)
+		require.Len(t, validationErrors, 1, body)
+		require.Len(t, validationErrors[0].SchemaValidationErrors, 1, body)
+		assert.Contains(t, validationErrors[0].SchemaValidationErrors[0].Reason, "out of range", body)
+	}
+
+	// trailing data is still rejected.
+	valid, _ := validate(`{"signed": 1} {}`)
+	assert.False(t, valid)
+}
+
+func TestNewValidator_DefaultInjection(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: Defaults
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            default: 20
+        - name: X-Region
+          in: header
+          schema:
+            type: string
+            default: eu
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                status:
+                  type: string
+                  default: available
+      responses:
+        '200':
+          description: OK
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	var recorded []config.InjectedDefault
+	v, errs := NewValidator(doc, config.WithDefaultInjection(func(_ message.Request, injected []config.InjectedDefault) {
+		recorded = append(recorded, injected...)
+	}))
+	require.Empty(t, errs)
+
+	for _, validate := range []func(*http.Request) (bool, []*errors.ValidationError){v.ValidateHttpRequest, v.ValidateHttpRequestSync} {
+		recorded = nil
+		request, _ := http.NewRequest(http.MethodPost, "https://things.com/pets", bytes.NewBufferString(`{"name":"fido"}`))
+		request.Header.Set("Content-Type", "application/json")
+
+		valid, validationErrors := validate(request)
+		assert.True(t, valid)
+		assert.Empty(t, validationErrors)
+		assert.Len(t, recorded, 3)
+
+		assert.Equal(t, "20", request.URL.Query().Get("limit"))
+		assert.Equal(t, "eu", request.Header.Get("X-Region"))
+		body, _ := io.ReadAll(request.Body)
+		assert.JSONEq(t, `{"name":"fido","status":"available"}`, string(body))
+	}
+
+	// invalid requests are left alone
+	recorded = nil
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/pets", bytes.NewBufferString(`{"status":"sold"}`))
+	request.Header.Set("Content-Type", "application/json")
+	valid, _ := v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	assert.Empty(t, recorded)
+	assert.Empty(t, request.URL.RawQuery)
+	assert.Empty(t, request.Header.Get("X-Region"))
+}
+
+func TestNewValidator_ValidationPolicy_InvalidPolicyReported(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: Policies
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      x-validation:
+        mode: loud
+      responses:
+        '200':
+          description: OK
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	v, errs := NewValidator(doc)
+	require.Empty(t, errs)
+
+	valid, validationErrors := v.ValidateDocument()
+	assert.False(t, valid)
+	require.Len(t, validationErrors, 1)
+	assert.Equal(t, helpers.ValidationPolicyInvalid, validationErrors[0].ValidationSubType)
+	assert.Equal(t, "Validation policy for GET /pets is invalid", validationErrors[0].Message)
+}
+
+func TestNewValidator_ValidateDocument_PathTemplateAnalysis(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: Path Analysis
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: OK
+  /pets/{name}:
+    delete:
+      parameters:
+        - name: name
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '204':
+          description: Deleted
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	// analysis is opt-in
+	v, _ := NewValidator(doc)
+	valid, errs := v.ValidateDocument()
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	v, _ = NewValidator(doc, config.WithPathTemplateAnalysis())
+	valid, errs = v.ValidateDocument()
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Equal(t, helpers.PathTemplateEquivalent, errs[0].ValidationSubType)
+	assert.Equal(t, 17, errs[0].SpecLine)
+}
+
 type dlclarkRegexp regexp2.Regexp
 
 func (re *dlclarkRegexp) MatchString(s string) bool {
@@ -694,6 +1339,7 @@ paths:
 	valid, errors := v.ValidateHttpRequestSync(request)
 
 	assert.True(t, valid)
+	assert.NotNil(t, errors)
 	assert.Len(t, errors, 0)
 }
 
@@ -3189,3 +3835,369 @@ paths:
 	}
 	assert.True(t, foundWriteOnly, "should report writeOnly violation")
 }
+
+func TestNewValidator_ValidateMessage(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pets:
+    post:
+      parameters:
+        - name: dry-run
+          in: query
+          schema:
+            type: boolean
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+      responses:
+        '201':
+          description: created
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id:
+                    type: integer
+`
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	built, errs := NewValidator(doc)
+	require.Empty(t, errs)
+	v, ok := built.(MessageValidator)
+	require.True(t, ok)
+
+	request, err := message.NewAPIGatewayRequest(&message.APIGatewayProxyRequest{
+		HTTPMethod:            http.MethodPost,
+		Path:                  "/pets",
+		Headers:               map[string]string{"Content-Type": "application/json"},
+		QueryStringParameters: map[string]string{"dry-run": "true"},
+		Body:                  `{"name":"fido"}`,
+	})
+	require.NoError(t, err)
+
+	valid, validationErrors := v.ValidateMessage(request)
+	assert.True(t, valid)
+	assert.Empty(t, validationErrors)
+
+	response, err := message.ParseRawResponse([]byte("HTTP/1.1 201 Created\r\n"+
+		"Content-Type: application/json\r\nContent-Length: 8\r\n\r\n{\"id\":1}"), request)
+	require.NoError(t, err)
+
+	valid, validationErrors = v.ValidateMessageResponse(request, response)
+	assert.True(t, valid)
+	assert.Empty(t, validationErrors)
+
+	request, _ = message.NewAPIGatewayRequest(&message.APIGatewayProxyRequest{
+		HTTPMethod:            http.MethodPost,
+		Path:                  "/pets",
+		Headers:               map[string]string{"Content-Type": "application/json"},
+		QueryStringParameters: map[string]string{"dry-run": "maybe"},
+		Body:                  `{}`,
+	})
+	valid, validationErrors = v.ValidateMessage(request)
+	assert.False(t, valid)
+	assert.Len(t, validationErrors, 2)
+
+	response, _ = message.ParseRawResponse([]byte("HTTP/1.1 201 Created\r\n"+
+		"Content-Type: application/json\r\nContent-Length: 2\r\n\r\n{}"), request)
+	valid, validationErrors = v.ValidateMessageResponse(request, response)
+	assert.False(t, valid)
+	assert.Len(t, validationErrors, 1)
+
+	// messages are validated natively, a sanitized body is put back into the message.
+	built, errs = NewValidator(doc, config.WithStrictMode(), config.WithStrictSanitize())
+	require.Empty(t, errs)
+	request, _ = message.NewAPIGatewayRequest(&message.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodPost,
+		Path:       "/pets",
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       `{"name":"fido","secret":"s3cr3t"}`,
+	})
+	valid, validationErrors = built.(MessageValidator).ValidateMessage(request)
+	assert.True(t, valid)
+	assert.Empty(t, validationErrors)
+	body, err := io.ReadAll(request.Body())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"fido"}`, string(body))
+}
+
+func TestNewValidator_ValidateHttpRequestContext(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pets:
+    post:
+      security:
+        - ApiKey: []
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: integer
+  /warned:
+    post:
+      x-validation:
+        mode: warn
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+components:
+  securitySchemes:
+    ApiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+`
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+
+	slow := false
+	authFn := func(ctx context.Context, input *config.AuthenticationInput) error {
+		if slow {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	}
+	v, errs := NewValidator(doc, config.WithAuthenticationFunc(authFn))
+	require.Empty(t, errs)
+	cv, ok := v.(ContextValidator)
+	require.True(t, ok)
+
+	newRequest := func(path string) *http.Request {
+		request, _ := http.NewRequest(http.MethodPost, "https://things.com"+path, strings.NewReader(`{"name":"fido"}`))
+		request.Header.Set("Content-Type", "application/json")
+		return request
+	}
+
+	// a live context validates as normal, and the body can still be read by the caller.
+	request := newRequest("/pets")
+	valid, validationErrors := cv.ValidateHttpRequestContext(context.Background(), request)
+	assert.True(t, valid)
+	assert.Empty(t, validationErrors)
+	body, _ := io.ReadAll(request.Body)
+	assert.Equal(t, `{"name":"fido"}`, string(body))
+
+	// a validation that outlives its deadline stops and reports a timeout.
+	slow = true
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	valid, validationErrors = cv.ValidateHttpRequestContext(ctx, newRequest("/pets"))
+	assert.False(t, valid)
+	require.Len(t, validationErrors, 1)
+	assert.Equal(t, helpers.ContextValidation, validationErrors[0].ValidationType)
+	assert.Equal(t, helpers.ValidationTimeout, validationErrors[0].ValidationSubType)
+	slow = false
+
+	// a cancelled context stops sync validation as well.
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	valid, validationErrors = cv.ValidateHttpRequestContext(cancelled, newRequest("/pets"))
+	assert.False(t, valid)
+	assert.True(t, errors.IsCancelled(validationErrors))
+	assert.Equal(t, helpers.ValidationCancelled, validationErrors[0].ValidationSubType)
+
+	// the context of the request itself is not checked, so a request is still validated after its handler
+	// returned and its context was cancelled.
+	valid, validationErrors = v.ValidateHttpRequestSync(newRequest("/pets").WithContext(cancelled))
+	assert.True(t, valid)
+	assert.Empty(t, validationErrors)
+	valid, validationErrors = v.ValidateHttpRequest(newRequest("/pets").WithContext(cancelled))
+	assert.True(t, valid)
+	assert.Empty(t, validationErrors)
+
+	// a warn policy doesn't hide a cancellation.
+	valid, validationErrors = cv.ValidateHttpRequestContext(cancelled, newRequest("/warned"))
+	assert.False(t, valid)
+	assert.True(t, errors.IsCancelled(validationErrors))
+
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"id":1}`)),
+	}
+	valid, validationErrors = cv.ValidateHttpResponseContext(cancelled, newRequest("/pets"), response)
+	assert.False(t, valid)
+	assert.True(t, errors.IsCancelled(validationErrors))
+
+	response.Body = io.NopCloser(strings.NewReader(`{"id":1}`))
+	valid, validationErrors = cv.ValidateHttpResponseContext(context.Background(), newRequest("/pets"), response)
+	assert.True(t, valid)
+	assert.Empty(t, validationErrors)
+
+	response.Body = io.NopCloser(strings.NewReader(`{"id":1}`))
+	valid, validationErrors = v.ValidateHttpRequestResponse(newRequest("/pets").WithContext(cancelled), response)
+	assert.True(t, valid)
+	assert.Empty(t, validationErrors)
+	response.Body = io.NopCloser(strings.NewReader(`{"id":1}`))
+	valid, validationErrors = v.ValidateHttpResponse(newRequest("/pets").WithContext(cancelled), response)
+	assert.True(t, valid)
+	assert.Empty(t, validationErrors)
+}
+
+const warmingSpec = `openapi: 3.1.0
+info:
+  title: Warming
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+    get:
+      operationId: getPet
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+    put:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                  pattern: '(?<=a)b'
+      responses:
+        '204':
+          description: Updated
+  /owners:
+    get:
+      operationId: listOwners
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            minimum: 1
+      responses:
+        '200':
+          description: OK
+`
+
+func TestCacheWarming_ParallelProgress(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(warmingSpec))
+	require.NoError(t, err)
+
+	var progress []config.WarmProgress
+	v, errs := NewValidator(doc, config.WithWarmWorkers(3), config.WithWarmProgress(func(p config.WarmProgress) {
+		progress = append(progress, p)
+	}))
+	require.Empty(t, errs)
+
+	// the path parameter, the response, the request body and the query parameter.
+	require.Len(t, progress, 5)
+	done := progress[4]
+	assert.True(t, done.Done)
+	assert.Equal(t, 4, done.Total)
+	assert.Equal(t, 4, done.Completed)
+	assert.Equal(t, 1, done.Failed)
+
+	var failed []config.WarmProgress
+	for _, p := range progress[:4] {
+		assert.False(t, p.Done)
+		if p.Err != nil {
+			failed = append(failed, p)
+		}
+	}
+	require.Len(t, failed, 1)
+	assert.Equal(t, "PUT", failed[0].Method)
+	assert.Equal(t, "/pets/{id}", failed[0].Path)
+	assert.Equal(t, "requestBody application/json", failed[0].Location)
+
+	assert.Equal(t, 3, schemaCacheEntryCount(v.(*validator).options.SchemaCache))
+}
+
+func TestCacheWarming_Operations(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(warmingSpec))
+	require.NoError(t, err)
+
+	var done config.WarmProgress
+	v, errs := NewValidator(doc,
+		config.WithWarmOperations("listOwners", "get /pets/{id}"),
+		config.WithWarmProgress(func(p config.WarmProgress) { done = p }))
+	require.Empty(t, errs)
+	assert.True(t, done.Done)
+	assert.Equal(t, 3, done.Total)
+	assert.Zero(t, done.Failed)
+
+	// operations that were not warmed compile on demand.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/owners?limit=0", nil)
+	valid, validationErrors := v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	assert.Len(t, validationErrors, 1)
+
+	doc, err = libopenapi.NewDocument([]byte(warmingSpec))
+	require.NoError(t, err)
+	_, errs = NewValidator(doc,
+		config.WithWarmOperations("nothing"),
+		config.WithWarmProgress(func(p config.WarmProgress) { done = p }))
+	require.Empty(t, errs)
+	assert.True(t, done.Done)
+	assert.Zero(t, done.Total)
+}
+
+func TestCacheWarming_Background(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(warmingSpec))
+	require.NoError(t, err)
+
+	warmed := make(chan config.WarmProgress, 1)
+	v, errs := NewValidator(doc, config.WithBackgroundWarming(), config.WithWarmWorkers(2),
+		config.WithWarmProgress(func(p config.WarmProgress) {
+			if p.Done {
+				warmed <- p
+			}
+		}))
+	require.Empty(t, errs)
+
+	// requests are validated while warming continues.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/pets/abc", nil)
+	valid, validationErrors := v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	assert.Len(t, validationErrors, 1)
+
+	done := <-warmed
+	assert.Equal(t, done.Total, done.Completed)
+	v.Release()
+
+	// releasing stops warming that has not finished.
+	doc, err = libopenapi.NewDocument([]byte(warmingSpec))
+	require.NoError(t, err)
+	v, errs = NewValidator(doc, config.WithBackgroundWarming())
+	require.Empty(t, errs)
+	v.Release()
+	v.Release()
+}
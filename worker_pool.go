@@ -0,0 +1,143 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/message"
+)
+
+// workerPool runs tasks on a bounded number of goroutines, shared by every validator. Tasks are never queued,
+// a task that finds every worker busy is left to the caller to run inline, so a saturated pool adds no latency.
+type workerPool struct {
+	size  int
+	tasks chan func()
+	start sync.Once
+}
+
+// requestPool validates request bodies in parallel with their parameters.
+var requestPool = &workerPool{size: runtime.GOMAXPROCS(0)}
+
+// submit hands a task to an idle worker, starting the workers the first time. It returns false when every
+// worker is busy, and the task has not been run.
+func (p *workerPool) submit(task func()) bool {
+	p.start.Do(func() {
+		p.tasks = make(chan func())
+		for range max(p.size, 1) {
+			go func() {
+				for task := range p.tasks {
+					task()
+				}
+			}()
+		}
+	})
+	select {
+	case p.tasks <- task:
+		return true
+	default:
+		return false
+	}
+}
+
+// pooledPhase is a phase of a request running on the request pool.
+type pooledPhase struct {
+	done     chan struct{}
+	errs     []*errors.ValidationError
+	duration time.Duration
+	detached message.Request // the copy of the request a detached phase runs on
+}
+
+// submitPhase runs a phase of a request on the request pool. It returns nil when every worker is busy, and the
+// phase has to be run inline.
+func submitPhase(
+	options *config.ValidationOptions,
+	phase Phase,
+	validate validationFunction,
+	request message.Request,
+	pathItem *v3.PathItem,
+	pathValue string,
+) *pooledPhase {
+	pooled := &pooledPhase{done: make(chan struct{})}
+	if !requestPool.submit(func() {
+		defer close(pooled.done)
+		pooled.errs, pooled.duration = runPhase(options, phase, validate, request, pathItem, pathValue)
+	}) {
+		return nil
+	}
+	return pooled
+}
+
+// detachPhase runs a phase of a request that carries a context on a copy of the request, with its own URL,
+// headers and body, so the caller can stop waiting for the phase once the context is done and hand the request
+// back while the phase is still running. The body is read until the context is done, and put back into the
+// request. The phase runs on the request pool, or on its own goroutine when every worker is busy, as it can't be
+// left behind when it runs inline. It returns the cancellation errors when the context is done before the body
+// has been read.
+func detachPhase(
+	ctx context.Context,
+	options *config.ValidationOptions,
+	phase Phase,
+	validate validationFunction,
+	request message.Request,
+	pathItem *v3.PathItem,
+	pathValue string,
+) (*pooledPhase, []*errors.ValidationError) {
+	body, err := message.ReadRequestBodyContext(ctx, request)
+	if err != nil {
+		return nil, errors.MessageContextDone(request)
+	}
+	u := *request.URL()
+	detached := message.WithContext(ctx, &bufferedRequest{
+		method: request.Method(),
+		url:    &u,
+		header: request.Header().Clone(),
+		body:   body,
+	})
+	pooled := &pooledPhase{done: make(chan struct{}), detached: detached}
+	task := func() {
+		defer close(pooled.done)
+		pooled.errs, pooled.duration = runPhase(options, phase, validate, detached, pathItem, pathValue)
+	}
+	if !requestPool.submit(task) {
+		go task()
+	}
+	return pooled, nil
+}
+
+// wait waits for the phase to finish, it returns straight away for a nil phase.
+func (p *pooledPhase) wait() {
+	if p != nil {
+		<-p.done
+	}
+}
+
+// waitContext waits for the phase to finish, or for ctx to be done, returning false when ctx is done first.
+func (p *pooledPhase) waitContext(ctx context.Context) bool {
+	if ctx == nil {
+		p.wait()
+		return true
+	}
+	select {
+	case <-p.done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// settle waits for a phase that reads the request of the caller, so it is done with the request before the
+// request is handed back. A detached phase is left to finish on its own.
+func (p *pooledPhase) settle() {
+	if p != nil && p.detached == nil {
+		<-p.done
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright 2023-2026 Princess Beef Heavy Industries, LLC / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/testify/assert"
+	"github.com/pb33f/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+func TestWorkerPool_Submit(t *testing.T) {
+	pool := &workerPool{size: 1}
+
+	// workers start with the first task, which may find none of them ready yet.
+	ran := make(chan struct{})
+	require.Eventually(t, func() bool {
+		return pool.submit(func() { close(ran) })
+	}, time.Second, time.Millisecond)
+	<-ran
+
+	// a busy pool does not queue tasks, callers run them inline.
+	release := make(chan struct{})
+	require.Eventually(t, func() bool {
+		return pool.submit(func() { <-release })
+	}, time.Second, time.Millisecond)
+	assert.False(t, pool.submit(func() { t.Error("task should not run") }))
+	close(release)
+
+	require.Eventually(t, func() bool {
+		return pool.submit(func() {})
+	}, time.Second, time.Millisecond)
+}
+
+const concurrencySpec = `openapi: 3.1.0
+info:
+  title: Concurrency
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    post:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: X-Trace
+          in: header
+          schema:
+            type: string
+            minLength: 3
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [pets]
+              properties:
+                pets:
+                  type: array
+                  items:
+                    type: object
+                    required: [name]
+                    properties:
+                      name:
+                        type: string
+                      tag:
+                        type: string
+      responses:
+        '204':
+          description: created`
+
+// concurrencyBody returns a body with n pets, the last of which is missing its name when invalid is set.
+func concurrencyBody(n int, invalid bool) string {
+	var body strings.Builder
+	body.WriteString(`{"pets":[`)
+	for i := range n {
+		if i > 0 {
+			body.WriteString(",")
+		}
+		if invalid && i == n-1 {
+			body.WriteString(`{"tag":"dog"}`)
+			continue
+		}
+		fmt.Fprintf(&body, `{"name":"pet-%d","tag":"dog"}`, i)
+	}
+	body.WriteString("]}")
+	return body.String()
+}
+
+func concurrencyRequest(path, trace, body string) *http.Request {
+	request, _ := http.NewRequest(http.MethodPost, "https://api.com"+path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Trace", trace)
+	return request
+}
+
+func TestValidateHttpRequest_ConcurrencySameResults(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(concurrencySpec))
+	require.NoError(t, err)
+
+	messages := func(validationErrors []*errors.ValidationError) []string {
+		var found []string
+		for _, validationError := range validationErrors {
+			found = append(found, validationError.Message)
+		}
+		return found
+	}
+
+	inline, errs := NewValidator(doc, config.WithConcurrency(config.ConcurrencyInline))
+	require.Empty(t, errs)
+	parallel, errs := NewValidator(doc, config.WithConcurrency(config.ConcurrencyParallel))
+	require.Empty(t, errs)
+	auto, errs := NewValidator(doc, config.WithParallelBodyThreshold(1024))
+	require.Empty(t, errs)
+
+	for _, n := range []int{1, 100} {
+		for _, invalid := range []bool{false, true} {
+			path, trace := "/pets/1", "abcd"
+			if invalid {
+				path, trace = "/pets/one", "a"
+			}
+			body := concurrencyBody(n, invalid)
+
+			valid, expected := inline.ValidateHttpRequest(concurrencyRequest(path, trace, body))
+			assert.Equal(t, !invalid, valid)
+			if invalid {
+				// the path, header and body errors are all reported.
+				assert.Len(t, expected, 3)
+			}
+			// synchronous validation reports the same errors, in the order the phases ran rather than sorted.
+			_, found := inline.ValidateHttpRequestSync(concurrencyRequest(path, trace, body))
+			sortValidationErrors(found)
+			assert.Equal(t, messages(expected), messages(found))
+			for _, v := range []Validator{parallel, auto} {
+				valid, found := v.ValidateHttpRequest(concurrencyRequest(path, trace, body))
+				assert.Equal(t, !invalid, valid)
+				assert.Equal(t, messages(expected), messages(found))
+			}
+		}
+	}
+}
+
+func TestValidateHttpRequest_ParallelCancelled(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(concurrencySpec))
+	require.NoError(t, err)
+	v, errs := NewValidator(doc, config.WithConcurrency(config.ConcurrencyParallel))
+	require.Empty(t, errs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request := concurrencyRequest("/pets/1", "abcd", concurrencyBody(10, false))
+	valid, found := v.(ContextValidator).ValidateHttpRequestContext(ctx, request)
+	assert.False(t, valid)
+	require.Len(t, found, 1)
+	assert.Equal(t, helpers.ContextValidation, found[0].ValidationType)
+}
+
+func TestValidateHttpRequest_CancelledWhileBodyOnPool(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(concurrencySpec))
+	require.NoError(t, err)
+	v, errs := NewValidator(doc, config.WithConcurrency(config.ConcurrencyParallel))
+	require.Empty(t, errs)
+	body := concurrencyBody(4000, false)
+
+	for range 20 {
+		ctx, cancel := context.WithCancel(context.Background())
+		request := concurrencyRequest("/pets/1", "abcd", body)
+		timer := time.AfterFunc(time.Millisecond, cancel)
+		valid, found := v.(ContextValidator).ValidateHttpRequestContext(ctx, request)
+		timer.Stop()
+		cancel()
+		if !valid {
+			require.Len(t, found, 1)
+			assert.Equal(t, helpers.ContextValidation, found[0].ValidationType)
+		}
+
+		// the request is handed back once the body is no longer read, so it can be reused straight away.
+		request.Body = http.NoBody
+	}
+}
+
+func TestValidateHttpRequest_ReturnsAtDeadline(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(concurrencySpec))
+	require.NoError(t, err)
+	body := concurrencyBody(100000, false)
+
+	for _, concurrency := range []config.Concurrency{config.ConcurrencyParallel, config.ConcurrencyInline} {
+		v, errs := NewValidator(doc, config.WithConcurrency(concurrency))
+		require.Empty(t, errs)
+
+		// the body takes much longer to validate than the deadline allows.
+		start := time.Now()
+		valid, _ := v.ValidateHttpRequestSync(concurrencyRequest("/pets/1", "abcd", body))
+		require.True(t, valid)
+		full := time.Since(start)
+
+		deadline := full / 10
+		ctx, cancel := context.WithTimeout(context.Background(), deadline)
+		request := concurrencyRequest("/pets/1", "abcd", body)
+		start = time.Now()
+		valid, found := v.(ContextValidator).ValidateHttpRequestContext(ctx, request)
+		elapsed := time.Since(start)
+		cancel()
+		assert.False(t, valid)
+		require.Len(t, found, 1)
+		assert.Equal(t, helpers.ContextValidation, found[0].ValidationType)
+		assert.Less(t, elapsed, deadline+full/2, "validation should stop at the deadline")
+
+		// the body is validated on a copy, the request is handed back with its body to be read again.
+		read, _ := io.ReadAll(request.Body)
+		assert.Equal(t, body, string(read))
+	}
+
+	// a body that is sent slowly is not waited for either.
+	v, errs := NewValidator(doc)
+	require.Empty(t, errs)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	request := concurrencyRequest("/pets/1", "abcd", "")
+	request.Body = io.NopCloser(iotest.OneByteReader(&slowReader{body: strings.NewReader(body), delay: time.Millisecond}))
+	start := time.Now()
+	valid, found := v.(ContextValidator).ValidateHttpRequestContext(ctx, request)
+	assert.False(t, valid)
+	require.Len(t, found, 1)
+	assert.Equal(t, helpers.ContextValidation, found[0].ValidationType)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+// slowReader waits delay before each read of its body.
+type slowReader struct {
+	body  io.Reader
+	delay time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	time.Sleep(r.delay)
+	return r.body.Read(p)
+}
+
+func benchmarkConcurrency(b *testing.B, pets int, validate func(Validator, *http.Request) (bool, []*errors.ValidationError), opts ...config.Option) {
+	doc, err := libopenapi.NewDocument([]byte(concurrencySpec))
+	require.NoError(b, err)
+	v, errs := NewValidator(doc, opts...)
+	require.Empty(b, errs)
+	body := concurrencyBody(pets, false)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			request, _ := http.NewRequest(http.MethodPost, "https://api.com/pets/1", strings.NewReader(body))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("X-Trace", "abcd")
+			if valid, _ := validate(v, request); !valid {
+				b.Fatal("request should be valid")
+			}
+		}
+	})
+}
+
+func validateSync(v Validator, request *http.Request) (bool, []*errors.ValidationError) {
+	return v.ValidateHttpRequestSync(request)
+}
+
+func validateConcurrent(v Validator, request *http.Request) (bool, []*errors.ValidationError) {
+	return v.ValidateHttpRequest(request)
+}
+
+// BenchmarkValidateHttpRequest compares the concurrency strategies of ValidateHttpRequest with
+// ValidateHttpRequestSync, for a small body and for a body above the default parallel body threshold.
+func BenchmarkValidateHttpRequest(b *testing.B) {
+	for _, size := range []struct {
+		name string
+		pets int
+	}{{"small", 2}, {"large", 4000}} {
+		b.Run(size.name+"/sync", func(b *testing.B) {
+			benchmarkConcurrency(b, size.pets, validateSync)
+		})
+		b.Run(size.name+"/auto", func(b *testing.B) {
+			benchmarkConcurrency(b, size.pets, validateConcurrent)
+		})
+		b.Run(size.name+"/inline", func(b *testing.B) {
+			benchmarkConcurrency(b, size.pets, validateConcurrent, config.WithConcurrency(config.ConcurrencyInline))
+		})
+		b.Run(size.name+"/parallel", func(b *testing.B) {
+			benchmarkConcurrency(b, size.pets, validateConcurrent, config.WithConcurrency(config.ConcurrencyParallel))
+		})
+	}
+}